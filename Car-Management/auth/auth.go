@@ -0,0 +1,86 @@
+// Package auth defines the JWT claims shared by token issuing and
+// verification, and carries the authenticated principal through request
+// contexts so every layer can see who is calling and for which tenant.
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+	// RolePlatformAdmin manages the tenants themselves and the settings they
+	// share. It is also an admin of its own tenant.
+	RolePlatformAdmin = "platform_admin"
+)
+
+var ErrNoTenant = errors.New("no tenant in request context")
+
+// Claims are the JWT claims issued at login and checked by the auth
+// middleware.
+type Claims struct {
+	Username string    `json:"username"`
+	TenantID uuid.UUID `json:"tenant_id"`
+	Role     string    `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// Principal is the authenticated caller.
+type Principal struct {
+	Username string
+	TenantID uuid.UUID
+	Role     string
+}
+
+func (p Principal) IsAdmin() bool {
+	return p.HasRole(RoleAdmin)
+}
+
+// HasRole reports whether the principal may act with role. A platform admin
+// has every role.
+func (p Principal) HasRole(role string) bool {
+	return p.Role == role || p.Role == RolePlatformAdmin
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// Actor names the caller for audit records, falling back to "system" for
+// work not done on behalf of a user.
+func Actor(ctx context.Context) string {
+	if p, ok := PrincipalFromContext(ctx); ok && p.Username != "" {
+		return p.Username
+	}
+	return "system"
+}
+
+// WithTenant scopes ctx to a tenant without a full principal, for background
+// jobs and tests.
+func WithTenant(ctx context.Context, tenantID uuid.UUID) context.Context {
+	p, _ := PrincipalFromContext(ctx)
+	p.TenantID = tenantID
+	return WithPrincipal(ctx, p)
+}
+
+// TenantFromContext returns the tenant every store query must be scoped to.
+// Stores fail closed with ErrNoTenant rather than returning unscoped data.
+func TenantFromContext(ctx context.Context) (uuid.UUID, error) {
+	p, ok := PrincipalFromContext(ctx)
+	if !ok || p.TenantID == uuid.Nil {
+		return uuid.Nil, ErrNoTenant
+	}
+	return p.TenantID, nil
+}
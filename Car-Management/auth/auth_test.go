@@ -0,0 +1,21 @@
+package auth
+
+import "testing"
+
+func TestHasRole(t *testing.T) {
+	tests := []struct {
+		role, want string
+		ok         bool
+	}{
+		{RoleAdmin, RoleAdmin, true},
+		{RoleUser, RoleAdmin, false},
+		{RoleAdmin, RolePlatformAdmin, false},
+		{RolePlatformAdmin, RoleAdmin, true},
+		{RolePlatformAdmin, RolePlatformAdmin, true},
+	}
+	for _, tt := range tests {
+		if got := (Principal{Role: tt.role}).HasRole(tt.want); got != tt.ok {
+			t.Errorf("%s.HasRole(%s) = %v, want %v", tt.role, tt.want, got, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "pbkdf2-sha256$600000$") {
+		t.Errorf("hash = %q, want the pbkdf2-sha256 scheme and work factor", hash)
+	}
+	if !CheckPassword(hash, "correct horse") {
+		t.Error("CheckPassword rejected the right password")
+	}
+	if CheckPassword(hash, "battery staple") {
+		t.Error("CheckPassword accepted a wrong password")
+	}
+
+	again, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again == hash {
+		t.Error("two hashes of one password are equal, want distinct salts")
+	}
+}
+
+func TestCheckPasswordRejectsMalformedHashes(t *testing.T) {
+	for _, hash := range []string{
+		"",
+		"correct horse",
+		"bcrypt$10$c2FsdA$a2V5",
+		"pbkdf2-sha256$many$c2FsdA$a2V5",
+		"pbkdf2-sha256$0$c2FsdA$a2V5",
+		"pbkdf2-sha256$1$!!$a2V5",
+		"pbkdf2-sha256$1$c2FsdA$",
+	} {
+		if CheckPassword(hash, "correct horse") {
+			t.Errorf("CheckPassword(%q) = true, want false", hash)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+// Package blob stores opaque file contents, such as car photos, by key.
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when no blob is stored under a key.
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore keeps the contents of files outside the database. Keys are
+// slash-separated relative paths chosen by the caller.
+type BlobStore interface {
+	// Put stores everything read from r under key, replacing any blob
+	// already there.
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Open returns the blob stored under key; the caller must close it.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob under key. Deleting a missing blob is not an
+	// error.
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,140 @@
+// Package cache provides a small in-process LRU cache with per-entry expiry.
+package cache
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LRU is a fixed-size, thread-safe cache. Entries are evicted when the cache
+// is full (least recently used first) or once their TTL has passed.
+//
+// Every removal bumps a generation counter. Callers that load a value outside
+// the lock can pass the generation they observed before loading to
+// SetIfGeneration, which drops the value if an invalidation happened in the
+// meantime instead of caching stale data.
+type LRU struct {
+	mu         sync.Mutex
+	size       int
+	ttl        time.Duration
+	ll         *list.List
+	items      map[string]*list.Element
+	generation uint64
+}
+
+type entry struct {
+	key       string
+	value     any
+	expiresAt time.Time
+}
+
+func NewLRU(size int, ttl time.Duration) *LRU {
+	if size <= 0 {
+		size = 1
+	}
+	return &LRU{
+		size:  size,
+		ttl:   ttl,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (c *LRU) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if c.ttl > 0 && time.Now().After(e.expiresAt) {
+		c.removeElement(el)
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return e.value, true
+}
+
+func (c *LRU) Set(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(key, value)
+}
+
+// SetIfGeneration stores the value only if no invalidation has happened since
+// generation was read. It reports whether the value was stored.
+func (c *LRU) SetIfGeneration(key string, value any, generation uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return false
+	}
+	c.set(key, value)
+	return true
+}
+
+func (c *LRU) Generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+func (c *LRU) Remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	if el, ok := c.items[key]; ok {
+		c.removeElement(el)
+	}
+}
+
+// RemovePrefix removes every entry whose key starts with prefix.
+func (c *LRU) RemovePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	for key, el := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			c.removeElement(el)
+		}
+	}
+}
+
+func (c *LRU) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.ll.Init()
+	c.items = make(map[string]*list.Element)
+}
+
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+func (c *LRU) set(key string, value any) {
+	expiresAt := time.Now().Add(c.ttl)
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry)
+		e.value = value
+		e.expiresAt = expiresAt
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+	for c.ll.Len() > c.size {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+func (c *LRU) removeElement(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.items, el.Value.(*entry).key)
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU(2, time.Minute)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a")
+	c.Set("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("b is still cached, want it evicted as least recently used")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("%s was evicted", key)
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len = %d, want 2", c.Len())
+	}
+}
+
+func TestLRUExpires(t *testing.T) {
+	c := NewLRU(10, time.Millisecond)
+	c.Set("a", 1)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Error("a is still cached after its TTL")
+	}
+}
+
+func TestLRURemovePrefix(t *testing.T) {
+	c := NewLRU(10, time.Minute)
+	c.Set("tenant-1/cars", 1)
+	c.Set("tenant-1/car:x", 2)
+	c.Set("tenant-2/cars", 3)
+	c.RemovePrefix("tenant-1/car")
+
+	for _, key := range []string{"tenant-1/cars", "tenant-1/car:x"} {
+		if _, ok := c.Get(key); ok {
+			t.Errorf("%s is still cached", key)
+		}
+	}
+	if _, ok := c.Get("tenant-2/cars"); !ok {
+		t.Error("tenant-2/cars was removed")
+	}
+}
+
+func TestLRUSetIfGeneration(t *testing.T) {
+	c := NewLRU(10, time.Minute)
+	generation := c.Generation()
+	// An invalidation between reading the generation and storing the loaded
+	// value means the value may be stale.
+	c.Remove("a")
+	if c.SetIfGeneration("a", 1, generation) {
+		t.Error("SetIfGeneration stored a value loaded before an invalidation")
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Error("a is cached")
+	}
+	if !c.SetIfGeneration("a", 1, c.Generation()) {
+		t.Error("SetIfGeneration refused the current generation")
+	}
+}
@@ -0,0 +1,215 @@
+// Package config loads and validates the service configuration.
+//
+// Values are resolved in this order, later sources overriding earlier ones:
+// built-in defaults, the YAML file named by CONFIG_FILE (optional), a .env
+// file in the working directory (optional), and finally the process
+// environment. Every environment variable can instead be supplied as a file
+// through the Docker-style KEY_FILE convention, e.g. DB_PASSWORD_FILE.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Server      Server      `yaml:"server"`
+	Database    Database    `yaml:"database"`
+	Auth        Auth        `yaml:"auth"`
+	Tracing     Tracing     `yaml:"tracing"`
+	Cache       Cache       `yaml:"cache"`
+	Attachments Attachments `yaml:"attachments"`
+	Similarity  Similarity  `yaml:"similarity"`
+}
+
+type Server struct {
+	Port              int           `yaml:"port"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	// ShutdownTimeout bounds the whole graceful shutdown: draining requests,
+	// stopping background workers, flushing traces and closing the pool.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// ShutdownDelay is how long /readyz reports shutting down before the
+	// listener closes, so load balancers can stop routing to the instance.
+	ShutdownDelay time.Duration `yaml:"shutdown_delay"`
+}
+
+type Database struct {
+	// Backend is "sql" for a database/sql store or "memory" for the
+	// in-process store.
+	Backend string `yaml:"backend"`
+	// Driver is "postgres" or "sqlite" when Backend is "sql".
+	Driver   string `yaml:"driver"`
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Name     string `yaml:"name"`
+	// DSN, when set, is used for the primary instead of the fields above.
+	DSN        string `yaml:"dsn"`
+	SQLitePath string `yaml:"sqlite_path"`
+
+	// ConnectTimeout is the longest startup waits for the database to accept
+	// connections, retrying with exponential backoff between
+	// ConnectBackoff and ConnectMaxBackoff.
+	ConnectTimeout    time.Duration `yaml:"connect_timeout"`
+	ConnectBackoff    time.Duration `yaml:"connect_backoff"`
+	ConnectMaxBackoff time.Duration `yaml:"connect_max_backoff"`
+
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+
+	ReplicaDSNs          []string      `yaml:"replica_dsns"`
+	ReplicaCheckInterval time.Duration `yaml:"replica_check_interval"`
+	ReplicaMaxLag        time.Duration `yaml:"replica_max_lag"`
+	// ReadYourWritesWindow is how long a client reads from the primary after
+	// a write. A replica in rotation was at most ReplicaMaxLag behind when
+	// last checked, up to ReplicaCheckInterval ago, so the window should
+	// cover both.
+	ReadYourWritesWindow time.Duration `yaml:"read_your_writes_window"`
+}
+
+type Auth struct {
+	JWTSecret string        `yaml:"jwt_secret"`
+	TokenTTL  time.Duration `yaml:"token_ttl"`
+	// AdminUsername and AdminPassword bootstrap an admin of the default
+	// tenant at startup, unless a user with that name already exists. No
+	// admin is created while AdminPassword is empty.
+	AdminUsername string `yaml:"admin_username"`
+	AdminPassword string `yaml:"admin_password"`
+}
+
+type Tracing struct {
+	// Endpoint is the host:port of the OTLP/HTTP trace collector.
+	Endpoint string `yaml:"endpoint"`
+}
+
+type Cache struct {
+	// Size is the maximum number of cached entries; 0 disables caching.
+	Size int           `yaml:"size"`
+	TTL  time.Duration `yaml:"ttl"`
+}
+
+// Attachments configures the photos and documents uploaded for cars, which
+// need the sql backend.
+type Attachments struct {
+	// Dir is where uploaded files and their thumbnails are kept.
+	Dir string `yaml:"dir"`
+	// MaxBytes is the largest file that can be uploaded.
+	MaxBytes int `yaml:"max_bytes"`
+}
+
+// Similarity holds the default weights GET /cars/{id}/similar scores cars
+// with; requests may override them. A weight of 0 ignores the attribute.
+type Similarity struct {
+	Price    float64 `yaml:"price"`
+	Year     float64 `yaml:"year"`
+	FuelType float64 `yaml:"fuel_type"`
+	Brand    float64 `yaml:"brand"`
+	Engine   float64 `yaml:"engine"`
+}
+
+func Default() Config {
+	return Config{
+		Server: Server{
+			Port:              8080,
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			ShutdownTimeout:   20 * time.Second,
+		},
+		Database: Database{
+			Backend:              "sql",
+			Driver:               "postgres",
+			Port:                 5432,
+			SQLitePath:           "car-management.db",
+			ConnectTimeout:       60 * time.Second,
+			ConnectBackoff:       500 * time.Millisecond,
+			ConnectMaxBackoff:    10 * time.Second,
+			MaxOpenConns:         25,
+			MaxIdleConns:         25,
+			ConnMaxLifetime:      30 * time.Minute,
+			ConnMaxIdleTime:      5 * time.Minute,
+			ReplicaCheckInterval: 10 * time.Second,
+			ReplicaMaxLag:        5 * time.Second,
+			ReadYourWritesWindow: 15 * time.Second,
+		},
+		Auth: Auth{
+			TokenTTL:      24 * time.Hour,
+			AdminUsername: "admin",
+		},
+		Tracing: Tracing{
+			Endpoint: "jaeger:4318",
+		},
+		Cache: Cache{
+			Size: 1024,
+			TTL:  30 * time.Second,
+		},
+		Attachments: Attachments{
+			Dir:      "attachments",
+			MaxBytes: 10 << 20,
+		},
+		Similarity: Similarity{
+			Price:    3,
+			Year:     1,
+			FuelType: 2,
+			Brand:    1,
+			Engine:   2,
+		},
+	}
+}
+
+// Load builds the configuration from every source and validates it. The
+// returned error lists every problem found, not just the first.
+func Load() (Config, error) {
+	cfg := Default()
+
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return cfg, fmt.Errorf("loading .env: %w", err)
+	}
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := loadFile(path, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+
+	if err := loadEnv(&cfg); err != nil {
+		return cfg, err
+	}
+
+	// STORE_BACKEND=postgres predates DB_DRIVER and still selects Postgres.
+	if cfg.Database.Backend == "postgres" {
+		log.Println("STORE_BACKEND=postgres is deprecated; use STORE_BACKEND=sql with DB_DRIVER=postgres")
+		cfg.Database.Backend = "sql"
+		cfg.Database.Driver = "postgres"
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	return nil
+}
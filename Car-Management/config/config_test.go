@@ -0,0 +1,162 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// isolate runs the test in an empty directory, so that no .env file is
+// picked up, with the required settings in the environment.
+func isolate(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("JWT_SECRET", "secret")
+	t.Setenv("DB_HOST", "db")
+	t.Setenv("DB_USER", "postgres")
+	t.Setenv("DB_NAME", "cars")
+	return dir
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	isolate(t)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Port != 8080 || cfg.Database.Driver != "postgres" || cfg.Cache.TTL != 30*time.Second {
+		t.Errorf("Load = %+v, want the defaults", cfg)
+	}
+}
+
+func TestLoadLayersSources(t *testing.T) {
+	dir := isolate(t)
+	t.Setenv("CONFIG_FILE", writeFile(t, dir, "config.yaml", `
+server:
+  port: 9000
+cache:
+  size: 10
+  ttl: 1m
+`))
+	writeFile(t, dir, ".env", "CACHE_SIZE=20\n")
+	t.Setenv("PORT", "9100")
+	t.Setenv("DB_PASSWORD_FILE", writeFile(t, dir, "password", "s3cret\n"))
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Port != 9100 {
+		t.Errorf("port = %d, want 9100 from the environment over the file", cfg.Server.Port)
+	}
+	if cfg.Cache.Size != 20 {
+		t.Errorf("cache size = %d, want 20 from .env over the file", cfg.Cache.Size)
+	}
+	if cfg.Cache.TTL != time.Minute {
+		t.Errorf("cache ttl = %s, want 1m from the file", cfg.Cache.TTL)
+	}
+	if cfg.Database.Password != "s3cret" {
+		t.Errorf("password = %q, want it read from DB_PASSWORD_FILE without the newline", cfg.Database.Password)
+	}
+}
+
+func TestLoadAcceptsPostgresBackend(t *testing.T) {
+	isolate(t)
+	t.Setenv("STORE_BACKEND", "postgres")
+	t.Setenv("DB_DRIVER", "sqlite")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Database.Backend != "sql" || cfg.Database.Driver != "postgres" {
+		t.Errorf("backend = %q, driver = %q, want sql on postgres", cfg.Database.Backend, cfg.Database.Driver)
+	}
+}
+
+func TestLoadRejectsKeyAndFile(t *testing.T) {
+	dir := isolate(t)
+	t.Setenv("DB_PASSWORD", "one")
+	t.Setenv("DB_PASSWORD_FILE", writeFile(t, dir, "password", "two"))
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "DB_PASSWORD and DB_PASSWORD_FILE are both set") {
+		t.Errorf("Load error = %v, want both set", err)
+	}
+}
+
+func TestLoadReportsEveryProblem(t *testing.T) {
+	isolate(t)
+	t.Setenv("PORT", "0")
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("CACHE_SIZE", "-1")
+	t.Setenv("SIMILAR_WEIGHT_PRICE", "-2")
+
+	_, err := Load()
+	if err == nil {
+		t.Fatal("Load succeeded, want an error")
+	}
+	for _, want := range []string{"PORT must be between", "JWT_SECRET is required", "CACHE_SIZE must not be negative", "SIMILAR_WEIGHT_PRICE must be a number of at least 0"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Load error does not mention %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestLoadRejectsMalformedValues(t *testing.T) {
+	isolate(t)
+	t.Setenv("PORT", "eighty")
+	t.Setenv("CACHE_TTL", "soon")
+	_, err := Load()
+	if err == nil {
+		t.Fatal("Load succeeded, want an error")
+	}
+	for _, want := range []string{`PORT must be an integer, got "eighty"`, `CACHE_TTL must be a duration`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Load error does not mention %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestValidateBackends(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		want   string
+	}{
+		{"memory needs no database", func(c *Config) { c.Database.Backend = "memory"; c.Database.Host = "" }, ""},
+		{"sqlite needs a path", func(c *Config) { c.Database.Driver = "sqlite"; c.Database.SQLitePath = "" }, "SQLITE_PATH is required"},
+		{"sqlite has no replicas", func(c *Config) { c.Database.Driver = "sqlite"; c.Database.ReplicaDSNs = []string{"x"} }, "DB_REPLICA_DSNS is only supported"},
+		{"unknown driver", func(c *Config) { c.Database.Driver = "mysql" }, "DB_DRIVER must be postgres or sqlite"},
+		{"unknown backend", func(c *Config) { c.Database.Backend = "redis" }, "STORE_BACKEND must be sql or memory"},
+		{"short admin password", func(c *Config) { c.Auth.AdminPassword = "short" }, "ADMIN_PASSWORD must be at least 8 characters"},
+		{"negative read-your-writes window", func(c *Config) {
+			c.Database.ReplicaDSNs = []string{"x"}
+			c.Database.ReadYourWritesWindow = -time.Second
+		}, "DB_READ_YOUR_WRITES_WINDOW must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Auth.JWTSecret = "secret"
+			cfg.Database.Host, cfg.Database.User, cfg.Database.Name = "db", "postgres", "cars"
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("Validate: %v", err)
+			case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+				t.Errorf("Validate error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// loadEnv overrides cfg with every variable that is set in the environment.
+func loadEnv(cfg *Config) error {
+	e := &envLoader{}
+
+	e.int("PORT", &cfg.Server.Port)
+	e.duration("HTTP_READ_TIMEOUT", &cfg.Server.ReadTimeout)
+	e.duration("HTTP_READ_HEADER_TIMEOUT", &cfg.Server.ReadHeaderTimeout)
+	e.duration("HTTP_WRITE_TIMEOUT", &cfg.Server.WriteTimeout)
+	e.duration("HTTP_IDLE_TIMEOUT", &cfg.Server.IdleTimeout)
+	e.duration("SHUTDOWN_TIMEOUT", &cfg.Server.ShutdownTimeout)
+	e.duration("SHUTDOWN_DELAY", &cfg.Server.ShutdownDelay)
+
+	e.string("STORE_BACKEND", &cfg.Database.Backend)
+	e.string("DB_DRIVER", &cfg.Database.Driver)
+	e.string("DB_HOST", &cfg.Database.Host)
+	e.int("DB_PORT", &cfg.Database.Port)
+	e.string("DB_USER", &cfg.Database.User)
+	e.string("DB_PASSWORD", &cfg.Database.Password)
+	e.string("DB_NAME", &cfg.Database.Name)
+	e.string("DB_DSN", &cfg.Database.DSN)
+	e.string("SQLITE_PATH", &cfg.Database.SQLitePath)
+	e.duration("DB_CONNECT_TIMEOUT", &cfg.Database.ConnectTimeout)
+	e.duration("DB_CONNECT_BACKOFF", &cfg.Database.ConnectBackoff)
+	e.duration("DB_CONNECT_MAX_BACKOFF", &cfg.Database.ConnectMaxBackoff)
+	e.int("DB_MAX_OPEN_CONNS", &cfg.Database.MaxOpenConns)
+	e.int("DB_MAX_IDLE_CONNS", &cfg.Database.MaxIdleConns)
+	e.duration("DB_CONN_MAX_LIFETIME", &cfg.Database.ConnMaxLifetime)
+	e.duration("DB_CONN_MAX_IDLE_TIME", &cfg.Database.ConnMaxIdleTime)
+	e.list("DB_REPLICA_DSNS", &cfg.Database.ReplicaDSNs)
+	e.duration("DB_REPLICA_CHECK_INTERVAL", &cfg.Database.ReplicaCheckInterval)
+	e.duration("DB_REPLICA_MAX_LAG", &cfg.Database.ReplicaMaxLag)
+	e.duration("DB_READ_YOUR_WRITES_WINDOW", &cfg.Database.ReadYourWritesWindow)
+
+	e.string("JWT_SECRET", &cfg.Auth.JWTSecret)
+	e.duration("JWT_TTL", &cfg.Auth.TokenTTL)
+	e.string("ADMIN_USERNAME", &cfg.Auth.AdminUsername)
+	e.string("ADMIN_PASSWORD", &cfg.Auth.AdminPassword)
+
+	e.string("TRACING_ENDPOINT", &cfg.Tracing.Endpoint)
+
+	e.int("CACHE_SIZE", &cfg.Cache.Size)
+	e.duration("CACHE_TTL", &cfg.Cache.TTL)
+
+	e.string("ATTACHMENT_DIR", &cfg.Attachments.Dir)
+	e.int("ATTACHMENT_MAX_BYTES", &cfg.Attachments.MaxBytes)
+
+	e.float("SIMILAR_WEIGHT_PRICE", &cfg.Similarity.Price)
+	e.float("SIMILAR_WEIGHT_YEAR", &cfg.Similarity.Year)
+	e.float("SIMILAR_WEIGHT_FUEL_TYPE", &cfg.Similarity.FuelType)
+	e.float("SIMILAR_WEIGHT_BRAND", &cfg.Similarity.Brand)
+	e.float("SIMILAR_WEIGHT_ENGINE", &cfg.Similarity.Engine)
+
+	return errors.Join(e.errs...)
+}
+
+type envLoader struct {
+	errs []error
+}
+
+// lookup returns the value of key, reading it from the file named by
+// key_FILE when that is set instead. Setting both is an error.
+func (e *envLoader) lookup(key string) (string, bool) {
+	value, ok := os.LookupEnv(key)
+	path, fromFile := os.LookupEnv(key + "_FILE")
+	if !fromFile {
+		return value, ok
+	}
+	if ok {
+		e.errs = append(e.errs, fmt.Errorf("%s and %s_FILE are both set", key, key))
+		return "", false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s_FILE: %w", key, err))
+		return "", false
+	}
+	return strings.TrimRight(string(data), "\r\n"), true
+}
+
+func (e *envLoader) string(key string, dst *string) {
+	if v, ok := e.lookup(key); ok {
+		*dst = v
+	}
+}
+
+func (e *envLoader) int(key string, dst *int) {
+	v, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s must be an integer, got %q", key, v))
+		return
+	}
+	*dst = n
+}
+
+func (e *envLoader) float(key string, dst *float64) {
+	v, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s must be a number, got %q", key, v))
+		return
+	}
+	*dst = f
+}
+
+func (e *envLoader) duration(key string, dst *time.Duration) {
+	v, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s must be a duration such as 30s, got %q", key, v))
+		return
+	}
+	*dst = d
+}
+
+// list reads a comma-separated value, ignoring empty items.
+func (e *envLoader) list(key string, dst *[]string) {
+	v, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*dst = items
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Validate checks the configuration and returns every problem it finds,
+// joined into a single error.
+func (c Config) Validate() error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		add("PORT must be between 1 and 65535, got %d", c.Server.Port)
+	}
+	for _, timeout := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"HTTP_READ_TIMEOUT", c.Server.ReadTimeout},
+		{"HTTP_READ_HEADER_TIMEOUT", c.Server.ReadHeaderTimeout},
+		{"HTTP_WRITE_TIMEOUT", c.Server.WriteTimeout},
+		{"HTTP_IDLE_TIMEOUT", c.Server.IdleTimeout},
+		{"SHUTDOWN_TIMEOUT", c.Server.ShutdownTimeout},
+	} {
+		if timeout.value <= 0 {
+			add("%s must be positive", timeout.name)
+		}
+	}
+	if c.Server.ShutdownDelay < 0 {
+		add("SHUTDOWN_DELAY must not be negative")
+	}
+
+	db := c.Database
+	switch db.Backend {
+	case "memory":
+	case "sql":
+		switch db.Driver {
+		case "postgres":
+			if db.DSN == "" {
+				if db.Host == "" {
+					add("DB_HOST is required when DB_DSN is not set")
+				}
+				if db.User == "" {
+					add("DB_USER is required when DB_DSN is not set")
+				}
+				if db.Name == "" {
+					add("DB_NAME is required when DB_DSN is not set")
+				}
+				if db.Port < 1 || db.Port > 65535 {
+					add("DB_PORT must be between 1 and 65535, got %d", db.Port)
+				}
+			}
+		case "sqlite":
+			if db.SQLitePath == "" {
+				add("SQLITE_PATH is required when DB_DRIVER is sqlite")
+			}
+			if len(db.ReplicaDSNs) > 0 {
+				add("DB_REPLICA_DSNS is only supported with DB_DRIVER postgres")
+			}
+		default:
+			add("DB_DRIVER must be postgres or sqlite, got %q", db.Driver)
+		}
+		if db.ConnectTimeout <= 0 {
+			add("DB_CONNECT_TIMEOUT must be positive")
+		}
+		if db.ConnectBackoff <= 0 {
+			add("DB_CONNECT_BACKOFF must be positive")
+		}
+		if db.ConnectMaxBackoff < db.ConnectBackoff {
+			add("DB_CONNECT_MAX_BACKOFF must be at least DB_CONNECT_BACKOFF")
+		}
+		if db.MaxOpenConns < 1 {
+			add("DB_MAX_OPEN_CONNS must be at least 1")
+		}
+		if db.MaxIdleConns < 0 || db.MaxIdleConns > db.MaxOpenConns {
+			add("DB_MAX_IDLE_CONNS must be between 0 and DB_MAX_OPEN_CONNS")
+		}
+		if db.ConnMaxLifetime < 0 {
+			add("DB_CONN_MAX_LIFETIME must not be negative")
+		}
+		if db.ConnMaxIdleTime < 0 {
+			add("DB_CONN_MAX_IDLE_TIME must not be negative")
+		}
+		if c.Attachments.Dir == "" {
+			add("ATTACHMENT_DIR is required when STORE_BACKEND is sql")
+		}
+		if len(db.ReplicaDSNs) > 0 {
+			if db.ReplicaCheckInterval <= 0 {
+				add("DB_REPLICA_CHECK_INTERVAL must be positive")
+			}
+			if db.ReplicaMaxLag < 0 {
+				add("DB_REPLICA_MAX_LAG must not be negative")
+			}
+			if db.ReadYourWritesWindow < 0 {
+				add("DB_READ_YOUR_WRITES_WINDOW must not be negative")
+			}
+		}
+	default:
+		add("STORE_BACKEND must be sql or memory, got %q", db.Backend)
+	}
+
+	if c.Auth.JWTSecret == "" {
+		add("JWT_SECRET is required")
+	}
+	if c.Auth.TokenTTL <= 0 {
+		add("JWT_TTL must be positive")
+	}
+	if c.Auth.AdminPassword != "" {
+		if strings.TrimSpace(c.Auth.AdminUsername) == "" {
+			add("ADMIN_USERNAME is required when ADMIN_PASSWORD is set")
+		}
+		if len(c.Auth.AdminPassword) < 8 {
+			add("ADMIN_PASSWORD must be at least 8 characters")
+		}
+	}
+
+	if c.Tracing.Endpoint == "" {
+		add("TRACING_ENDPOINT is required")
+	}
+
+	if c.Cache.Size < 0 {
+		add("CACHE_SIZE must not be negative")
+	}
+	if c.Cache.Size > 0 && c.Cache.TTL <= 0 {
+		add("CACHE_TTL must be positive when caching is enabled")
+	}
+
+	if c.Attachments.MaxBytes < 1 {
+		add("ATTACHMENT_MAX_BYTES must be at least 1")
+	}
+
+	var total float64
+	for _, weight := range []struct {
+		name  string
+		value float64
+	}{
+		{"SIMILAR_WEIGHT_PRICE", c.Similarity.Price},
+		{"SIMILAR_WEIGHT_YEAR", c.Similarity.Year},
+		{"SIMILAR_WEIGHT_FUEL_TYPE", c.Similarity.FuelType},
+		{"SIMILAR_WEIGHT_BRAND", c.Similarity.Brand},
+		{"SIMILAR_WEIGHT_ENGINE", c.Similarity.Engine},
+	} {
+		if weight.value < 0 || math.IsNaN(weight.value) || math.IsInf(weight.value, 0) {
+			add("%s must be a number of at least 0", weight.name)
+		}
+		total += weight.value
+	}
+	if total <= 0 {
+		add("at least one SIMILAR_WEIGHT_* must be above 0")
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+}
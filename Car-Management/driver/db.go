@@ -0,0 +1,134 @@
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+)
+
+// DB wraps the primary *sql.DB, any read replicas and the Dialect. Every
+// query passed through it is rebound for the dialect, so stores can share one
+// set of SQL statements across Postgres and SQLite.
+//
+// Reads (QueryContext, QueryRowContext) are spread across healthy replicas
+// unless the context asks for the primary; writes and transactions always go
+// to the primary. On Postgres every statement, wherever it runs, is scoped to
+// the tenant in its context for the row-level security policies.
+type DB struct {
+	db       *sql.DB
+	dialect  Dialect
+	replicas []*replica
+	next     atomic.Uint64
+
+	stopChecks context.CancelFunc
+	checksDone sync.WaitGroup
+}
+
+func NewDB(db *sql.DB, dialect Dialect) *DB {
+	return &DB{db: db, dialect: dialect}
+}
+
+func (d *DB) Dialect() Dialect {
+	return d.dialect
+}
+
+// SQL exposes the primary pool for callers that need it directly, such as
+// pool statistics. Queries should go through DB so they are rebound.
+func (d *DB) SQL() *sql.DB {
+	return d.db
+}
+
+func (d *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return d.reader(ctx).QueryContext(ctx, d.dialect.Rebind(query), args...)
+}
+
+func (d *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return d.reader(ctx).QueryRowContext(ctx, d.dialect.Rebind(query), args...)
+}
+
+func (d *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	markWrite(ctx)
+	return d.db.ExecContext(ctx, d.dialect.Rebind(query), args...)
+}
+
+// BeginTx always starts the transaction on the primary, and counts as a write
+// for read-your-writes purposes.
+func (d *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	markWrite(ctx)
+	tx, err := d.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{tx: tx, dialect: d.dialect}, nil
+}
+
+func (d *DB) PingContext(ctx context.Context) error {
+	return d.db.PingContext(ctx)
+}
+
+// StopReplicaChecks stops the replica health checks and waits for the
+// running check to finish, or for ctx to expire.
+func (d *DB) StopReplicaChecks(ctx context.Context) error {
+	if d.stopChecks == nil {
+		return nil
+	}
+	d.stopChecks()
+
+	done := make(chan struct{})
+	go func() {
+		d.checksDone.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops the replica health checks and closes every pool.
+func (d *DB) Close() error {
+	if d.stopChecks != nil {
+		d.stopChecks()
+		d.checksDone.Wait()
+	}
+	err := d.db.Close()
+	for _, r := range d.replicas {
+		if cerr := r.db.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// Tx is a transaction whose queries are rebound like those on DB.
+type Tx struct {
+	tx      *sql.Tx
+	dialect Dialect
+}
+
+func (t *Tx) Dialect() Dialect {
+	return t.dialect
+}
+
+func (t *Tx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return t.tx.QueryContext(ctx, t.dialect.Rebind(query), args...)
+}
+
+func (t *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return t.tx.QueryRowContext(ctx, t.dialect.Rebind(query), args...)
+}
+
+func (t *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return t.tx.ExecContext(ctx, t.dialect.Rebind(query), args...)
+}
+
+func (t *Tx) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t *Tx) Rollback() error {
+	return t.tx.Rollback()
+}
@@ -0,0 +1,67 @@
+package driver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect identifies the SQL flavour spoken by the configured database.
+// Stores write their queries once, using Postgres-style $N placeholders, and
+// the dialect rewrites them for the target database.
+type Dialect string
+
+const (
+	Postgres Dialect = "postgres"
+	SQLite   Dialect = "sqlite"
+)
+
+func ParseDialect(name string) (Dialect, error) {
+	switch strings.ToLower(name) {
+	case "", "postgres", "postgresql":
+		return Postgres, nil
+	case "sqlite", "sqlite3":
+		return SQLite, nil
+	default:
+		return "", fmt.Errorf("unsupported DB_DRIVER %q (want postgres or sqlite)", name)
+	}
+}
+
+// DriverName is the database/sql driver registered for the dialect.
+func (d Dialect) DriverName() string {
+	if d == SQLite {
+		return "sqlite3"
+	}
+	return "postgres"
+}
+
+// Rebind rewrites $N placeholders into the dialect's native form. SQLite's
+// ?N placeholders are numbered as well, so queries that reuse or reorder
+// arguments keep working. Placeholders inside quoted literals are untouched.
+func (d Dialect) Rebind(query string) string {
+	if d != SQLite || !strings.Contains(query, "$") {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query))
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '$' && i+1 < len(query) && isDigit(query[i+1]):
+			c = '?'
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
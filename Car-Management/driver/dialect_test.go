@@ -0,0 +1,47 @@
+package driver
+
+import "testing"
+
+func TestParseDialect(t *testing.T) {
+	tests := []struct {
+		name string
+		want Dialect
+	}{
+		{"", Postgres},
+		{"postgres", Postgres},
+		{"PostgreSQL", Postgres},
+		{"sqlite", SQLite},
+		{"SQLite3", SQLite},
+	}
+	for _, tt := range tests {
+		got, err := ParseDialect(tt.name)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDialect(%q) = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+	if _, err := ParseDialect("mysql"); err == nil {
+		t.Error("ParseDialect(mysql) succeeded, want an error")
+	}
+}
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`SELECT * FROM car WHERE id=$1 AND tenant_id=$2`, `SELECT * FROM car WHERE id=?1 AND tenant_id=?2`},
+		// Reused and reordered placeholders keep their numbers.
+		{`UPDATE car SET name=$2 WHERE id=$1 OR parent=$1`, `UPDATE car SET name=?2 WHERE id=?1 OR parent=?1`},
+		{`SELECT 'costs $5', "col$1" FROM car WHERE id=$1`, `SELECT 'costs $5', "col$1" FROM car WHERE id=?1`},
+		{`SELECT price$ FROM car`, `SELECT price$ FROM car`},
+		{`SELECT 1`, `SELECT 1`},
+	}
+	for _, tt := range tests {
+		if got := SQLite.Rebind(tt.query); got != tt.want {
+			t.Errorf("SQLite.Rebind(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+		if got := Postgres.Rebind(tt.query); got != tt.query {
+			t.Errorf("Postgres.Rebind(%q) = %q, want it unchanged", tt.query, got)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/nitesh111sinha/car-management/config"
+)
+
+// InitDB opens the primary pool and waits for the database to accept
+// connections, retrying with exponential backoff for up to
+// cfg.ConnectTimeout. Replica pools are opened without waiting; the replica
+// health checks keep unreachable replicas out of rotation.
+func InitDB(ctx context.Context, cfg config.Database) (*DB, error) {
+	dialect, err := ParseDialect(cfg.Driver)
+	if err != nil {
+		return nil, err
+	}
+
+	var connStr string
+	switch dialect {
+	case SQLite:
+		connStr = sqliteDSN(cfg)
+	default:
+		connStr = postgresDSN(cfg)
+	}
+
+	sqlDB, err := openPool(dialect, connStr, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := waitForDB(ctx, sqlDB, cfg); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	log.Println("Successfully connected to the", dialect, "database")
+
+	db := NewDB(sqlDB, dialect)
+	for i, dsn := range cfg.ReplicaDSNs {
+		name := "replica-" + strconv.Itoa(i+1)
+		replicaDB, err := openPool(dialect, dsn, cfg)
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("opening %s: %w", name, err)
+		}
+		db.AddReplica(name, replicaDB)
+		log.Println("Registered read", name)
+	}
+	return db, nil
+}
+
+// openPool opens a pool without connecting. Postgres pools scope every
+// connection to the tenant of each statement's context, for the row-level
+// security policies.
+func openPool(dialect Dialect, dsn string, cfg config.Database) (*sql.DB, error) {
+	var sqlDB *sql.DB
+	switch dialect {
+	case Postgres:
+		connector, err := pq.NewConnector(dsn)
+		if err != nil {
+			return nil, err
+		}
+		sqlDB = sql.OpenDB(tenantConnector{connector})
+	default:
+		var err error
+		sqlDB, err = sql.Open(dialect.DriverName(), dsn)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	return sqlDB, nil
+}
+
+// waitForDB pings until the database answers, doubling the delay between
+// attempts from cfg.ConnectBackoff up to cfg.ConnectMaxBackoff. Delays are
+// jittered so that many instances restarting together don't retry in lockstep.
+func waitForDB(ctx context.Context, sqlDB *sql.DB, cfg config.Database) error {
+	ctx, cancel := context.WithTimeout(ctx, cfg.ConnectTimeout)
+	defer cancel()
+
+	backoff := cfg.ConnectBackoff
+	for attempt := 1; ; attempt++ {
+		err := sqlDB.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+
+		wait := backoff/2 + rand.N(backoff/2+1)
+		log.Printf("Database not ready (attempt %d): %v; retrying in %s", attempt, err, wait.Round(time.Millisecond))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("database not reachable after %d attempts within %s: %w", attempt, cfg.ConnectTimeout, err)
+		case <-timer.C:
+		}
+
+		backoff = min(backoff*2, cfg.ConnectMaxBackoff)
+	}
+}
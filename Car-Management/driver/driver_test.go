@@ -0,0 +1,102 @@
+package driver
+
+import (
+	"context"
+	"database/sql"
+	sqldriver "database/sql/driver"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nitesh111sinha/car-management/config"
+)
+
+var errRefused = errors.New("connection refused")
+
+// flakyConnector refuses the first failures connections, standing in for a
+// database that is still starting.
+type flakyConnector struct {
+	failures int
+	attempts int
+}
+
+func (c *flakyConnector) Connect(context.Context) (sqldriver.Conn, error) {
+	c.attempts++
+	if c.attempts <= c.failures {
+		return nil, errRefused
+	}
+	return flakyConn{}, nil
+}
+
+func (c *flakyConnector) Driver() sqldriver.Driver { return nil }
+
+type flakyConn struct{}
+
+func (flakyConn) Prepare(string) (sqldriver.Stmt, error) { return nil, errors.ErrUnsupported }
+func (flakyConn) Close() error                           { return nil }
+func (flakyConn) Begin() (sqldriver.Tx, error)           { return nil, errors.ErrUnsupported }
+
+func retryConfig(timeout time.Duration) config.Database {
+	return config.Database{
+		ConnectTimeout:    timeout,
+		ConnectBackoff:    time.Millisecond,
+		ConnectMaxBackoff: 4 * time.Millisecond,
+	}
+}
+
+func TestWaitForDBRetriesUntilReachable(t *testing.T) {
+	connector := &flakyConnector{failures: 3}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	if err := waitForDB(context.Background(), db, retryConfig(time.Second)); err != nil {
+		t.Fatalf("waitForDB: %v", err)
+	}
+	if connector.attempts != 4 {
+		t.Errorf("connected after %d attempts, want 4", connector.attempts)
+	}
+}
+
+func TestWaitForDBGivesUpAfterTimeout(t *testing.T) {
+	connector := &flakyConnector{failures: 1 << 30}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	start := time.Now()
+	err := waitForDB(context.Background(), db, retryConfig(50*time.Millisecond))
+	if err == nil || !strings.Contains(err.Error(), "database not reachable") {
+		t.Fatalf("waitForDB = %v, want a not reachable error", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("gave up after %s, want about the 50ms connect timeout", elapsed)
+	}
+	if connector.attempts < 2 {
+		t.Errorf("made %d attempts, want retries", connector.attempts)
+	}
+}
+
+func TestInitDBSQLite(t *testing.T) {
+	cfg := retryConfig(time.Second)
+	cfg.Driver = "sqlite"
+	cfg.SQLitePath = filepath.Join(t.TempDir(), "car.db")
+	cfg.MaxOpenConns = 2
+
+	db, err := InitDB(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	defer db.Close()
+	if db.Dialect() != SQLite {
+		t.Errorf("dialect = %v, want sqlite", db.Dialect())
+	}
+}
+
+func TestInitDBRejectsUnknownDriver(t *testing.T) {
+	cfg := retryConfig(time.Second)
+	cfg.Driver = "oracle"
+	if _, err := InitDB(context.Background(), cfg); err == nil {
+		t.Error("InitDB accepted an unknown driver")
+	}
+}
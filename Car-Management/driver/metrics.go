@@ -0,0 +1,17 @@
+package driver
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// Collectors returns Prometheus collectors exposing connection pool
+// statistics (open, in-use and idle connections, waits, closures) for the
+// primary and every replica, labelled db_name="primary", "replica-1", ...
+func (d *DB) Collectors() []prometheus.Collector {
+	cs := []prometheus.Collector{collectors.NewDBStatsCollector(d.db, "primary")}
+	for _, r := range d.replicas {
+		cs = append(cs, collectors.NewDBStatsCollector(r.db, r.name))
+	}
+	return cs
+}
@@ -1,50 +1,31 @@
 package driver
 
 import (
-	"database/sql"
+	"errors"
 	"fmt"
-	"os"
-	"time"
 
-	_ "github.com/lib/pq" // PostgreSQL driver
-)
-
-var db *sql.DB
+	"github.com/lib/pq" // PostgreSQL driver
 
-func InitDB() {
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_PORT"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"))
-
-	fmt.Println("Waiting to connect to the database")
-	time.Sleep(5 * time.Second)
+	"github.com/nitesh111sinha/car-management/config"
+)
 
-	var err error
-	db, err = sql.Open("postgres", connStr)
-	defer func() {
-		err := recover()
-		if err != nil {
-			fmt.Println("Error connecting to the database", err)
-		}
-	}()
-	if err != nil {
-		panic(err)
+// postgresDSN returns cfg.DSN when set, otherwise a DSN assembled from the
+// individual connection settings.
+func postgresDSN(cfg config.Database) string {
+	if cfg.DSN != "" {
+		return cfg.DSN
 	}
-	if err := db.Ping(); err != nil {
-		panic(err)
-	}
-	fmt.Println("Successfully connected to the database")
-}
-
-func GetDB() *sql.DB {
-	return db
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
+		cfg.Host,
+		cfg.Port,
+		cfg.User,
+		cfg.Password,
+		cfg.Name)
 }
 
-func CloseDB() {
-	if err := db.Close(); err != nil {
-		fmt.Println("Error closing the database connection", err)
-	}
+// IsExclusionViolation reports whether err is Postgres rejecting a row that
+// conflicts with another under an exclusion constraint.
+func IsExclusionViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23P01"
 }
@@ -0,0 +1,167 @@
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// replicaLagQuery reports how far a Postgres standby is behind, in seconds.
+// A standby that has replayed everything it received is not lagging even if
+// the primary has been idle for a while.
+const replicaLagQuery = `SELECT CASE
+    WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+    ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+END`
+
+type replica struct {
+	name    string
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+// AddReplica registers a read replica. Replicas start out healthy and are
+// taken out of rotation by the health checks when they fail or fall behind.
+func (d *DB) AddReplica(name string, db *sql.DB) {
+	r := &replica{name: name, db: db}
+	r.healthy.Store(true)
+	d.replicas = append(d.replicas, r)
+}
+
+// reader picks the pool for a read: the primary when the context requires
+// it, otherwise the next healthy replica in round-robin order, falling back
+// to the primary when none is available.
+func (d *DB) reader(ctx context.Context) *sql.DB {
+	if len(d.replicas) == 0 || UsesPrimary(ctx) {
+		return d.db
+	}
+	start := d.next.Add(1)
+	for i := range d.replicas {
+		r := d.replicas[(start+uint64(i))%uint64(len(d.replicas))]
+		if r.healthy.Load() {
+			return r.db
+		}
+	}
+	return d.db
+}
+
+// StartReplicaChecks pings every replica each interval and removes it from
+// rotation while it is unreachable or more than maxLag behind the primary.
+// The checks stop when ctx is cancelled or the DB is closed.
+func (d *DB) StartReplicaChecks(ctx context.Context, interval, maxLag time.Duration) {
+	if len(d.replicas) == 0 {
+		return
+	}
+	ctx, d.stopChecks = context.WithCancel(ctx)
+	d.checksDone.Add(1)
+	go func() {
+		defer d.checksDone.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			d.checkReplicas(ctx, interval, maxLag)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (d *DB) checkReplicas(ctx context.Context, timeout, maxLag time.Duration) {
+	for _, r := range d.replicas {
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := d.checkReplica(checkCtx, r, maxLag)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+
+		healthy := err == nil
+		if r.healthy.Swap(healthy) != healthy {
+			if healthy {
+				log.Println("Replica", r.name, "is back in rotation")
+			} else {
+				log.Println("Replica", r.name, "removed from rotation:", err)
+			}
+		}
+	}
+}
+
+func (d *DB) checkReplica(ctx context.Context, r *replica, maxLag time.Duration) error {
+	if err := r.db.PingContext(ctx); err != nil {
+		return err
+	}
+	if d.dialect != Postgres || maxLag <= 0 {
+		return nil
+	}
+
+	var lagSeconds float64
+	if err := r.db.QueryRowContext(ctx, replicaLagQuery).Scan(&lagSeconds); err != nil {
+		return err
+	}
+	if lag := time.Duration(lagSeconds * float64(time.Second)); lag > maxLag {
+		return &LagError{Lag: lag, MaxLag: maxLag}
+	}
+	return nil
+}
+
+type LagError struct {
+	Lag    time.Duration
+	MaxLag time.Duration
+}
+
+func (e *LagError) Error() string {
+	return "replication lag " + e.Lag.String() + " exceeds " + e.MaxLag.String()
+}
+
+type primaryKey struct{}
+
+// WithPrimary returns a context whose reads always go to the primary.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+type rywKey struct{}
+
+// rywState records whether a write has been made through a read-your-writes
+// context.
+type rywState struct {
+	wrote atomic.Bool
+}
+
+// WithReadYourWrites returns a context that tracks writes made through it.
+// Once any write or transaction has been issued with the context, later reads
+// with it go to the primary so they observe that write even if the replicas
+// have not caught up yet.
+func WithReadYourWrites(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(rywKey{}).(*rywState); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, rywKey{}, &rywState{})
+}
+
+// Wrote reports whether a write has been made through the read-your-writes
+// context ctx.
+func Wrote(ctx context.Context) bool {
+	state, ok := ctx.Value(rywKey{}).(*rywState)
+	return ok && state.wrote.Load()
+}
+
+func markWrite(ctx context.Context) {
+	if state, ok := ctx.Value(rywKey{}).(*rywState); ok {
+		state.wrote.Store(true)
+	}
+}
+
+// UsesPrimary reports whether reads made with ctx go to the primary: the
+// context asked for it, or a write has been made through it.
+func UsesPrimary(ctx context.Context) bool {
+	if primary, _ := ctx.Value(primaryKey{}).(bool); primary {
+		return true
+	}
+	return Wrote(ctx)
+}
@@ -0,0 +1,104 @@
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// openNamedPool opens a SQLite database holding a single row naming it, so
+// that reads show which pool served them.
+func openNamedPool(t *testing.T, name string) *sql.DB {
+	t.Helper()
+	db, err := sql.Open(SQLite.DriverName(), filepath.Join(t.TempDir(), name+".db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(`CREATE TABLE source (name TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO source (name) VALUES (?)`, name); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func servedBy(t *testing.T, ctx context.Context, db *DB) string {
+	t.Helper()
+	var name string
+	if err := db.QueryRowContext(ctx, `SELECT name FROM source`).Scan(&name); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func newReplicatedDB(t *testing.T) (*DB, *sql.DB) {
+	t.Helper()
+	db := NewDB(openNamedPool(t, "primary"), SQLite)
+	replica := openNamedPool(t, "replica")
+	db.AddReplica("replica", replica)
+	return db, replica
+}
+
+func TestReadsGoToReplicas(t *testing.T) {
+	db, _ := newReplicatedDB(t)
+	ctx := context.Background()
+	if got := servedBy(t, ctx, db); got != "replica" {
+		t.Errorf("read served by %s, want replica", got)
+	}
+	if got := servedBy(t, WithPrimary(ctx), db); got != "primary" {
+		t.Errorf("read with WithPrimary served by %s, want primary", got)
+	}
+}
+
+func TestReadYourWrites(t *testing.T) {
+	db, _ := newReplicatedDB(t)
+
+	ctx := WithReadYourWrites(context.Background())
+	if got := servedBy(t, ctx, db); got != "replica" {
+		t.Errorf("read before a write served by %s, want replica", got)
+	}
+	if _, err := db.ExecContext(ctx, `UPDATE source SET name = name`); err != nil {
+		t.Fatal(err)
+	}
+	if !Wrote(ctx) {
+		t.Error("Wrote = false after ExecContext")
+	}
+	if got := servedBy(t, ctx, db); got != "primary" {
+		t.Errorf("read after a write served by %s, want primary", got)
+	}
+
+	// A transaction counts as a write even before it runs a statement.
+	ctx = WithReadYourWrites(context.Background())
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.Rollback()
+	if got := servedBy(t, ctx, db); got != "primary" {
+		t.Errorf("read after a transaction served by %s, want primary", got)
+	}
+
+	// Writes without a read-your-writes context are not tracked.
+	ctx = context.Background()
+	if _, err := db.ExecContext(ctx, `UPDATE source SET name = name`); err != nil {
+		t.Fatal(err)
+	}
+	if Wrote(ctx) {
+		t.Error("Wrote = true without WithReadYourWrites")
+	}
+}
+
+func TestUnhealthyReplicasLeaveRotation(t *testing.T) {
+	db, replica := newReplicatedDB(t)
+	ctx := context.Background()
+
+	replica.Close()
+	db.checkReplicas(ctx, time.Second, 0)
+	if got := servedBy(t, ctx, db); got != "primary" {
+		t.Errorf("read with the only replica down served by %s, want primary", got)
+	}
+}
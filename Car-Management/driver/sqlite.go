@@ -0,0 +1,16 @@
+package driver
+
+import (
+	"fmt"
+
+	_ "github.com/mattn/go-sqlite3" // SQLite driver
+
+	"github.com/nitesh111sinha/car-management/config"
+)
+
+// sqliteDSN enables foreign keys (needed for ON DELETE CASCADE), WAL mode for
+// concurrent readers and immediate transactions so writers queue on the busy
+// timeout instead of failing with a lock upgrade error.
+func sqliteDSN(cfg config.Database) string {
+	return fmt.Sprintf("file:%s?_foreign_keys=on&_journal_mode=WAL&_busy_timeout=5000&_txlock=immediate", cfg.SQLitePath)
+}
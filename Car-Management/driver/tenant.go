@@ -0,0 +1,157 @@
+package driver
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/nitesh111sinha/car-management/auth"
+)
+
+// setTenantQuery scopes the session to a tenant for the row-level security
+// policies, which only let it see rows whose tenant_id matches. The empty
+// string matches nothing.
+const setTenantQuery = `SELECT set_config('app.tenant_id', $1, false)`
+
+// tenantConnector wraps a Postgres connector so that every statement, on the
+// primary and on replicas, in and out of transactions, runs with
+// app.tenant_id set to the tenant of its context, or to nothing when the
+// context has no tenant.
+type tenantConnector struct {
+	driver.Connector
+}
+
+// pgConn is what tenantConn needs from the wrapped connection; lib/pq
+// connections provide all of it.
+type pgConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+}
+
+func (c tenantConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pg, ok := conn.(pgConn)
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("driver connection %T cannot be scoped to tenants", conn)
+	}
+	return &tenantConn{pgConn: pg}, nil
+}
+
+// tenantConn remembers which tenant its session is scoped to, so that the
+// setting only costs a round trip when the next statement is for another
+// tenant.
+type tenantConn struct {
+	pgConn
+	tenant string
+	// scoped is false until the session has been scoped, and again after a
+	// rollback may have undone the setting.
+	scoped bool
+}
+
+func (c *tenantConn) scope(ctx context.Context) error {
+	var tenant string
+	if tenantID, err := auth.TenantFromContext(ctx); err == nil {
+		tenant = tenantID.String()
+	}
+	if c.scoped && c.tenant == tenant {
+		return nil
+	}
+	c.scoped = false
+	if _, err := c.pgConn.ExecContext(ctx, setTenantQuery, []driver.NamedValue{{Ordinal: 1, Value: tenant}}); err != nil {
+		return err
+	}
+	c.tenant, c.scoped = tenant, true
+	return nil
+}
+
+func (c *tenantConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := c.scope(ctx); err != nil {
+		return nil, err
+	}
+	return c.pgConn.QueryContext(ctx, query, args)
+}
+
+func (c *tenantConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := c.scope(ctx); err != nil {
+		return nil, err
+	}
+	return c.pgConn.ExecContext(ctx, query, args)
+}
+
+func (c *tenantConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.pgConn.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &tenantStmt{Stmt: stmt, conn: c}, nil
+}
+
+// BeginTx scopes the session before the transaction starts, so the setting
+// outlives a commit.
+func (c *tenantConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if err := c.scope(ctx); err != nil {
+		return nil, err
+	}
+	tx, err := c.pgConn.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &tenantTx{Tx: tx, conn: c}, nil
+}
+
+// tenantTx forgets the session's tenant on rollback, which reverts any
+// setting made inside the transaction.
+type tenantTx struct {
+	driver.Tx
+	conn *tenantConn
+}
+
+func (t *tenantTx) Rollback() error {
+	t.conn.scoped = false
+	return t.Tx.Rollback()
+}
+
+// tenantStmt scopes the session before each execution of a prepared
+// statement.
+type tenantStmt struct {
+	driver.Stmt
+	conn *tenantConn
+}
+
+func (s *tenantStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if err := s.conn.scope(ctx); err != nil {
+		return nil, err
+	}
+	if stmt, ok := s.Stmt.(driver.StmtExecContext); ok {
+		return stmt.ExecContext(ctx, args)
+	}
+	return s.Stmt.Exec(namedValues(args))
+}
+
+func (s *tenantStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if err := s.conn.scope(ctx); err != nil {
+		return nil, err
+	}
+	if stmt, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		return stmt.QueryContext(ctx, args)
+	}
+	return s.Stmt.Query(namedValues(args))
+}
+
+func namedValues(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return values
+}
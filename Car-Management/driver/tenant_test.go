@@ -0,0 +1,145 @@
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+)
+
+// fakeConn records the statements run on it, standing in for a lib/pq
+// connection.
+type fakeConn struct {
+	log *[]string
+}
+
+func (c fakeConn) record(query string, args []driver.NamedValue) {
+	entry := query
+	for _, arg := range args {
+		entry += " [" + arg.Value.(string) + "]"
+	}
+	*c.log = append(*c.log, entry)
+}
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c fakeConn) Close() error                              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+func (c fakeConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+func (c fakeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.record("BEGIN", nil)
+	return fakeTx(c), nil
+}
+func (c fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.record(query, args)
+	return driver.RowsAffected(0), nil
+}
+func (c fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.record(query, args)
+	return &fakeRows{}, nil
+}
+func (c fakeConn) Ping(ctx context.Context) error         { return nil }
+func (c fakeConn) ResetSession(ctx context.Context) error { return nil }
+func (c fakeConn) IsValid() bool                          { return true }
+
+type fakeTx fakeConn
+
+func (t fakeTx) Commit() error   { fakeConn(t).record("COMMIT", nil); return nil }
+func (t fakeTx) Rollback() error { fakeConn(t).record("ROLLBACK", nil); return nil }
+
+type fakeRows struct{}
+
+func (r *fakeRows) Columns() []string              { return []string{"n"} }
+func (r *fakeRows) Close() error                   { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error { return io.EOF }
+
+type fakeConnector struct {
+	log *[]string
+}
+
+func (c fakeConnector) Connect(ctx context.Context) (driver.Conn, error) { return fakeConn(c), nil }
+func (c fakeConnector) Driver() driver.Driver                            { return nil }
+
+func newTenantPool(t *testing.T) (*sql.DB, *[]string) {
+	t.Helper()
+	log := &[]string{}
+	db := sql.OpenDB(tenantConnector{fakeConnector{log: log}})
+	// One connection, so that every statement shares the session.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db, log
+}
+
+func TestTenantConnScopesEveryStatement(t *testing.T) {
+	db, log := newTenantPool(t)
+	tenantA, tenantB := uuid.New(), uuid.New()
+	ctxA := auth.WithTenant(context.Background(), tenantA)
+	ctxB := auth.WithTenant(context.Background(), tenantB)
+
+	steps := []struct {
+		ctx   context.Context
+		query string
+	}{
+		{ctxA, "SELECT a1"},
+		{ctxA, "SELECT a2"},
+		{ctxB, "SELECT b"},
+		{context.Background(), "SELECT none"},
+	}
+	for _, step := range steps {
+		rows, err := db.QueryContext(step.ctx, step.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows.Close()
+	}
+
+	want := []string{
+		setTenantQuery + " [" + tenantA.String() + "]",
+		"SELECT a1",
+		// Still scoped to tenant A; no round trip.
+		"SELECT a2",
+		setTenantQuery + " [" + tenantB.String() + "]",
+		"SELECT b",
+		// Statements without a tenant are scoped to none, so they see no
+		// rows instead of the previous tenant's.
+		setTenantQuery + " []",
+		"SELECT none",
+	}
+	if !slices.Equal(*log, want) {
+		t.Errorf("statements =\n%q\nwant\n%q", *log, want)
+	}
+}
+
+func TestTenantConnRescopesAfterRollback(t *testing.T) {
+	db, log := newTenantPool(t)
+	tenantID := uuid.New()
+	ctx := auth.WithTenant(context.Background(), tenantID)
+	set := setTenantQuery + " [" + tenantID.String() + "]"
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE x"); err != nil {
+		t.Fatal(err)
+	}
+	tx.Rollback()
+	if _, err := db.ExecContext(ctx, "UPDATE y"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The session is scoped before BEGIN so that the setting survives the
+	// transaction, and again after the rollback in case it was undone.
+	want := []string{set, "BEGIN", "UPDATE x", "ROLLBACK", set, "UPDATE y"}
+	if !slices.Equal(*log, want) {
+		t.Errorf("statements =\n%q\nwant\n%q", *log, want)
+	}
+}
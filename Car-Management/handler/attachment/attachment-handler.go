@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+// multipartOverhead is how much of an upload request may be multipart
+// framing and other form fields rather than the file itself.
+const multipartOverhead = 1 << 20
+
+type AttachmentHandler struct {
+	attachmentService service.AttachmentServiceInterface
+	maxBytes          int64
+}
+
+// NewAttachmentHandler returns an attachment handler that accepts files of up
+// to maxBytes.
+func NewAttachmentHandler(attachmentService service.AttachmentServiceInterface, maxBytes int64) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+		maxBytes:          maxBytes,
+	}
+}
+
+// AddAttachment uploads the "file" field of a multipart/form-data request as
+// an attachment of the car in the path.
+func (h *AttachmentHandler) AddAttachment(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("attachment-handler")
+	ctx, span := tracer.Start(r.Context(), "AddAttachment-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	carID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxBytes+multipartOverhead)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			http.Error(w, `multipart field "file" is required`, http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), statusFor(err))
+			return
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+		attachment, err := h.attachmentService.AddAttachment(ctx, carID, part.FileName(), part)
+		if err != nil {
+			http.Error(w, err.Error(), statusFor(err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		err = json.NewEncoder(w).Encode(attachment)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		return
+	}
+}
+
+func (h *AttachmentHandler) GetAttachmentsByCar(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("attachment-handler")
+	ctx, span := tracer.Start(r.Context(), "GetAttachmentsByCar-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	id := vars["id"]
+	attachments, err := h.attachmentService.GetAttachmentsByCar(ctx, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(attachments)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// ReorderAttachments sets the gallery order of the car in the path.
+func (h *AttachmentHandler) ReorderAttachments(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("attachment-handler")
+	ctx, span := tracer.Start(r.Context(), "ReorderAttachments-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	id := vars["id"]
+	var request models.ReorderAttachmentsRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	attachments, err := h.attachmentService.ReorderAttachments(ctx, id, request.IDs)
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(attachments)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *AttachmentHandler) GetAttachmentById(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("attachment-handler")
+	ctx, span := tracer.Start(r.Context(), "GetAttachmentById-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	id := vars["id"]
+	attachment, err := h.attachmentService.GetAttachmentById(ctx, id)
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(attachment)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetAttachmentContent serves the uploaded file.
+func (h *AttachmentHandler) GetAttachmentContent(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("attachment-handler")
+	ctx, span := tracer.Start(r.Context(), "GetAttachmentContent-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	id := vars["id"]
+	attachment, content, err := h.attachmentService.OpenAttachment(ctx, id)
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	defer content.Close()
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	writeContent(w, attachment.ContentType, attachment.Filename, content)
+}
+
+// GetAttachmentThumbnail serves the JPEG thumbnail of an image attachment.
+func (h *AttachmentHandler) GetAttachmentThumbnail(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("attachment-handler")
+	ctx, span := tracer.Start(r.Context(), "GetAttachmentThumbnail-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	id := vars["id"]
+	attachment, content, err := h.attachmentService.OpenThumbnail(ctx, id)
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	defer content.Close()
+	writeContent(w, "image/jpeg", attachment.Filename, content)
+}
+
+func (h *AttachmentHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("attachment-handler")
+	ctx, span := tracer.Start(r.Context(), "DeleteAttachment-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if err := h.attachmentService.DeleteAttachment(ctx, id); err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeContent streams a stored file. An attachment's content never changes,
+// so clients may cache it; nosniff keeps browsers to the sniffed type the
+// upload was checked against.
+func writeContent(w http.ResponseWriter, contentType, filename string, content io.Reader) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": filename}))
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, content)
+}
+
+func statusFor(err error) int {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.Is(err, store.ErrCarNotFound), errors.Is(err, store.ErrAttachmentNotFound), errors.Is(err, store.ErrNoThumbnail):
+		return http.StatusNotFound
+	case errors.Is(err, store.ErrAttachmentTooLarge), errors.As(err, &tooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, store.ErrUnsupportedAttachment):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, store.ErrInvalidAttachmentOrder):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
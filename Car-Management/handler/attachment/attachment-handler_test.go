@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
+)
+
+// fakeAttachments reads what is uploaded, as the real service does, and then
+// fails every call with err.
+type fakeAttachments struct {
+	service.AttachmentServiceInterface
+	err error
+}
+
+func (f fakeAttachments) AddAttachment(ctx context.Context, carID uuid.UUID, filename string, content io.Reader) (models.Attachment, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	return models.Attachment{CarID: carID, Filename: filename, Size: int64(len(data))}, f.err
+}
+
+func (f fakeAttachments) OpenAttachment(ctx context.Context, attachmentID string) (models.Attachment, io.ReadCloser, error) {
+	if f.err != nil {
+		return models.Attachment{}, nil, f.err
+	}
+	attachment := models.Attachment{Filename: "spec.pdf", ContentType: "application/pdf", Size: 9}
+	return attachment, io.NopCloser(strings.NewReader("%PDF-1.7\n")), nil
+}
+
+func (f fakeAttachments) OpenThumbnail(ctx context.Context, attachmentID string) (models.Attachment, io.ReadCloser, error) {
+	if f.err != nil {
+		return models.Attachment{}, nil, f.err
+	}
+	return models.Attachment{Filename: "photo.png"}, io.NopCloser(strings.NewReader("jpeg")), nil
+}
+
+func (f fakeAttachments) ReorderAttachments(ctx context.Context, carID string, ids []uuid.UUID) ([]models.Attachment, error) {
+	return nil, f.err
+}
+
+func (f fakeAttachments) DeleteAttachment(ctx context.Context, attachmentID string) error {
+	return f.err
+}
+
+func withID(r *http.Request, id string) *http.Request {
+	return mux.SetURLVars(r, map[string]string{"id": id})
+}
+
+// uploadRequest returns a multipart upload of content in field.
+func uploadRequest(t *testing.T, carID, field string, content []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if err := form.WriteField("caption", "front"); err != nil {
+		t.Fatal(err)
+	}
+	part, err := form.CreateFormFile(field, "photo.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	form.Close()
+	r := httptest.NewRequest(http.MethodPost, "/cars/"+carID+"/attachments", &body)
+	r.Header.Set("Content-Type", form.FormDataContentType())
+	return withID(r, carID)
+}
+
+func TestAddAttachment(t *testing.T) {
+	tests := []struct {
+		name    string
+		carID   string
+		field   string
+		content []byte
+		err     error
+		want    int
+	}{
+		{"uploaded", uuid.NewString(), "file", []byte("content"), nil, http.StatusCreated},
+		{"invalid car id", "civic", "file", []byte("content"), nil, http.StatusBadRequest},
+		{"no file field", uuid.NewString(), "photo", []byte("content"), nil, http.StatusBadRequest},
+		{"body over the limit", uuid.NewString(), "file", make([]byte, 2*multipartOverhead), nil, http.StatusRequestEntityTooLarge},
+		{"file over the limit", uuid.NewString(), "file", []byte("content"), store.ErrAttachmentTooLarge, http.StatusRequestEntityTooLarge},
+		{"unsupported type", uuid.NewString(), "file", []byte("content"), store.ErrUnsupportedAttachment, http.StatusUnsupportedMediaType},
+		{"unknown car", uuid.NewString(), "file", []byte("content"), store.ErrCarNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAttachmentHandler(fakeAttachments{err: tt.err}, 1024)
+			rec := httptest.NewRecorder()
+			h.AddAttachment(rec, uploadRequest(t, tt.carID, tt.field, tt.content))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	r := withID(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`)), uuid.NewString())
+	r.Header.Set("Content-Type", "application/json")
+	NewAttachmentHandler(fakeAttachments{}, 1024).AddAttachment(rec, r)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("JSON upload status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestGetAttachmentContent(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewAttachmentHandler(fakeAttachments{}, 1024).GetAttachmentContent(rec, withID(httptest.NewRequest(http.MethodGet, "/", nil), uuid.NewString()))
+	if rec.Code != http.StatusOK || rec.Body.String() != "%PDF-1.7\n" {
+		t.Fatalf("response = %d %q, want the stored file", rec.Code, rec.Body)
+	}
+	for header, want := range map[string]string{
+		"Content-Type":           "application/pdf",
+		"Content-Length":         "9",
+		"Content-Disposition":    `inline; filename=spec.pdf`,
+		"X-Content-Type-Options": "nosniff",
+	} {
+		if got := rec.Header().Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	NewAttachmentHandler(fakeAttachments{err: store.ErrAttachmentNotFound}, 1024).GetAttachmentContent(rec, withID(httptest.NewRequest(http.MethodGet, "/", nil), uuid.NewString()))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("missing attachment status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestGetAttachmentThumbnail(t *testing.T) {
+	for err, want := range map[error]int{
+		nil:                         http.StatusOK,
+		store.ErrNoThumbnail:        http.StatusNotFound,
+		store.ErrAttachmentNotFound: http.StatusNotFound,
+	} {
+		rec := httptest.NewRecorder()
+		NewAttachmentHandler(fakeAttachments{err: err}, 1024).GetAttachmentThumbnail(rec, withID(httptest.NewRequest(http.MethodGet, "/", nil), uuid.NewString()))
+		if rec.Code != want {
+			t.Errorf("%v: status = %d, want %d", err, rec.Code, want)
+		}
+		if err == nil && rec.Header().Get("Content-Type") != "image/jpeg" {
+			t.Errorf("thumbnail Content-Type = %q, want image/jpeg", rec.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestReorderAttachments(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"reordered", `{"ids":[]}`, nil, http.StatusOK},
+		{"malformed body", `{"ids":`, nil, http.StatusBadRequest},
+		{"ids do not match", `{"ids":[]}`, store.ErrInvalidAttachmentOrder, http.StatusConflict},
+		{"unknown car", `{"ids":[]}`, store.ErrCarNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			r := withID(httptest.NewRequest(http.MethodPut, "/", strings.NewReader(tt.body)), uuid.NewString())
+			NewAttachmentHandler(fakeAttachments{err: tt.err}, 1024).ReorderAttachments(rec, r)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteAttachment(t *testing.T) {
+	for err, want := range map[error]int{
+		nil:                         http.StatusNoContent,
+		store.ErrAttachmentNotFound: http.StatusNotFound,
+	} {
+		rec := httptest.NewRecorder()
+		NewAttachmentHandler(fakeAttachments{err: err}, 1024).DeleteAttachment(rec, withID(httptest.NewRequest(http.MethodDelete, "/", nil), uuid.NewString()))
+		if rec.Code != want {
+			t.Errorf("%v: status = %d, want %d", err, rec.Code, want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+type BrandHandler struct {
+	brandService service.BrandServiceInterface
+}
+
+func NewBrandHandler(brandService service.BrandServiceInterface) *BrandHandler {
+	return &BrandHandler{
+		brandService: brandService,
+	}
+}
+
+func (h *BrandHandler) GetBrands(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("brand-handler")
+	ctx, span := tracer.Start(r.Context(), "GetBrands-Handler")
+	defer span.End()
+	brands, err := h.brandService.GetBrands(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(brands)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *BrandHandler) GetBrandById(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("brand-handler")
+	ctx, span := tracer.Start(r.Context(), "GetBrandById-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	id := vars["id"]
+	brand, err := h.brandService.GetBrandById(ctx, id)
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(brand)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *BrandHandler) CreateBrand(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("brand-handler")
+	ctx, span := tracer.Start(r.Context(), "CreateBrand-Handler")
+	defer span.End()
+	var brandRequest models.BrandRequest
+	err := json.NewDecoder(r.Body).Decode(&brandRequest)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := models.ValidateBrandRequest(brandRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	createdBrand, err := h.brandService.CreateBrand(ctx, models.Brand{
+		Name:    brandRequest.Name,
+		Aliases: brandRequest.Aliases,
+		Country: brandRequest.Country,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(createdBrand)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// UpdateBrand replaces the brand's name, aliases and country.
+func (h *BrandHandler) UpdateBrand(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("brand-handler")
+	ctx, span := tracer.Start(r.Context(), "UpdateBrand-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	brandID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var brandRequest models.BrandRequest
+	err = json.NewDecoder(r.Body).Decode(&brandRequest)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := models.ValidateBrandRequest(brandRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	updatedBrand, err := h.brandService.UpdateBrand(ctx, models.Brand{
+		ID:      brandID,
+		Name:    brandRequest.Name,
+		Aliases: brandRequest.Aliases,
+		Country: brandRequest.Country,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(updatedBrand)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *BrandHandler) DeleteBrand(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("brand-handler")
+	ctx, span := tracer.Start(r.Context(), "DeleteBrand-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if err := h.brandService.DeleteBrand(ctx, id); err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, store.ErrBrandNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, store.ErrDuplicateBrand), errors.Is(err, store.ErrBrandInUse):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
+)
+
+// fakeBrands fails every call with err.
+type fakeBrands struct {
+	service.BrandServiceInterface
+	err error
+}
+
+func (f fakeBrands) CreateBrand(ctx context.Context, brand models.Brand) (models.Brand, error) {
+	return brand, f.err
+}
+
+func (f fakeBrands) UpdateBrand(ctx context.Context, brand models.Brand) (models.Brand, error) {
+	return brand, f.err
+}
+
+func (f fakeBrands) DeleteBrand(ctx context.Context, brandID string) error {
+	return f.err
+}
+
+func TestCreateBrand(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"created", `{"name":"Volkswagen","aliases":["VW"]}`, nil, http.StatusCreated},
+		{"missing name", `{"aliases":["VW"]}`, nil, http.StatusBadRequest},
+		{"empty alias", `{"name":"Volkswagen","aliases":[" "]}`, nil, http.StatusBadRequest},
+		{"alias repeats name", `{"name":"Volkswagen","aliases":["volkswagen"]}`, nil, http.StatusBadRequest},
+		{"taken", `{"name":"Volkswagen"}`, store.ErrDuplicateBrand, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewBrandHandler(fakeBrands{err: tt.err})
+			rec := httptest.NewRecorder()
+			h.CreateBrand(rec, httptest.NewRequest(http.MethodPost, "/brands", strings.NewReader(tt.body)))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestUpdateBrand(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		err  error
+		want int
+	}{
+		{"updated", uuid.NewString(), nil, http.StatusOK},
+		{"invalid id", "vw", nil, http.StatusBadRequest},
+		{"unknown brand", uuid.NewString(), store.ErrBrandNotFound, http.StatusNotFound},
+		{"taken", uuid.NewString(), store.ErrDuplicateBrand, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewBrandHandler(fakeBrands{err: tt.err})
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/brands/x", strings.NewReader(`{"name":"Volkswagen"}`))
+			h.UpdateBrand(rec, mux.SetURLVars(req, map[string]string{"id": tt.id}))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestDeleteBrand(t *testing.T) {
+	for err, want := range map[error]int{
+		nil:                    http.StatusNoContent,
+		store.ErrBrandNotFound: http.StatusNotFound,
+		store.ErrBrandInUse:    http.StatusConflict,
+	} {
+		h := NewBrandHandler(fakeBrands{err: err})
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, "/brands/x", nil)
+		h.DeleteBrand(rec, mux.SetURLVars(req, map[string]string{"id": uuid.NewString()}))
+		if rec.Code != want {
+			t.Errorf("DeleteBrand with %v: status = %d, want %d", err, rec.Code, want)
+		}
+	}
+}
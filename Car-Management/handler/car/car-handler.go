@@ -1,23 +1,53 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/handler"
 	"github.com/nitesh111sinha/car-management/models"
 	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
 	"go.opentelemetry.io/otel"
 )
 
+// errNoConversion is returned for ?currency= when there are no exchange
+// rates to convert with.
+var errNoConversion = errors.New("currency conversion needs STORE_BACKEND=sql")
+
+const (
+	defaultSimilarLimit = 5
+	maxSimilarLimit     = 20
+)
+
 type CarHandler struct {
-	carService service.CarServiceInterface
+	carService   service.CarServiceInterface
+	stockService service.StockServiceInterface
+	rateService  service.ExchangeRateServiceInterface
+	// attachmentService is nil without the sql backend.
+	attachmentService service.AttachmentServiceInterface
+	// similarity is the default weighting of GET /cars/{id}/similar.
+	similarity models.SimilarityWeights
 }
 
-func NewCarHandler(carService service.CarServiceInterface) *CarHandler {
+// NewCarHandler returns a car handler. stockService and attachmentService
+// may be nil, in which case GET /cars/{id} omits stock availability and
+// attachments, and rateService may be nil, in which case reads reject
+// ?currency=.
+func NewCarHandler(carService service.CarServiceInterface, stockService service.StockServiceInterface, rateService service.ExchangeRateServiceInterface, attachmentService service.AttachmentServiceInterface, similarity models.SimilarityWeights) *CarHandler {
 	return &CarHandler{
-		carService: carService,
+		carService:        carService,
+		stockService:      stockService,
+		rateService:       rateService,
+		attachmentService: attachmentService,
+		similarity:        similarity,
 	}
 }
 
@@ -34,11 +64,72 @@ func (h *CarHandler) GetCarById(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if car.ID != uuid.Nil {
+		converted, err := h.convertPrices(ctx, []models.Car{car}, r.URL.Query().Get("currency"))
+		if err != nil {
+			http.Error(w, err.Error(), statusFor(err))
+			return
+		}
+		car = converted[0]
+	}
+	if h.stockService != nil && car.ID != uuid.Nil {
+		availability, err := h.stockService.GetAvailability(ctx, id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		car.Availability = &availability
+	}
+	if h.attachmentService != nil && car.ID != uuid.Nil {
+		attachments, err := h.attachmentService.GetAttachmentsByCar(ctx, id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		car.Attachments = attachments
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(car)
 }
 
+// GetCarByVIN looks a car up by the VIN in the path, in any case.
+func (h *CarHandler) GetCarByVIN(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("car-handler")
+	ctx, span := tracer.Start(r.Context(), "GetCarByVIN-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	car, err := h.carService.GetCarByVIN(ctx, strings.ToUpper(vars["vin"]))
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	converted, err := h.convertPrices(ctx, []models.Car{car}, r.URL.Query().Get("currency"))
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(converted[0])
+}
+
+// DecodeVIN reports what the VIN in the path says about the vehicle, without
+// looking it up.
+func (h *CarHandler) DecodeVIN(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("car-handler")
+	_, span := tracer.Start(r.Context(), "DecodeVIN-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	info, err := models.DecodeVIN(strings.ToUpper(vars["vin"]))
+	if handler.WriteValidationError(w, err) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(info)
+}
+
 func (h *CarHandler) GetCars(w http.ResponseWriter, r *http.Request) {
 	tracer := otel.Tracer("car-handler")
 	ctx, span := tracer.Start(r.Context(), "GetCars-Handler")
@@ -48,11 +139,117 @@ func (h *CarHandler) GetCars(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	cars, err = h.convertPrices(ctx, cars, r.URL.Query().Get("currency"))
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(cars)
 }
 
+// CompareCars lines up the cars in the ids query parameter, a comma
+// separated list of car ids, and marks the best value of each attribute.
+// Prices are converted when ?currency= is given, so that cars listed in
+// different currencies can be ranked.
+func (h *CarHandler) CompareCars(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("car-handler")
+	ctx, span := tracer.Start(r.Context(), "CompareCars-Handler")
+	defer span.End()
+	var ids []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, value := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		id, err := uuid.Parse(strings.TrimSpace(value))
+		if err != nil {
+			http.Error(w, "ids must be a comma separated list of car ids", http.StatusBadRequest)
+			return
+		}
+		if seen[id] {
+			http.Error(w, "ids must not repeat a car", http.StatusBadRequest)
+			return
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) < models.MinComparedCars || len(ids) > models.MaxComparedCars {
+		http.Error(w, "ids must list between "+strconv.Itoa(models.MinComparedCars)+" and "+strconv.Itoa(models.MaxComparedCars)+" cars", http.StatusBadRequest)
+		return
+	}
+	cars, err := h.carService.GetCarsByIds(ctx, ids)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for i, id := range ids {
+		if i >= len(cars) || cars[i].ID != id {
+			http.Error(w, "car "+id.String()+" not found", http.StatusNotFound)
+			return
+		}
+	}
+	cars, err = h.convertPrices(ctx, cars, r.URL.Query().Get("currency"))
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(models.CompareCars(cars))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetSimilarCars recommends alternatives to the car in the path, best first.
+// limit (default 5) caps the number of cars, and weights, such as
+// price:3,brand:0, overrides the configured weight of some attributes.
+func (h *CarHandler) GetSimilarCars(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("car-handler")
+	ctx, span := tracer.Start(r.Context(), "GetSimilarCars-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	query := r.URL.Query()
+	limit := defaultSimilarLimit
+	if value := query.Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxSimilarLimit {
+			http.Error(w, "limit must be a number between 1 and "+strconv.Itoa(maxSimilarLimit), http.StatusBadRequest)
+			return
+		}
+	}
+	weights, err := parseWeights(query.Get("weights"), h.similarity)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	similar, err := h.carService.GetSimilarCars(ctx, vars["id"], weights, limit)
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	cars := make([]models.Car, len(similar))
+	for i, result := range similar {
+		cars[i] = result.Car
+	}
+	cars, err = h.convertPrices(ctx, cars, query.Get("currency"))
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	for i := range similar {
+		similar[i].Car = cars[i]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(similar)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
 func (h *CarHandler) UpdateCar(w http.ResponseWriter, r *http.Request) {
 	tracer := otel.Tracer("car-handler")
 	ctx, span := tracer.Start(r.Context(), "UpdateCar-Handler")
@@ -72,8 +269,11 @@ func (h *CarHandler) UpdateCar(w http.ResponseWriter, r *http.Request) {
 	}
 	car.ID = carID
 	updatedCar, err := h.carService.UpdateCar(ctx, car)
+	if handler.WriteValidationError(w, err) {
+		return
+	}
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), statusFor(err))
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -93,7 +293,7 @@ func (h *CarHandler) DeleteCar(w http.ResponseWriter, r *http.Request) {
 	id := vars["id"]
 
 	if err := h.carService.DeleteCar(ctx, id); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), statusFor(err))
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -111,6 +311,11 @@ func (h *CarHandler) GetCarByBrand(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	cars, err = h.convertPrices(ctx, cars, r.URL.Query().Get("currency"))
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	err = json.NewEncoder(w).Encode(cars)
@@ -131,8 +336,11 @@ func (h *CarHandler) CreateCar(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	createdCar, err := h.carService.CreateCar(ctx, car)
+	if handler.WriteValidationError(w, err) {
+		return
+	}
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), statusFor(err))
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -143,3 +351,66 @@ func (h *CarHandler) CreateCar(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// convertPrices converts the prices of cars to currency, or returns them as
+// they are if currency is empty.
+func (h *CarHandler) convertPrices(ctx context.Context, cars []models.Car, currency string) ([]models.Car, error) {
+	if currency == "" {
+		return cars, nil
+	}
+	if h.rateService == nil {
+		return nil, errNoConversion
+	}
+	return h.rateService.ConvertCars(ctx, cars, strings.ToUpper(currency))
+}
+
+// parseWeights overrides defaults with the weights in value, a comma
+// separated list of attribute:weight pairs.
+func parseWeights(value string, defaults models.SimilarityWeights) (models.SimilarityWeights, error) {
+	weights := defaults
+	if value == "" {
+		return weights, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		name, number, _ := strings.Cut(strings.TrimSpace(pair), ":")
+		weight, err := strconv.ParseFloat(number, 64)
+		if err != nil || weight < 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+			return weights, errors.New("weights must be attribute:weight pairs with weights of at least 0, such as price:3,brand:0")
+		}
+		switch name {
+		case models.SimilarPrice:
+			weights.Price = weight
+		case models.SimilarYear:
+			weights.Year = weight
+		case models.SimilarFuelType:
+			weights.FuelType = weight
+		case models.SimilarBrand:
+			weights.Brand = weight
+		case models.SimilarEngine:
+			weights.Engine = weight
+		default:
+			return weights, errors.New("weights can be given for price, year, fuel_type, brand and engine")
+		}
+	}
+	if weights.Total() <= 0 {
+		return weights, errors.New("at least one weight must be above 0")
+	}
+	return weights, nil
+}
+
+// statusFor maps car errors to a status; a car naming an engine or trim it
+// cannot have, or a currency without an exchange rate, is the client's
+// mistake.
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, store.ErrCarNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, store.ErrDuplicateCarVIN), errors.Is(err, store.ErrCarHasOrders):
+		return http.StatusConflict
+	case errors.Is(err, store.ErrInvalidEngine), errors.Is(err, store.ErrInvalidTrim), errors.Is(err, store.ErrPowertrainMismatch),
+		errors.Is(err, store.ErrExchangeRateNotFound), errors.Is(err, errNoConversion):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
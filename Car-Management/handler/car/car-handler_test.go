@@ -0,0 +1,346 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	carService "github.com/nitesh111sinha/car-management/service/car"
+	"github.com/nitesh111sinha/car-management/store"
+	"github.com/nitesh111sinha/car-management/store/memory"
+)
+
+// testWeights count every attribute equally.
+var testWeights = models.SimilarityWeights{Price: 1, Year: 1, FuelType: 1, Brand: 1, Engine: 1}
+
+// newHandler serves cars from an in-memory store holding one engine, scoped
+// to the default tenant.
+func newHandler(t *testing.T) (*CarHandler, context.Context, models.Engine) {
+	t.Helper()
+	db := memory.NewDB()
+	ctx := auth.WithTenant(context.Background(), models.DefaultTenantID)
+	engine, err := memory.NewEngineStore(db).CreateEngine(ctx, models.Engine{Displacement: 2000, NoOfCylinders: 4, CarRange: 600})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cars := carService.NewCarService(memory.NewCarStore(db), nil)
+	return NewCarHandler(cars, nil, nil, nil, testWeights), ctx, engine
+}
+
+func carBody(engineID uuid.UUID) string {
+	return `{"name":"Civic","year":"2023","brand":"Honda","fuel_type":"Petrol","engine":{"engine_id":"` + engineID.String() + `"},"price":{"amount":2500000,"currency":"USD"}}`
+}
+
+func serve(ctx context.Context, fn http.HandlerFunc, method, body string, vars map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/cars", strings.NewReader(body)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	fn(rec, mux.SetURLVars(req, vars))
+	return rec
+}
+
+func TestCreateCar(t *testing.T) {
+	h, ctx, engine := newHandler(t)
+
+	rec := serve(ctx, h.CreateCar, http.MethodPost, carBody(engine.EngineID), nil)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201: %s", rec.Code, rec.Body)
+	}
+
+	if rec := serve(ctx, h.CreateCar, http.MethodPost, `{"name":`, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("malformed body: status = %d, want 400", rec.Code)
+	}
+	if rec := serve(ctx, h.CreateCar, http.MethodPost, carBody(uuid.New()), nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown engine: status = %d, want 400: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestCreateCarReportsEveryFieldError(t *testing.T) {
+	h, ctx, _ := newHandler(t)
+
+	rec := serve(ctx, h.CreateCar, http.MethodPost, `{"year":"1800","fuel_type":"Steam","price":{"amount":0,"currency":"USD"}}`, nil)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	var body models.ValidationError
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	pointers := map[string]string{}
+	for _, fieldError := range body.Errors {
+		pointers[fieldError.Pointer] = fieldError.Code
+	}
+	for pointer, code := range map[string]string{
+		"/name":             models.CodeRequired,
+		"/year":             models.CodeOutOfRange,
+		"/brand":            models.CodeRequired,
+		"/fuel_type":        models.CodeInvalidChoice,
+		"/engine/engine_id": models.CodeRequired,
+		"/price/amount":     models.CodeMustBePositive,
+	} {
+		if pointers[pointer] != code {
+			t.Errorf("%s = %q, want %q (errors %+v)", pointer, pointers[pointer], code, body.Errors)
+		}
+	}
+}
+
+func TestUpdateCar(t *testing.T) {
+	h, ctx, engine := newHandler(t)
+	var created models.Car
+	json.NewDecoder(serve(ctx, h.CreateCar, http.MethodPost, carBody(engine.EngineID), nil).Body).Decode(&created)
+
+	tests := []struct {
+		name string
+		id   string
+		body string
+		want int
+	}{
+		{"updated", created.ID.String(), carBody(engine.EngineID), http.StatusOK},
+		{"invalid id", "civic", carBody(engine.EngineID), http.StatusBadRequest},
+		{"invalid car", created.ID.String(), `{"name":"Civic"}`, http.StatusUnprocessableEntity},
+		{"unknown car", uuid.NewString(), carBody(engine.EngineID), http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(ctx, h.UpdateCar, http.MethodPut, tt.body, map[string]string{"id": tt.id})
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestDeleteUnknownCar(t *testing.T) {
+	h, ctx, _ := newHandler(t)
+	if rec := serve(ctx, h.DeleteCar, http.MethodDelete, "", map[string]string{"id": uuid.NewString()}); rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+// fakeCars fails every call with err.
+type fakeCars struct {
+	service.CarServiceInterface
+	err error
+}
+
+func (f fakeCars) DeleteCar(ctx context.Context, carID string) error {
+	return f.err
+}
+
+func TestDeleteCarWithOrders(t *testing.T) {
+	h := NewCarHandler(fakeCars{err: store.ErrCarHasOrders}, nil, nil, nil, testWeights)
+	if rec := serve(context.Background(), h.DeleteCar, http.MethodDelete, "", map[string]string{"id": uuid.NewString()}); rec.Code != http.StatusConflict {
+		t.Errorf("status = %d, want 409", rec.Code)
+	}
+}
+
+// fakeRates converts to no currency but USD, leaving prices as they are.
+type fakeRates struct {
+	service.ExchangeRateServiceInterface
+}
+
+func (fakeRates) ConvertCars(ctx context.Context, cars []models.Car, currency string) ([]models.Car, error) {
+	if currency != "USD" {
+		return nil, fmt.Errorf("%w: %s", store.ErrExchangeRateNotFound, currency)
+	}
+	return cars, nil
+}
+
+func TestGetCarsInCurrency(t *testing.T) {
+	h, ctx, engine := newHandler(t)
+	serve(ctx, h.CreateCar, http.MethodPost, carBody(engine.EngineID), nil)
+
+	if rec := serve(ctx, h.GetCars, http.MethodGet, "", nil); rec.Code != http.StatusOK {
+		t.Errorf("without currency: status = %d, want 200", rec.Code)
+	}
+	get := func(query string) int {
+		req := httptest.NewRequest(http.MethodGet, "/cars"+query, nil).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		h.GetCars(rec, req)
+		return rec.Code
+	}
+	if got := get("?currency=EUR"); got != http.StatusBadRequest {
+		t.Errorf("without exchange rates: status = %d, want 400", got)
+	}
+
+	h.rateService = fakeRates{}
+	if got := get("?currency=usd"); got != http.StatusOK {
+		t.Errorf("currency with a rate: status = %d, want 200", got)
+	}
+	if got := get("?currency=GBP"); got != http.StatusBadRequest {
+		t.Errorf("currency without a rate: status = %d, want 400", got)
+	}
+}
+
+func TestDecodeVIN(t *testing.T) {
+	h, ctx, _ := newHandler(t)
+
+	rec := serve(ctx, h.DecodeVIN, http.MethodGet, "", map[string]string{"vin": "1hgcm82633a004352"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var info models.VINInfo
+	if err := json.NewDecoder(rec.Body).Decode(&info); err != nil {
+		t.Fatal(err)
+	}
+	if info.VIN != "1HGCM82633A004352" || info.Manufacturer != "Honda" || info.ModelYear != 2003 {
+		t.Errorf("info = %+v, want a 2003 Honda", info)
+	}
+
+	rec = serve(ctx, h.DecodeVIN, http.MethodGet, "", map[string]string{"vin": "1HGCM82643A004352"})
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), models.CodeInvalidCheckDigit) {
+		t.Errorf("bad check digit: response = %d %s, want 422 %s", rec.Code, rec.Body, models.CodeInvalidCheckDigit)
+	}
+}
+
+func TestCarVIN(t *testing.T) {
+	h, ctx, engine := newHandler(t)
+	body := strings.Replace(carBody(engine.EngineID), `"year":"2023"`, `"year":"2003","vin":"1HGCM82633A004352"`, 1)
+
+	if rec := serve(ctx, h.CreateCar, http.MethodPost, body, nil); rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201: %s", rec.Code, rec.Body)
+	}
+	if rec := serve(ctx, h.CreateCar, http.MethodPost, body, nil); rec.Code != http.StatusConflict {
+		t.Errorf("duplicate VIN: status = %d, want 409: %s", rec.Code, rec.Body)
+	}
+	toyota := strings.Replace(body, "Honda", "Toyota", 1)
+	if rec := serve(ctx, h.CreateCar, http.MethodPost, toyota, nil); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("VIN of another manufacturer: status = %d, want 422: %s", rec.Code, rec.Body)
+	}
+
+	rec := serve(ctx, h.GetCarByVIN, http.MethodGet, "", map[string]string{"vin": "1hgcm82633a004352"})
+	var car models.Car
+	json.NewDecoder(rec.Body).Decode(&car)
+	if rec.Code != http.StatusOK || car.VIN != "1HGCM82633A004352" {
+		t.Errorf("GetCarByVIN = %d %+v, want the car", rec.Code, car)
+	}
+	if rec := serve(ctx, h.GetCarByVIN, http.MethodGet, "", map[string]string{"vin": "1M8GDM9AXKP042788"}); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown VIN: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestCompareCars(t *testing.T) {
+	h, ctx, engine := newHandler(t)
+	var ids []string
+	for i := 0; i < 2; i++ {
+		var created models.Car
+		json.NewDecoder(serve(ctx, h.CreateCar, http.MethodPost, carBody(engine.EngineID), nil).Body).Decode(&created)
+		ids = append(ids, created.ID.String())
+	}
+	compare := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/cars/compare?"+query, nil).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		h.CompareCars(rec, req)
+		return rec
+	}
+
+	rec := compare("ids=" + ids[1] + ",%20" + ids[0])
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var comparison models.CarComparison
+	if err := json.NewDecoder(rec.Body).Decode(&comparison); err != nil {
+		t.Fatal(err)
+	}
+	if len(comparison.Cars) != 2 || comparison.Cars[0].ID.String() != ids[1] || len(comparison.Attributes) == 0 {
+		t.Errorf("comparison = %+v, want both cars in the order asked", comparison)
+	}
+
+	missing := uuid.NewString()
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"no ids", "", http.StatusBadRequest},
+		{"invalid id", "ids=" + ids[0] + ",civic", http.StatusBadRequest},
+		{"repeated id", "ids=" + ids[0] + "," + ids[0], http.StatusBadRequest},
+		{"one car", "ids=" + ids[0], http.StatusBadRequest},
+		{"five cars", "ids=" + strings.Join([]string{ids[0], ids[1], uuid.NewString(), uuid.NewString(), uuid.NewString()}, ","), http.StatusBadRequest},
+		{"unknown car", "ids=" + ids[0] + "," + missing, http.StatusNotFound},
+		{"currency without rates", "ids=" + ids[0] + "," + ids[1] + "&currency=EUR", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := compare(tt.query); rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+	if rec := compare("ids=" + ids[0] + "," + missing); !strings.Contains(rec.Body.String(), missing) {
+		t.Errorf("not found body = %q, want it to name car %s", rec.Body, missing)
+	}
+}
+
+func TestGetSimilarCars(t *testing.T) {
+	h, ctx, engine := newHandler(t)
+	var ids []string
+	for i := 0; i < 7; i++ {
+		var created models.Car
+		json.NewDecoder(serve(ctx, h.CreateCar, http.MethodPost, carBody(engine.EngineID), nil).Body).Decode(&created)
+		ids = append(ids, created.ID.String())
+	}
+	similar := func(id, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/cars/"+id+"/similar?"+query, nil).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		h.GetSimilarCars(rec, mux.SetURLVars(req, map[string]string{"id": id}))
+		return rec
+	}
+
+	rec := similar(ids[0], "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var results []models.SimilarCar
+	if err := json.NewDecoder(rec.Body).Decode(&results); err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != defaultSimilarLimit || results[0].Score != 1 {
+		t.Errorf("results = %+v, want %d identical cars", results, defaultSimilarLimit)
+	}
+	for _, result := range results {
+		if result.Car.ID.String() == ids[0] {
+			t.Error("the car is recommended as similar to itself")
+		}
+	}
+	rec = similar(ids[0], "limit=2&weights=price:3,brand:0")
+	json.NewDecoder(rec.Body).Decode(&results)
+	if rec.Code != http.StatusOK || len(results) != 2 {
+		t.Errorf("limit 2 = %d with %d cars, want 200 with 2", rec.Code, len(results))
+	}
+
+	tests := []struct {
+		name  string
+		id    string
+		query string
+		want  int
+	}{
+		{"limit zero", ids[0], "limit=0", http.StatusBadRequest},
+		{"limit above maximum", ids[0], "limit=21", http.StatusBadRequest},
+		{"limit not a number", ids[0], "limit=x", http.StatusBadRequest},
+		{"negative weight", ids[0], "weights=price:-1", http.StatusBadRequest},
+		{"weight not a number", ids[0], "weights=price:abc", http.StatusBadRequest},
+		{"unknown attribute", ids[0], "weights=colour:1", http.StatusBadRequest},
+		{"every weight zero", ids[0], "weights=price:0,year:0,fuel_type:0,brand:0,engine:0", http.StatusBadRequest},
+		{"unknown car", uuid.NewString(), "", http.StatusNotFound},
+		{"currency without rates", ids[0], "currency=EUR", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := similar(tt.id, tt.query); rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+// CatalogHandler serves the brand > model > trim tree.
+type CatalogHandler struct {
+	catalogService service.CatalogServiceInterface
+}
+
+func NewCatalogHandler(catalogService service.CatalogServiceInterface) *CatalogHandler {
+	return &CatalogHandler{
+		catalogService: catalogService,
+	}
+}
+
+// GetModelsByBrand lists the models of the brand in the path.
+func (h *CatalogHandler) GetModelsByBrand(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("catalog-handler")
+	ctx, span := tracer.Start(r.Context(), "GetModelsByBrand-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	carModels, err := h.catalogService.GetModelsByBrand(ctx, vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(carModels)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *CatalogHandler) GetModelById(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("catalog-handler")
+	ctx, span := tracer.Start(r.Context(), "GetModelById-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	model, err := h.catalogService.GetModelById(ctx, vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(model)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// CreateModel adds a model to the brand in the path.
+func (h *CatalogHandler) CreateModel(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("catalog-handler")
+	ctx, span := tracer.Start(r.Context(), "CreateModel-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	brandID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var request models.CarModelRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := models.ValidateCarModelRequest(request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	createdModel, err := h.catalogService.CreateModel(ctx, models.CarModel{BrandID: brandID, Name: request.Name})
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(createdModel)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// UpdateModel renames a model.
+func (h *CatalogHandler) UpdateModel(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("catalog-handler")
+	ctx, span := tracer.Start(r.Context(), "UpdateModel-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	modelID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var request models.CarModelRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := models.ValidateCarModelRequest(request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	updatedModel, err := h.catalogService.UpdateModel(ctx, models.CarModel{ID: modelID, Name: request.Name})
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(updatedModel)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *CatalogHandler) DeleteModel(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("catalog-handler")
+	ctx, span := tracer.Start(r.Context(), "DeleteModel-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	if err := h.catalogService.DeleteModel(ctx, vars["id"]); err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetTrimsByModel lists the trims of the model in the path, newest first.
+func (h *CatalogHandler) GetTrimsByModel(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("catalog-handler")
+	ctx, span := tracer.Start(r.Context(), "GetTrimsByModel-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	trims, err := h.catalogService.GetTrimsByModel(ctx, vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(trims)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *CatalogHandler) GetTrimById(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("catalog-handler")
+	ctx, span := tracer.Start(r.Context(), "GetTrimById-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	trim, err := h.catalogService.GetTrimById(ctx, vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(trim)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// CreateTrim adds a trim to the model in the path.
+func (h *CatalogHandler) CreateTrim(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("catalog-handler")
+	ctx, span := tracer.Start(r.Context(), "CreateTrim-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	modelID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var request models.TrimRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := models.ValidateTrimRequest(request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	createdTrim, err := h.catalogService.CreateTrim(ctx, trimFromRequest(uuid.Nil, modelID, request))
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(createdTrim)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// UpdateTrim replaces a trim's name, year and engines.
+func (h *CatalogHandler) UpdateTrim(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("catalog-handler")
+	ctx, span := tracer.Start(r.Context(), "UpdateTrim-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	trimID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var request models.TrimRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := models.ValidateTrimRequest(request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	updatedTrim, err := h.catalogService.UpdateTrim(ctx, trimFromRequest(trimID, uuid.Nil, request))
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(updatedTrim)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *CatalogHandler) DeleteTrim(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("catalog-handler")
+	ctx, span := tracer.Start(r.Context(), "DeleteTrim-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	if err := h.catalogService.DeleteTrim(ctx, vars["id"]); err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func trimFromRequest(trimID, modelID uuid.UUID, request models.TrimRequest) models.Trim {
+	trim := models.Trim{ID: trimID, ModelID: modelID, Name: request.Name, Year: request.Year}
+	for _, engineID := range request.EngineIDs {
+		trim.Engines = append(trim.Engines, models.Engine{EngineID: engineID})
+	}
+	return trim
+}
+
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, store.ErrBrandNotFound), errors.Is(err, store.ErrModelNotFound), errors.Is(err, store.ErrTrimNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, store.ErrInvalidEngine):
+		return http.StatusBadRequest
+	case errors.Is(err, store.ErrDuplicateModel), errors.Is(err, store.ErrDuplicateTrim),
+		errors.Is(err, store.ErrModelInUse), errors.Is(err, store.ErrTrimInUse), errors.Is(err, store.ErrTrimEngineInUse):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
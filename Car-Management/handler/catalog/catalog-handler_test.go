@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
+)
+
+// fakeCatalog fails every call with err.
+type fakeCatalog struct {
+	service.CatalogServiceInterface
+	err error
+}
+
+func (f fakeCatalog) CreateModel(ctx context.Context, model models.CarModel) (models.CarModel, error) {
+	return model, f.err
+}
+
+func (f fakeCatalog) CreateTrim(ctx context.Context, trim models.Trim) (models.Trim, error) {
+	return trim, f.err
+}
+
+func (f fakeCatalog) DeleteModel(ctx context.Context, modelID string) error {
+	return f.err
+}
+
+func (f fakeCatalog) DeleteTrim(ctx context.Context, trimID string) error {
+	return f.err
+}
+
+func withID(r *http.Request, id string) *http.Request {
+	return mux.SetURLVars(r, map[string]string{"id": id})
+}
+
+func TestCreateModel(t *testing.T) {
+	tests := []struct {
+		name    string
+		brandID string
+		body    string
+		err     error
+		want    int
+	}{
+		{"created", uuid.NewString(), `{"name":"Civic"}`, nil, http.StatusCreated},
+		{"invalid brand id", "honda", `{"name":"Civic"}`, nil, http.StatusBadRequest},
+		{"missing name", uuid.NewString(), `{"name":" "}`, nil, http.StatusBadRequest},
+		{"unknown brand", uuid.NewString(), `{"name":"Civic"}`, store.ErrBrandNotFound, http.StatusNotFound},
+		{"taken", uuid.NewString(), `{"name":"Civic"}`, store.ErrDuplicateModel, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewCatalogHandler(fakeCatalog{err: tt.err})
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/brands/x/models", strings.NewReader(tt.body))
+			h.CreateModel(rec, withID(req, tt.brandID))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestCreateTrim(t *testing.T) {
+	engine := uuid.NewString()
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"created", `{"name":"EX","year":"2023","engine_ids":["` + engine + `"]}`, nil, http.StatusCreated},
+		{"bad year", `{"name":"EX","year":"23","engine_ids":["` + engine + `"]}`, nil, http.StatusBadRequest},
+		{"no engines", `{"name":"EX","year":"2023","engine_ids":[]}`, nil, http.StatusBadRequest},
+		{"engine twice", `{"name":"EX","year":"2023","engine_ids":["` + engine + `","` + engine + `"]}`, nil, http.StatusBadRequest},
+		{"unknown engine", `{"name":"EX","year":"2023","engine_ids":["` + engine + `"]}`, store.ErrInvalidEngine, http.StatusBadRequest},
+		{"unknown model", `{"name":"EX","year":"2023","engine_ids":["` + engine + `"]}`, store.ErrModelNotFound, http.StatusNotFound},
+		{"taken", `{"name":"EX","year":"2023","engine_ids":["` + engine + `"]}`, store.ErrDuplicateTrim, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewCatalogHandler(fakeCatalog{err: tt.err})
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/models/x/trims", strings.NewReader(tt.body))
+			h.CreateTrim(rec, withID(req, uuid.NewString()))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestDeleteInUse(t *testing.T) {
+	for err, want := range map[error]int{
+		nil:                    http.StatusNoContent,
+		store.ErrModelNotFound: http.StatusNotFound,
+		store.ErrModelInUse:    http.StatusConflict,
+	} {
+		rec := httptest.NewRecorder()
+		NewCatalogHandler(fakeCatalog{err: err}).DeleteModel(rec, withID(httptest.NewRequest(http.MethodDelete, "/models/x", nil), uuid.NewString()))
+		if rec.Code != want {
+			t.Errorf("DeleteModel with %v: status = %d, want %d", err, rec.Code, want)
+		}
+	}
+	for err, want := range map[error]int{
+		store.ErrTrimNotFound: http.StatusNotFound,
+		store.ErrTrimInUse:    http.StatusConflict,
+	} {
+		rec := httptest.NewRecorder()
+		NewCatalogHandler(fakeCatalog{err: err}).DeleteTrim(rec, withID(httptest.NewRequest(http.MethodDelete, "/trims/x", nil), uuid.NewString()))
+		if rec.Code != want {
+			t.Errorf("DeleteTrim with %v: status = %d, want %d", err, rec.Code, want)
+		}
+	}
+}
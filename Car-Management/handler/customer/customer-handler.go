@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/handler"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+type CustomerHandler struct {
+	customerService service.CustomerServiceInterface
+}
+
+func NewCustomerHandler(customerService service.CustomerServiceInterface) *CustomerHandler {
+	return &CustomerHandler{
+		customerService: customerService,
+	}
+}
+
+func (h *CustomerHandler) CreateCustomer(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("customer-handler")
+	ctx, span := tracer.Start(r.Context(), "CreateCustomer-Handler")
+	defer span.End()
+	var request models.CustomerRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if handler.WriteValidationError(w, models.ValidateCustomerRequest(&request)) {
+		return
+	}
+	customer, err := h.customerService.CreateCustomer(ctx, models.Customer{
+		Name:    request.Name,
+		Email:   request.Email,
+		Phone:   request.Phone,
+		Consent: request.Consent,
+		Notes:   request.Notes,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(customer)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// SearchCustomers lists the customers matching the q query parameter by
+// name, email address or phone number, or every customer without it.
+func (h *CustomerHandler) SearchCustomers(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("customer-handler")
+	ctx, span := tracer.Start(r.Context(), "SearchCustomers-Handler")
+	defer span.End()
+	customers, err := h.customerService.SearchCustomers(ctx, r.URL.Query().Get("q"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(customers)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *CustomerHandler) GetCustomerById(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("customer-handler")
+	ctx, span := tracer.Start(r.Context(), "GetCustomerById-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	customer, err := h.customerService.GetCustomerById(ctx, vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(customer)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *CustomerHandler) UpdateCustomer(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("customer-handler")
+	ctx, span := tracer.Start(r.Context(), "UpdateCustomer-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	customerID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var request models.CustomerRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if handler.WriteValidationError(w, models.ValidateCustomerRequest(&request)) {
+		return
+	}
+	customer, err := h.customerService.UpdateCustomer(ctx, models.Customer{
+		ID:      customerID,
+		Name:    request.Name,
+		Email:   request.Email,
+		Phone:   request.Phone,
+		Consent: request.Consent,
+		Notes:   request.Notes,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(customer)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// CreateEnquiry records a question of the customer in the path.
+func (h *CustomerHandler) CreateEnquiry(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("customer-handler")
+	ctx, span := tracer.Start(r.Context(), "CreateEnquiry-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	customerID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var request models.EnquiryRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if handler.WriteValidationError(w, models.ValidateEnquiryRequest(request)) {
+		return
+	}
+	enquiry, err := h.customerService.CreateEnquiry(ctx, models.Enquiry{
+		CustomerID: customerID,
+		CarID:      request.CarID,
+		Channel:    request.Channel,
+		Message:    request.Message,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(enquiry)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *CustomerHandler) GetEnquiriesByCustomer(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("customer-handler")
+	ctx, span := tracer.Start(r.Context(), "GetEnquiriesByCustomer-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	enquiries, err := h.customerService.GetEnquiriesByCustomer(ctx, vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(enquiries)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *CustomerHandler) GetTimeline(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("customer-handler")
+	ctx, span := tracer.Start(r.Context(), "GetTimeline-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	timeline, err := h.customerService.GetTimeline(ctx, vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(timeline)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// ExportCustomer answers with everything held about the customer in the
+// path, as a JSON file download.
+func (h *CustomerHandler) ExportCustomer(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("customer-handler")
+	ctx, span := tracer.Start(r.Context(), "ExportCustomer-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	record, err := h.customerService.ExportCustomer(ctx, vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	filename := "customer-" + record.Customer.ID.String() + ".json"
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(record)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// EraseCustomer erases the personal data of the customer in the path. It
+// cannot be undone.
+func (h *CustomerHandler) EraseCustomer(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("customer-handler")
+	ctx, span := tracer.Start(r.Context(), "EraseCustomer-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	customer, err := h.customerService.EraseCustomer(ctx, vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(customer)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, store.ErrCustomerNotFound), errors.Is(err, store.ErrCarNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, store.ErrDuplicateCustomerEmail), errors.Is(err, store.ErrCustomerErased):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
+)
+
+// fakeCustomers fails every call with err.
+type fakeCustomers struct {
+	service.CustomerServiceInterface
+	err error
+}
+
+func (f fakeCustomers) CreateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error) {
+	return customer, f.err
+}
+
+func (f fakeCustomers) UpdateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error) {
+	return customer, f.err
+}
+
+func (f fakeCustomers) CreateEnquiry(ctx context.Context, enquiry models.Enquiry) (models.Enquiry, error) {
+	return enquiry, f.err
+}
+
+func (f fakeCustomers) ExportCustomer(ctx context.Context, customerID string) (models.CustomerRecord, error) {
+	return models.CustomerRecord{Customer: models.Customer{ID: uuid.MustParse(customerID)}}, f.err
+}
+
+func (f fakeCustomers) EraseCustomer(ctx context.Context, customerID string) (models.Customer, error) {
+	return models.Customer{}, f.err
+}
+
+func serve(fn http.HandlerFunc, body string, vars map[string]string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	fn(rec, mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)), vars))
+	return rec
+}
+
+const validCustomer = `{"name":"Ann Lee","email":"ann@example.com","consent":{"email":true}}`
+
+func TestCreateCustomer(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"created", validCustomer, nil, http.StatusCreated},
+		{"malformed body", `{"name":`, nil, http.StatusBadRequest},
+		{"invalid customer", `{"email":"ann@"}`, nil, http.StatusUnprocessableEntity},
+		{"email taken", validCustomer, store.ErrDuplicateCustomerEmail, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := serve(NewCustomerHandler(fakeCustomers{err: tt.err}).CreateCustomer, tt.body, nil); rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestUpdateCustomer(t *testing.T) {
+	for err, want := range map[error]int{
+		nil:                             http.StatusOK,
+		store.ErrCustomerNotFound:       http.StatusNotFound,
+		store.ErrCustomerErased:         http.StatusConflict,
+		store.ErrDuplicateCustomerEmail: http.StatusConflict,
+	} {
+		if rec := serve(NewCustomerHandler(fakeCustomers{err: err}).UpdateCustomer, validCustomer, map[string]string{"id": uuid.NewString()}); rec.Code != want {
+			t.Errorf("%v: status = %d, want %d", err, rec.Code, want)
+		}
+	}
+	if rec := serve(NewCustomerHandler(fakeCustomers{}).UpdateCustomer, validCustomer, map[string]string{"id": "ann"}); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid id: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestCreateEnquiry(t *testing.T) {
+	body := `{"channel":"email","message":"Price?"}`
+	for err, want := range map[error]int{
+		nil:                       http.StatusCreated,
+		store.ErrCustomerNotFound: http.StatusNotFound,
+		store.ErrCarNotFound:      http.StatusNotFound,
+		store.ErrCustomerErased:   http.StatusConflict,
+	} {
+		if rec := serve(NewCustomerHandler(fakeCustomers{err: err}).CreateEnquiry, body, map[string]string{"id": uuid.NewString()}); rec.Code != want {
+			t.Errorf("%v: status = %d, want %d", err, rec.Code, want)
+		}
+	}
+	if rec := serve(NewCustomerHandler(fakeCustomers{}).CreateEnquiry, `{"channel":"fax"}`, map[string]string{"id": uuid.NewString()}); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("invalid enquiry: status = %d, want 422", rec.Code)
+	}
+}
+
+func TestExportCustomer(t *testing.T) {
+	id := uuid.NewString()
+	rec := serve(NewCustomerHandler(fakeCustomers{}).ExportCustomer, "", map[string]string{"id": id})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if got, want := rec.Header().Get("Content-Disposition"), "attachment; filename=customer-"+id+".json"; got != want {
+		t.Errorf("Content-Disposition = %q, want %q", got, want)
+	}
+	if rec := serve(NewCustomerHandler(fakeCustomers{err: store.ErrCustomerNotFound}).ExportCustomer, "", map[string]string{"id": id}); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown customer: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestEraseCustomer(t *testing.T) {
+	for err, want := range map[error]int{
+		nil:                       http.StatusOK,
+		store.ErrCustomerNotFound: http.StatusNotFound,
+		store.ErrCustomerErased:   http.StatusConflict,
+	} {
+		if rec := serve(NewCustomerHandler(fakeCustomers{err: err}).EraseCustomer, "", map[string]string{"id": uuid.NewString()}); rec.Code != want {
+			t.Errorf("%v: status = %d, want %d", err, rec.Code, want)
+		}
+	}
+}
@@ -1,13 +1,18 @@
 package handler
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/handler"
 	"github.com/nitesh111sinha/car-management/models"
 	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
 	"go.opentelemetry.io/otel"
 )
 
@@ -53,6 +58,9 @@ func (h *EngineHandler) CreateEngine(w http.ResponseWriter, r *http.Request) {
 	}
 	engine.EngineID = uuid.New()
 	createdEngine, err := h.engineService.CreateEngine(ctx, engine)
+	if handler.WriteValidationError(w, err) {
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -103,6 +111,13 @@ func (h *EngineHandler) UpdateEngine(w http.ResponseWriter, r *http.Request) {
 	}
 	engine.EngineID = engineID
 	updatedEngine, err := h.engineService.UpdateEngine(ctx, engineID.String(), engine)
+	if handler.WriteValidationError(w, err) {
+		return
+	}
+	if errors.Is(err, store.ErrPowertrainMismatch) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -116,15 +131,62 @@ func (h *EngineHandler) UpdateEngine(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// DeleteEngine deletes an engine that no car uses. While cars use it the
+// response is 409 with the cars listed. ?cascade=true deletes those cars as
+// well and is reserved for admins.
 func (h *EngineHandler) DeleteEngine(w http.ResponseWriter, r *http.Request) {
 	tracer := otel.Tracer("engine-handler")
 	ctx, span := tracer.Start(r.Context(), "DeleteEngine-Handler")
 	defer span.End()
 	vars := mux.Vars(r)
 	id := vars["id"]
-	if err := h.engineService.DeleteEngine(ctx, id); err != nil {
+	cascade := r.URL.Query().Get("cascade") == "true"
+	if cascade {
+		principal, ok := auth.PrincipalFromContext(ctx)
+		if !ok || !principal.IsAdmin() {
+			http.Error(w, "cascade=true requires the admin role", http.StatusForbidden)
+			return
+		}
+	}
+	err := h.engineService.DeleteEngine(ctx, id, cascade)
+	var inUse *store.EngineInUseError
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+	case errors.As(err, &inUse):
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(models.EngineInUse{Error: inUse.Error(), Cars: inUse.Cars})
+	case errors.Is(err, store.ErrCarHasOrders):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "engine not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// GetEngineCars lists the cars that use the engine in the path.
+func (h *EngineHandler) GetEngineCars(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("engine-handler")
+	ctx, span := tracer.Start(r.Context(), "GetEngineCars-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	id := vars["id"]
+	cars, err := h.engineService.GetEngineCars(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "engine not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(cars)
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	engineService "github.com/nitesh111sinha/car-management/service/engine"
+	"github.com/nitesh111sinha/car-management/store"
+	"github.com/nitesh111sinha/car-management/store/memory"
+)
+
+// fakeEngines fails every call with err and records whether a delete
+// cascaded.
+type fakeEngines struct {
+	service.EngineServiceInterface
+	err      error
+	cascaded *bool
+}
+
+func (f fakeEngines) DeleteEngine(ctx context.Context, engineID string, cascade bool) error {
+	if f.cascaded != nil {
+		*f.cascaded = cascade
+	}
+	return f.err
+}
+
+func (f fakeEngines) GetEngineCars(ctx context.Context, engineID string) ([]models.Car, error) {
+	return []models.Car{}, f.err
+}
+
+func deleteEngine(h *EngineHandler, query string, role string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodDelete, "/engines/x"+query, nil)
+	if role != "" {
+		req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{Username: "alice", TenantID: models.DefaultTenantID, Role: role}))
+	}
+	rec := httptest.NewRecorder()
+	h.DeleteEngine(rec, mux.SetURLVars(req, map[string]string{"id": uuid.NewString()}))
+	return rec
+}
+
+func TestDeleteEngineInUseListsCars(t *testing.T) {
+	car := models.Car{ID: uuid.New(), Name: "Civic", Brand: "Honda"}
+	h := NewEngineHandler(fakeEngines{err: &store.EngineInUseError{Cars: []models.Car{car}}})
+
+	rec := deleteEngine(h, "", auth.RoleUser)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409", rec.Code)
+	}
+	var body models.EngineInUse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Error != store.ErrEngineInUse.Error() || len(body.Cars) != 1 || body.Cars[0].ID != car.ID {
+		t.Errorf("body = %+v, want the error and the car using the engine", body)
+	}
+}
+
+func TestDeleteEngineCascade(t *testing.T) {
+	tests := []struct {
+		name string
+		role string
+		want int
+	}{
+		{"admin", auth.RoleAdmin, http.StatusNoContent},
+		{"user", auth.RoleUser, http.StatusForbidden},
+		{"anonymous", "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cascaded bool
+			rec := deleteEngine(NewEngineHandler(fakeEngines{cascaded: &cascaded}), "?cascade=true", tt.role)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if cascaded != (tt.want == http.StatusNoContent) {
+				t.Errorf("cascaded = %v with status %d", cascaded, rec.Code)
+			}
+		})
+	}
+}
+
+func TestDeleteEngineErrors(t *testing.T) {
+	for err, want := range map[error]int{
+		nil:                   http.StatusNoContent,
+		sql.ErrNoRows:         http.StatusNotFound,
+		store.ErrCarHasOrders: http.StatusConflict,
+	} {
+		if rec := deleteEngine(NewEngineHandler(fakeEngines{err: err}), "", auth.RoleUser); rec.Code != want {
+			t.Errorf("DeleteEngine with %v: status = %d, want %d", err, rec.Code, want)
+		}
+	}
+}
+
+func TestGetEngineCarsNotFound(t *testing.T) {
+	h := NewEngineHandler(fakeEngines{err: sql.ErrNoRows})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/engines/x/cars", nil)
+	h.GetEngineCars(rec, mux.SetURLVars(req, map[string]string{"id": uuid.NewString()}))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestCreateEngineReportsEveryFieldError(t *testing.T) {
+	h := NewEngineHandler(engineService.NewEngineService(memory.NewEngineStore(memory.NewDB())))
+	req := httptest.NewRequest(http.MethodPost, "/engines", strings.NewReader(`{"type":"bev","displacement":2000,"car_range":450}`))
+	rec := httptest.NewRecorder()
+	h.CreateEngine(rec, req.WithContext(auth.WithTenant(req.Context(), models.DefaultTenantID)))
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422: %s", rec.Code, rec.Body)
+	}
+	var body models.ValidationError
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Errors) != 2 || body.Errors[0].Pointer != "/displacement" || body.Errors[1].Pointer != "/motor" {
+		t.Errorf("errors = %+v, want /displacement and /motor", body.Errors)
+	}
+}
+
+func TestUpdateEnginePowertrainMismatch(t *testing.T) {
+	db := memory.NewDB()
+	ctx := auth.WithTenant(context.Background(), models.DefaultTenantID)
+	engine, err := memory.NewEngineStore(db).CreateEngine(ctx, models.Engine{Displacement: 2000, NoOfCylinders: 4, CarRange: 600})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = memory.NewCarStore(db).CreateCar(ctx, models.Car{
+		Name:     "Civic",
+		Year:     "2023",
+		Brand:    "Honda",
+		FuelType: "Petrol",
+		Engine:   models.Engine{EngineID: engine.EngineID},
+		Price:    models.Money{Amount: 2500000, Currency: "USD"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewEngineHandler(engineService.NewEngineService(memory.NewEngineStore(db)))
+	body := `{"type":"bev","car_range":450,"motor":{"battery_kwh":75,"charging_power_kw":170,"efficiency_wh_per_km":160}}`
+	req := httptest.NewRequest(http.MethodPut, "/engines/x", strings.NewReader(body)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	h.UpdateEngine(rec, mux.SetURLVars(req, map[string]string{"id": engine.EngineID.String()}))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("turning a petrol car's engine into a bev: status = %d, want 409: %s", rec.Code, rec.Body)
+	}
+}
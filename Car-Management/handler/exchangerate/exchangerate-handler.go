@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/handler"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+type ExchangeRateHandler struct {
+	rateService service.ExchangeRateServiceInterface
+}
+
+func NewExchangeRateHandler(rateService service.ExchangeRateServiceInterface) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		rateService: rateService,
+	}
+}
+
+func (h *ExchangeRateHandler) GetExchangeRates(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("exchangerate-handler")
+	ctx, span := tracer.Start(r.Context(), "GetExchangeRates-Handler")
+	defer span.End()
+	rates, err := h.rateService.GetExchangeRates(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(rates)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// SetExchangeRate creates or replaces the rate of the currency in the path.
+func (h *ExchangeRateHandler) SetExchangeRate(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("exchangerate-handler")
+	ctx, span := tracer.Start(r.Context(), "SetExchangeRate-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	var request models.ExchangeRateRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	rate, err := h.rateService.SetExchangeRate(ctx, models.ExchangeRate{
+		Currency: strings.ToUpper(vars["currency"]),
+		Rate:     request.Rate,
+	})
+	if handler.WriteValidationError(w, err) {
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(rate)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *ExchangeRateHandler) DeleteExchangeRate(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("exchangerate-handler")
+	ctx, span := tracer.Start(r.Context(), "DeleteExchangeRate-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	if err := h.rateService.DeleteExchangeRate(ctx, strings.ToUpper(vars["currency"])); err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, store.ErrExchangeRateNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, store.ErrBaseCurrencyRate):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	exchangeRateService "github.com/nitesh111sinha/car-management/service/exchangerate"
+	"github.com/nitesh111sinha/car-management/store/exchangerate"
+	"github.com/nitesh111sinha/car-management/store/storetest"
+)
+
+func TestExchangeRateEndpoints(t *testing.T) {
+	h := NewExchangeRateHandler(exchangeRateService.NewExchangeRateService(exchangerate.NewExchangeRateStore(storetest.OpenSQLite(t))))
+	serve := func(fn http.HandlerFunc, method, currency, body string) int {
+		req := httptest.NewRequest(method, "/admin/exchange-rates/"+currency, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		fn(rec, mux.SetURLVars(req, map[string]string{"currency": currency}))
+		return rec.Code
+	}
+
+	tests := []struct {
+		name     string
+		fn       http.HandlerFunc
+		method   string
+		currency string
+		body     string
+		want     int
+	}{
+		{"set", h.SetExchangeRate, http.MethodPut, "eur", `{"rate":0.92}`, http.StatusOK},
+		{"malformed body", h.SetExchangeRate, http.MethodPut, "EUR", `{"rate":`, http.StatusBadRequest},
+		{"invalid rate", h.SetExchangeRate, http.MethodPut, "EUR", `{"rate":0}`, http.StatusUnprocessableEntity},
+		{"unknown currency", h.SetExchangeRate, http.MethodPut, "XYZ", `{"rate":1}`, http.StatusUnprocessableEntity},
+		{"base currency", h.SetExchangeRate, http.MethodPut, "USD", `{"rate":2}`, http.StatusConflict},
+		{"delete", h.DeleteExchangeRate, http.MethodDelete, "EUR", "", http.StatusNoContent},
+		{"delete again", h.DeleteExchangeRate, http.MethodDelete, "EUR", "", http.StatusNotFound},
+		{"delete base currency", h.DeleteExchangeRate, http.MethodDelete, "usd", "", http.StatusConflict},
+	}
+	for _, tt := range tests {
+		if got := serve(tt.fn, tt.method, tt.currency, tt.body); got != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/nitesh111sinha/car-management/driver"
+	"github.com/nitesh111sinha/car-management/store/migrations"
+)
+
+// DatabaseCheck pings the primary database pool.
+func DatabaseCheck(db *driver.DB) Check {
+	return Check{
+		Name:     "database",
+		Critical: true,
+		Run:      db.PingContext,
+	}
+}
+
+// MigrationsCheck fails while any known migration has not been applied, for
+// example while another instance is still migrating the schema.
+func MigrationsCheck(db *driver.DB) Check {
+	return Check{
+		Name:     "migrations",
+		Critical: true,
+		Run: func(ctx context.Context) error {
+			status, err := migrations.Status(ctx, db)
+			if err != nil {
+				return err
+			}
+			pending := 0
+			for _, migration := range status {
+				if !migration.Applied() {
+					pending++
+				}
+			}
+			if pending > 0 {
+				return fmt.Errorf("%d pending migrations", pending)
+			}
+			return nil
+		},
+	}
+}
+
+// TraceExporterCheck verifies the trace collector accepts TCP connections.
+// Losing traces doesn't stop the service from doing its job, so the check is
+// not critical.
+func TraceExporterCheck(endpoint string) Check {
+	return Check{
+		Name:     "trace_exporter",
+		Critical: false,
+		Run: func(ctx context.Context) error {
+			var dialer net.Dialer
+			conn, err := dialer.DialContext(ctx, "tcp", endpoint)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		},
+	}
+}
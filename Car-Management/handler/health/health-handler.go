@@ -0,0 +1,125 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Check is a single readiness dependency. A failing critical check makes the
+// instance not ready; a failing non-critical check is reported as degraded
+// but does not take the instance out of rotation.
+type Check struct {
+	Name     string
+	Critical bool
+	Run      func(ctx context.Context) error
+}
+
+type CheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type ReadinessResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+type HealthHandler struct {
+	checks       []Check
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+// NewHealthHandler returns a handler that runs checks on every readiness
+// probe, giving each at most timeout to complete.
+func NewHealthHandler(timeout time.Duration, checks ...Check) *HealthHandler {
+	return &HealthHandler{
+		checks:  checks,
+		timeout: timeout,
+	}
+}
+
+// SetShuttingDown makes every later readiness probe fail, so load balancers
+// stop routing new traffic while in-flight requests drain.
+func (h *HealthHandler) SetShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// Liveness reports that the process is up and serving HTTP. It deliberately
+// checks no dependencies, so a database outage doesn't get the pod restarted.
+func (h *HealthHandler) Liveness(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// Readiness runs every dependency check concurrently and reports per-check
+// status and latency. It responds 503 when shutting down or when any critical
+// check fails.
+func (h *HealthHandler) Readiness(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	response := ReadinessResponse{
+		Status: "ready",
+		Checks: make(map[string]CheckResult, len(h.checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func(check Check) {
+			defer wg.Done()
+			result, ok := runCheck(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			response.Checks[check.Name] = result
+			if !ok && check.Critical {
+				response.Status = "not_ready"
+			}
+		}(check)
+	}
+	wg.Wait()
+
+	if h.shuttingDown.Load() {
+		response.Status = "shutting_down"
+	}
+
+	status := http.StatusOK
+	if response.Status != "ready" {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+func runCheck(ctx context.Context, check Check) (CheckResult, bool) {
+	start := time.Now()
+	err := check.Run(ctx)
+	result := CheckResult{
+		Status:    "up",
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err == nil {
+		return result, true
+	}
+
+	result.Error = err.Error()
+	if check.Critical {
+		result.Status = "down"
+	} else {
+		result.Status = "degraded"
+	}
+	return result, false
+}
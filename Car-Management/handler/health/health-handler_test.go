@@ -0,0 +1,100 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func readiness(t *testing.T, h *HealthHandler) (int, ReadinessResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.Readiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var response ReadinessResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	return rec.Code, response
+}
+
+func check(name string, critical bool, err error) Check {
+	return Check{Name: name, Critical: critical, Run: func(ctx context.Context) error { return err }}
+}
+
+func TestLivenessChecksNothing(t *testing.T) {
+	h := NewHealthHandler(time.Second, check("database", true, errors.New("down")))
+	rec := httptest.NewRecorder()
+	h.Liveness(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200 while a dependency is down", rec.Code)
+	}
+}
+
+func TestReadiness(t *testing.T) {
+	tests := []struct {
+		name       string
+		checks     []Check
+		wantCode   int
+		wantStatus string
+		wantChecks map[string]string
+	}{
+		{
+			name:       "all up",
+			checks:     []Check{check("database", true, nil), check("trace_exporter", false, nil)},
+			wantCode:   http.StatusOK,
+			wantStatus: "ready",
+			wantChecks: map[string]string{"database": "up", "trace_exporter": "up"},
+		},
+		{
+			name:       "non-critical failure degrades",
+			checks:     []Check{check("database", true, nil), check("trace_exporter", false, errors.New("refused"))},
+			wantCode:   http.StatusOK,
+			wantStatus: "ready",
+			wantChecks: map[string]string{"database": "up", "trace_exporter": "degraded"},
+		},
+		{
+			name:       "critical failure",
+			checks:     []Check{check("database", true, errors.New("refused")), check("trace_exporter", false, nil)},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: "not_ready",
+			wantChecks: map[string]string{"database": "down", "trace_exporter": "up"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, response := readiness(t, NewHealthHandler(time.Second, tt.checks...))
+			if code != tt.wantCode || response.Status != tt.wantStatus {
+				t.Errorf("readiness = %d %q, want %d %q", code, response.Status, tt.wantCode, tt.wantStatus)
+			}
+			for name, want := range tt.wantChecks {
+				if got := response.Checks[name].Status; got != want {
+					t.Errorf("check %s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReadinessTimesOutSlowChecks(t *testing.T) {
+	slow := Check{Name: "database", Critical: true, Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+	code, response := readiness(t, NewHealthHandler(10*time.Millisecond, slow))
+	if code != http.StatusServiceUnavailable || response.Checks["database"].Error == "" {
+		t.Errorf("readiness = %d %+v, want 503 with the timeout reported", code, response)
+	}
+}
+
+func TestReadinessFailsWhileShuttingDown(t *testing.T) {
+	h := NewHealthHandler(time.Second, check("database", true, nil))
+	h.SetShuttingDown()
+	code, response := readiness(t, h)
+	if code != http.StatusServiceUnavailable || response.Status != "shutting_down" {
+		t.Errorf("readiness = %d %q, want 503 shutting_down", code, response.Status)
+	}
+}
@@ -2,46 +2,61 @@ package login
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/config"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
 )
 
-// Request body
+// Request body. Every user belongs to one dealership; TenantID is optional
+// and, when given, must be that dealership.
 type Credentials struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
+	TenantID string `json:"tenant_id"`
 }
 
-// JWT claims (MUST match middleware)
-type Claims struct {
-	Username string `json:"username"`
-	jwt.RegisteredClaims
+type LoginHandler struct {
+	auth  config.Auth
+	users service.UserServiceInterface
 }
 
-func GenerateToken(username string) (string, error) {
-	claims := Claims{
-		Username: username,
+func NewLoginHandler(auth config.Auth, users service.UserServiceInterface) *LoginHandler {
+	return &LoginHandler{
+		auth:  auth,
+		users: users,
+	}
+}
+
+func GenerateToken(cfg config.Auth, principal auth.Principal) (string, error) {
+	now := time.Now()
+	claims := auth.Claims{
+		Username: principal.Username,
+		TenantID: principal.TenantID,
+		Role:     principal.Role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(cfg.TokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
+	if cfg.JWTSecret == "" {
 		return "", jwt.ErrSignatureInvalid
 	}
 
-	return token.SignedString([]byte(secret))
+	return token.SignedString([]byte(cfg.JWTSecret))
 }
 
-func LoginHandler(w http.ResponseWriter, r *http.Request) {
+func (h *LoginHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var credentials Credentials
 
 	if err := json.NewDecoder(r.Body).Decode(&credentials); err != nil {
@@ -49,13 +64,36 @@ func LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Demo validation (replace with DB later)
-	if credentials.Username != "admin" || credentials.Password != "admin123" {
-		http.Error(w, "invalid credentials", http.StatusUnauthorized)
+	var tenantID uuid.UUID
+	if credentials.TenantID != "" {
+		id, err := uuid.Parse(credentials.TenantID)
+		if err != nil {
+			http.Error(w, "invalid tenant_id", http.StatusBadRequest)
+			return
+		}
+		tenantID = id
+	}
+
+	user, err := h.users.Authenticate(r.Context(), credentials.Username, credentials.Password)
+	if err != nil {
+		if errors.Is(err, store.ErrInvalidCredentials) {
+			http.Error(w, "invalid credentials", http.StatusUnauthorized)
+			return
+		}
+		http.Error(w, "failed to look up user", http.StatusInternalServerError)
+		return
+	}
+	// The tenant and role always come from the user, never from the request.
+	if tenantID != uuid.Nil && tenantID != user.TenantID {
+		http.Error(w, "user does not belong to tenant_id", http.StatusForbidden)
 		return
 	}
 
-	tokenString, err := GenerateToken(credentials.Username)
+	tokenString, err := GenerateToken(h.auth, auth.Principal{
+		Username: user.Username,
+		TenantID: user.TenantID,
+		Role:     user.Role,
+	})
 	if err != nil {
 		http.Error(w, "failed to generate token", http.StatusInternalServerError)
 		return
@@ -0,0 +1,100 @@
+package login
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/config"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
+)
+
+var testAuth = config.Auth{JWTSecret: "test-secret", TokenTTL: time.Hour}
+
+// fakeUsers knows a single user, alice, whose password is "secret123".
+type fakeUsers struct {
+	service.UserServiceInterface
+	user models.User
+}
+
+func (f fakeUsers) Authenticate(ctx context.Context, username, password string) (models.User, error) {
+	if username != f.user.Username || password != "secret123" {
+		return models.User{}, store.ErrInvalidCredentials
+	}
+	return f.user, nil
+}
+
+func login(body string) *httptest.ResponseRecorder {
+	h := NewLoginHandler(testAuth, fakeUsers{user: models.User{
+		Username: "alice",
+		TenantID: models.DefaultTenantID,
+		Role:     auth.RoleUser,
+	}})
+	rec := httptest.NewRecorder()
+	h.Login(rec, httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body)))
+	return rec
+}
+
+func TestLoginIssuesTokenForUsersTenantAndRole(t *testing.T) {
+	for _, body := range []string{
+		`{"username":"alice","password":"secret123"}`,
+		`{"username":"alice","password":"secret123","tenant_id":"` + models.DefaultTenantID.String() + `"}`,
+	} {
+		rec := login(body)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("login %s: status %d: %s", body, rec.Code, rec.Body)
+		}
+		var response struct {
+			Token string `json:"token"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		var claims auth.Claims
+		_, err := jwt.ParseWithClaims(response.Token, &claims, func(*jwt.Token) (any, error) {
+			return []byte(testAuth.JWTSecret), nil
+		})
+		if err != nil {
+			t.Fatalf("parsing token: %v", err)
+		}
+		if claims.Username != "alice" || claims.TenantID != models.DefaultTenantID || claims.Role != auth.RoleUser {
+			t.Errorf("claims = %+v, want alice, the default tenant and role user", claims)
+		}
+	}
+}
+
+func TestLoginErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"malformed body", `{"username":`, http.StatusBadRequest},
+		{"invalid tenant_id", `{"username":"alice","password":"secret123","tenant_id":"acme"}`, http.StatusBadRequest},
+		{"wrong password", `{"username":"alice","password":"secret456"}`, http.StatusUnauthorized},
+		{"unknown user", `{"username":"bob","password":"secret123"}`, http.StatusUnauthorized},
+		{"other tenant", `{"username":"alice","password":"secret123","tenant_id":"` + uuid.NewString() + `"}`, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := login(tt.body); rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestGenerateTokenRequiresSecret(t *testing.T) {
+	if _, err := GenerateToken(config.Auth{TokenTTL: time.Hour}, auth.Principal{Username: "alice"}); err == nil {
+		t.Error("GenerateToken signed a token without a secret")
+	}
+}
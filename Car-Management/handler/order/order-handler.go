@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/handler"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+type OrderHandler struct {
+	orderService service.OrderServiceInterface
+}
+
+func NewOrderHandler(orderService service.OrderServiceInterface) *OrderHandler {
+	return &OrderHandler{
+		orderService: orderService,
+	}
+}
+
+// CreateOrder drafts an order for the car in the path.
+func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("order-handler")
+	ctx, span := tracer.Start(r.Context(), "CreateOrder-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	carID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var request models.OrderRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if handler.WriteValidationError(w, models.ValidateOrderRequest(&request)) {
+		return
+	}
+	order, err := h.orderService.CreateOrder(ctx, models.Order{
+		CarID:        carID,
+		CustomerName: request.CustomerName,
+		CustomerID:   request.CustomerID,
+		AgreedPrice:  request.AgreedPrice,
+		Deposit:      request.Deposit,
+		Notes:        request.Notes,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(order)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetOrders lists the tenant's orders, optionally filtered by the status
+// query parameter.
+func (h *OrderHandler) GetOrders(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("order-handler")
+	ctx, span := tracer.Start(r.Context(), "GetOrders-Handler")
+	defer span.End()
+	status := r.URL.Query().Get("status")
+	if status != "" && !models.ValidOrderStatus(status) {
+		http.Error(w, "status must be draft, confirmed, paid, delivered or cancelled", http.StatusBadRequest)
+		return
+	}
+	orders, err := h.orderService.GetOrders(ctx, status)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(orders)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *OrderHandler) GetOrdersByCar(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("order-handler")
+	ctx, span := tracer.Start(r.Context(), "GetOrdersByCar-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	orders, err := h.orderService.GetOrdersByCar(ctx, vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(orders)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *OrderHandler) GetOrderById(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("order-handler")
+	ctx, span := tracer.Start(r.Context(), "GetOrderById-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	order, err := h.orderService.GetOrderById(ctx, vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(order)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// UpdateOrder changes a draft order.
+func (h *OrderHandler) UpdateOrder(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("order-handler")
+	ctx, span := tracer.Start(r.Context(), "UpdateOrder-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	orderID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var request models.OrderRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if handler.WriteValidationError(w, models.ValidateOrderRequest(&request)) {
+		return
+	}
+	order, err := h.orderService.UpdateOrder(ctx, models.Order{
+		ID:           orderID,
+		CustomerName: request.CustomerName,
+		CustomerID:   request.CustomerID,
+		AgreedPrice:  request.AgreedPrice,
+		Deposit:      request.Deposit,
+		Notes:        request.Notes,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(order)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// ConfirmOrder confirms a draft, reserving a stock unit of the car.
+func (h *OrderHandler) ConfirmOrder(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, "ConfirmOrder-Handler", models.OrderConfirmed)
+}
+
+func (h *OrderHandler) PayOrder(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, "PayOrder-Handler", models.OrderPaid)
+}
+
+// DeliverOrder hands the car over, selling the reserved stock unit.
+func (h *OrderHandler) DeliverOrder(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, "DeliverOrder-Handler", models.OrderDelivered)
+}
+
+// CancelOrder cancels an order that has not been delivered, putting its
+// stock unit back in stock.
+func (h *OrderHandler) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, "CancelOrder-Handler", models.OrderCancelled)
+}
+
+// transition moves the order in the path to status. The request body, a
+// models.TransitionRequest, is optional.
+func (h *OrderHandler) transition(w http.ResponseWriter, r *http.Request, spanName, status string) {
+	tracer := otel.Tracer("order-handler")
+	ctx, span := tracer.Start(r.Context(), spanName)
+	defer span.End()
+	vars := mux.Vars(r)
+	var request models.TransitionRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	order, err := h.orderService.TransitionOrder(ctx, vars["id"], status, request)
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(order)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetOrderTransitions returns the audit trail of the order in the path.
+func (h *OrderHandler) GetOrderTransitions(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("order-handler")
+	ctx, span := tracer.Start(r.Context(), "GetOrderTransitions-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	transitions, err := h.orderService.GetOrderTransitions(ctx, vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(transitions)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, store.ErrInvalidCustomer):
+		return http.StatusBadRequest
+	case errors.Is(err, store.ErrCarNotFound), errors.Is(err, store.ErrOrderNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, store.ErrOrderNotDraft), errors.Is(err, store.ErrInvalidOrderTransition),
+		errors.Is(err, store.ErrNoStockAvailable), errors.Is(err, store.ErrStockUnitUnavailable):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
+)
+
+// fakeOrders fails every call with err and records the last status an
+// order was moved to.
+type fakeOrders struct {
+	service.OrderServiceInterface
+	err    error
+	status *string
+}
+
+func (f fakeOrders) CreateOrder(ctx context.Context, order models.Order) (models.Order, error) {
+	return order, f.err
+}
+
+func (f fakeOrders) GetOrders(ctx context.Context, status string) ([]models.Order, error) {
+	return []models.Order{}, f.err
+}
+
+func (f fakeOrders) UpdateOrder(ctx context.Context, order models.Order) (models.Order, error) {
+	return order, f.err
+}
+
+func (f fakeOrders) TransitionOrder(ctx context.Context, orderID, status string, request models.TransitionRequest) (models.Order, error) {
+	if f.status != nil {
+		*f.status = status
+	}
+	return models.Order{Status: status}, f.err
+}
+
+func (f fakeOrders) GetOrderTransitions(ctx context.Context, orderID string) ([]models.OrderTransition, error) {
+	return []models.OrderTransition{}, f.err
+}
+
+func serve(fn http.HandlerFunc, target, body string, vars map[string]string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	fn(rec, mux.SetURLVars(httptest.NewRequest(http.MethodPost, target, strings.NewReader(body)), vars))
+	return rec
+}
+
+const validOrder = `{"customer_name":"Alex","agreed_price":{"amount":2400000,"currency":"USD"},"deposit":{"amount":100000}}`
+
+func TestCreateOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		carID string
+		body  string
+		err   error
+		want  int
+	}{
+		{"drafted", uuid.NewString(), validOrder, nil, http.StatusCreated},
+		{"invalid car id", "civic", validOrder, nil, http.StatusBadRequest},
+		{"malformed body", uuid.NewString(), `{"customer_name":`, nil, http.StatusBadRequest},
+		{"invalid order", uuid.NewString(), `{"agreed_price":{"amount":100,"currency":"USD"},"deposit":{"amount":200}}`, nil, http.StatusUnprocessableEntity},
+		{"unknown customer", uuid.NewString(), validOrder, store.ErrInvalidCustomer, http.StatusBadRequest},
+		{"unknown car", uuid.NewString(), validOrder, store.ErrCarNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(NewOrderHandler(fakeOrders{err: tt.err}).CreateOrder, "/", tt.body, map[string]string{"id": tt.carID})
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestUpdateOrder(t *testing.T) {
+	for err, want := range map[error]int{
+		nil:                    http.StatusOK,
+		store.ErrOrderNotFound: http.StatusNotFound,
+		store.ErrOrderNotDraft: http.StatusConflict,
+	} {
+		rec := serve(NewOrderHandler(fakeOrders{err: err}).UpdateOrder, "/", validOrder, map[string]string{"id": uuid.NewString()})
+		if rec.Code != want {
+			t.Errorf("%v: status = %d, want %d", err, rec.Code, want)
+		}
+	}
+}
+
+func TestTransitions(t *testing.T) {
+	var status string
+	h := NewOrderHandler(fakeOrders{status: &status})
+	vars := map[string]string{"id": uuid.NewString()}
+	for fn, want := range map[string]struct {
+		handler http.HandlerFunc
+		status  string
+	}{
+		"confirm": {h.ConfirmOrder, models.OrderConfirmed},
+		"pay":     {h.PayOrder, models.OrderPaid},
+		"deliver": {h.DeliverOrder, models.OrderDelivered},
+		"cancel":  {h.CancelOrder, models.OrderCancelled},
+	} {
+		// The body is optional.
+		if rec := serve(want.handler, "/", "", vars); rec.Code != http.StatusOK || status != want.status {
+			t.Errorf("%s: status %d moved the order to %q, want 200 and %q", fn, rec.Code, status, want.status)
+		}
+	}
+	if rec := serve(h.ConfirmOrder, "/", `{"stock_unit_id":"`+uuid.NewString()+`"}`, vars); rec.Code != http.StatusOK {
+		t.Errorf("confirm with a unit: status = %d, want 200", rec.Code)
+	}
+	if rec := serve(h.ConfirmOrder, "/", `{"note":`, vars); rec.Code != http.StatusBadRequest {
+		t.Errorf("malformed body: status = %d, want 400", rec.Code)
+	}
+
+	for err, want := range map[error]int{
+		store.ErrOrderNotFound:          http.StatusNotFound,
+		store.ErrInvalidOrderTransition: http.StatusConflict,
+		store.ErrNoStockAvailable:       http.StatusConflict,
+		store.ErrStockUnitUnavailable:   http.StatusConflict,
+	} {
+		if rec := serve(NewOrderHandler(fakeOrders{err: err}).ConfirmOrder, "/", "", vars); rec.Code != want {
+			t.Errorf("%v: status = %d, want %d", err, rec.Code, want)
+		}
+	}
+}
+
+func TestGetOrders(t *testing.T) {
+	h := NewOrderHandler(fakeOrders{})
+	if rec := serve(h.GetOrders, "/?status=paid", "", nil); rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rec.Code)
+	}
+	if rec := serve(h.GetOrders, "/?status=shipped", "", nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown status: status = %d, want 400", rec.Code)
+	}
+	if rec := serve(NewOrderHandler(fakeOrders{err: store.ErrOrderNotFound}).GetOrderTransitions, "/", "", map[string]string{"id": uuid.NewString()}); rec.Code != http.StatusNotFound {
+		t.Errorf("transitions of an unknown order: status = %d, want 404", rec.Code)
+	}
+}
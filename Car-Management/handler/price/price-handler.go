@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/service"
+	"go.opentelemetry.io/otel"
+)
+
+// defaultWindow is the analytics window used when none is given.
+const defaultWindow = 30 * 24 * time.Hour
+
+type PriceHandler struct {
+	priceService service.PriceServiceInterface
+}
+
+func NewPriceHandler(priceService service.PriceServiceInterface) *PriceHandler {
+	return &PriceHandler{
+		priceService: priceService,
+	}
+}
+
+// GetPriceHistory returns the car's price timeline, oldest change first.
+func (h *PriceHandler) GetPriceHistory(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("price-handler")
+	ctx, span := tracer.Start(r.Context(), "GetPriceHistory-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	id := vars["id"]
+	changes, err := h.priceService.GetPriceHistory(ctx, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(changes)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetPriceMovements reports price drops and increases per brand. The optional
+// window query parameter takes a duration such as 72h or a number of days
+// such as 30d.
+func (h *PriceHandler) GetPriceMovements(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("price-handler")
+	ctx, span := tracer.Start(r.Context(), "GetPriceMovements-Handler")
+	defer span.End()
+	window := defaultWindow
+	if value := r.URL.Query().Get("window"); value != "" {
+		parsed, err := parseWindow(value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		window = parsed
+	}
+	report, err := h.priceService.GetPriceMovements(ctx, window)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(report)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func parseWindow(value string) (time.Duration, error) {
+	errInvalid := errors.New("window must be a positive duration such as 72h or 30d")
+	var window time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, errInvalid
+		}
+		window = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, errInvalid
+		}
+		window = d
+	}
+	if window <= 0 {
+		return 0, errInvalid
+	}
+	return window, nil
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+)
+
+// fakePrices records the window it was asked for.
+type fakePrices struct {
+	service.PriceServiceInterface
+	window *time.Duration
+}
+
+func (f fakePrices) GetPriceMovements(ctx context.Context, window time.Duration) (models.PriceMovementReport, error) {
+	*f.window = window
+	return models.PriceMovementReport{}, nil
+}
+
+func TestGetPriceMovementsWindow(t *testing.T) {
+	tests := []struct {
+		query  string
+		want   int
+		window time.Duration
+	}{
+		{"", http.StatusOK, defaultWindow},
+		{"?window=72h", http.StatusOK, 72 * time.Hour},
+		{"?window=30d", http.StatusOK, 30 * 24 * time.Hour},
+		{"?window=0d", http.StatusBadRequest, 0},
+		{"?window=-1h", http.StatusBadRequest, 0},
+		{"?window=month", http.StatusBadRequest, 0},
+		{"?window=1.5d", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var window time.Duration
+			h := NewPriceHandler(fakePrices{window: &window})
+			rec := httptest.NewRecorder()
+			h.GetPriceMovements(rec, httptest.NewRequest(http.MethodGet, "/cars/price-movements"+tt.query, nil))
+			if rec.Code != tt.want || window != tt.window {
+				t.Errorf("status %d, window %s; want %d, %s", rec.Code, window, tt.want, tt.window)
+			}
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/handler"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	defaultAvailabilityDays = 7
+	maxAvailabilityDays     = 31
+	defaultSlot             = 30 * time.Minute
+	minSlot                 = 15 * time.Minute
+)
+
+type ReservationHandler struct {
+	reservationService service.ReservationServiceInterface
+}
+
+func NewReservationHandler(reservationService service.ReservationServiceInterface) *ReservationHandler {
+	return &ReservationHandler{
+		reservationService: reservationService,
+	}
+}
+
+func (h *ReservationHandler) GetLocations(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("reservation-handler")
+	ctx, span := tracer.Start(r.Context(), "GetLocations-Handler")
+	defer span.End()
+	locations, err := h.reservationService.GetLocations(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(locations)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *ReservationHandler) GetLocationHours(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("reservation-handler")
+	ctx, span := tracer.Start(r.Context(), "GetLocationHours-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	hours, err := h.reservationService.GetLocationHours(ctx, vars["location"])
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(hours)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// SetLocationHours creates the location in the path, or replaces its time
+// zone and weekly opening hours.
+func (h *ReservationHandler) SetLocationHours(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("reservation-handler")
+	ctx, span := tracer.Start(r.Context(), "SetLocationHours-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	var request models.LocationHoursRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if handler.WriteValidationError(w, models.ValidateLocationHours(request)) {
+		return
+	}
+	hours, err := h.reservationService.SetLocationHours(ctx, models.LocationHours{
+		Location: vars["location"],
+		TimeZone: request.TimeZone,
+		Hours:    request.Hours,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(hours)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// BookTestDrive reserves the car in the path for a test drive.
+func (h *ReservationHandler) BookTestDrive(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("reservation-handler")
+	ctx, span := tracer.Start(r.Context(), "BookTestDrive-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	carID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var request models.ReservationRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if handler.WriteValidationError(w, models.ValidateReservation(request, time.Now())) {
+		return
+	}
+	reservation, err := h.reservationService.BookTestDrive(ctx, models.Reservation{
+		CarID:        carID,
+		Location:     request.Location,
+		CustomerName: request.CustomerName,
+		CustomerID:   request.CustomerID,
+		Notes:        request.Notes,
+		StartsAt:     request.StartsAt,
+		EndsAt:       request.EndsAt,
+	})
+	if handler.WriteValidationError(w, err) {
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(reservation)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *ReservationHandler) GetReservationsByCar(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("reservation-handler")
+	ctx, span := tracer.Start(r.Context(), "GetReservationsByCar-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	reservations, err := h.reservationService.GetReservationsByCar(ctx, vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(reservations)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *ReservationHandler) GetReservationById(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("reservation-handler")
+	ctx, span := tracer.Start(r.Context(), "GetReservationById-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	reservation, err := h.reservationService.GetReservationById(ctx, vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(reservation)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// RescheduleReservation moves the test drive in the path to another time,
+// and optionally another location.
+func (h *ReservationHandler) RescheduleReservation(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("reservation-handler")
+	ctx, span := tracer.Start(r.Context(), "RescheduleReservation-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	var request models.RescheduleRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if handler.WriteValidationError(w, models.ValidateReschedule(request, time.Now())) {
+		return
+	}
+	reservation, err := h.reservationService.RescheduleReservation(ctx, vars["id"], request)
+	if handler.WriteValidationError(w, err) {
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(reservation)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *ReservationHandler) CancelReservation(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("reservation-handler")
+	ctx, span := tracer.Start(r.Context(), "CancelReservation-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	reservation, err := h.reservationService.CancelReservation(ctx, vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(reservation)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetAvailability lists the free test drive slots of the car in the path.
+// The location query parameter is required; from (a date such as
+// 2024-06-01, default today), days (default 7) and slot (a duration such as
+// 45m, default 30m) are optional.
+func (h *ReservationHandler) GetAvailability(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("reservation-handler")
+	ctx, span := tracer.Start(r.Context(), "GetAvailability-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	carID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query := r.URL.Query()
+	location := query.Get("location")
+	if location == "" {
+		http.Error(w, "location is required", http.StatusBadRequest)
+		return
+	}
+	var from time.Time
+	if value := query.Get("from"); value != "" {
+		from, err = time.Parse(time.DateOnly, value)
+		if err != nil {
+			http.Error(w, "from must be a date such as 2024-06-01", http.StatusBadRequest)
+			return
+		}
+	}
+	days := defaultAvailabilityDays
+	if value := query.Get("days"); value != "" {
+		days, err = strconv.Atoi(value)
+		if err != nil || days < 1 || days > maxAvailabilityDays {
+			http.Error(w, "days must be a number between 1 and "+strconv.Itoa(maxAvailabilityDays), http.StatusBadRequest)
+			return
+		}
+	}
+	slot := defaultSlot
+	if value := query.Get("slot"); value != "" {
+		slot, err = time.ParseDuration(value)
+		if err != nil || slot < minSlot || slot > models.MaxTestDrive || slot%time.Minute != 0 {
+			http.Error(w, "slot must be a whole number of minutes between "+minSlot.String()+" and "+models.MaxTestDrive.String(), http.StatusBadRequest)
+			return
+		}
+	}
+	availability, err := h.reservationService.GetAvailability(ctx, carID, location, from, days, slot)
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(availability)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, store.ErrInvalidCustomer):
+		return http.StatusBadRequest
+	case errors.Is(err, store.ErrCarNotFound), errors.Is(err, store.ErrReservationNotFound), errors.Is(err, store.ErrLocationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, store.ErrReservationConflict), errors.Is(err, store.ErrReservationCancelled):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
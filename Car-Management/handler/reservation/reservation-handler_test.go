@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
+)
+
+// fakeReservations fails every call with err and records the availability
+// query it was asked.
+type fakeReservations struct {
+	service.ReservationServiceInterface
+	err  error
+	from *time.Time
+	days *int
+	slot *time.Duration
+}
+
+func (f fakeReservations) SetLocationHours(ctx context.Context, hours models.LocationHours) (models.LocationHours, error) {
+	return hours, f.err
+}
+
+func (f fakeReservations) BookTestDrive(ctx context.Context, reservation models.Reservation) (models.Reservation, error) {
+	return reservation, f.err
+}
+
+func (f fakeReservations) RescheduleReservation(ctx context.Context, reservationID string, request models.RescheduleRequest) (models.Reservation, error) {
+	return models.Reservation{}, f.err
+}
+
+func (f fakeReservations) CancelReservation(ctx context.Context, reservationID string) (models.Reservation, error) {
+	return models.Reservation{}, f.err
+}
+
+func (f fakeReservations) GetAvailability(ctx context.Context, carID uuid.UUID, location string, from time.Time, days int, slot time.Duration) (models.SlotAvailability, error) {
+	if f.from != nil {
+		*f.from, *f.days, *f.slot = from, days, slot
+	}
+	return models.SlotAvailability{}, f.err
+}
+
+func serve(fn http.HandlerFunc, method, target, body string, vars map[string]string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	fn(rec, mux.SetURLVars(httptest.NewRequest(method, target, strings.NewReader(body)), vars))
+	return rec
+}
+
+// testDrive returns a booking request for an hour starting tomorrow.
+func testDrive() string {
+	start := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Hour)
+	return `{"location":"Showroom","customer_name":"Alex","starts_at":"` + start.Format(time.RFC3339) + `","ends_at":"` + start.Add(time.Hour).Format(time.RFC3339) + `"}`
+}
+
+func TestBookTestDrive(t *testing.T) {
+	tests := []struct {
+		name  string
+		carID string
+		body  string
+		err   error
+		want  int
+	}{
+		{"booked", uuid.NewString(), testDrive(), nil, http.StatusCreated},
+		{"invalid car id", "civic", testDrive(), nil, http.StatusBadRequest},
+		{"malformed body", uuid.NewString(), `{"location":`, nil, http.StatusBadRequest},
+		{"missing fields", uuid.NewString(), `{}`, nil, http.StatusUnprocessableEntity},
+		{"in the past", uuid.NewString(), `{"location":"Showroom","customer_name":"Alex","starts_at":"2020-01-01T10:00:00Z","ends_at":"2020-01-01T11:00:00Z"}`, nil, http.StatusUnprocessableEntity},
+		{"outside opening hours", uuid.NewString(), testDrive(), &models.ValidationError{}, http.StatusUnprocessableEntity},
+		{"unknown customer", uuid.NewString(), testDrive(), store.ErrInvalidCustomer, http.StatusBadRequest},
+		{"unknown car", uuid.NewString(), testDrive(), store.ErrCarNotFound, http.StatusNotFound},
+		{"unknown location", uuid.NewString(), testDrive(), store.ErrLocationNotFound, http.StatusNotFound},
+		{"already booked", uuid.NewString(), testDrive(), store.ErrReservationConflict, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewReservationHandler(fakeReservations{err: tt.err})
+			rec := serve(h.BookTestDrive, http.MethodPost, "/", tt.body, map[string]string{"id": tt.carID})
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestRescheduleAndCancelReservation(t *testing.T) {
+	for err, want := range map[error]int{
+		nil:                           http.StatusOK,
+		store.ErrReservationNotFound:  http.StatusNotFound,
+		store.ErrReservationConflict:  http.StatusConflict,
+		store.ErrReservationCancelled: http.StatusConflict,
+	} {
+		h := NewReservationHandler(fakeReservations{err: err})
+		vars := map[string]string{"id": uuid.NewString()}
+		if rec := serve(h.RescheduleReservation, http.MethodPut, "/", testDrive(), vars); rec.Code != want {
+			t.Errorf("reschedule, %v: status = %d, want %d", err, rec.Code, want)
+		}
+		if err == store.ErrReservationConflict {
+			continue
+		}
+		if rec := serve(h.CancelReservation, http.MethodPost, "/", "", vars); rec.Code != want {
+			t.Errorf("cancel, %v: status = %d, want %d", err, rec.Code, want)
+		}
+	}
+
+	h := NewReservationHandler(fakeReservations{})
+	if rec := serve(h.RescheduleReservation, http.MethodPut, "/", `{}`, map[string]string{"id": uuid.NewString()}); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("reschedule without a time: status = %d, want 422", rec.Code)
+	}
+}
+
+func TestSetLocationHours(t *testing.T) {
+	h := NewReservationHandler(fakeReservations{})
+	vars := map[string]string{"location": "Showroom"}
+	if rec := serve(h.SetLocationHours, http.MethodPut, "/", `{"time_zone":"Europe/Berlin","hours":[{"weekday":1,"opens":"09:00","closes":"17:00"}]}`, vars); rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if rec := serve(h.SetLocationHours, http.MethodPut, "/", `{"time_zone":"Mars/Olympus"}`, vars); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("unknown time zone: status = %d, want 422", rec.Code)
+	}
+	if rec := serve(h.SetLocationHours, http.MethodPut, "/", `{"time_zone":`, vars); rec.Code != http.StatusBadRequest {
+		t.Errorf("malformed body: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestGetAvailability(t *testing.T) {
+	var from time.Time
+	var days int
+	var slot time.Duration
+	h := NewReservationHandler(fakeReservations{from: &from, days: &days, slot: &slot})
+	vars := map[string]string{"id": uuid.NewString()}
+
+	if rec := serve(h.GetAvailability, http.MethodGet, "/?location=Showroom", "", vars); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if !from.IsZero() || days != defaultAvailabilityDays || slot != defaultSlot {
+		t.Errorf("defaults = %v, %d days, %v slots; want today, %d days, %v slots", from, days, slot, defaultAvailabilityDays, defaultSlot)
+	}
+	if rec := serve(h.GetAvailability, http.MethodGet, "/?location=Showroom&from=2026-06-01&days=3&slot=45m", "", vars); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if from.Format(time.DateOnly) != "2026-06-01" || days != 3 || slot != 45*time.Minute {
+		t.Errorf("query = %v, %d days, %v slots; want 2026-06-01, 3 days, 45m slots", from, days, slot)
+	}
+
+	for _, target := range []string{
+		"/",
+		"/?location=Showroom&from=June",
+		"/?location=Showroom&days=0",
+		"/?location=Showroom&days=32",
+		"/?location=Showroom&slot=10m",
+		"/?location=Showroom&slot=5h",
+		"/?location=Showroom&slot=30m30s",
+	} {
+		if rec := serve(h.GetAvailability, http.MethodGet, target, "", vars); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", target, rec.Code)
+		}
+	}
+	if rec := serve(h.GetAvailability, http.MethodGet, "/?location=Showroom", "", map[string]string{"id": "civic"}); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid car id: status = %d, want 400", rec.Code)
+	}
+	for err, want := range map[error]int{
+		store.ErrCarNotFound:      http.StatusNotFound,
+		store.ErrLocationNotFound: http.StatusNotFound,
+	} {
+		h := NewReservationHandler(fakeReservations{err: err})
+		if rec := serve(h.GetAvailability, http.MethodGet, "/?location=Showroom", "", vars); rec.Code != want {
+			t.Errorf("%v: status = %d, want %d", err, rec.Code, want)
+		}
+	}
+}
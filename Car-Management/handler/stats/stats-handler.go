@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"go.opentelemetry.io/otel"
+)
+
+type StatsHandler struct {
+	statsService service.StatsServiceInterface
+}
+
+func NewStatsHandler(statsService service.StatsServiceInterface) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+	}
+}
+
+// GetCarStats serves inventory statistics. Optional query parameters brand,
+// fuel_type, year_from, year_to, min_price and max_price filter the cars
+// included.
+func (h *StatsHandler) GetCarStats(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("stats-handler")
+	ctx, span := tracer.Start(r.Context(), "GetCarStats-Handler")
+	defer span.End()
+	query := r.URL.Query()
+	filter := models.CarStatsFilter{
+		Brand:    query.Get("brand"),
+		FuelType: query.Get("fuel_type"),
+		YearFrom: query.Get("year_from"),
+		YearTo:   query.Get("year_to"),
+	}
+	for _, param := range []struct {
+		name string
+		dst  **float64
+	}{
+		{"min_price", &filter.MinPrice},
+		{"max_price", &filter.MaxPrice},
+	} {
+		value := query.Get(param.name)
+		if value == "" {
+			continue
+		}
+		price, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			http.Error(w, param.name+" must be a number", http.StatusBadRequest)
+			return
+		}
+		*param.dst = &price
+	}
+	if err := models.ValidateCarStatsFilter(filter); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stats, err := h.statsService.GetCarStats(ctx, filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(stats)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
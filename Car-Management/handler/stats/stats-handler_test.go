@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nitesh111sinha/car-management/models"
+)
+
+// fakeStats records the filter it was asked for.
+type fakeStats struct {
+	filter *models.CarStatsFilter
+}
+
+func (f fakeStats) GetCarStats(ctx context.Context, filter models.CarStatsFilter) (models.CarStats, error) {
+	*f.filter = filter
+	return models.CarStats{Currency: models.BaseCurrency}, nil
+}
+
+func TestGetCarStatsParsesFilter(t *testing.T) {
+	var filter models.CarStatsFilter
+	h := NewStatsHandler(fakeStats{filter: &filter})
+	rec := httptest.NewRecorder()
+	h.GetCarStats(rec, httptest.NewRequest(http.MethodGet, "/stats/cars?brand=vw&fuel_type=Petrol&year_from=2019&year_to=2023&min_price=10000&max_price=25000.50", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if filter.Brand != "vw" || filter.FuelType != "Petrol" || filter.YearFrom != "2019" || filter.YearTo != "2023" ||
+		filter.MinPrice == nil || *filter.MinPrice != 10000 || filter.MaxPrice == nil || *filter.MaxPrice != 25000.50 {
+		t.Errorf("filter = %+v, want every parameter", filter)
+	}
+}
+
+func TestGetCarStatsRejectsInvalidFilters(t *testing.T) {
+	for _, query := range []string{
+		"min_price=cheap",
+		"max_price=1e",
+		"year_from=19",
+		"year_from=2023&year_to=2019",
+		"min_price=30000&max_price=20000",
+	} {
+		var filter models.CarStatsFilter
+		h := NewStatsHandler(fakeStats{filter: &filter})
+		rec := httptest.NewRecorder()
+		h.GetCarStats(rec, httptest.NewRequest(http.MethodGet, "/stats/cars?"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", query, rec.Code)
+		}
+	}
+}
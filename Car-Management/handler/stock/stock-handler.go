@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+type StockHandler struct {
+	stockService service.StockServiceInterface
+}
+
+func NewStockHandler(stockService service.StockServiceInterface) *StockHandler {
+	return &StockHandler{
+		stockService: stockService,
+	}
+}
+
+// ReceiveUnit adds a newly delivered unit of the car in the path to stock.
+func (h *StockHandler) ReceiveUnit(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("stock-handler")
+	ctx, span := tracer.Start(r.Context(), "ReceiveUnit-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	carID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var request models.ReceiveStockRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := models.ValidateReceiveStockRequest(request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	unit, err := h.stockService.ReceiveUnit(ctx, models.StockUnit{
+		CarID:    carID,
+		VIN:      request.VIN,
+		Colour:   request.Colour,
+		Location: request.Location,
+		Lot:      request.Lot,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(unit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *StockHandler) GetUnitsByCar(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("stock-handler")
+	ctx, span := tracer.Start(r.Context(), "GetUnitsByCar-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	id := vars["id"]
+	units, err := h.stockService.GetUnitsByCar(ctx, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(units)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *StockHandler) GetUnitById(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("stock-handler")
+	ctx, span := tracer.Start(r.Context(), "GetUnitById-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	id := vars["id"]
+	unit, err := h.stockService.GetUnitById(ctx, id)
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(unit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *StockHandler) MoveUnit(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("stock-handler")
+	ctx, span := tracer.Start(r.Context(), "MoveUnit-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	id := vars["id"]
+	var request models.MoveStockRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := models.ValidateMoveStockRequest(request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	unit, err := h.stockService.MoveUnit(ctx, id, request.Location, request.Lot)
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(unit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *StockHandler) SellUnit(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("stock-handler")
+	ctx, span := tracer.Start(r.Context(), "SellUnit-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	id := vars["id"]
+	unit, err := h.stockService.SellUnit(ctx, id)
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(unit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, store.ErrCarNotFound), errors.Is(err, store.ErrStockUnitNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, store.ErrDuplicateVIN), errors.Is(err, store.ErrInvalidStockTransition):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
+)
+
+// fakeStock fails every call with err.
+type fakeStock struct {
+	service.StockServiceInterface
+	err error
+}
+
+func (f fakeStock) ReceiveUnit(ctx context.Context, unit models.StockUnit) (models.StockUnit, error) {
+	unit.Status = models.StockInStock
+	return unit, f.err
+}
+
+func (f fakeStock) MoveUnit(ctx context.Context, unitID, location, lot string) (models.StockUnit, error) {
+	return models.StockUnit{Location: location, Lot: lot}, f.err
+}
+
+func (f fakeStock) SellUnit(ctx context.Context, unitID string) (models.StockUnit, error) {
+	return models.StockUnit{Status: models.StockSold}, f.err
+}
+
+func withID(r *http.Request, id string) *http.Request {
+	return mux.SetURLVars(r, map[string]string{"id": id})
+}
+
+const validUnit = `{"vin":"1HGCM82633A004352","colour":"Red","location":"Main","lot":"A1"}`
+
+func TestReceiveUnit(t *testing.T) {
+	tests := []struct {
+		name  string
+		carID string
+		body  string
+		err   error
+		want  int
+	}{
+		{"received", uuid.NewString(), validUnit, nil, http.StatusCreated},
+		{"invalid car id", "civic", validUnit, nil, http.StatusBadRequest},
+		{"bad check digit", uuid.NewString(), strings.Replace(validUnit, "1HGCM82633A004352", "1HGCM82643A004352", 1), nil, http.StatusBadRequest},
+		{"missing colour", uuid.NewString(), strings.Replace(validUnit, `"Red"`, `""`, 1), nil, http.StatusBadRequest},
+		{"unknown car", uuid.NewString(), validUnit, store.ErrCarNotFound, http.StatusNotFound},
+		{"duplicate VIN", uuid.NewString(), validUnit, store.ErrDuplicateVIN, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewStockHandler(fakeStock{err: tt.err})
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/cars/x/stock", strings.NewReader(tt.body))
+			h.ReceiveUnit(rec, withID(req, tt.carID))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestMoveUnit(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"moved", `{"location":"Annex","lot":"B7"}`, nil, http.StatusOK},
+		{"missing location", `{"lot":"B7"}`, nil, http.StatusBadRequest},
+		{"unknown unit", `{"location":"Annex"}`, store.ErrStockUnitNotFound, http.StatusNotFound},
+		{"sold unit", `{"location":"Annex"}`, store.ErrInvalidStockTransition, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewStockHandler(fakeStock{err: tt.err})
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/stock/x/location", strings.NewReader(tt.body))
+			h.MoveUnit(rec, withID(req, uuid.NewString()))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestSellUnit(t *testing.T) {
+	for err, want := range map[error]int{
+		nil:                             http.StatusOK,
+		store.ErrStockUnitNotFound:      http.StatusNotFound,
+		store.ErrInvalidStockTransition: http.StatusConflict,
+	} {
+		h := NewStockHandler(fakeStock{err: err})
+		rec := httptest.NewRecorder()
+		h.SellUnit(rec, withID(httptest.NewRequest(http.MethodPost, "/stock/x/sell", nil), uuid.NewString()))
+		if rec.Code != want {
+			t.Errorf("SellUnit with %v: status = %d, want %d", err, rec.Code, want)
+		}
+	}
+}
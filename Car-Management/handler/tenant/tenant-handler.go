@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+// TenantHandler serves the admin endpoints that manage dealerships.
+type TenantHandler struct {
+	tenantService service.TenantServiceInterface
+}
+
+func NewTenantHandler(tenantService service.TenantServiceInterface) *TenantHandler {
+	return &TenantHandler{
+		tenantService: tenantService,
+	}
+}
+
+func (h *TenantHandler) GetTenants(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("tenant-handler")
+	ctx, span := tracer.Start(r.Context(), "GetTenants-Handler")
+	defer span.End()
+	tenants, err := h.tenantService.GetTenants(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(tenants)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *TenantHandler) GetTenantById(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("tenant-handler")
+	ctx, span := tracer.Start(r.Context(), "GetTenantById-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	id := vars["id"]
+	tenant, err := h.tenantService.GetTenantById(ctx, id)
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(tenant)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *TenantHandler) CreateTenant(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("tenant-handler")
+	ctx, span := tracer.Start(r.Context(), "CreateTenant-Handler")
+	defer span.End()
+	var tenantRequest models.TenantRequest
+	err := json.NewDecoder(r.Body).Decode(&tenantRequest)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := models.ValidateTenantRequest(tenantRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	createdTenant, err := h.tenantService.CreateTenant(ctx, models.Tenant{Name: tenantRequest.Name})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(createdTenant)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *TenantHandler) UpdateTenant(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("tenant-handler")
+	ctx, span := tracer.Start(r.Context(), "UpdateTenant-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	tenantID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var tenantRequest models.TenantRequest
+	err = json.NewDecoder(r.Body).Decode(&tenantRequest)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := models.ValidateTenantRequest(tenantRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	updatedTenant, err := h.tenantService.UpdateTenant(ctx, models.Tenant{ID: tenantID, Name: tenantRequest.Name})
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(updatedTenant)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *TenantHandler) DeleteTenant(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("tenant-handler")
+	ctx, span := tracer.Start(r.Context(), "DeleteTenant-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if id == models.DefaultTenantID.String() {
+		http.Error(w, "the default tenant cannot be deleted", http.StatusConflict)
+		return
+	}
+	if err := h.tenantService.DeleteTenant(ctx, id); err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, store.ErrTenantNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, store.ErrTenantInUse):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
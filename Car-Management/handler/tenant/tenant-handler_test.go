@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
+)
+
+// fakeTenants fails every call with err.
+type fakeTenants struct {
+	service.TenantServiceInterface
+	err error
+}
+
+func (f fakeTenants) GetTenantById(ctx context.Context, tenantID string) (models.Tenant, error) {
+	return models.Tenant{}, f.err
+}
+
+func (f fakeTenants) UpdateTenant(ctx context.Context, tenant models.Tenant) (models.Tenant, error) {
+	return tenant, f.err
+}
+
+func (f fakeTenants) DeleteTenant(ctx context.Context, tenantID string) error {
+	return f.err
+}
+
+func withID(r *http.Request, id string) *http.Request {
+	return mux.SetURLVars(r, map[string]string{"id": id})
+}
+
+func TestGetTenantByIdNotFound(t *testing.T) {
+	h := NewTenantHandler(fakeTenants{err: store.ErrTenantNotFound})
+	rec := httptest.NewRecorder()
+	h.GetTenantById(rec, withID(httptest.NewRequest(http.MethodGet, "/admin/tenants/x", nil), uuid.NewString()))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestUpdateTenant(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		body string
+		err  error
+		want int
+	}{
+		{"updated", uuid.NewString(), `{"name":"North"}`, nil, http.StatusOK},
+		{"invalid id", "north", `{"name":"North"}`, nil, http.StatusBadRequest},
+		{"missing name", uuid.NewString(), `{"name":""}`, nil, http.StatusBadRequest},
+		{"unknown tenant", uuid.NewString(), `{"name":"North"}`, store.ErrTenantNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewTenantHandler(fakeTenants{err: tt.err})
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/admin/tenants/x", strings.NewReader(tt.body))
+			h.UpdateTenant(rec, withID(req, tt.id))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestDeleteTenant(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		err  error
+		want int
+	}{
+		{"deleted", uuid.NewString(), nil, http.StatusNoContent},
+		{"default tenant", models.DefaultTenantID.String(), nil, http.StatusConflict},
+		{"in use", uuid.NewString(), store.ErrTenantInUse, http.StatusConflict},
+		{"unknown tenant", uuid.NewString(), store.ErrTenantNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewTenantHandler(fakeTenants{err: tt.err})
+			rec := httptest.NewRecorder()
+			h.DeleteTenant(rec, withID(httptest.NewRequest(http.MethodDelete, "/admin/tenants/x", nil), tt.id))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
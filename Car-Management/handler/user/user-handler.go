@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+// UserHandler serves the admin endpoints that manage who can sign in.
+type UserHandler struct {
+	userService service.UserServiceInterface
+}
+
+func NewUserHandler(userService service.UserServiceInterface) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+	}
+}
+
+func (h *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("user-handler")
+	ctx, span := tracer.Start(r.Context(), "GetUsers-Handler")
+	defer span.End()
+	users, err := h.userService.GetUsers(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(users)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("user-handler")
+	ctx, span := tracer.Start(r.Context(), "CreateUser-Handler")
+	defer span.End()
+	var userRequest models.UserRequest
+	err := json.NewDecoder(r.Body).Decode(&userRequest)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := models.ValidateUserRequest(userRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	createdUser, err := h.userService.CreateUser(ctx, userRequest)
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(createdUser)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("user-handler")
+	ctx, span := tracer.Start(r.Context(), "DeleteUser-Handler")
+	defer span.End()
+	vars := mux.Vars(r)
+	if err := h.userService.DeleteUser(ctx, vars["id"]); err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, store.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, store.ErrForeignTenant):
+		return http.StatusForbidden
+	case errors.Is(err, store.ErrTenantNotFound):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, store.ErrDuplicateUsername):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store"
+)
+
+// fakeUsers fails every call with err.
+type fakeUsers struct {
+	service.UserServiceInterface
+	err error
+}
+
+func (f fakeUsers) CreateUser(ctx context.Context, request models.UserRequest) (models.User, error) {
+	if f.err != nil {
+		return models.User{}, f.err
+	}
+	return models.User{Username: request.Username, TenantID: request.TenantID, Role: request.Role}, nil
+}
+
+func (f fakeUsers) DeleteUser(ctx context.Context, userID string) error {
+	return f.err
+}
+
+const validUser = `{"username":"clerk","password":"secret123","tenant_id":"00000000-0000-0000-0000-000000000001","role":"user"}`
+
+func TestCreateUser(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"created", validUser, nil, http.StatusCreated},
+		{"malformed body", `{`, nil, http.StatusBadRequest},
+		{"short password", strings.Replace(validUser, "secret123", "short", 1), nil, http.StatusBadRequest},
+		{"unknown role", strings.Replace(validUser, `"user"`, `"owner"`, 1), nil, http.StatusBadRequest},
+		{"another tenant", validUser, store.ErrForeignTenant, http.StatusForbidden},
+		{"unknown tenant", validUser, store.ErrTenantNotFound, http.StatusUnprocessableEntity},
+		{"duplicate username", validUser, store.ErrDuplicateUsername, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewUserHandler(fakeUsers{err: tt.err})
+			rec := httptest.NewRecorder()
+			h.CreateUser(rec, httptest.NewRequest(http.MethodPost, "/admin/users", strings.NewReader(tt.body)))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if rec.Code == http.StatusCreated && strings.Contains(rec.Body.String(), "secret123") {
+				t.Errorf("response %s leaks the password", rec.Body)
+			}
+		})
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	for err, want := range map[error]int{
+		nil:                   http.StatusNoContent,
+		store.ErrUserNotFound: http.StatusNotFound,
+	} {
+		h := NewUserHandler(fakeUsers{err: err})
+		rec := httptest.NewRecorder()
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodDelete, "/admin/users/1", nil), map[string]string{"id": "1"})
+		h.DeleteUser(rec, req)
+		if rec.Code != want {
+			t.Errorf("DeleteUser with %v: status = %d, want %d", err, rec.Code, want)
+		}
+	}
+}
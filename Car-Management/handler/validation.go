@@ -0,0 +1,23 @@
+// Package handler holds what the HTTP handlers in its subpackages share.
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/nitesh111sinha/car-management/models"
+)
+
+// WriteValidationError answers 422 with every field error when err is a
+// *models.ValidationError, and reports whether it did.
+func WriteValidationError(w http.ResponseWriter, err error) bool {
+	var invalid *models.ValidationError
+	if !errors.As(err, &invalid) {
+		return false
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(invalid)
+	return true
+}
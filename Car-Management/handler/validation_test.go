@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nitesh111sinha/car-management/models"
+)
+
+func TestWriteValidationError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := models.ValidateCar(models.Car{})
+	if !WriteValidationError(rec, err) {
+		t.Fatalf("WriteValidationError(%v) = false, want true", err)
+	}
+	var body models.ValidationError
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusUnprocessableEntity || rec.Header().Get("Content-Type") != "application/json" || len(body.Errors) == 0 {
+		t.Errorf("response = %d %q %+v, want 422 JSON with the field errors", rec.Code, rec.Header().Get("Content-Type"), body)
+	}
+
+	rec = httptest.NewRecorder()
+	if WriteValidationError(rec, errors.New("boom")) || WriteValidationError(rec, nil) || rec.Body.Len() != 0 {
+		t.Error("WriteValidationError wrote a response for an error that is not a *ValidationError")
+	}
+}
@@ -2,89 +2,276 @@ package main
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
+	// Locations' opening hours are kept in IANA time zones, which must load
+	// even where the host has no zoneinfo database.
+	_ "time/tzdata"
 
 	"github.com/gorilla/mux"
-	"github.com/joho/godotenv"
 
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/blob"
+	"github.com/nitesh111sinha/car-management/config"
 	"github.com/nitesh111sinha/car-management/driver"
+	attachmentHandler "github.com/nitesh111sinha/car-management/handler/attachment"
+	brandHandler "github.com/nitesh111sinha/car-management/handler/brand"
 	carHandler "github.com/nitesh111sinha/car-management/handler/car"
+	catalogHandler "github.com/nitesh111sinha/car-management/handler/catalog"
+	customerHandler "github.com/nitesh111sinha/car-management/handler/customer"
 	engineHandler "github.com/nitesh111sinha/car-management/handler/engine"
+	exchangeRateHandler "github.com/nitesh111sinha/car-management/handler/exchangerate"
+	"github.com/nitesh111sinha/car-management/handler/health"
 	"github.com/nitesh111sinha/car-management/handler/login"
+	orderHandler "github.com/nitesh111sinha/car-management/handler/order"
+	priceHandler "github.com/nitesh111sinha/car-management/handler/price"
+	reservationHandler "github.com/nitesh111sinha/car-management/handler/reservation"
+	statsHandler "github.com/nitesh111sinha/car-management/handler/stats"
+	stockHandler "github.com/nitesh111sinha/car-management/handler/stock"
+	tenantHandler "github.com/nitesh111sinha/car-management/handler/tenant"
+	userHandler "github.com/nitesh111sinha/car-management/handler/user"
 	"github.com/nitesh111sinha/car-management/middleware"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	attachmentService "github.com/nitesh111sinha/car-management/service/attachment"
+	brandService "github.com/nitesh111sinha/car-management/service/brand"
+	cachedService "github.com/nitesh111sinha/car-management/service/cached"
 	carService "github.com/nitesh111sinha/car-management/service/car"
+	catalogService "github.com/nitesh111sinha/car-management/service/catalog"
+	customerService "github.com/nitesh111sinha/car-management/service/customer"
 	engineService "github.com/nitesh111sinha/car-management/service/engine"
+	exchangeRateService "github.com/nitesh111sinha/car-management/service/exchangerate"
+	orderService "github.com/nitesh111sinha/car-management/service/order"
+	priceService "github.com/nitesh111sinha/car-management/service/price"
+	reservationService "github.com/nitesh111sinha/car-management/service/reservation"
+	statsService "github.com/nitesh111sinha/car-management/service/stats"
+	stockService "github.com/nitesh111sinha/car-management/service/stock"
+	tenantService "github.com/nitesh111sinha/car-management/service/tenant"
+	userService "github.com/nitesh111sinha/car-management/service/user"
+	"github.com/nitesh111sinha/car-management/store"
+	attachmentStore "github.com/nitesh111sinha/car-management/store/attachment"
+	brandStore "github.com/nitesh111sinha/car-management/store/brand"
 	carStore "github.com/nitesh111sinha/car-management/store/car"
+	catalogStore "github.com/nitesh111sinha/car-management/store/catalog"
+	customerStore "github.com/nitesh111sinha/car-management/store/customer"
 	engineStore "github.com/nitesh111sinha/car-management/store/engine"
+	exchangeRateStore "github.com/nitesh111sinha/car-management/store/exchangerate"
+	"github.com/nitesh111sinha/car-management/store/memory"
+	"github.com/nitesh111sinha/car-management/store/migrations"
+	orderStore "github.com/nitesh111sinha/car-management/store/order"
+	priceStore "github.com/nitesh111sinha/car-management/store/price"
+	reservationStore "github.com/nitesh111sinha/car-management/store/reservation"
+	statsStore "github.com/nitesh111sinha/car-management/store/stats"
+	stockStore "github.com/nitesh111sinha/car-management/store/stock"
+	tenantStore "github.com/nitesh111sinha/car-management/store/tenant"
+	userStore "github.com/nitesh111sinha/car-management/store/user"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// readinessTimeout bounds how long a single /readyz probe waits on its checks.
+const readinessTimeout = 2 * time.Second
+
 func main() {
-	if err := godotenv.Load(); err != nil {
-		panic(err)
+	if err := run(); err != nil {
+		log.Fatal(err)
 	}
+}
 
-	traceProvider, err := startTracing()
+// run wires the application together and serves until SIGINT or SIGTERM.
+// Returning instead of exiting lets the shutdown sequence run to completion.
+func run() error {
+	cfg, err := config.Load()
 	if err != nil {
-		panic(err)
+		return err
 	}
-	defer func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		if err := traceProvider.Shutdown(ctx); err != nil {
-			log.Println("Trace shutdown error:", err)
-		}
-	}()
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Background workers run until shutdown cancels workersCtx.
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	traceProvider, err := startTracing(cfg.Tracing)
+	if err != nil {
+		return err
+	}
 	otel.SetTracerProvider(traceProvider)
 
-	driver.InitDB()
-	defer driver.CloseDB()
+	var (
+		db      *driver.DB
+		cars    store.CarStoreInterface
+		engines store.EngineStoreInterface
+		tenants store.TenantStoreInterface
+		users   store.UserStoreInterface
+		// Stores below exist only for the sql backend; their routes are not
+		// registered with the in-memory store.
+		brands  store.BrandStoreInterface
+		catalog store.CatalogStoreInterface
+		stock   store.StockStoreInterface
+		prices  store.PriceStoreInterface
+		stats   store.StatsStoreInterface
+		rates   store.ExchangeRateStoreInterface
+		// attachments' files are kept in blobs.
+		attachments  store.AttachmentStoreInterface
+		blobs        blob.BlobStore
+		reservations store.ReservationStoreInterface
+		orders       store.OrderStoreInterface
+		customers    store.CustomerStoreInterface
+	)
 
-	db := driver.GetDB()
+	switch cfg.Database.Backend {
+	case "memory":
+		log.Println("Using in-memory store; data will not survive a restart")
+		memDB := memory.NewDB()
+		cars = memory.NewCarStore(memDB)
+		engines = memory.NewEngineStore(memDB)
+		tenants = memory.NewTenantStore(memDB)
+		users = memory.NewUserStore(memDB)
+	case "sql":
+		db, err = driver.InitDB(ctx, cfg.Database)
+		if err != nil {
+			return fmt.Errorf("connecting to database: %w", err)
+		}
+		prometheus.MustRegister(db.Collectors()...)
 
-	carStore := carStore.NewCarStore(db)
-	engineStore := engineStore.NewEngineStore(db)
+		if err := migrations.Migrate(ctx, db); err != nil {
+			db.Close()
+			return fmt.Errorf("running migrations: %w", err)
+		}
+		db.StartReplicaChecks(workersCtx, cfg.Database.ReplicaCheckInterval, cfg.Database.ReplicaMaxLag)
 
-	carService := carService.NewCarService(carStore)
-	engineService := engineService.NewEngineService(engineStore)
+		cars = carStore.NewCarStore(db)
+		engines = engineStore.NewEngineStore(db)
+		tenants = tenantStore.NewTenantStore(db)
+		users = userStore.NewUserStore(db)
+		brands = brandStore.NewBrandStore(db)
+		catalog = catalogStore.NewCatalogStore(db)
+		stock = stockStore.NewStockStore(db)
+		prices = priceStore.NewPriceStore(db)
+		stats = statsStore.NewStatsStore(db)
+		rates = exchangeRateStore.NewExchangeRateStore(db)
+		attachments = attachmentStore.NewAttachmentStore(db)
+		reservations = reservationStore.NewReservationStore(db)
+		orders = orderStore.NewOrderStore(db)
+		customers = customerStore.NewCustomerStore(db)
+		blobs, err = blob.NewLocalStore(cfg.Attachments.Dir)
+		if err != nil {
+			db.Close()
+			return err
+		}
+	}
 
-	carHandler := carHandler.NewCarHandler(carService)
-	engineHandler := engineHandler.NewEngineHandler(engineService)
+	var carService service.CarServiceInterface = carService.NewCarService(cars, rates)
+	var engineService service.EngineServiceInterface = engineService.NewEngineService(engines)
+	tenantService := tenantService.NewTenantService(tenants)
+	userService := userService.NewUserService(users)
+	if cfg.Auth.AdminPassword != "" {
+		err := userService.EnsureUser(ctx, models.UserRequest{
+			Username: cfg.Auth.AdminUsername,
+			Password: cfg.Auth.AdminPassword,
+			TenantID: models.DefaultTenantID,
+			Role:     auth.RolePlatformAdmin,
+		})
+		if err != nil {
+			if db != nil {
+				db.Close()
+			}
+			return fmt.Errorf("creating admin user: %w", err)
+		}
+	}
+	var brandCatalog service.BrandServiceInterface
+	if brands != nil {
+		brandCatalog = brandService.NewBrandService(brands)
+	}
+	var stocks service.StockServiceInterface
+	if stock != nil {
+		stocks = stockService.NewStockService(stock)
+	}
+	var statistics service.StatsServiceInterface
+	if stats != nil {
+		statistics = statsService.NewStatsService(stats)
+	}
+	var exchangeRates service.ExchangeRateServiceInterface
+	if rates != nil {
+		exchangeRates = exchangeRateService.NewExchangeRateService(rates)
+	}
 
-	schemaFile := os.Getenv("SCHEMA_FILE")
-	if schemaFile == "" {
-		panic("SCHEMA_FILE not set")
+	var carAttachments service.AttachmentServiceInterface
+	if attachments != nil {
+		attachmentFiles := attachmentService.NewAttachmentService(attachments, blobs, int64(cfg.Attachments.MaxBytes))
+		carAttachments = attachmentFiles
+		carService = attachmentService.NewCarService(carService, attachmentFiles)
+		engineService = attachmentService.NewEngineService(engineService, attachmentFiles)
 	}
 
-	if err := executeSchema(db, schemaFile); err != nil {
-		log.Fatal("Failed to execute schema:", err)
+	if cfg.Cache.Size > 0 {
+		serviceCache := cachedService.NewCache(cfg.Cache.Size, cfg.Cache.TTL)
+		carService = cachedService.NewCarService(carService, serviceCache)
+		engineService = cachedService.NewEngineService(engineService, serviceCache)
+		if brandCatalog != nil {
+			brandCatalog = cachedService.NewBrandService(brandCatalog, serviceCache)
+		}
+		if statistics != nil {
+			statistics = cachedService.NewStatsService(statistics, serviceCache)
+		}
+		if exchangeRates != nil {
+			exchangeRates = cachedService.NewExchangeRateService(exchangeRates, serviceCache)
+		}
 	}
 
+	carHandler := carHandler.NewCarHandler(carService, stocks, exchangeRates, carAttachments, models.SimilarityWeights{
+		Price:    cfg.Similarity.Price,
+		Year:     cfg.Similarity.Year,
+		FuelType: cfg.Similarity.FuelType,
+		Brand:    cfg.Similarity.Brand,
+		Engine:   cfg.Similarity.Engine,
+	})
+	engineHandler := engineHandler.NewEngineHandler(engineService)
+	tenantHandler := tenantHandler.NewTenantHandler(tenantService)
+	userHandler := userHandler.NewUserHandler(userService)
+	loginHandler := login.NewLoginHandler(cfg.Auth, userService)
+
+	checks := []health.Check{health.TraceExporterCheck(cfg.Tracing.Endpoint)}
+	if db != nil {
+		checks = append(checks, health.DatabaseCheck(db), health.MigrationsCheck(db))
+	}
+	healthHandler := health.NewHealthHandler(readinessTimeout, checks...)
+
 	router := mux.NewRouter()
 	router.Use(otelmux.Middleware("car-management"))
 	router.Use(middleware.MetricsMiddleware)
+	router.Use(middleware.ReadYourWrites(readYourWritesWindow(cfg.Database)))
 
-	router.HandleFunc("/login", login.LoginHandler).Methods("POST")
+	router.HandleFunc("/healthz", healthHandler.Liveness).Methods("GET")
+	router.HandleFunc("/readyz", healthHandler.Readiness).Methods("GET")
+	router.HandleFunc("/login", loginHandler.Login).Methods("POST")
 
 	protected := router.PathPrefix("/").Subrouter()
-	protected.Use(middleware.AuthMiddleware)
+	protected.Use(middleware.AuthMiddleware(cfg.Auth))
 
 	protected.HandleFunc("/cars", carHandler.GetCars).Methods("GET")
 	protected.HandleFunc("/cars/{id:[0-9a-fA-F-]{36}}", carHandler.GetCarById).Methods("GET")
+	protected.HandleFunc("/cars/{id:[0-9a-fA-F-]{36}}/similar", carHandler.GetSimilarCars).Methods("GET")
+	protected.HandleFunc("/cars/compare", carHandler.CompareCars).Methods("GET")
 	protected.HandleFunc("/cars/brand/{brand}", carHandler.GetCarByBrand).Methods("GET")
+	protected.HandleFunc("/cars/vin/{vin}", carHandler.GetCarByVIN).Methods("GET")
+	protected.HandleFunc("/cars/vin/{vin}/decode", carHandler.DecodeVIN).Methods("GET")
 	protected.HandleFunc("/cars", carHandler.CreateCar).Methods("POST")
 	protected.HandleFunc("/cars/{id:[0-9a-fA-F-]{36}}", carHandler.UpdateCar).Methods("PUT")
 	protected.HandleFunc("/cars/{id:[0-9a-fA-F-]{36}}", carHandler.DeleteCar).Methods("DELETE")
@@ -94,31 +281,204 @@ func main() {
 	protected.HandleFunc("/engines", engineHandler.CreateEngine).Methods("POST")
 	protected.HandleFunc("/engines/{id}", engineHandler.UpdateEngine).Methods("PUT")
 	protected.HandleFunc("/engines/{id}", engineHandler.DeleteEngine).Methods("DELETE")
+	protected.HandleFunc("/engines/{id}/cars", engineHandler.GetEngineCars).Methods("GET")
+
+	if brandCatalog != nil {
+		brandHandler := brandHandler.NewBrandHandler(brandCatalog)
+		protected.HandleFunc("/brands", brandHandler.GetBrands).Methods("GET")
+		protected.HandleFunc("/brands/{id:[0-9a-fA-F-]{36}}", brandHandler.GetBrandById).Methods("GET")
+		protected.HandleFunc("/brands", brandHandler.CreateBrand).Methods("POST")
+		protected.HandleFunc("/brands/{id:[0-9a-fA-F-]{36}}", brandHandler.UpdateBrand).Methods("PUT")
+		protected.HandleFunc("/brands/{id:[0-9a-fA-F-]{36}}", brandHandler.DeleteBrand).Methods("DELETE")
+	}
+
+	if catalog != nil {
+		catalogHandler := catalogHandler.NewCatalogHandler(catalogService.NewCatalogService(catalog))
+		protected.HandleFunc("/brands/{id:[0-9a-fA-F-]{36}}/models", catalogHandler.GetModelsByBrand).Methods("GET")
+		protected.HandleFunc("/brands/{id:[0-9a-fA-F-]{36}}/models", catalogHandler.CreateModel).Methods("POST")
+		protected.HandleFunc("/models/{id:[0-9a-fA-F-]{36}}", catalogHandler.GetModelById).Methods("GET")
+		protected.HandleFunc("/models/{id:[0-9a-fA-F-]{36}}", catalogHandler.UpdateModel).Methods("PUT")
+		protected.HandleFunc("/models/{id:[0-9a-fA-F-]{36}}", catalogHandler.DeleteModel).Methods("DELETE")
+		protected.HandleFunc("/models/{id:[0-9a-fA-F-]{36}}/trims", catalogHandler.GetTrimsByModel).Methods("GET")
+		protected.HandleFunc("/models/{id:[0-9a-fA-F-]{36}}/trims", catalogHandler.CreateTrim).Methods("POST")
+		protected.HandleFunc("/trims/{id:[0-9a-fA-F-]{36}}", catalogHandler.GetTrimById).Methods("GET")
+		protected.HandleFunc("/trims/{id:[0-9a-fA-F-]{36}}", catalogHandler.UpdateTrim).Methods("PUT")
+		protected.HandleFunc("/trims/{id:[0-9a-fA-F-]{36}}", catalogHandler.DeleteTrim).Methods("DELETE")
+	}
+
+	if stocks != nil {
+		stockHandler := stockHandler.NewStockHandler(stocks)
+		protected.HandleFunc("/cars/{id:[0-9a-fA-F-]{36}}/stock", stockHandler.GetUnitsByCar).Methods("GET")
+		protected.HandleFunc("/cars/{id:[0-9a-fA-F-]{36}}/stock", stockHandler.ReceiveUnit).Methods("POST")
+		protected.HandleFunc("/stock/{id}", stockHandler.GetUnitById).Methods("GET")
+		protected.HandleFunc("/stock/{id}/location", stockHandler.MoveUnit).Methods("PUT")
+		protected.HandleFunc("/stock/{id}/sell", stockHandler.SellUnit).Methods("POST")
+	}
+
+	if carAttachments != nil {
+		attachmentHandler := attachmentHandler.NewAttachmentHandler(carAttachments, int64(cfg.Attachments.MaxBytes))
+		protected.HandleFunc("/cars/{id:[0-9a-fA-F-]{36}}/attachments", attachmentHandler.GetAttachmentsByCar).Methods("GET")
+		protected.HandleFunc("/cars/{id:[0-9a-fA-F-]{36}}/attachments", attachmentHandler.AddAttachment).Methods("POST")
+		protected.HandleFunc("/cars/{id:[0-9a-fA-F-]{36}}/attachments/order", attachmentHandler.ReorderAttachments).Methods("PUT")
+		protected.HandleFunc("/attachments/{id}", attachmentHandler.GetAttachmentById).Methods("GET")
+		protected.HandleFunc("/attachments/{id}/content", attachmentHandler.GetAttachmentContent).Methods("GET")
+		protected.HandleFunc("/attachments/{id}/thumbnail", attachmentHandler.GetAttachmentThumbnail).Methods("GET")
+		protected.HandleFunc("/attachments/{id}", attachmentHandler.DeleteAttachment).Methods("DELETE")
+	}
+
+	if reservations != nil {
+		reservationHandler := reservationHandler.NewReservationHandler(reservationService.NewReservationService(reservations))
+		protected.HandleFunc("/locations", reservationHandler.GetLocations).Methods("GET")
+		protected.HandleFunc("/locations/{location}/hours", reservationHandler.GetLocationHours).Methods("GET")
+		protected.HandleFunc("/locations/{location}/hours", reservationHandler.SetLocationHours).Methods("PUT")
+		protected.HandleFunc("/cars/{id:[0-9a-fA-F-]{36}}/reservations", reservationHandler.GetReservationsByCar).Methods("GET")
+		protected.HandleFunc("/cars/{id:[0-9a-fA-F-]{36}}/reservations", reservationHandler.BookTestDrive).Methods("POST")
+		protected.HandleFunc("/cars/{id:[0-9a-fA-F-]{36}}/availability", reservationHandler.GetAvailability).Methods("GET")
+		protected.HandleFunc("/reservations/{id}", reservationHandler.GetReservationById).Methods("GET")
+		protected.HandleFunc("/reservations/{id}", reservationHandler.RescheduleReservation).Methods("PUT")
+		protected.HandleFunc("/reservations/{id}/cancel", reservationHandler.CancelReservation).Methods("POST")
+	}
+
+	if orders != nil {
+		orderHandler := orderHandler.NewOrderHandler(orderService.NewOrderService(orders))
+		protected.HandleFunc("/orders", orderHandler.GetOrders).Methods("GET")
+		protected.HandleFunc("/cars/{id:[0-9a-fA-F-]{36}}/orders", orderHandler.GetOrdersByCar).Methods("GET")
+		protected.HandleFunc("/cars/{id:[0-9a-fA-F-]{36}}/orders", orderHandler.CreateOrder).Methods("POST")
+		protected.HandleFunc("/orders/{id}", orderHandler.GetOrderById).Methods("GET")
+		protected.HandleFunc("/orders/{id}", orderHandler.UpdateOrder).Methods("PUT")
+		protected.HandleFunc("/orders/{id}/transitions", orderHandler.GetOrderTransitions).Methods("GET")
+		protected.HandleFunc("/orders/{id}/confirm", orderHandler.ConfirmOrder).Methods("POST")
+		protected.HandleFunc("/orders/{id}/pay", orderHandler.PayOrder).Methods("POST")
+		protected.HandleFunc("/orders/{id}/deliver", orderHandler.DeliverOrder).Methods("POST")
+		protected.HandleFunc("/orders/{id}/cancel", orderHandler.CancelOrder).Methods("POST")
+	}
+
+	if prices != nil {
+		priceHandler := priceHandler.NewPriceHandler(priceService.NewPriceService(prices))
+		protected.HandleFunc("/cars/{id:[0-9a-fA-F-]{36}}/prices", priceHandler.GetPriceHistory).Methods("GET")
+		protected.HandleFunc("/stats/prices", priceHandler.GetPriceMovements).Methods("GET")
+	}
+
+	if statistics != nil {
+		statsHandler := statsHandler.NewStatsHandler(statistics)
+		protected.HandleFunc("/stats/cars", statsHandler.GetCarStats).Methods("GET")
+	}
+
+	admin := protected.PathPrefix("/admin").Subrouter()
+	admin.Use(middleware.RequireRole(auth.RoleAdmin))
+
+	// Tenants are managed by platform admins; other admins only manage their
+	// own tenant.
+	platform := middleware.RequireRole(auth.RolePlatformAdmin)
+	admin.Handle("/tenants", platform(http.HandlerFunc(tenantHandler.GetTenants))).Methods("GET")
+	admin.Handle("/tenants/{id}", platform(http.HandlerFunc(tenantHandler.GetTenantById))).Methods("GET")
+	admin.Handle("/tenants", platform(http.HandlerFunc(tenantHandler.CreateTenant))).Methods("POST")
+	admin.Handle("/tenants/{id}", platform(http.HandlerFunc(tenantHandler.UpdateTenant))).Methods("PUT")
+	admin.Handle("/tenants/{id}", platform(http.HandlerFunc(tenantHandler.DeleteTenant))).Methods("DELETE")
+
+	admin.HandleFunc("/users", userHandler.GetUsers).Methods("GET")
+	admin.HandleFunc("/users", userHandler.CreateUser).Methods("POST")
+	admin.HandleFunc("/users/{id}", userHandler.DeleteUser).Methods("DELETE")
+
+	if exchangeRates != nil {
+		exchangeRateHandler := exchangeRateHandler.NewExchangeRateHandler(exchangeRates)
+		protected.HandleFunc("/exchange-rates", exchangeRateHandler.GetExchangeRates).Methods("GET")
+		// Every tenant converts prices with the same rates.
+		admin.Handle("/exchange-rates/{currency}", platform(http.HandlerFunc(exchangeRateHandler.SetExchangeRate))).Methods("PUT")
+		admin.Handle("/exchange-rates/{currency}", platform(http.HandlerFunc(exchangeRateHandler.DeleteExchangeRate))).Methods("DELETE")
+	}
+
+	if customers != nil {
+		customerHandler := customerHandler.NewCustomerHandler(customerService.NewCustomerService(customers))
+		protected.HandleFunc("/customers", customerHandler.SearchCustomers).Methods("GET")
+		protected.HandleFunc("/customers", customerHandler.CreateCustomer).Methods("POST")
+		protected.HandleFunc("/customers/{id}", customerHandler.GetCustomerById).Methods("GET")
+		protected.HandleFunc("/customers/{id}", customerHandler.UpdateCustomer).Methods("PUT")
+		protected.HandleFunc("/customers/{id}/enquiries", customerHandler.GetEnquiriesByCustomer).Methods("GET")
+		protected.HandleFunc("/customers/{id}/enquiries", customerHandler.CreateEnquiry).Methods("POST")
+		protected.HandleFunc("/customers/{id}/timeline", customerHandler.GetTimeline).Methods("GET")
+		protected.HandleFunc("/customers/{id}/export", customerHandler.ExportCustomer).Methods("GET")
+		admin.HandleFunc("/customers/{id}/erase", customerHandler.EraseCustomer).Methods("POST")
+	}
 
 	router.Handle("/metrics", promhttp.Handler())
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	server := &http.Server{
+		Addr:              ":" + strconv.Itoa(cfg.Server.Port),
+		Handler:           router,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Println("Server started on port", cfg.Server.Port)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	var runErr error
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			runErr = fmt.Errorf("serving HTTP: %w", err)
+		}
+	case <-ctx.Done():
+		log.Println("Shutdown signal received")
 	}
+	// A second signal skips the graceful shutdown and kills the process.
+	stop()
+
+	// Fail readiness first and give load balancers time to notice before the
+	// listener closes.
+	healthHandler.SetShuttingDown()
+	time.Sleep(cfg.Server.ShutdownDelay)
 
-	log.Println("Server started on port", port)
-	log.Fatal(http.ListenAndServe(":"+port, router))
+	return errors.Join(runErr, shutdown(cfg.Server.ShutdownTimeout, server, stopWorkers, db, traceProvider))
 }
 
-func executeSchema(db *sql.DB, schemaFile string) error {
-	sqlFile, err := os.ReadFile(schemaFile)
-	if err != nil {
+// shutdown stops the application in dependency order within timeout: stop
+// accepting connections and drain in-flight requests, stop background
+// workers, flush pending traces, and finally close the database pool.
+func shutdown(timeout time.Duration, server *http.Server, stopWorkers context.CancelFunc, db *driver.DB, traceProvider *sdktrace.TracerProvider) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+
+	if err := server.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("draining HTTP server: %w", err))
+	}
+
+	stopWorkers()
+	if db != nil {
+		if err := db.StopReplicaChecks(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stopping replica checks: %w", err))
+		}
+	}
+
+	if err := traceProvider.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("flushing traces: %w", err))
+	}
+
+	if db != nil {
+		if err := db.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("closing database: %w", err))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
 		return err
 	}
-	_, err = db.Exec(string(sqlFile))
-	return err
+	log.Println("Shutdown complete")
+	return nil
 }
 
-func startTracing() (*sdktrace.TracerProvider, error) {
+func startTracing(cfg config.Tracing) (*sdktrace.TracerProvider, error) {
 	exporter, err := otlptracehttp.New(
 		context.Background(),
-		otlptracehttp.WithEndpoint("jaeger:4318"),
+		otlptracehttp.WithEndpoint(cfg.Endpoint),
 		otlptracehttp.WithInsecure(),
 	)
 	if err != nil {
@@ -144,3 +504,12 @@ func startTracing() (*sdktrace.TracerProvider, error) {
 
 	return tp, nil
 }
+
+// readYourWritesWindow is how long after a write a client keeps reading from
+// the primary. Without replicas every read goes to the primary anyway.
+func readYourWritesWindow(cfg config.Database) time.Duration {
+	if len(cfg.ReplicaDSNs) == 0 {
+		return 0
+	}
+	return cfg.ReadYourWritesWindow
+}
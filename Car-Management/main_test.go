@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nitesh111sinha/car-management/driver"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// startServer serves handler on a free port and returns the server and its
+// URL.
+func startServer(t *testing.T, handler http.Handler) (*http.Server, string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http.Server{Handler: handler}
+	go server.Serve(listener)
+	return server, "http://" + listener.Addr().String()
+}
+
+func openSQLite(t *testing.T) *driver.DB {
+	t.Helper()
+	sqlDB, err := sql.Open(driver.SQLite.DriverName(), filepath.Join(t.TempDir(), "car-management.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return driver.NewDB(sqlDB, driver.SQLite)
+}
+
+func TestShutdownDrainsInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	server, url := startServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("done"))
+	}))
+
+	type result struct {
+		status int
+		err    error
+	}
+	response := make(chan result, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			response <- result{err: err}
+			return
+		}
+		resp.Body.Close()
+		response <- result{status: resp.StatusCode}
+	}()
+	<-started
+
+	db := openSQLite(t)
+	stopped := false
+	if err := shutdown(5*time.Second, server, func() { stopped = true }, db, sdktrace.NewTracerProvider()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	if got := <-response; got.err != nil || got.status != http.StatusOK {
+		t.Errorf("in-flight request = %d, %v; want it to complete with 200", got.status, got.err)
+	}
+	if !stopped {
+		t.Error("background workers were not stopped")
+	}
+	if err := db.PingContext(context.Background()); err == nil {
+		t.Error("database is still open after shutdown")
+	}
+	if _, err := http.Get(url); err == nil {
+		t.Error("server still accepts connections after shutdown")
+	}
+}
+
+func TestShutdownGivesUpAfterTimeout(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	server, url := startServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+	defer close(release)
+	go http.Get(url)
+	<-started
+
+	err := shutdown(50*time.Millisecond, server, func() {}, nil, sdktrace.NewTracerProvider())
+	if err == nil || !strings.Contains(err.Error(), "draining HTTP server") {
+		t.Errorf("shutdown error = %v, want the drain to time out", err)
+	}
+}
@@ -1,34 +1,27 @@
 package middleware
 
 import (
-	"context"
 	"net/http"
-	"os"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/config"
+	"github.com/nitesh111sinha/car-management/models"
 )
 
-type Claims struct {
-	Username string `json:"username"`
-	jwt.RegisteredClaims
+// AuthMiddleware validates the bearer token on every request using the
+// configured JWT secret.
+func AuthMiddleware(cfg config.Auth) func(http.Handler) http.Handler {
+	jwtKey := []byte(cfg.JWTSecret)
+	return func(next http.Handler) http.Handler {
+		return authenticate(jwtKey, next)
+	}
 }
 
-// Use a private context key type (best practice)
-type contextKey string
-
-const usernameKey contextKey = "username"
-
-func AuthMiddleware(next http.Handler) http.Handler {
+func authenticate(jwtKey []byte, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Load the JWT secret after godotenv has populated the environment
-		secret := os.Getenv("JWT_SECRET")
-		if secret == "" {
-			http.Error(w, "server misconfiguration: JWT secret not set", http.StatusInternalServerError)
-			return
-		}
-		jwtKey := []byte(secret)
-
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
 			http.Error(w, "missing authorization header", http.StatusUnauthorized)
@@ -43,7 +36,7 @@ func AuthMiddleware(next http.Handler) http.Handler {
 		}
 
 		tokenString := parts[1]
-		claims := &Claims{}
+		claims := &auth.Claims{}
 
 		token, err := jwt.ParseWithClaims(
 			tokenString,
@@ -62,7 +55,33 @@ func AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), usernameKey, claims.Username)
+		// Tokens issued before tenants existed carry no tenant claim; they
+		// belong to the default dealership.
+		tenantID := claims.TenantID
+		if tenantID == uuid.Nil {
+			tenantID = models.DefaultTenantID
+		}
+
+		ctx := auth.WithPrincipal(r.Context(), auth.Principal{
+			Username: claims.Username,
+			TenantID: tenantID,
+			Role:     claims.Role,
+		})
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// RequireRole rejects requests whose principal does not have role. It must be
+// installed after AuthMiddleware.
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.PrincipalFromContext(r.Context())
+			if !ok || !principal.HasRole(role) {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nitesh111sinha/car-management/auth"
+)
+
+func TestRequireRole(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	tests := []struct {
+		name     string
+		required string
+		role     string
+		want     int
+	}{
+		{"admin route, admin", auth.RoleAdmin, auth.RoleAdmin, http.StatusNoContent},
+		{"admin route, user", auth.RoleAdmin, auth.RoleUser, http.StatusForbidden},
+		{"admin route, platform admin", auth.RoleAdmin, auth.RolePlatformAdmin, http.StatusNoContent},
+		{"exchange rate change, tenant admin", auth.RolePlatformAdmin, auth.RoleAdmin, http.StatusForbidden},
+		{"exchange rate change, platform admin", auth.RolePlatformAdmin, auth.RolePlatformAdmin, http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/admin/exchange-rates/EUR", nil)
+			req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{Username: "jo", Role: tt.role}))
+			rec := httptest.NewRecorder()
+			RequireRole(tt.required)(ok).ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	RequireRole(auth.RoleAdmin)(ok).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/users", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status without a principal = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -63,10 +64,10 @@ func MetricsMiddleware(next http.Handler) http.Handler {
 		duration := time.Since(start)
 
 		// increment the request counter
-		requestCounter.WithLabelValues(r.URL.Path, r.Method, string(ww.statusCode)).Inc()
+		requestCounter.WithLabelValues(r.URL.Path, r.Method, strconv.Itoa(ww.statusCode)).Inc()
 
 		// increment the status counter
-		statusCounter.WithLabelValues(r.URL.Path, r.Method, string(ww.statusCode)).Inc()
+		statusCounter.WithLabelValues(r.URL.Path, r.Method, strconv.Itoa(ww.statusCode)).Inc()
 
 		// record the request duration
 		requestDuration.WithLabelValues(r.URL.Path, r.Method).Observe(duration.Seconds())
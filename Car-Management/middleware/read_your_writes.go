@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nitesh111sinha/car-management/driver"
+)
+
+// readYourWritesCookie marks a client that has written recently. Its value is
+// when the mark expires, in Unix milliseconds.
+const readYourWritesCookie = "ryw_until"
+
+// ReadYourWrites scopes every request so that, once the request has written to
+// the database, its remaining reads are served by the primary rather than a
+// replica that may not have caught up yet.
+//
+// The client's next requests may reach a lagging replica too, so for window
+// after a write they also read from the primary: the response to a request
+// that wrote sets a cookie, and requests carrying it before it expires skip
+// the replicas. A window of 0 disables the cookie, for deployments without
+// replicas.
+func ReadYourWrites(window time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := driver.WithReadYourWrites(r.Context())
+			if window <= 0 {
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+			if wroteRecently(r, window) {
+				ctx = driver.WithPrimary(ctx)
+			}
+			rw := &rywWriter{ResponseWriter: w, ctx: ctx, window: window, secure: r.TLS != nil}
+			next.ServeHTTP(rw, r.WithContext(ctx))
+			// Handlers that write no body leave the headers to be sent now.
+			rw.mark()
+		})
+	}
+}
+
+// wroteRecently reports whether r carries an unexpired write mark. Marks
+// that claim to last longer than window were not set by ReadYourWrites and
+// are ignored.
+func wroteRecently(r *http.Request, window time.Duration) bool {
+	cookie, err := r.Cookie(readYourWritesCookie)
+	if err != nil {
+		return false
+	}
+	until, err := strconv.ParseInt(cookie.Value, 10, 64)
+	if err != nil {
+		return false
+	}
+	now := time.Now()
+	expires := time.UnixMilli(until)
+	return expires.After(now) && !expires.After(now.Add(window))
+}
+
+// rywWriter sets the write mark on the response, just before the headers
+// are sent, if the request has written by then.
+type rywWriter struct {
+	http.ResponseWriter
+	ctx    context.Context
+	window time.Duration
+	secure bool
+	marked bool
+}
+
+func (w *rywWriter) mark() {
+	if w.marked {
+		return
+	}
+	w.marked = true
+	if !driver.Wrote(w.ctx) {
+		return
+	}
+	http.SetCookie(w.ResponseWriter, &http.Cookie{
+		Name:     readYourWritesCookie,
+		Value:    strconv.FormatInt(time.Now().Add(w.window).UnixMilli(), 10),
+		Path:     "/",
+		MaxAge:   int((w.window + time.Second - 1) / time.Second),
+		HttpOnly: true,
+		Secure:   w.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (w *rywWriter) WriteHeader(statusCode int) {
+	w.mark()
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *rywWriter) Write(b []byte) (int, error) {
+	w.mark()
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *rywWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nitesh111sinha/car-management/driver"
+)
+
+// openPool opens a SQLite database holding a single row naming it, so that
+// reads show whether the primary or the replica served them.
+func openPool(t *testing.T, name string) *sql.DB {
+	t.Helper()
+	db, err := sql.Open(driver.SQLite.DriverName(), filepath.Join(t.TempDir(), name+".db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(`CREATE TABLE source (name TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO source (name) VALUES (?)`, name); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// newRYWServer serves GET by reporting which database answered a read, and
+// POST by writing to the primary.
+func newRYWServer(t *testing.T, window time.Duration) http.Handler {
+	t.Helper()
+	db := driver.NewDB(openPool(t, "primary"), driver.SQLite)
+	db.AddReplica("replica", openPool(t, "replica"))
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			if _, err := db.ExecContext(r.Context(), `UPDATE source SET name = name`); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		var name string
+		if err := db.QueryRowContext(r.Context(), `SELECT name FROM source`).Scan(&name); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(name))
+	})
+	return ReadYourWrites(window)(handler)
+}
+
+func serve(h http.Handler, method string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/cars", nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func rywCookie(rec *httptest.ResponseRecorder) *http.Cookie {
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == readYourWritesCookie {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestReadYourWritesAcrossRequests(t *testing.T) {
+	h := newRYWServer(t, time.Minute)
+
+	if rec := serve(h, http.MethodGet); rec.Body.String() != "replica" || rywCookie(rec) != nil {
+		t.Fatalf("read before any write: served by %q, cookie %v; want replica and no cookie", rec.Body.String(), rywCookie(rec))
+	}
+
+	rec := serve(h, http.MethodPost)
+	cookie := rywCookie(rec)
+	if rec.Code != http.StatusCreated || cookie == nil {
+		t.Fatalf("write: status %d, cookie %v; want %d and a cookie", rec.Code, cookie, http.StatusCreated)
+	}
+	if cookie.MaxAge != 60 || !cookie.HttpOnly {
+		t.Errorf("cookie = %+v, want MaxAge 60 and HttpOnly", cookie)
+	}
+
+	if rec := serve(h, http.MethodGet, cookie); rec.Body.String() != "primary" {
+		t.Errorf("read after a write served by %q, want primary", rec.Body.String())
+	}
+}
+
+func TestReadYourWritesIgnoresInvalidMarks(t *testing.T) {
+	h := newRYWServer(t, time.Minute)
+	until := func(d time.Duration) string {
+		return strconv.FormatInt(time.Now().Add(d).UnixMilli(), 10)
+	}
+
+	for name, value := range map[string]string{
+		"expired":       until(-time.Second),
+		"beyond window": until(time.Hour),
+		"malformed":     "soon",
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec := serve(h, http.MethodGet, &http.Cookie{Name: readYourWritesCookie, Value: value})
+			if rec.Body.String() != "replica" {
+				t.Errorf("served by %q, want replica", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestReadYourWritesWithoutWindowSetsNoCookie(t *testing.T) {
+	h := newRYWServer(t, 0)
+	if rec := serve(h, http.MethodPost); rywCookie(rec) != nil {
+		t.Errorf("write set cookie %v with a window of 0", rywCookie(rec))
+	}
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment kinds. Images get a thumbnail and make up a car's gallery;
+// documents are files such as spec sheets.
+const (
+	AttachmentImage    = "image"
+	AttachmentDocument = "document"
+)
+
+// attachmentKinds lists the content types that can be uploaded, as sniffed
+// from the file's first bytes, with the kind of each.
+var attachmentKinds = map[string]string{
+	"image/jpeg":      AttachmentImage,
+	"image/png":       AttachmentImage,
+	"image/gif":       AttachmentImage,
+	"application/pdf": AttachmentDocument,
+}
+
+// Attachment is a file uploaded for a car. Its content lives in a blob store;
+// URL and ThumbnailURL are where the API serves it.
+type Attachment struct {
+	ID           uuid.UUID `json:"id"`
+	CarID        uuid.UUID `json:"car_id"`
+	Kind         string    `json:"kind"`
+	Filename     string    `json:"filename"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	Position     int       `json:"position"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty"`
+	CreatedBy    string    `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+	// BlobKey and ThumbnailKey locate the content and thumbnail in the blob
+	// store. ThumbnailKey is empty for documents.
+	BlobKey      string `json:"-"`
+	ThumbnailKey string `json:"-"`
+}
+
+// SetURLs fills in URL and, when there is a thumbnail, ThumbnailURL.
+func (a *Attachment) SetURLs() {
+	a.URL = "/attachments/" + a.ID.String() + "/content"
+	a.ThumbnailURL = ""
+	if a.ThumbnailKey != "" {
+		a.ThumbnailURL = "/attachments/" + a.ID.String() + "/thumbnail"
+	}
+}
+
+// AttachmentKind returns the kind of a file with contentType, and whether
+// such files can be uploaded at all.
+func AttachmentKind(contentType string) (string, bool) {
+	kind, ok := attachmentKinds[contentType]
+	return kind, ok
+}
+
+// ReorderAttachmentsRequest lists every attachment of a car in its new
+// order.
+type ReorderAttachmentsRequest struct {
+	IDs []uuid.UUID `json:"ids"`
+}
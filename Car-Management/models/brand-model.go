@@ -0,0 +1,45 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Brand is a manufacturer. Cars refer to it by its canonical Name or any of
+// its Aliases, compared case-insensitively, so "BMW", "bmw" and "B.M.W." can
+// all resolve to the same brand.
+type Brand struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Aliases   []string  `json:"aliases"`
+	Country   string    `json:"country"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type BrandRequest struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+	Country string   `json:"country"`
+}
+
+func ValidateBrandRequest(brandRequest BrandRequest) error {
+	if strings.TrimSpace(brandRequest.Name) == "" {
+		return errors.New("name is required")
+	}
+	seen := map[string]bool{strings.ToLower(strings.TrimSpace(brandRequest.Name)): true}
+	for _, alias := range brandRequest.Aliases {
+		key := strings.ToLower(strings.TrimSpace(alias))
+		if key == "" {
+			return errors.New("aliases must not be empty")
+		}
+		if seen[key] {
+			return errors.New("alias " + alias + " repeats the name or another alias")
+		}
+		seen[key] = true
+	}
+	return nil
+}
@@ -9,108 +9,114 @@ import (
 )
 
 type Car struct {
-	ID        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	Year      string    `json:"year"`
-	Brand     string    `json:"brand"`
-	FuelType  string    `json:"fuel_type"`
-	Engine    Engine    `json:"engine"`
-	Price     float64   `json:"price"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Year     string    `json:"year"`
+	Brand    string    `json:"brand"`
+	FuelType string    `json:"fuel_type"`
+	Engine   Engine    `json:"engine"`
+	Price    Money     `json:"price"`
+	// VIN is optional: a car may be a listing rather than one vehicle.
+	VIN string `json:"vin,omitempty"`
+	// TrimID optionally places the car in the brand > model > trim catalogue.
+	TrimID    uuid.NullUUID `json:"trim_id"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	// Availability is only filled in by GET /cars/{id} and for similar cars.
+	Availability *Availability `json:"availability,omitempty"`
+	// Attachments is only filled in by GET /cars/{id}, in gallery order.
+	Attachments []Attachment `json:"attachments,omitempty"`
 }
 
 type CarRequest struct {
-	Name     string  `json:"name"`
-	Year     string  `json:"year"`
-	Brand    string  `json:"brand"`
-	FuelType string  `json:"fuel_type"`
-	Engine   Engine  `json:"engine"`
-	Price    float64 `json:"price"`
+	Name     string `json:"name"`
+	Year     string `json:"year"`
+	Brand    string `json:"brand"`
+	FuelType string `json:"fuel_type"`
+	Engine   Engine `json:"engine"`
+	Price    Money  `json:"price"`
+	VIN      string `json:"vin"`
 }
 
 func ValidateRequest(carRequest CarRequest) error {
-	if err := validateName(carRequest.Name); err != nil {
-		return err
+	return ValidateCar(Car{
+		Name:     carRequest.Name,
+		Year:     carRequest.Year,
+		Brand:    carRequest.Brand,
+		FuelType: carRequest.FuelType,
+		Engine:   carRequest.Engine,
+		Price:    carRequest.Price,
+		VIN:      carRequest.VIN,
+	})
+}
+
+// ValidateCar checks every field of a car being created or updated and
+// returns a *ValidationError listing all problems, or nil. The engine is a
+// reference: only its id is required, though any specs sent along must be
+// positive.
+func ValidateCar(car Car) error {
+	v := &ValidationError{}
+	if car.Name == "" {
+		v.add("/name", CodeRequired, "name is required")
 	}
-	if err := validateYear(carRequest.Year); err != nil {
-		return err
+	if code, message := checkYear(car.Year); code != "" {
+		v.add("/year", code, message)
 	}
-	if err := validateBrand(carRequest.Brand); err != nil {
-		return err
+	if car.Brand == "" {
+		v.add("/brand", CodeRequired, "brand is required")
 	}
-	if err := validateFuelType(carRequest.FuelType); err != nil {
-		return err
+	if !validFuelType(car.FuelType) {
+		v.add("/fuel_type", CodeInvalidChoice, "fuel type must be Petrol, Diesel, Electric, or Hybrid")
 	}
-	if err := validateEngine(carRequest.Engine); err != nil {
-		return err
+	if car.Engine.EngineID == uuid.Nil {
+		v.add("/engine/engine_id", CodeRequired, "engine id is required and must be a valid uuid")
 	}
-	if err := validatePrice(carRequest.Price); err != nil {
-		return err
+	if car.Engine.Displacement < 0 {
+		v.add("/engine/displacement", CodeMustBePositive, "displacement must be a positive number")
 	}
-	return nil
+	if car.Engine.NoOfCylinders < 0 {
+		v.add("/engine/no_of_cylinders", CodeMustBePositive, "no of cylinders must be a positive number")
+	}
+	if car.Engine.CarRange < 0 {
+		v.add("/engine/car_range", CodeMustBePositive, "car range must be a positive number")
+	}
+	validatePrice(v, car.Price)
+	if car.VIN != "" {
+		validateCarVIN(v, car)
+	}
+	return v.err()
 }
 
-func validateName(name string) error {
-	if name == "" {
-		return errors.New("name is required")
+func validateYear(year string) error {
+	if _, message := checkYear(year); message != "" {
+		return errors.New(message)
 	}
 	return nil
 }
 
-func validateYear(year string) error {
+// checkYear returns the code and message of the problem with year, or empty
+// strings if there is none.
+func checkYear(year string) (code, message string) {
 	if year == "" {
-		return errors.New("year is required")
+		return CodeRequired, "year is required"
 	}
-	_, err := strconv.Atoi(year)
+	yearInt, err := strconv.Atoi(year)
 	if err != nil {
-		return errors.New("year must be a number")
+		return CodeNotANumber, "year must be a number"
 	}
-
 	currentYear := time.Now().Year()
-	yearInt, _ := strconv.Atoi(year)
 	if yearInt < 1886 || yearInt > currentYear {
-		return errors.New("year must be between 1886 and " + strconv.Itoa(currentYear))
+		return CodeOutOfRange, "year must be between 1886 and " + strconv.Itoa(currentYear)
 	}
-	return nil
+	return "", ""
 }
 
-func validateBrand(brand string) error {
-	if brand == "" {
-		return errors.New("brand is required")
-	}
-	return nil
-}
-
-func validateFuelType(fuelType string) error {
+func validFuelType(fuelType string) bool {
 	validFuelTypes := []string{"Petrol", "Diesel", "Electric", "Hybrid"}
 	for _, validFuelType := range validFuelTypes {
 		if fuelType == validFuelType {
-			return nil
+			return true
 		}
 	}
-	return errors.New("fuel type must be Petrol, Diesel, Electric, or Hybrid")
-}
-
-func validateEngine(engine Engine) error {
-	if engine.EngineID == uuid.Nil {
-		return errors.New("engine id is required and must be a valid uuid")
-	}
-	if engine.Displacement <= 0 {
-		return errors.New("displacement is required and must be a positive number")
-	}
-	if engine.NoOfCylinders <= 0 {
-		return errors.New("no of cylinders is required and must be a positive number")
-	}
-	if engine.CarRange <= 0 {
-		return errors.New("car range is required and must be a positive number")
-	}
-	return nil
-}
-
-func validatePrice(price float64) error {
-	if price <= 0 {
-		return errors.New("price is required and must be a positive number")
-	}
-	return nil
+	return false
 }
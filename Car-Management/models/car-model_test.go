@@ -0,0 +1,92 @@
+package models
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func validCar() Car {
+	return Car{
+		Name:     "Civic",
+		Year:     "2023",
+		Brand:    "Honda",
+		FuelType: "Petrol",
+		Engine:   Engine{EngineID: uuid.New()},
+		Price:    Money{Amount: 2500000, Currency: "USD"},
+	}
+}
+
+// fieldErrors returns the pointer and code of every problem in err.
+func fieldErrors(t *testing.T, err error) []string {
+	t.Helper()
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("error = %v, want a *ValidationError", err)
+	}
+	var problems []string
+	for _, fieldError := range invalid.Errors {
+		problems = append(problems, fieldError.String())
+	}
+	return problems
+}
+
+func TestValidateCarAcceptsValidCar(t *testing.T) {
+	if err := ValidateCar(validCar()); err != nil {
+		t.Errorf("ValidateCar = %v, want nil", err)
+	}
+}
+
+func TestValidateCarListsEveryProblem(t *testing.T) {
+	car := Car{
+		Year:     "next year",
+		FuelType: "Steam",
+		Engine:   Engine{Displacement: -1, NoOfCylinders: -4, CarRange: -600},
+		Price:    Money{Amount: -5, Currency: "XYZ"},
+	}
+	got := fieldErrors(t, ValidateCar(car))
+	want := []string{
+		"/name: required",
+		"/year: not_a_number",
+		"/brand: required",
+		"/fuel_type: invalid_choice",
+		"/engine/engine_id: required",
+		"/engine/displacement: must_be_positive",
+		"/engine/no_of_cylinders: must_be_positive",
+		"/engine/car_range: must_be_positive",
+		"/price/amount: must_be_positive",
+		"/price/currency: invalid_choice",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("problems = %v\nwant %v", got, want)
+	}
+}
+
+func TestValidateCarYear(t *testing.T) {
+	next := strconv.Itoa(time.Now().Year() + 1)
+	for year, want := range map[string]string{
+		"":     "/year: required",
+		"1885": "/year: out_of_range",
+		next:   "/year: out_of_range",
+	} {
+		car := validCar()
+		car.Year = year
+		if got := fieldErrors(t, ValidateCar(car)); !slices.Equal(got, []string{want}) {
+			t.Errorf("year %q: problems = %v, want [%s]", year, got, want)
+		}
+	}
+}
+
+func TestValidationErrorMessage(t *testing.T) {
+	car := validCar()
+	car.Name = ""
+	car.Price.Currency = ""
+	err := ValidateCar(car)
+	if want := "validation failed: /name: required, /price/currency: required"; err == nil || err.Error() != want {
+		t.Errorf("Error() = %v, want %q", err, want)
+	}
+}
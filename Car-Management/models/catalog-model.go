@@ -0,0 +1,70 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CarModel is a product line of a brand, such as the Honda Civic.
+type CarModel struct {
+	ID        uuid.UUID `json:"id"`
+	BrandID   uuid.UUID `json:"brand_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Trim is one variant and model year of a model, such as the Civic EX 2023.
+// A trim is offered with one or more engines.
+type Trim struct {
+	ID        uuid.UUID `json:"id"`
+	ModelID   uuid.UUID `json:"model_id"`
+	Name      string    `json:"name"`
+	Year      string    `json:"year"`
+	Engines   []Engine  `json:"engines"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CarModelRequest struct {
+	Name string `json:"name"`
+}
+
+type TrimRequest struct {
+	Name      string      `json:"name"`
+	Year      string      `json:"year"`
+	EngineIDs []uuid.UUID `json:"engine_ids"`
+}
+
+func ValidateCarModelRequest(request CarModelRequest) error {
+	if strings.TrimSpace(request.Name) == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+func ValidateTrimRequest(request TrimRequest) error {
+	if strings.TrimSpace(request.Name) == "" {
+		return errors.New("name is required")
+	}
+	if err := validateYear(request.Year); err != nil {
+		return err
+	}
+	if len(request.EngineIDs) == 0 {
+		return errors.New("engine ids are required")
+	}
+	seen := make(map[uuid.UUID]bool)
+	for _, id := range request.EngineIDs {
+		if id == uuid.Nil {
+			return errors.New("engine ids must be valid uuids")
+		}
+		if seen[id] {
+			return errors.New("engine " + id.String() + " is listed twice")
+		}
+		seen[id] = true
+	}
+	return nil
+}
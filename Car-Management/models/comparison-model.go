@@ -0,0 +1,146 @@
+package models
+
+import (
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// How many cars can be compared at once.
+const (
+	MinComparedCars = 2
+	MaxComparedCars = 4
+)
+
+// Directions in which an attribute's values get better.
+const (
+	BetterLower  = "lower"
+	BetterHigher = "higher"
+)
+
+// CarComparison lines cars up attribute by attribute. Every attribute has one
+// value per car, in the order of Cars.
+type CarComparison struct {
+	Cars       []Car               `json:"cars"`
+	Attributes []ComparedAttribute `json:"attributes"`
+}
+
+// ComparedAttribute is one row of a comparison. A value is nil when the
+// attribute does not apply to the car, such as the battery of a combustion
+// engine. Differs is set unless every car has the same value. For attributes
+// where one direction is better, Best lists the cars with the best value when
+// the values differ; prices in different currencies are not ranked.
+type ComparedAttribute struct {
+	Name    string      `json:"name"`
+	Values  []any       `json:"values"`
+	Differs bool        `json:"differs"`
+	Better  string      `json:"better,omitempty"`
+	Best    []uuid.UUID `json:"best,omitempty"`
+}
+
+// comparedField describes how to read an attribute from a car. rank is nil
+// for attributes that are not ranked, and reports false for cars it cannot
+// rank.
+type comparedField struct {
+	name   string
+	better string
+	value  func(car Car) any
+	rank   func(car Car) (float64, bool)
+}
+
+// CompareCars builds the comparison of cars, which must have their engines
+// loaded.
+func CompareCars(cars []Car) CarComparison {
+	fields := []comparedField{
+		{name: "name", value: func(car Car) any { return car.Name }},
+		{name: "brand", value: func(car Car) any { return car.Brand }},
+		{name: "year", better: BetterHigher,
+			value: func(car Car) any { return car.Year },
+			rank: func(car Car) (float64, bool) {
+				year, err := strconv.Atoi(car.Year)
+				return float64(year), err == nil
+			}},
+		{name: "fuel_type", value: func(car Car) any { return car.FuelType }},
+		{name: "price", better: BetterLower,
+			value: func(car Car) any { return car.Price },
+			rank: func(car Car) (float64, bool) {
+				return float64(car.Price.Amount), car.Price.Currency == cars[0].Price.Currency
+			}},
+		{name: "engine_type", value: func(car Car) any { return car.Engine.Type }},
+		{name: "displacement", value: func(car Car) any { return car.Engine.Displacement }},
+		{name: "no_of_cylinders", value: func(car Car) any { return car.Engine.NoOfCylinders }},
+		{name: "car_range", better: BetterHigher,
+			value: func(car Car) any { return car.Engine.CarRange },
+			rank: func(car Car) (float64, bool) {
+				return float64(car.Engine.CarRange), true
+			}},
+		motorField("battery_kwh", BetterHigher, func(motor *ElectricMotor) float64 { return motor.BatteryKWh }),
+		motorField("charging_power_kw", BetterHigher, func(motor *ElectricMotor) float64 { return motor.ChargingPowerKW }),
+		motorField("efficiency_wh_per_km", BetterLower, func(motor *ElectricMotor) float64 { return motor.EfficiencyWhPerKm }),
+	}
+
+	comparison := CarComparison{Cars: cars, Attributes: make([]ComparedAttribute, 0, len(fields))}
+	for _, field := range fields {
+		attribute := ComparedAttribute{Name: field.name, Better: field.better, Values: make([]any, len(cars))}
+		for i, car := range cars {
+			attribute.Values[i] = field.value(car)
+			if attribute.Values[i] != attribute.Values[0] {
+				attribute.Differs = true
+			}
+		}
+		if field.rank != nil && attribute.Differs {
+			attribute.Best = best(cars, field)
+		}
+		comparison.Attributes = append(comparison.Attributes, attribute)
+	}
+	return comparison
+}
+
+// motorField compares a spec of electric motors. Cars without one have no
+// value.
+func motorField(name, better string, spec func(motor *ElectricMotor) float64) comparedField {
+	return comparedField{name: name, better: better,
+		value: func(car Car) any {
+			if car.Engine.Motor == nil {
+				return nil
+			}
+			return spec(car.Engine.Motor)
+		},
+		rank: func(car Car) (float64, bool) {
+			if car.Engine.Motor == nil {
+				return 0, false
+			}
+			return spec(car.Engine.Motor), true
+		}}
+}
+
+// best returns the cars with the best value of the field. Cars the attribute
+// does not apply to are skipped; it returns nil when fewer than two cars are
+// left, or when a car has a value that cannot be ranked against the others.
+func best(cars []Car, field comparedField) []uuid.UUID {
+	var ids []uuid.UUID
+	var top float64
+	ranked := 0
+	for _, car := range cars {
+		value, ok := field.rank(car)
+		if !ok {
+			if field.value(car) != nil {
+				return nil
+			}
+			continue
+		}
+		ranked++
+		better := (field.better == BetterHigher && value > top) || (field.better == BetterLower && value < top)
+		switch {
+		case ranked == 1 || better:
+			top = value
+			ids = []uuid.UUID{car.ID}
+		case value == top:
+			ids = append(ids, car.ID)
+		}
+	}
+	if ranked < 2 {
+		return nil
+	}
+	return ids
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func comparedCar(name, year string, price Money, engine Engine) Car {
+	return Car{ID: uuid.New(), Name: name, Brand: "Honda", Year: year, FuelType: "Petrol", Price: price, Engine: engine}
+}
+
+func attribute(t *testing.T, comparison CarComparison, name string) ComparedAttribute {
+	t.Helper()
+	for _, attribute := range comparison.Attributes {
+		if attribute.Name == name {
+			return attribute
+		}
+	}
+	t.Fatalf("comparison has no attribute %s", name)
+	return ComparedAttribute{}
+}
+
+func TestCompareCars(t *testing.T) {
+	ice := Engine{Type: PowertrainICE, Displacement: 2000, NoOfCylinders: 4, CarRange: 600}
+	civic := comparedCar("Civic", "2022", Money{Amount: 2500000, Currency: "USD"}, ice)
+	accord := comparedCar("Accord", "2024", Money{Amount: 3000000, Currency: "USD"}, ice)
+	jazz := comparedCar("Jazz", "2024", Money{Amount: 2500000, Currency: "USD"}, Engine{Type: PowertrainICE, Displacement: 1300, NoOfCylinders: 4, CarRange: 500})
+
+	comparison := CompareCars([]Car{civic, accord, jazz})
+	if len(comparison.Cars) != 3 || comparison.Cars[0].ID != civic.ID {
+		t.Errorf("cars = %+v, want the cars in the order given", comparison.Cars)
+	}
+
+	tests := []struct {
+		name    string
+		differs bool
+		better  string
+		best    []uuid.UUID
+	}{
+		{"name", true, "", nil},
+		{"brand", false, "", nil},
+		{"year", true, BetterHigher, []uuid.UUID{accord.ID, jazz.ID}},
+		{"price", true, BetterLower, []uuid.UUID{civic.ID, jazz.ID}},
+		{"displacement", true, "", nil},
+		{"no_of_cylinders", false, "", nil},
+		{"car_range", true, BetterHigher, []uuid.UUID{civic.ID, accord.ID}},
+		{"battery_kwh", false, BetterHigher, nil},
+	}
+	for _, tt := range tests {
+		got := attribute(t, comparison, tt.name)
+		if got.Differs != tt.differs || got.Better != tt.better || !slices.Equal(got.Best, tt.best) {
+			t.Errorf("%s = differs %v, better %q, best %v; want %v, %q, %v", tt.name, got.Differs, got.Better, got.Best, tt.differs, tt.better, tt.best)
+		}
+		if len(got.Values) != 3 {
+			t.Errorf("%s has %d values, want one per car", tt.name, len(got.Values))
+		}
+	}
+	if got := attribute(t, comparison, "battery_kwh").Values; got[0] != nil {
+		t.Errorf("battery of a combustion engine = %v, want nil", got[0])
+	}
+}
+
+func TestCompareCarsElectric(t *testing.T) {
+	ice := comparedCar("Civic", "2024", Money{Amount: 2500000, Currency: "USD"}, Engine{Type: PowertrainICE, Displacement: 2000, NoOfCylinders: 4, CarRange: 600})
+	small := comparedCar("e", "2024", Money{Amount: 3500000, Currency: "USD"}, Engine{Type: PowertrainBEV, CarRange: 220, Motor: &ElectricMotor{BatteryKWh: 35, ChargingPowerKW: 50, EfficiencyWhPerKm: 150}})
+	large := comparedCar("Prologue", "2024", Money{Amount: 4800000, Currency: "USD"}, Engine{Type: PowertrainBEV, CarRange: 450, Motor: &ElectricMotor{BatteryKWh: 85, ChargingPowerKW: 150, EfficiencyWhPerKm: 190}})
+
+	comparison := CompareCars([]Car{ice, small, large})
+	for name, want := range map[string][]uuid.UUID{
+		"battery_kwh":          {large.ID},
+		"charging_power_kw":    {large.ID},
+		"efficiency_wh_per_km": {small.ID},
+	} {
+		got := attribute(t, comparison, name)
+		if !slices.Equal(got.Best, want) || got.Values[0] != nil {
+			t.Errorf("%s = %v best %v, want no value for the combustion car and best %v", name, got.Values, got.Best, want)
+		}
+	}
+
+	// With only one electric car there is nothing to rank it against.
+	if got := attribute(t, CompareCars([]Car{ice, small}), "battery_kwh"); !got.Differs || got.Best != nil {
+		t.Errorf("battery_kwh with one electric car = differs %v, best %v; want differs and no best", got.Differs, got.Best)
+	}
+}
+
+func TestCompareCarsInDifferentCurrencies(t *testing.T) {
+	engine := Engine{Type: PowertrainICE, CarRange: 600}
+	dollars := comparedCar("Civic", "2024", Money{Amount: 2500000, Currency: "USD"}, engine)
+	euros := comparedCar("Civic", "2024", Money{Amount: 2000000, Currency: "EUR"}, engine)
+
+	price := attribute(t, CompareCars([]Car{dollars, euros}), "price")
+	if !price.Differs || price.Best != nil {
+		t.Errorf("price = differs %v, best %v; want differs and not ranked", price.Differs, price.Best)
+	}
+	if name := attribute(t, CompareCars([]Car{dollars, euros}), "name"); name.Differs {
+		t.Error("name differs for two cars called Civic")
+	}
+}
@@ -0,0 +1,206 @@
+package models
+
+import (
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErasedCustomerName replaces the customer's name on the reservations and
+// orders of an erased customer.
+const ErasedCustomerName = "erased customer"
+
+// Enquiry channels.
+const (
+	EnquiryEmail  = "email"
+	EnquiryPhone  = "phone"
+	EnquiryWalkIn = "walk_in"
+	EnquiryWeb    = "web"
+)
+
+// Timeline event types.
+const (
+	EventCustomerCreated = "customer_created"
+	EventReservation     = "reservation"
+	EventOrder           = "order"
+	EventOrderTransition = "order_transition"
+	EventEnquiry         = "enquiry"
+)
+
+// Customer is a person the dealership deals with. ErasedAt is set once their
+// personal data has been erased; the record is kept for the reservations and
+// orders that refer to it.
+type Customer struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	Email     string     `json:"email"`
+	Phone     string     `json:"phone"`
+	Consent   Consent    `json:"consent"`
+	Notes     string     `json:"notes"`
+	ErasedAt  *time.Time `json:"erased_at,omitempty"`
+	CreatedBy string     `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Consent records how the customer agreed to be contacted for marketing.
+type Consent struct {
+	Email bool `json:"email"`
+	SMS   bool `json:"sms"`
+	Phone bool `json:"phone"`
+}
+
+type CustomerRequest struct {
+	Name    string  `json:"name"`
+	Email   string  `json:"email"`
+	Phone   string  `json:"phone"`
+	Consent Consent `json:"consent"`
+	Notes   string  `json:"notes"`
+}
+
+// Enquiry is a question a customer asked, optionally about a car.
+type Enquiry struct {
+	ID         uuid.UUID     `json:"id"`
+	CustomerID uuid.UUID     `json:"customer_id"`
+	CarID      uuid.NullUUID `json:"car_id"`
+	Channel    string        `json:"channel"`
+	Message    string        `json:"message"`
+	CreatedBy  string        `json:"created_by"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+type EnquiryRequest struct {
+	CarID   uuid.NullUUID `json:"car_id"`
+	Channel string        `json:"channel"`
+	Message string        `json:"message"`
+}
+
+// CustomerRecord is everything held about a customer, as returned by the
+// export endpoint.
+type CustomerRecord struct {
+	Customer         Customer          `json:"customer"`
+	Reservations     []Reservation     `json:"reservations"`
+	Orders           []Order           `json:"orders"`
+	OrderTransitions []OrderTransition `json:"order_transitions"`
+	Enquiries        []Enquiry         `json:"enquiries"`
+}
+
+// TimelineEvent is one interaction with a customer.
+type TimelineEvent struct {
+	Type    string        `json:"type"`
+	At      time.Time     `json:"at"`
+	ID      uuid.UUID     `json:"id"`
+	CarID   uuid.NullUUID `json:"car_id"`
+	Summary string        `json:"summary"`
+}
+
+// ValidateCustomerRequest checks a customer being created or changed, and
+// normalises the email address to lower case.
+func ValidateCustomerRequest(request *CustomerRequest) error {
+	v := &ValidationError{}
+	request.Name = strings.TrimSpace(request.Name)
+	request.Email = strings.ToLower(strings.TrimSpace(request.Email))
+	request.Phone = strings.TrimSpace(request.Phone)
+	if request.Name == "" {
+		v.add("/name", CodeRequired, "name is required")
+	}
+	if request.Email != "" {
+		if address, err := mail.ParseAddress(request.Email); err != nil || address.Address != request.Email {
+			v.add("/email", CodeInvalidFormat, "email must be an address such as ann@example.com")
+		}
+	}
+	if request.Phone != "" {
+		if strings.Trim(request.Phone, "0123456789 +-()./") != "" || len(PhoneDigits(request.Phone)) < 6 {
+			v.add("/phone", CodeInvalidFormat, "phone must be a number of at least 6 digits, optionally with + - ( ) . / and spaces")
+		}
+	}
+	if request.Consent.Email && request.Email == "" {
+		v.add("/consent/email", CodeNotAllowed, "email consent needs an email address")
+	}
+	if (request.Consent.SMS || request.Consent.Phone) && request.Phone == "" {
+		v.add("/consent", CodeNotAllowed, "sms and phone consent need a phone number")
+	}
+	return v.err()
+}
+
+func ValidateEnquiryRequest(request EnquiryRequest) error {
+	v := &ValidationError{}
+	switch request.Channel {
+	case EnquiryEmail, EnquiryPhone, EnquiryWalkIn, EnquiryWeb:
+	case "":
+		v.add("/channel", CodeRequired, "channel is required")
+	default:
+		v.add("/channel", CodeInvalidChoice, "channel must be email, phone, walk_in or web")
+	}
+	if strings.TrimSpace(request.Message) == "" {
+		v.add("/message", CodeRequired, "message is required")
+	}
+	return v.err()
+}
+
+// PhoneDigits strips a phone number down to its digits, which is how phone
+// numbers are searched.
+func PhoneDigits(phone string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+}
+
+// Timeline lists the interactions in a customer's record, newest first.
+func Timeline(record CustomerRecord) []TimelineEvent {
+	events := []TimelineEvent{{
+		Type:    EventCustomerCreated,
+		At:      record.Customer.CreatedAt,
+		ID:      record.Customer.ID,
+		Summary: "customer record created by " + record.Customer.CreatedBy,
+	}}
+	for _, reservation := range record.Reservations {
+		events = append(events, TimelineEvent{
+			Type:    EventReservation,
+			At:      reservation.CreatedAt,
+			ID:      reservation.ID,
+			CarID:   uuid.NullUUID{UUID: reservation.CarID, Valid: true},
+			Summary: "test drive at " + reservation.Location + " on " + reservation.StartsAt.Format(time.RFC3339) + " (" + reservation.Status + ")",
+		})
+	}
+	carOf := make(map[uuid.UUID]uuid.UUID, len(record.Orders))
+	for _, order := range record.Orders {
+		carOf[order.ID] = order.CarID
+	}
+	for _, transition := range record.OrderTransitions {
+		event := TimelineEvent{
+			Type:    EventOrderTransition,
+			At:      transition.ChangedAt,
+			ID:      transition.OrderID,
+			CarID:   uuid.NullUUID{UUID: carOf[transition.OrderID], Valid: true},
+			Summary: "order " + transition.FromStatus + " → " + transition.ToStatus,
+		}
+		if transition.FromStatus == "" {
+			event.Type = EventOrder
+			event.Summary = "order drafted"
+		}
+		if transition.Note != "" {
+			event.Summary += ": " + transition.Note
+		}
+		events = append(events, event)
+	}
+	for _, enquiry := range record.Enquiries {
+		events = append(events, TimelineEvent{
+			Type:    EventEnquiry,
+			At:      enquiry.CreatedAt,
+			ID:      enquiry.ID,
+			CarID:   enquiry.CarID,
+			Summary: "enquiry by " + enquiry.Channel + ": " + enquiry.Message,
+		})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].At.After(events[j].At)
+	})
+	return events
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestValidateCustomerRequest(t *testing.T) {
+	request := CustomerRequest{Name: "  Ann Lee ", Email: " Ann@Example.COM ", Phone: "+49 (30) 123-456", Consent: Consent{Email: true, SMS: true}}
+	if err := ValidateCustomerRequest(&request); err != nil {
+		t.Fatalf("ValidateCustomerRequest = %v, want nil", err)
+	}
+	if request.Name != "Ann Lee" || request.Email != "ann@example.com" || request.Phone != "+49 (30) 123-456" {
+		t.Errorf("request = %+v, want trimmed with a lower case email", request)
+	}
+
+	tests := []struct {
+		name    string
+		request CustomerRequest
+		want    []string
+	}{
+		{"no name", CustomerRequest{Name: " "}, []string{"/name: " + CodeRequired}},
+		{"bad email", CustomerRequest{Name: "Ann", Email: "ann@"}, []string{"/email: " + CodeInvalidFormat}},
+		{"email with a display name", CustomerRequest{Name: "Ann", Email: "Ann <ann@example.com>"}, []string{"/email: " + CodeInvalidFormat}},
+		{"letters in phone", CustomerRequest{Name: "Ann", Phone: "call me"}, []string{"/phone: " + CodeInvalidFormat}},
+		{"short phone", CustomerRequest{Name: "Ann", Phone: "12-34"}, []string{"/phone: " + CodeInvalidFormat}},
+		{"consent without contact", CustomerRequest{Name: "Ann", Consent: Consent{Email: true, Phone: true}}, []string{
+			"/consent/email: " + CodeNotAllowed,
+			"/consent: " + CodeNotAllowed,
+		}},
+	}
+	for _, tt := range tests {
+		if got := fieldErrors(t, ValidateCustomerRequest(&tt.request)); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: errors = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateEnquiryRequest(t *testing.T) {
+	if err := ValidateEnquiryRequest(EnquiryRequest{Channel: EnquiryWalkIn, Message: "Is it still available?"}); err != nil {
+		t.Errorf("ValidateEnquiryRequest = %v, want nil", err)
+	}
+	want := []string{"/channel: " + CodeRequired, "/message: " + CodeRequired}
+	if got := fieldErrors(t, ValidateEnquiryRequest(EnquiryRequest{Message: " "})); !slices.Equal(got, want) {
+		t.Errorf("errors = %v, want %v", got, want)
+	}
+	want = []string{"/channel: " + CodeInvalidChoice}
+	if got := fieldErrors(t, ValidateEnquiryRequest(EnquiryRequest{Channel: "fax", Message: "hi"})); !slices.Equal(got, want) {
+		t.Errorf("errors = %v, want %v", got, want)
+	}
+}
+
+func TestPhoneDigits(t *testing.T) {
+	if got := PhoneDigits("+49 (30) 123-456"); got != "4930123456" {
+		t.Errorf("PhoneDigits = %q, want 4930123456", got)
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	day := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	carID, orderID := uuid.New(), uuid.New()
+	record := CustomerRecord{
+		Customer:     Customer{ID: uuid.New(), CreatedBy: "alice", CreatedAt: day},
+		Reservations: []Reservation{{CarID: carID, Location: "Showroom", Status: ReservationBooked, StartsAt: day.AddDate(0, 0, 5), CreatedAt: day.Add(1 * time.Hour)}},
+		Orders:       []Order{{ID: orderID, CarID: carID}},
+		OrderTransitions: []OrderTransition{
+			{OrderID: orderID, ToStatus: OrderDraft, ChangedAt: day.Add(3 * time.Hour)},
+			{OrderID: orderID, FromStatus: OrderDraft, ToStatus: OrderConfirmed, Note: "deposit received", ChangedAt: day.Add(4 * time.Hour)},
+		},
+		Enquiries: []Enquiry{{Channel: EnquiryEmail, Message: "Price?", CreatedAt: day.Add(2 * time.Hour)}},
+	}
+
+	events := Timeline(record)
+	var types, summaries []string
+	for _, event := range events {
+		types = append(types, event.Type)
+		summaries = append(summaries, event.Summary)
+	}
+	want := []string{EventOrderTransition, EventOrder, EventEnquiry, EventReservation, EventCustomerCreated}
+	if !slices.Equal(types, want) {
+		t.Fatalf("event types = %v, want %v, newest first", types, want)
+	}
+	if summaries[0] != "order draft → confirmed: deposit received" || summaries[1] != "order drafted" || summaries[4] != "customer record created by alice" {
+		t.Errorf("summaries = %q", summaries)
+	}
+	if events[0].CarID.UUID != carID || events[2].CarID.Valid {
+		t.Errorf("car ids = %v and %v, want the order's car and none for the enquiry", events[0].CarID, events[2].CarID)
+	}
+}
@@ -1,53 +1,138 @@
 package models
 
 import (
-	"errors"
 	"github.com/google/uuid"
 )
 
+// Powertrain types. An engine without a type is a combustion engine, so
+// clients that predate electric powertrains keep working.
+const (
+	PowertrainICE    = "ice"
+	PowertrainBEV    = "bev"
+	PowertrainHybrid = "hybrid"
+)
+
+// Engine is a car's powertrain: a combustion engine (ice), a battery electric
+// motor (bev) or a hybrid of both. Displacement and cylinders describe the
+// combustion side and are zero for bev; Motor describes the electric side and
+// is nil for ice. CarRange is the total range in km.
 type Engine struct {
-	EngineID      uuid.UUID `json:"engine_id"`
-	Displacement  int64     `json:"displacement"`
-	NoOfCylinders int64     `json:"no_of_cylinders"`
-	CarRange      int64     `json:"car_range"`
+	EngineID      uuid.UUID      `json:"engine_id"`
+	Type          string         `json:"type"`
+	Displacement  int64          `json:"displacement"`
+	NoOfCylinders int64          `json:"no_of_cylinders"`
+	CarRange      int64          `json:"car_range"`
+	Motor         *ElectricMotor `json:"motor,omitempty"`
+}
+
+// ElectricMotor is the electric side of a bev or hybrid powertrain.
+type ElectricMotor struct {
+	BatteryKWh float64 `json:"battery_kwh"`
+	// ChargingPowerKW is the peak charging power; zero for hybrids that
+	// cannot be plugged in.
+	ChargingPowerKW   float64 `json:"charging_power_kw"`
+	EfficiencyWhPerKm float64 `json:"efficiency_wh_per_km"`
+}
+
+// EngineInUse is the body of the 409 response to deleting an engine that
+// cars still use.
+type EngineInUse struct {
+	Error string `json:"error"`
+	Cars  []Car  `json:"cars"`
 }
 
 type EngineRequest struct {
-	Displacement  int64     `json:"displacement"`
-	NoOfCylinders int64     `json:"no_of_cylinders"`
-	CarRange      int64     `json:"car_range"`
+	Type          string         `json:"type"`
+	Displacement  int64          `json:"displacement"`
+	NoOfCylinders int64          `json:"no_of_cylinders"`
+	CarRange      int64          `json:"car_range"`
+	Motor         *ElectricMotor `json:"motor"`
 }
 
 func ValidateEngineRequest(engineRequest EngineRequest) error {
-	if err := validateDisplacement(engineRequest.Displacement); err != nil {
-		return err
-	}
-	if err := validateNoOfCylinders(engineRequest.NoOfCylinders); err != nil {
-		return err
+	return ValidateEngine(Engine{
+		Type:          engineRequest.Type,
+		Displacement:  engineRequest.Displacement,
+		NoOfCylinders: engineRequest.NoOfCylinders,
+		CarRange:      engineRequest.CarRange,
+		Motor:         engineRequest.Motor,
+	})
+}
+
+// ValidateEngine checks the specs of an engine being created or updated
+// against the rules of its powertrain type and returns a *ValidationError
+// listing all problems, or nil.
+func ValidateEngine(engine Engine) error {
+	v := &ValidationError{}
+	powertrain := PowertrainType(engine)
+	switch powertrain {
+	case PowertrainICE, PowertrainHybrid:
+		if engine.Displacement <= 0 {
+			v.add("/displacement", CodeMustBePositive, "displacement is required and must be a positive number")
+		}
+		if engine.NoOfCylinders <= 0 {
+			v.add("/no_of_cylinders", CodeMustBePositive, "no of cylinders is required and must be a positive number")
+		}
+	case PowertrainBEV:
+		if engine.Displacement != 0 {
+			v.add("/displacement", CodeNotAllowed, "a bev has no displacement")
+		}
+		if engine.NoOfCylinders != 0 {
+			v.add("/no_of_cylinders", CodeNotAllowed, "a bev has no cylinders")
+		}
+	default:
+		v.add("/type", CodeInvalidChoice, "type must be ice, bev or hybrid")
+		return v.err()
 	}
-	if err := validateCarRange(engineRequest.CarRange); err != nil {
-		return err
+	if engine.CarRange <= 0 {
+		v.add("/car_range", CodeMustBePositive, "car range is required and must be a positive number")
 	}
-	return nil
-}
 
-func validateDisplacement(displacement int64) error {
-	if displacement <= 0 {
-		return errors.New("displacement is required and must be a positive number")
+	if powertrain == PowertrainICE {
+		if engine.Motor != nil {
+			v.add("/motor", CodeNotAllowed, "an ice engine has no electric motor")
+		}
+		return v.err()
+	}
+	if engine.Motor == nil {
+		v.add("/motor", CodeRequired, "motor is required for "+powertrain+" powertrains")
+		return v.err()
+	}
+	if engine.Motor.BatteryKWh <= 0 {
+		v.add("/motor/battery_kwh", CodeMustBePositive, "battery capacity is required and must be a positive number")
+	}
+	if engine.Motor.EfficiencyWhPerKm <= 0 {
+		v.add("/motor/efficiency_wh_per_km", CodeMustBePositive, "efficiency is required and must be a positive number")
+	}
+	// A bev must charge from the grid; a hybrid may charge only on the move.
+	if powertrain == PowertrainBEV && engine.Motor.ChargingPowerKW <= 0 {
+		v.add("/motor/charging_power_kw", CodeMustBePositive, "charging power is required and must be a positive number")
+	}
+	if powertrain == PowertrainHybrid && engine.Motor.ChargingPowerKW < 0 {
+		v.add("/motor/charging_power_kw", CodeMustBePositive, "charging power must not be negative")
 	}
-	return nil
+	return v.err()
 }
 
-func validateNoOfCylinders(noOfCylinders int64) error {
-	if noOfCylinders <= 0 {
-		return errors.New("no of cylinders is required and must be a positive number")
+// PowertrainType returns the engine's powertrain type, treating an empty type
+// as ice.
+func PowertrainType(engine Engine) string {
+	if engine.Type == "" {
+		return PowertrainICE
 	}
-	return nil
+	return engine.Type
 }
 
-func validateCarRange(carRange int64) error {
-	if carRange <= 0 {
-		return errors.New("car range is required and must be a positive number")
+// FuelTypeFits reports whether a car of the fuel type can use the powertrain:
+// Electric cars need a bev, Hybrid cars a hybrid and Petrol and Diesel cars
+// a combustion engine.
+func FuelTypeFits(fuelType, powertrain string) bool {
+	switch fuelType {
+	case "Electric":
+		return powertrain == PowertrainBEV
+	case "Hybrid":
+		return powertrain == PowertrainHybrid
+	default:
+		return powertrain == PowertrainICE
 	}
-	return nil
 }
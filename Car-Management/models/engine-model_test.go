@@ -0,0 +1,62 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestValidateEngine(t *testing.T) {
+	motor := func(battery, charging, efficiency float64) *ElectricMotor {
+		return &ElectricMotor{BatteryKWh: battery, ChargingPowerKW: charging, EfficiencyWhPerKm: efficiency}
+	}
+	tests := []struct {
+		name   string
+		engine Engine
+		want   []string
+	}{
+		{"ice without a type", Engine{Displacement: 2000, NoOfCylinders: 4, CarRange: 600}, nil},
+		{"bev", Engine{Type: PowertrainBEV, CarRange: 450, Motor: motor(75, 170, 160)}, nil},
+		{"plug-less hybrid", Engine{Type: PowertrainHybrid, Displacement: 1800, NoOfCylinders: 4, CarRange: 900, Motor: motor(1.3, 0, 120)}, nil},
+		{"unknown type", Engine{Type: "steam"}, []string{"/type: invalid_choice"}},
+		{"empty ice", Engine{}, []string{"/displacement: must_be_positive", "/no_of_cylinders: must_be_positive", "/car_range: must_be_positive"}},
+		{"ice with a motor", Engine{Displacement: 2000, NoOfCylinders: 4, CarRange: 600, Motor: motor(75, 170, 160)}, []string{"/motor: not_allowed"}},
+		{"bev with cylinders", Engine{Type: PowertrainBEV, Displacement: 2000, NoOfCylinders: 4, CarRange: 450, Motor: motor(75, 170, 160)}, []string{"/displacement: not_allowed", "/no_of_cylinders: not_allowed"}},
+		{"bev without a motor", Engine{Type: PowertrainBEV, CarRange: 450}, []string{"/motor: required"}},
+		{"bev that cannot charge", Engine{Type: PowertrainBEV, CarRange: 450, Motor: motor(0, 0, 0)}, []string{"/motor/battery_kwh: must_be_positive", "/motor/efficiency_wh_per_km: must_be_positive", "/motor/charging_power_kw: must_be_positive"}},
+		{"hybrid with negative charging", Engine{Type: PowertrainHybrid, Displacement: 1800, NoOfCylinders: 4, CarRange: 900, Motor: motor(1.3, -1, 120)}, []string{"/motor/charging_power_kw: must_be_positive"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEngine(tt.engine)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("ValidateEngine = %v, want nil", err)
+				}
+				return
+			}
+			if got := fieldErrors(t, err); !slices.Equal(got, tt.want) {
+				t.Errorf("problems = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuelTypeFits(t *testing.T) {
+	for _, tt := range []struct {
+		fuelType, powertrain string
+		want                 bool
+	}{
+		{"Petrol", PowertrainICE, true},
+		{"Diesel", PowertrainICE, true},
+		{"Electric", PowertrainBEV, true},
+		{"Hybrid", PowertrainHybrid, true},
+		{"Petrol", PowertrainBEV, false},
+		{"Electric", PowertrainICE, false},
+		{"Electric", PowertrainHybrid, false},
+		{"Hybrid", PowertrainICE, false},
+	} {
+		if got := FuelTypeFits(tt.fuelType, tt.powertrain); got != tt.want {
+			t.Errorf("FuelTypeFits(%s, %s) = %v, want %v", tt.fuelType, tt.powertrain, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+)
+
+// BaseCurrency is the currency exchange rates are quoted against and
+// statistics are reported in.
+const BaseCurrency = "USD"
+
+// currencyMinorUnits lists the supported ISO 4217 currencies with the number
+// of digits after the decimal point of each.
+var currencyMinorUnits = map[string]int{
+	"AED": 2, "AUD": 2, "BHD": 3, "BRL": 2, "CAD": 2, "CHF": 2, "CNY": 2,
+	"CZK": 2, "DKK": 2, "EUR": 2, "GBP": 2, "HKD": 2, "HUF": 2, "INR": 2,
+	"ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0, "KWD": 3, "MXN": 2, "NOK": 2,
+	"NZD": 2, "OMR": 3, "PLN": 2, "SAR": 2, "SEK": 2, "SGD": 2, "TRY": 2,
+	"USD": 2, "ZAR": 2,
+}
+
+// Money is an amount in the minor unit of its currency, e.g. cents for USD,
+// so that prices never pick up floating point rounding noise.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// UnmarshalJSON also accepts a bare number, which is how prices were sent
+// before they carried a currency; it is read as major units of BaseCurrency.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var major float64
+	if err := json.Unmarshal(data, &major); err == nil {
+		*m = Money{Amount: MinorUnits(major, BaseCurrency), Currency: BaseCurrency}
+		return nil
+	}
+	type money Money
+	var decoded money
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return errors.New("price must be an object with amount and currency")
+	}
+	*m = Money(decoded)
+	return nil
+}
+
+// IsCurrency reports whether code is a supported ISO 4217 currency code.
+func IsCurrency(code string) bool {
+	_, ok := currencyMinorUnits[code]
+	return ok
+}
+
+// MinorUnits converts an amount in major units of currency, such as 12.34
+// USD, to minor units, rounding to the nearest one.
+func MinorUnits(major float64, currency string) int64 {
+	return int64(math.Round(major * scale(currency)))
+}
+
+// scale is the number of minor units in one major unit of currency.
+func scale(currency string) float64 {
+	return math.Pow10(currencyMinorUnits[currency])
+}
+
+// ExchangeRate is how many units of Currency one unit of BaseCurrency buys.
+type ExchangeRate struct {
+	Currency  string    `json:"currency"`
+	Rate      float64   `json:"rate"`
+	UpdatedBy string    `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BaseFactor is what an amount in minor units of the rate's currency is
+// multiplied by to get minor units of BaseCurrency. Stores keep it next to
+// the rate so that SQL can convert prices.
+func (r ExchangeRate) BaseFactor() float64 {
+	return scale(BaseCurrency) / (r.Rate * scale(r.Currency))
+}
+
+// Convert returns m in the currency of to. from must be the rate of m's
+// currency. The result is rounded to the nearest minor unit.
+func Convert(m Money, from, to ExchangeRate) Money {
+	amount := float64(m.Amount) * from.BaseFactor() / to.BaseFactor()
+	return Money{Amount: int64(math.Round(amount)), Currency: to.Currency}
+}
+
+type ExchangeRateRequest struct {
+	Rate float64 `json:"rate"`
+}
+
+// ValidateExchangeRate checks a rate being set for currency.
+func ValidateExchangeRate(currency string, request ExchangeRateRequest) error {
+	v := &ValidationError{}
+	if !IsCurrency(currency) {
+		v.add("/currency", CodeInvalidChoice, "currency must be a supported ISO 4217 code, e.g. EUR")
+	}
+	if request.Rate <= 0 || math.IsInf(request.Rate, 0) {
+		v.add("/rate", CodeMustBePositive, "rate is required and must be a positive number")
+	}
+	return v.err()
+}
+
+// validatePrice records the problems with a car's price under /price.
+func validatePrice(v *ValidationError, price Money) {
+	if price.Amount <= 0 {
+		v.add("/price/amount", CodeMustBePositive, "price amount is required and must be a positive number of minor units")
+	}
+	switch {
+	case price.Currency == "":
+		v.add("/price/currency", CodeRequired, "price currency is required")
+	case !IsCurrency(price.Currency):
+		v.add("/price/currency", CodeInvalidChoice, "price currency must be a supported ISO 4217 code, e.g. USD")
+	}
+}
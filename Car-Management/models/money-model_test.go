@@ -0,0 +1,81 @@
+package models
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestMinorUnits(t *testing.T) {
+	for _, tt := range []struct {
+		major    float64
+		currency string
+		want     int64
+	}{
+		{12.34, "USD", 1234},
+		{0.1 + 0.2, "EUR", 30},
+		{1999.5, "JPY", 2000},
+		{1.2345, "BHD", 1235},
+	} {
+		if got := MinorUnits(tt.major, tt.currency); got != tt.want {
+			t.Errorf("MinorUnits(%v, %s) = %d, want %d", tt.major, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	usd := ExchangeRate{Currency: "USD", Rate: 1}
+	eur := ExchangeRate{Currency: "EUR", Rate: 0.5}
+	jpy := ExchangeRate{Currency: "JPY", Rate: 150}
+	bhd := ExchangeRate{Currency: "BHD", Rate: 0.376}
+
+	for _, tt := range []struct {
+		money    Money
+		from, to ExchangeRate
+		want     Money
+	}{
+		{Money{Amount: 1000, Currency: "USD"}, usd, eur, Money{Amount: 500, Currency: "EUR"}},
+		{Money{Amount: 500, Currency: "EUR"}, eur, usd, Money{Amount: 1000, Currency: "USD"}},
+		{Money{Amount: 1000, Currency: "EUR"}, eur, jpy, Money{Amount: 3000, Currency: "JPY"}},
+		{Money{Amount: 3000, Currency: "JPY"}, jpy, eur, Money{Amount: 1000, Currency: "EUR"}},
+		{Money{Amount: 10000, Currency: "USD"}, usd, bhd, Money{Amount: 37600, Currency: "BHD"}},
+		{Money{Amount: 1, Currency: "USD"}, usd, ExchangeRate{Currency: "EUR", Rate: 0.3}, Money{Amount: 0, Currency: "EUR"}},
+	} {
+		if got := Convert(tt.money, tt.from, tt.to); got != tt.want {
+			t.Errorf("Convert(%+v, %s, %s) = %+v, want %+v", tt.money, tt.from.Currency, tt.to.Currency, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	for _, tt := range []struct {
+		data string
+		want Money
+	}{
+		{`{"amount":2500000,"currency":"EUR"}`, Money{Amount: 2500000, Currency: "EUR"}},
+		{`25000.5`, Money{Amount: 2500050, Currency: BaseCurrency}},
+		{`25000`, Money{Amount: 2500000, Currency: BaseCurrency}},
+	} {
+		var got Money
+		if err := json.Unmarshal([]byte(tt.data), &got); err != nil || got != tt.want {
+			t.Errorf("Unmarshal(%s) = %+v, %v; want %+v", tt.data, got, err, tt.want)
+		}
+	}
+
+	for _, data := range []string{`"25000"`, `true`, `[1]`} {
+		var got Money
+		if err := json.Unmarshal([]byte(data), &got); err == nil {
+			t.Errorf("Unmarshal(%s) = %+v, want an error", data, got)
+		}
+	}
+}
+
+func TestValidateExchangeRate(t *testing.T) {
+	if err := ValidateExchangeRate("EUR", ExchangeRateRequest{Rate: 0.92}); err != nil {
+		t.Errorf("ValidateExchangeRate(EUR 0.92) = %v, want nil", err)
+	}
+	got := fieldErrors(t, ValidateExchangeRate("XYZ", ExchangeRateRequest{Rate: 0}))
+	if want := []string{"/currency: invalid_choice", "/rate: must_be_positive"}; !slices.Equal(got, want) {
+		t.Errorf("problems = %v, want %v", got, want)
+	}
+}
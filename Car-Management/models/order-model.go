@@ -0,0 +1,127 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Order statuses. An order is drafted, confirmed, which reserves a stock
+// unit of the car, paid and finally delivered, which sells the unit. It can
+// be cancelled until it is delivered, releasing the unit again.
+const (
+	OrderDraft     = "draft"
+	OrderConfirmed = "confirmed"
+	OrderPaid      = "paid"
+	OrderDelivered = "delivered"
+	OrderCancelled = "cancelled"
+)
+
+// ValidOrderStatus reports whether status is one of the order statuses.
+func ValidOrderStatus(status string) bool {
+	switch status {
+	case OrderDraft, OrderConfirmed, OrderPaid, OrderDelivered, OrderCancelled:
+		return true
+	}
+	return false
+}
+
+// orderTransitions lists the statuses each status may change to.
+var orderTransitions = map[string][]string{
+	OrderDraft:     {OrderConfirmed, OrderCancelled},
+	OrderConfirmed: {OrderPaid, OrderCancelled},
+	OrderPaid:      {OrderDelivered, OrderCancelled},
+}
+
+// CanTransition reports whether an order may change from status from to
+// status to.
+func CanTransition(from, to string) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Order is the sale of a car to a customer, who may have a customer record.
+// AgreedPrice and Deposit are in the same currency. StockUnitID is the unit
+// reserved for the order once it is confirmed.
+type Order struct {
+	ID           uuid.UUID     `json:"id"`
+	CarID        uuid.UUID     `json:"car_id"`
+	CustomerName string        `json:"customer_name"`
+	CustomerID   uuid.NullUUID `json:"customer_id"`
+	AgreedPrice  Money         `json:"agreed_price"`
+	Deposit      Money         `json:"deposit"`
+	Notes        string        `json:"notes"`
+	Status       string        `json:"status"`
+	StockUnitID  uuid.NullUUID `json:"stock_unit_id"`
+	CreatedBy    string        `json:"created_by"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+// OrderTransition records one status change of an order. FromStatus is
+// empty for the order's creation.
+type OrderTransition struct {
+	ID         uuid.UUID `json:"id"`
+	OrderID    uuid.UUID `json:"order_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Note       string    `json:"note"`
+	ChangedBy  string    `json:"changed_by"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+// OrderRequest drafts an order or changes a draft. A deposit without a
+// currency is in the agreed price's currency. With a CustomerID the customer
+// name may be left out; it is then taken from the customer record.
+type OrderRequest struct {
+	CustomerName string        `json:"customer_name"`
+	CustomerID   uuid.NullUUID `json:"customer_id"`
+	AgreedPrice  Money         `json:"agreed_price"`
+	Deposit      Money         `json:"deposit"`
+	Notes        string        `json:"notes"`
+}
+
+// TransitionRequest carries an optional note for the audit trail. When an
+// order is confirmed, StockUnitID picks the unit to reserve; by default the
+// longest-held unit in stock is taken.
+type TransitionRequest struct {
+	Note        string        `json:"note"`
+	StockUnitID uuid.NullUUID `json:"stock_unit_id"`
+}
+
+// ValidateOrderRequest checks an order being drafted or changed and fills in
+// the deposit's currency when it was left out.
+func ValidateOrderRequest(request *OrderRequest) error {
+	v := &ValidationError{}
+	if request.CustomerName == "" && !request.CustomerID.Valid {
+		v.add("/customer_name", CodeRequired, "customer name or customer id is required")
+	}
+	price := request.AgreedPrice
+	if price.Amount <= 0 {
+		v.add("/agreed_price/amount", CodeMustBePositive, "agreed price amount is required and must be a positive number of minor units")
+	}
+	switch {
+	case price.Currency == "":
+		v.add("/agreed_price/currency", CodeRequired, "agreed price currency is required")
+	case !IsCurrency(price.Currency):
+		v.add("/agreed_price/currency", CodeInvalidChoice, "agreed price currency must be a supported ISO 4217 code, e.g. USD")
+	}
+	if request.Deposit.Currency == "" {
+		request.Deposit.Currency = price.Currency
+	}
+	deposit := request.Deposit
+	switch {
+	case deposit.Amount < 0:
+		v.add("/deposit/amount", CodeMustBePositive, "deposit amount must not be negative")
+	case deposit.Amount > price.Amount && price.Amount > 0:
+		v.add("/deposit/amount", CodeOutOfRange, "deposit must not exceed the agreed price")
+	}
+	if deposit.Currency != price.Currency {
+		v.add("/deposit/currency", CodeMismatch, "deposit must be in the agreed price's currency")
+	}
+	return v.err()
+}
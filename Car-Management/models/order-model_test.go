@@ -0,0 +1,84 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	allowed := map[[2]string]bool{
+		{OrderDraft, OrderConfirmed}:     true,
+		{OrderDraft, OrderCancelled}:     true,
+		{OrderConfirmed, OrderPaid}:      true,
+		{OrderConfirmed, OrderCancelled}: true,
+		{OrderPaid, OrderDelivered}:      true,
+		{OrderPaid, OrderCancelled}:      true,
+	}
+	statuses := []string{OrderDraft, OrderConfirmed, OrderPaid, OrderDelivered, OrderCancelled}
+	for _, from := range statuses {
+		for _, to := range statuses {
+			if got := CanTransition(from, to); got != allowed[[2]string{from, to}] {
+				t.Errorf("CanTransition(%s, %s) = %v, want %v", from, to, got, !got)
+			}
+		}
+	}
+	if CanTransition("", OrderDraft) || CanTransition(OrderDraft, "shipped") {
+		t.Error("CanTransition allowed an unknown status")
+	}
+}
+
+func TestValidOrderStatus(t *testing.T) {
+	for _, status := range []string{OrderDraft, OrderConfirmed, OrderPaid, OrderDelivered, OrderCancelled} {
+		if !ValidOrderStatus(status) {
+			t.Errorf("ValidOrderStatus(%s) = false, want true", status)
+		}
+	}
+	for _, status := range []string{"", "Draft", "shipped"} {
+		if ValidOrderStatus(status) {
+			t.Errorf("ValidOrderStatus(%q) = true, want false", status)
+		}
+	}
+}
+
+func TestValidateOrderRequest(t *testing.T) {
+	request := OrderRequest{
+		CustomerName: "Alex",
+		AgreedPrice:  Money{Amount: 2400000, Currency: "EUR"},
+		Deposit:      Money{Amount: 100000},
+	}
+	if err := ValidateOrderRequest(&request); err != nil {
+		t.Fatalf("ValidateOrderRequest = %v, want nil", err)
+	}
+	if request.Deposit.Currency != "EUR" {
+		t.Errorf("deposit currency = %q, want the agreed price's EUR", request.Deposit.Currency)
+	}
+
+	tests := []struct {
+		name    string
+		request OrderRequest
+		want    []string
+	}{
+		{"nothing", OrderRequest{}, []string{
+			"/customer_name: " + CodeRequired,
+			"/agreed_price/amount: " + CodeMustBePositive,
+			"/agreed_price/currency: " + CodeRequired,
+		}},
+		{"unknown currency", OrderRequest{CustomerName: "Alex", AgreedPrice: Money{Amount: 1, Currency: "XYZ"}}, []string{
+			"/agreed_price/currency: " + CodeInvalidChoice,
+		}},
+		{"negative deposit", OrderRequest{CustomerName: "Alex", AgreedPrice: Money{Amount: 100, Currency: "USD"}, Deposit: Money{Amount: -1}}, []string{
+			"/deposit/amount: " + CodeMustBePositive,
+		}},
+		{"deposit above price", OrderRequest{CustomerName: "Alex", AgreedPrice: Money{Amount: 100, Currency: "USD"}, Deposit: Money{Amount: 101}}, []string{
+			"/deposit/amount: " + CodeOutOfRange,
+		}},
+		{"deposit in another currency", OrderRequest{CustomerName: "Alex", AgreedPrice: Money{Amount: 100, Currency: "USD"}, Deposit: Money{Amount: 10, Currency: "EUR"}}, []string{
+			"/deposit/currency: " + CodeMismatch,
+		}},
+	}
+	for _, tt := range tests {
+		if got := fieldErrors(t, ValidateOrderRequest(&tt.request)); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: errors = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
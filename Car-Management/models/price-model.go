@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PriceChange records one change of a car's price. OldPrice is nil for the
+// price the car was listed with.
+type PriceChange struct {
+	ID        uuid.UUID `json:"id"`
+	CarID     uuid.UUID `json:"car_id"`
+	OldPrice  *Money    `json:"old_price"`
+	NewPrice  Money     `json:"new_price"`
+	ChangedBy string    `json:"changed_by"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// BrandPriceMovement summarises the price changes of one brand's cars in one
+// currency. Totals are in minor units of Currency.
+type BrandPriceMovement struct {
+	Brand         string `json:"brand"`
+	Currency      string `json:"currency"`
+	Drops         int    `json:"drops"`
+	Increases     int    `json:"increases"`
+	TotalDrop     int64  `json:"total_drop"`
+	TotalIncrease int64  `json:"total_increase"`
+}
+
+type PriceMovementReport struct {
+	Since  time.Time            `json:"since"`
+	Until  time.Time            `json:"until"`
+	Brands []BrandPriceMovement `json:"brands"`
+}
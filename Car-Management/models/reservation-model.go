@@ -0,0 +1,272 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reservation statuses. Only booked reservations hold their time slot.
+const (
+	ReservationBooked    = "booked"
+	ReservationCancelled = "cancelled"
+)
+
+// MaxTestDrive is the longest a test drive may be booked for.
+const MaxTestDrive = 4 * time.Hour
+
+// Reservation is a test drive of a car at a location, for a customer who may
+// have a customer record.
+type Reservation struct {
+	ID           uuid.UUID     `json:"id"`
+	CarID        uuid.UUID     `json:"car_id"`
+	Location     string        `json:"location"`
+	CustomerName string        `json:"customer_name"`
+	CustomerID   uuid.NullUUID `json:"customer_id"`
+	Notes        string        `json:"notes"`
+	Status       string        `json:"status"`
+	StartsAt     time.Time     `json:"starts_at"`
+	EndsAt       time.Time     `json:"ends_at"`
+	CreatedBy    string        `json:"created_by"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+// ReservationRequest books a test drive. With a CustomerID the customer
+// name may be left out; it is then taken from the customer record.
+type ReservationRequest struct {
+	Location     string        `json:"location"`
+	CustomerName string        `json:"customer_name"`
+	CustomerID   uuid.NullUUID `json:"customer_id"`
+	Notes        string        `json:"notes"`
+	StartsAt     time.Time     `json:"starts_at"`
+	EndsAt       time.Time     `json:"ends_at"`
+}
+
+// RescheduleRequest moves a reservation. An empty location keeps the
+// current one.
+type RescheduleRequest struct {
+	Location string    `json:"location"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+// OpeningHours is one opening period of a location on a day of the week,
+// from 0 for Sunday to 6 for Saturday. Opens and Closes are local times
+// written as HH:MM; a day may have several periods.
+type OpeningHours struct {
+	Weekday int    `json:"weekday"`
+	Opens   string `json:"opens"`
+	Closes  string `json:"closes"`
+}
+
+// LocationHours is the weekly schedule of a location, in its IANA time
+// zone. Test drives can only be booked while it is open.
+type LocationHours struct {
+	Location string         `json:"location"`
+	TimeZone string         `json:"time_zone"`
+	Hours    []OpeningHours `json:"hours"`
+}
+
+type LocationHoursRequest struct {
+	TimeZone string         `json:"time_zone"`
+	Hours    []OpeningHours `json:"hours"`
+}
+
+// Slot is a free period in which a test drive can be booked.
+type Slot struct {
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+// SlotAvailability lists the free slots of a car at a location.
+type SlotAvailability struct {
+	CarID       uuid.UUID `json:"car_id"`
+	Location    string    `json:"location"`
+	TimeZone    string    `json:"time_zone"`
+	SlotMinutes int       `json:"slot_minutes"`
+	Slots       []Slot    `json:"slots"`
+}
+
+// ValidateReservation checks the time and details of a reservation being
+// booked or rescheduled; now is the current time.
+func ValidateReservation(request ReservationRequest, now time.Time) error {
+	v := &ValidationError{}
+	if request.Location == "" {
+		v.add("/location", CodeRequired, "location is required")
+	}
+	if request.CustomerName == "" && !request.CustomerID.Valid {
+		v.add("/customer_name", CodeRequired, "customer name or customer id is required")
+	}
+	validateReservationTime(v, request.StartsAt, request.EndsAt, now)
+	return v.err()
+}
+
+// ValidateReschedule checks the new time of a reservation; now is the
+// current time.
+func ValidateReschedule(request RescheduleRequest, now time.Time) error {
+	v := &ValidationError{}
+	validateReservationTime(v, request.StartsAt, request.EndsAt, now)
+	return v.err()
+}
+
+func validateReservationTime(v *ValidationError, startsAt, endsAt, now time.Time) {
+	switch {
+	case startsAt.IsZero():
+		v.add("/starts_at", CodeRequired, "starts at is required, as an RFC 3339 time")
+	case startsAt.Before(now):
+		v.add("/starts_at", CodeOutOfRange, "starts at must be in the future")
+	}
+	switch {
+	case endsAt.IsZero():
+		v.add("/ends_at", CodeRequired, "ends at is required, as an RFC 3339 time")
+	case !startsAt.IsZero() && !endsAt.After(startsAt):
+		v.add("/ends_at", CodeOutOfRange, "ends at must be after starts at")
+	case !startsAt.IsZero() && endsAt.Sub(startsAt) > MaxTestDrive:
+		v.add("/ends_at", CodeOutOfRange, "a test drive may last at most "+MaxTestDrive.String())
+	}
+}
+
+// ValidateOpeningHours checks that a test drive from startsAt to endsAt
+// falls within one of the location's opening periods.
+func ValidateOpeningHours(location LocationHours, startsAt, endsAt time.Time) error {
+	v := &ValidationError{}
+	if !WithinOpeningHours(location, startsAt, endsAt) {
+		v.add("/starts_at", CodeNotAllowed, "the test drive must fall within the opening hours of "+location.Location)
+	}
+	return v.err()
+}
+
+// ValidateLocationHours checks a location's weekly schedule. Periods on the
+// same day must not overlap.
+func ValidateLocationHours(request LocationHoursRequest) error {
+	v := &ValidationError{}
+	if request.TimeZone == "" {
+		v.add("/time_zone", CodeRequired, "time zone is required, e.g. Europe/Berlin")
+	} else if _, err := time.LoadLocation(request.TimeZone); err != nil {
+		v.add("/time_zone", CodeInvalidChoice, "time zone must be an IANA time zone, e.g. Europe/Berlin")
+	}
+	for i, hours := range request.Hours {
+		pointer := "/hours/" + strconv.Itoa(i)
+		if hours.Weekday < 0 || hours.Weekday > 6 {
+			v.add(pointer+"/weekday", CodeOutOfRange, "weekday must be between 0 (Sunday) and 6 (Saturday)")
+		}
+		opens, opensOK := parseClock(hours.Opens)
+		if !opensOK {
+			v.add(pointer+"/opens", CodeInvalidFormat, "opens must be a time of day written as HH:MM")
+		}
+		closes, closesOK := parseClock(hours.Closes)
+		if !closesOK {
+			v.add(pointer+"/closes", CodeInvalidFormat, "closes must be a time of day written as HH:MM")
+		}
+		if opensOK && closesOK && closes <= opens {
+			v.add(pointer+"/closes", CodeOutOfRange, "closes must be after opens")
+		}
+	}
+	if len(v.Errors) == 0 {
+		sorted := sortedHours(request.Hours)
+		for i := 1; i < len(sorted); i++ {
+			if sorted[i].Weekday == sorted[i-1].Weekday && sorted[i].Opens < sorted[i-1].Closes {
+				v.add("/hours", CodeNotAllowed, fmt.Sprintf("opening periods on weekday %d overlap", sorted[i].Weekday))
+			}
+		}
+	}
+	return v.err()
+}
+
+// WithinOpeningHours reports whether the period from start to end lies
+// within a single opening period of the location.
+func WithinOpeningHours(location LocationHours, start, end time.Time) bool {
+	tz, err := time.LoadLocation(location.TimeZone)
+	if err != nil {
+		return false
+	}
+	for _, period := range openPeriods(location.Hours, tz, start.In(tz).AddDate(0, 0, -1), start.In(tz).AddDate(0, 0, 1)) {
+		if !start.Before(period.StartsAt) && !end.After(period.EndsAt) {
+			return true
+		}
+	}
+	return false
+}
+
+// FreeSlots splits the location's opening periods on the days from `from`
+// up to `to` into slots of length slot, and returns those that start after
+// now and overlap none of the booked reservations. Times are in the
+// location's time zone.
+func FreeSlots(location LocationHours, booked []Reservation, from, to, now time.Time, slot time.Duration) []Slot {
+	slots := []Slot{}
+	tz, err := time.LoadLocation(location.TimeZone)
+	if err != nil {
+		return slots
+	}
+	for _, period := range openPeriods(location.Hours, tz, from, to) {
+		for start := period.StartsAt; !start.Add(slot).After(period.EndsAt); start = start.Add(slot) {
+			end := start.Add(slot)
+			if start.Before(now) || overlapsAny(booked, start, end) {
+				continue
+			}
+			slots = append(slots, Slot{StartsAt: start, EndsAt: end})
+		}
+	}
+	return slots
+}
+
+// openPeriods returns the opening periods of every day from the date of
+// `from` up to, but not including, the date of `to`, in order.
+func openPeriods(hours []OpeningHours, tz *time.Location, from, to time.Time) []Slot {
+	var periods []Slot
+	sorted := sortedHours(hours)
+	from, to = from.In(tz), to.In(tz)
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, tz)
+	last := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, tz)
+	for ; day.Before(last); day = day.AddDate(0, 0, 1) {
+		for _, h := range sorted {
+			if h.Weekday != int(day.Weekday()) {
+				continue
+			}
+			opens, _ := parseClock(h.Opens)
+			closes, _ := parseClock(h.Closes)
+			periods = append(periods, Slot{
+				StartsAt: time.Date(day.Year(), day.Month(), day.Day(), opens/60, opens%60, 0, 0, tz),
+				EndsAt:   time.Date(day.Year(), day.Month(), day.Day(), closes/60, closes%60, 0, 0, tz),
+			})
+		}
+	}
+	return periods
+}
+
+func overlapsAny(reservations []Reservation, start, end time.Time) bool {
+	for _, reservation := range reservations {
+		if reservation.Status == ReservationBooked && start.Before(reservation.EndsAt) && reservation.StartsAt.Before(end) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedHours(hours []OpeningHours) []OpeningHours {
+	sorted := append([]OpeningHours(nil), hours...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Weekday != sorted[j].Weekday {
+			return sorted[i].Weekday < sorted[j].Weekday
+		}
+		return sorted[i].Opens < sorted[j].Opens
+	})
+	return sorted
+}
+
+// parseClock reads a time of day written as HH:MM, from 00:00 to 24:00, as
+// minutes after midnight.
+func parseClock(clock string) (int, bool) {
+	t, err := time.Parse("15:04", clock)
+	if err == nil && len(clock) == 5 {
+		return t.Hour()*60 + t.Minute(), true
+	}
+	if clock == "24:00" {
+		return 24 * 60, true
+	}
+	return 0, false
+}
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"slices"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+// berlin is open in the morning every day, and in the afternoon on weekdays.
+func berlin() LocationHours {
+	hours := []OpeningHours{}
+	for day := 0; day <= 6; day++ {
+		hours = append(hours, OpeningHours{Weekday: day, Opens: "09:00", Closes: "12:00"})
+		if day >= 1 && day <= 5 {
+			hours = append(hours, OpeningHours{Weekday: day, Opens: "13:00", Closes: "15:00"})
+		}
+	}
+	return LocationHours{Location: "Berlin", TimeZone: "Europe/Berlin", Hours: hours}
+}
+
+func startTimes(slots []Slot) []string {
+	var starts []string
+	for _, slot := range slots {
+		starts = append(starts, slot.StartsAt.UTC().Format("01-02 15:04"))
+	}
+	return starts
+}
+
+func utc(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestFreeSlots(t *testing.T) {
+	// Monday 2026-06-01; Berlin is two hours ahead of UTC in summer.
+	from, to := utc("2026-06-01T00:00:00+02:00"), utc("2026-06-02T00:00:00+02:00")
+	now := utc("2026-05-01T00:00:00Z")
+
+	all := FreeSlots(berlin(), nil, from, to, now, time.Hour)
+	want := []string{"06-01 07:00", "06-01 08:00", "06-01 09:00", "06-01 11:00", "06-01 12:00"}
+	if got := startTimes(all); !slices.Equal(got, want) {
+		t.Errorf("slots = %v, want %v", got, want)
+	}
+
+	booked := []Reservation{
+		{Status: ReservationBooked, StartsAt: utc("2026-06-01T07:30:00Z"), EndsAt: utc("2026-06-01T08:15:00Z")},
+		{Status: ReservationCancelled, StartsAt: utc("2026-06-01T11:00:00Z"), EndsAt: utc("2026-06-01T12:00:00Z")},
+	}
+	want = []string{"06-01 09:00", "06-01 11:00", "06-01 12:00"}
+	if got := startTimes(FreeSlots(berlin(), booked, from, to, now, time.Hour)); !slices.Equal(got, want) {
+		t.Errorf("slots around a booking = %v, want %v", got, want)
+	}
+
+	// Slots that have already begun are left out.
+	want = []string{"06-01 11:00", "06-01 12:00"}
+	if got := startTimes(FreeSlots(berlin(), nil, from, to, utc("2026-06-01T09:01:00Z"), time.Hour)); !slices.Equal(got, want) {
+		t.Errorf("slots later in the day = %v, want %v", got, want)
+	}
+
+	// A slot that does not fit before closing is left out.
+	if got := FreeSlots(berlin(), nil, from, to, now, 90*time.Minute); len(got) != 3 {
+		t.Errorf("90 minute slots = %v, want two in the morning and one in the afternoon", startTimes(got))
+	}
+}
+
+func TestFreeSlotsFollowLocalTime(t *testing.T) {
+	// Clocks in Berlin go forward on Sunday 2026-03-29, so opening at 09:00
+	// is an hour earlier in UTC than on the Saturday.
+	from, to := utc("2026-03-28T00:00:00+01:00"), utc("2026-03-30T00:00:00+02:00")
+	slots := FreeSlots(berlin(), nil, from, to, utc("2026-01-01T00:00:00Z"), 3*time.Hour)
+	want := []string{"03-28 08:00", "03-29 07:00"}
+	if got := startTimes(slots); !slices.Equal(got, want) {
+		t.Errorf("slots = %v, want %v", got, want)
+	}
+
+	if got := FreeSlots(LocationHours{TimeZone: "Mars/Olympus"}, nil, from, to, from, time.Hour); len(got) != 0 {
+		t.Errorf("slots with an unknown time zone = %v, want none", got)
+	}
+}
+
+func TestWithinOpeningHours(t *testing.T) {
+	for _, tt := range []struct {
+		start, end string
+		want       bool
+	}{
+		{"2026-06-01T09:00:00+02:00", "2026-06-01T10:00:00+02:00", true},
+		{"2026-06-01T11:00:00+02:00", "2026-06-01T12:00:00+02:00", true},
+		{"2026-06-01T11:30:00+02:00", "2026-06-01T13:30:00+02:00", false},
+		{"2026-06-01T08:30:00+02:00", "2026-06-01T09:30:00+02:00", false},
+		// Saturday afternoons are closed.
+		{"2026-06-06T13:00:00+02:00", "2026-06-06T14:00:00+02:00", false},
+		// The same Monday morning, given in UTC.
+		{"2026-06-01T07:00:00Z", "2026-06-01T08:00:00Z", true},
+	} {
+		if got := WithinOpeningHours(berlin(), utc(tt.start), utc(tt.end)); got != tt.want {
+			t.Errorf("WithinOpeningHours(%s, %s) = %v, want %v", tt.start, tt.end, got, tt.want)
+		}
+	}
+	if err := ValidateOpeningHours(berlin(), utc("2026-06-06T13:00:00+02:00"), utc("2026-06-06T14:00:00+02:00")); !slices.Equal(fieldErrors(t, err), []string{"/starts_at: " + CodeNotAllowed}) {
+		t.Errorf("ValidateOpeningHours = %v, want /starts_at not allowed", err)
+	}
+}
+
+func TestValidateLocationHours(t *testing.T) {
+	if err := ValidateLocationHours(LocationHoursRequest{TimeZone: "Europe/Berlin", Hours: berlin().Hours}); err != nil {
+		t.Errorf("ValidateLocationHours = %v, want nil", err)
+	}
+	if err := ValidateLocationHours(LocationHoursRequest{TimeZone: "UTC", Hours: []OpeningHours{{Weekday: 1, Opens: "00:00", Closes: "24:00"}}}); err != nil {
+		t.Errorf("open all day = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name    string
+		request LocationHoursRequest
+		want    []string
+	}{
+		{"no time zone", LocationHoursRequest{}, []string{"/time_zone: " + CodeRequired}},
+		{"unknown time zone", LocationHoursRequest{TimeZone: "Mars/Olympus"}, []string{"/time_zone: " + CodeInvalidChoice}},
+		{"bad period", LocationHoursRequest{TimeZone: "UTC", Hours: []OpeningHours{{Weekday: 7, Opens: "9:00", Closes: "25:00"}}},
+			[]string{"/hours/0/weekday: " + CodeOutOfRange, "/hours/0/opens: " + CodeInvalidFormat, "/hours/0/closes: " + CodeInvalidFormat}},
+		{"closes before opening", LocationHoursRequest{TimeZone: "UTC", Hours: []OpeningHours{{Weekday: 1, Opens: "17:00", Closes: "09:00"}}},
+			[]string{"/hours/0/closes: " + CodeOutOfRange}},
+		{"overlapping periods", LocationHoursRequest{TimeZone: "UTC", Hours: []OpeningHours{
+			{Weekday: 1, Opens: "12:00", Closes: "17:00"},
+			{Weekday: 1, Opens: "09:00", Closes: "12:30"},
+		}}, []string{"/hours: " + CodeNotAllowed}},
+	}
+	for _, tt := range tests {
+		if got := fieldErrors(t, ValidateLocationHours(tt.request)); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: errors = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateReservation(t *testing.T) {
+	now := utc("2026-06-01T00:00:00Z")
+	valid := ReservationRequest{
+		Location:     "Berlin",
+		CustomerName: "Alex",
+		StartsAt:     utc("2026-06-02T09:00:00Z"),
+		EndsAt:       utc("2026-06-02T10:00:00Z"),
+	}
+	if err := ValidateReservation(valid, now); err != nil {
+		t.Errorf("ValidateReservation = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*ReservationRequest)
+		want   []string
+	}{
+		{"nothing", func(r *ReservationRequest) { *r = ReservationRequest{} },
+			[]string{"/location: " + CodeRequired, "/customer_name: " + CodeRequired, "/starts_at: " + CodeRequired, "/ends_at: " + CodeRequired}},
+		{"in the past", func(r *ReservationRequest) { r.StartsAt, r.EndsAt = now.Add(-time.Hour), now.Add(time.Hour) },
+			[]string{"/starts_at: " + CodeOutOfRange}},
+		{"ends first", func(r *ReservationRequest) { r.EndsAt = r.StartsAt },
+			[]string{"/ends_at: " + CodeOutOfRange}},
+		{"too long", func(r *ReservationRequest) { r.EndsAt = r.StartsAt.Add(MaxTestDrive + time.Minute) },
+			[]string{"/ends_at: " + CodeOutOfRange}},
+	}
+	for _, tt := range tests {
+		request := valid
+		tt.modify(&request)
+		if got := fieldErrors(t, ValidateReservation(request, now)); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: errors = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// A customer record stands in for the name.
+	request := valid
+	request.CustomerName = ""
+	request.CustomerID.Valid = true
+	if err := ValidateReservation(request, now); err != nil {
+		t.Errorf("ValidateReservation with a customer id = %v, want nil", err)
+	}
+}
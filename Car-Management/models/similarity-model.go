@@ -0,0 +1,260 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Attributes similar cars are scored on.
+const (
+	SimilarPrice    = "price"
+	SimilarYear     = "year"
+	SimilarFuelType = "fuel_type"
+	SimilarBrand    = "brand"
+	SimilarEngine   = "engine"
+)
+
+// similarYearSpan is how many years apart two cars must be to have nothing
+// in common by age.
+const similarYearSpan = 10
+
+// SimilarityWeights says how much each attribute counts towards the score of
+// a similar car. A weight of 0 ignores the attribute.
+type SimilarityWeights struct {
+	Price    float64 `json:"price"`
+	Year     float64 `json:"year"`
+	FuelType float64 `json:"fuel_type"`
+	Brand    float64 `json:"brand"`
+	Engine   float64 `json:"engine"`
+}
+
+// SimilarCar is a car recommended in place of another. Score runs from 0,
+// nothing in common, to 1, alike in every weighted attribute. Reasons
+// explains the recommendation, most telling attribute first.
+type SimilarCar struct {
+	Car     Car              `json:"car"`
+	Score   float64          `json:"score"`
+	Matches []AttributeMatch `json:"matches"`
+	Reasons []string         `json:"reasons"`
+}
+
+// AttributeMatch is how alike one attribute of the two cars is, from 0 to 1,
+// and how much that added to the score.
+type AttributeMatch struct {
+	Attribute    string  `json:"attribute"`
+	Similarity   float64 `json:"similarity"`
+	Weight       float64 `json:"weight"`
+	Contribution float64 `json:"contribution"`
+}
+
+// Total is the sum of the weights, which must be above 0 to score cars.
+func (w SimilarityWeights) Total() float64 {
+	return w.Price + w.Year + w.FuelType + w.Brand + w.Engine
+}
+
+// SimilarCars scores every candidate other than car by its likeness to car
+// and returns the best limit of them, best first. Both car and the
+// candidates must have their engines loaded. Candidates that are sold out,
+// with stock units but none in stock, are left out. Prices in different
+// currencies are compared in BaseCurrency, converted with rates, which are
+// keyed by currency; prices without a rate are not alike at all.
+func SimilarCars(car Car, candidates []Car, weights SimilarityWeights, limit int, rates map[string]ExchangeRate) []SimilarCar {
+	total := weights.Total()
+	similar := []SimilarCar{}
+	price := func(a, b Car) (float64, string) {
+		return priceSimilarity(a, b, rates)
+	}
+	for _, candidate := range candidates {
+		if candidate.ID == car.ID || soldOut(candidate) {
+			continue
+		}
+		result := SimilarCar{Car: candidate, Matches: []AttributeMatch{}, Reasons: []string{}}
+		type reason struct {
+			contribution float64
+			text         string
+		}
+		var reasons []reason
+		for _, attribute := range []struct {
+			name   string
+			weight float64
+			match  func(a, b Car) (float64, string)
+		}{
+			{SimilarPrice, weights.Price, price},
+			{SimilarYear, weights.Year, yearSimilarity},
+			{SimilarFuelType, weights.FuelType, fuelTypeSimilarity},
+			{SimilarBrand, weights.Brand, brandSimilarity},
+			{SimilarEngine, weights.Engine, engineSimilarity},
+		} {
+			if attribute.weight == 0 {
+				continue
+			}
+			similarity, text := attribute.match(car, candidate)
+			contribution := attribute.weight * similarity / total
+			result.Score += contribution
+			result.Matches = append(result.Matches, AttributeMatch{
+				Attribute:    attribute.name,
+				Similarity:   round(similarity),
+				Weight:       attribute.weight,
+				Contribution: round(contribution),
+			})
+			if text != "" {
+				reasons = append(reasons, reason{contribution, text})
+			}
+		}
+		sort.SliceStable(reasons, func(i, j int) bool {
+			return reasons[i].contribution > reasons[j].contribution
+		})
+		for _, reason := range reasons {
+			result.Reasons = append(result.Reasons, reason.text)
+		}
+		result.Score = round(result.Score)
+		similar = append(similar, result)
+	}
+	sort.SliceStable(similar, func(i, j int) bool {
+		if similar[i].Score != similar[j].Score {
+			return similar[i].Score > similar[j].Score
+		}
+		return similar[i].Car.ID.String() < similar[j].Car.ID.String()
+	})
+	if len(similar) > limit {
+		similar = similar[:limit]
+	}
+	return similar
+}
+
+// The similarity functions below compare one attribute of car a with car b,
+// and describe the likeness when it is worth mentioning.
+
+func priceSimilarity(a, b Car, rates map[string]ExchangeRate) (float64, string) {
+	priceA, priceB := a.Price, b.Price
+	if priceA.Currency != priceB.Currency {
+		var okA, okB bool
+		priceA, okA = inBaseCurrency(priceA, rates)
+		priceB, okB = inBaseCurrency(priceB, rates)
+		if !okA || !okB {
+			return 0, ""
+		}
+	}
+	similarity := ratio(float64(priceA.Amount), float64(priceB.Amount))
+	switch {
+	case priceA.Amount == priceB.Amount:
+		return similarity, "same price"
+	case similarity >= 0.8:
+		difference := math.Abs(float64(priceB.Amount-priceA.Amount)) / float64(priceA.Amount)
+		direction := "lower"
+		if priceB.Amount > priceA.Amount {
+			direction = "higher"
+		}
+		return similarity, fmt.Sprintf("price %.0f%% %s", math.Ceil(difference*100), direction)
+	}
+	return similarity, ""
+}
+
+// inBaseCurrency converts m to BaseCurrency, and reports false if its
+// currency has no rate.
+func inBaseCurrency(m Money, rates map[string]ExchangeRate) (Money, bool) {
+	if m.Currency == BaseCurrency {
+		return m, true
+	}
+	rate, ok := rates[m.Currency]
+	if !ok {
+		return Money{}, false
+	}
+	return Convert(m, rate, ExchangeRate{Currency: BaseCurrency, Rate: 1}), true
+}
+
+// soldOut reports whether car has stock units but none left in stock. Cars
+// without units are listings and never sell out.
+func soldOut(car Car) bool {
+	a := car.Availability
+	return a != nil && a.InStock == 0 && a.Reserved+a.Sold > 0
+}
+
+func yearSimilarity(a, b Car) (float64, string) {
+	yearA, errA := strconv.Atoi(a.Year)
+	yearB, errB := strconv.Atoi(b.Year)
+	if errA != nil || errB != nil {
+		return 0, ""
+	}
+	apart := yearB - yearA
+	if apart < 0 {
+		apart = -apart
+	}
+	similarity := math.Max(0, 1-float64(apart)/similarYearSpan)
+	switch {
+	case apart == 0:
+		return similarity, "same model year"
+	case apart == 1:
+		return similarity, "one model year apart"
+	case apart <= 3:
+		return similarity, strconv.Itoa(apart) + " model years apart"
+	}
+	return similarity, ""
+}
+
+// fuelTypeSimilarity counts different fuels of the same powertrain, such as
+// petrol and diesel, as half alike.
+func fuelTypeSimilarity(a, b Car) (float64, string) {
+	switch {
+	case strings.EqualFold(a.FuelType, b.FuelType):
+		return 1, "same fuel type (" + b.FuelType + ")"
+	case a.Engine.Type == b.Engine.Type:
+		return 0.5, ""
+	}
+	return 0, ""
+}
+
+func brandSimilarity(a, b Car) (float64, string) {
+	if strings.EqualFold(a.Brand, b.Brand) {
+		return 1, "same brand (" + b.Brand + ")"
+	}
+	return 0, ""
+}
+
+// engineSimilarity averages the likeness of the specs both engines have.
+// Engines of different powertrains are half as alike.
+func engineSimilarity(a, b Car) (float64, string) {
+	engineA, engineB := a.Engine, b.Engine
+	ratios := []float64{ratio(float64(engineA.CarRange), float64(engineB.CarRange))}
+	if engineA.Displacement > 0 && engineB.Displacement > 0 {
+		ratios = append(ratios, ratio(float64(engineA.Displacement), float64(engineB.Displacement)))
+	}
+	if engineA.NoOfCylinders > 0 && engineB.NoOfCylinders > 0 {
+		ratios = append(ratios, ratio(float64(engineA.NoOfCylinders), float64(engineB.NoOfCylinders)))
+	}
+	if engineA.Motor != nil && engineB.Motor != nil {
+		ratios = append(ratios,
+			ratio(engineA.Motor.BatteryKWh, engineB.Motor.BatteryKWh),
+			ratio(engineA.Motor.ChargingPowerKW, engineB.Motor.ChargingPowerKW),
+			ratio(engineA.Motor.EfficiencyWhPerKm, engineB.Motor.EfficiencyWhPerKm))
+	}
+	var sum float64
+	for _, r := range ratios {
+		sum += r
+	}
+	similarity := sum / float64(len(ratios))
+	if engineA.Type != engineB.Type {
+		similarity /= 2
+	}
+	if similarity < 0.8 {
+		return similarity, ""
+	}
+	return similarity, fmt.Sprintf("similar %s engine with %d km range (vs %d km)", engineB.Type, engineB.CarRange, engineA.CarRange)
+}
+
+// ratio is the smaller of two non-negative values divided by the larger, so
+// 1 when they are equal and 0 when only one is 0.
+func ratio(x, y float64) float64 {
+	if x == y {
+		return 1
+	}
+	return math.Min(x, y) / math.Max(x, y)
+}
+
+// round keeps three decimals, enough to tell scores apart.
+func round(x float64) float64 {
+	return math.Round(x*1000) / 1000
+}
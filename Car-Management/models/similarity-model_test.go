@@ -0,0 +1,111 @@
+package models
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+var equalWeights = SimilarityWeights{Price: 1, Year: 1, FuelType: 1, Brand: 1, Engine: 1}
+
+func similarCar(brand, year, fuelType string, price Money, engine Engine) Car {
+	return Car{ID: uuid.New(), Name: brand + " " + year, Brand: brand, Year: year, FuelType: fuelType, Price: price, Engine: engine}
+}
+
+func ids(similar []SimilarCar) []uuid.UUID {
+	var ids []uuid.UUID
+	for _, result := range similar {
+		ids = append(ids, result.Car.ID)
+	}
+	return ids
+}
+
+var petrolEngine = Engine{Type: PowertrainICE, Displacement: 2000, NoOfCylinders: 4, CarRange: 600}
+
+func TestSimilarCarsRanking(t *testing.T) {
+	car := similarCar("Honda", "2023", "Petrol", Money{Amount: 2500000, Currency: "USD"}, petrolEngine)
+	twin := car
+	twin.ID = uuid.New()
+	close := similarCar("Honda", "2022", "Petrol", Money{Amount: 2700000, Currency: "USD"}, petrolEngine)
+	far := similarCar("Tesla", "2015", "Electric", Money{Amount: 9000000, Currency: "USD"},
+		Engine{Type: PowertrainBEV, CarRange: 400, Motor: &ElectricMotor{BatteryKWh: 75, ChargingPowerKW: 150, EfficiencyWhPerKm: 180}})
+
+	similar := SimilarCars(car, []Car{far, car, close, twin}, equalWeights, 10, nil)
+	if got, want := ids(similar), []uuid.UUID{twin.ID, close.ID, far.ID}; !slices.Equal(got, want) {
+		t.Fatalf("similar cars = %v, want %v without the car itself", got, want)
+	}
+	if similar[0].Score != 1 {
+		t.Errorf("identical car scores %v, want 1", similar[0].Score)
+	}
+	if !(similar[1].Score < 1 && similar[1].Score > similar[2].Score) {
+		t.Errorf("scores = %v, %v, want close above far", similar[1].Score, similar[2].Score)
+	}
+	if len(similar[0].Matches) != 5 || similar[0].Matches[0].Contribution != 0.2 {
+		t.Errorf("matches = %+v, want five attributes each adding 0.2", similar[0].Matches)
+	}
+	wantReasons := []string{"same fuel type (Petrol)", "same brand (Honda)", "similar ice engine with 600 km range (vs 600 km)", "price 8% higher", "one model year apart"}
+	if !slices.Equal(similar[1].Reasons, wantReasons) {
+		t.Errorf("reasons = %q, want %q", similar[1].Reasons, wantReasons)
+	}
+	if len(similar[2].Reasons) != 0 {
+		t.Errorf("reasons for a car with nothing in common = %q, want none", similar[2].Reasons)
+	}
+
+	if got := SimilarCars(car, []Car{far, close, twin}, equalWeights, 2, nil); !slices.Equal(ids(got), []uuid.UUID{twin.ID, close.ID}) {
+		t.Errorf("limit 2 = %v, want the best two", ids(got))
+	}
+}
+
+func TestSimilarCarsWeights(t *testing.T) {
+	car := similarCar("Honda", "2023", "Petrol", Money{Amount: 2500000, Currency: "USD"}, petrolEngine)
+	sameBrand := similarCar("Honda", "2010", "Diesel", Money{Amount: 8000000, Currency: "USD"}, petrolEngine)
+	samePrice := similarCar("Toyota", "2023", "Petrol", Money{Amount: 2500000, Currency: "USD"}, petrolEngine)
+
+	byBrand := SimilarCars(car, []Car{samePrice, sameBrand}, SimilarityWeights{Brand: 1}, 10, nil)
+	if ids(byBrand)[0] != sameBrand.ID || byBrand[0].Score != 1 || byBrand[1].Score != 0 {
+		t.Errorf("by brand = %+v, want the Honda alone to score", byBrand)
+	}
+	if len(byBrand[0].Matches) != 1 || byBrand[0].Matches[0].Attribute != SimilarBrand {
+		t.Errorf("matches = %+v, want only the weighted brand", byBrand[0].Matches)
+	}
+	if byPrice := SimilarCars(car, []Car{sameBrand, samePrice}, SimilarityWeights{Price: 3, Brand: 1}, 10, nil); ids(byPrice)[0] != samePrice.ID {
+		t.Errorf("weighted by price = %v, want the Toyota first", ids(byPrice))
+	}
+
+	// Diesel and petrol share a powertrain, so they are half alike.
+	fuel := SimilarCars(car, []Car{sameBrand}, SimilarityWeights{FuelType: 1}, 10, nil)
+	if fuel[0].Score != 0.5 {
+		t.Errorf("petrol against diesel = %v, want 0.5", fuel[0].Score)
+	}
+}
+
+func TestSimilarCarsConvertPrices(t *testing.T) {
+	car := similarCar("Honda", "2023", "Petrol", Money{Amount: 2500000, Currency: "USD"}, petrolEngine)
+	euros := similarCar("Honda", "2023", "Petrol", Money{Amount: 1250000, Currency: "EUR"}, petrolEngine)
+	rates := map[string]ExchangeRate{"EUR": {Currency: "EUR", Rate: 0.5}}
+	byPrice := SimilarityWeights{Price: 1}
+
+	if got := SimilarCars(car, []Car{euros}, byPrice, 10, rates); got[0].Score != 1 || !slices.Contains(got[0].Reasons, "same price") {
+		t.Errorf("12,500 EUR at 2 USD each against 25,000 USD = %+v, want the same price", got[0])
+	}
+	if got := SimilarCars(car, []Car{euros}, byPrice, 10, nil); got[0].Score != 0 {
+		t.Errorf("price without a rate scores %v, want 0", got[0].Score)
+	}
+}
+
+func TestSimilarCarsLeaveOutSoldOutCars(t *testing.T) {
+	car := similarCar("Honda", "2023", "Petrol", Money{Amount: 2500000, Currency: "USD"}, petrolEngine)
+	listing := similarCar("Honda", "2023", "Petrol", car.Price, petrolEngine)
+	inStock := similarCar("Honda", "2023", "Petrol", car.Price, petrolEngine)
+	inStock.Availability = &Availability{InStock: 1, Sold: 3}
+	soldOut := similarCar("Honda", "2023", "Petrol", car.Price, petrolEngine)
+	soldOut.Availability = &Availability{Reserved: 1, Sold: 2}
+	noUnits := similarCar("Honda", "2023", "Petrol", car.Price, petrolEngine)
+	noUnits.Availability = &Availability{}
+
+	got := SimilarCars(car, []Car{listing, inStock, soldOut, noUnits}, equalWeights, 10, nil)
+	if len(got) != 3 || slices.Contains(ids(got), soldOut.ID) {
+		t.Errorf("similar cars = %v, want all but the sold out car", ids(got))
+	}
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// CarStatsFilter narrows the cars that statistics are computed over. Empty
+// fields do not filter. Prices are in major units of BaseCurrency.
+type CarStatsFilter struct {
+	Brand    string
+	FuelType string
+	YearFrom string
+	YearTo   string
+	MinPrice *float64
+	MaxPrice *float64
+}
+
+// Key identifies the filter for caching.
+func (f CarStatsFilter) Key() string {
+	price := func(p *float64) string {
+		if p == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*p, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%q|%q|%s|%s|%s|%s", f.Brand, f.FuelType, f.YearFrom, f.YearTo, price(f.MinPrice), price(f.MaxPrice))
+}
+
+func ValidateCarStatsFilter(f CarStatsFilter) error {
+	for _, year := range []string{f.YearFrom, f.YearTo} {
+		if year == "" {
+			continue
+		}
+		if _, err := strconv.Atoi(year); err != nil || len(year) != 4 {
+			return errors.New("year_from and year_to must be four-digit years")
+		}
+	}
+	if f.YearFrom != "" && f.YearTo != "" && f.YearFrom > f.YearTo {
+		return errors.New("year_from must not be after year_to")
+	}
+	if f.MinPrice != nil && f.MaxPrice != nil && *f.MinPrice > *f.MaxPrice {
+		return errors.New("min_price must not be greater than max_price")
+	}
+	return nil
+}
+
+// PriceStats summarises the cars in one group. Prices are in minor units of
+// BaseCurrency.
+type PriceStats struct {
+	Key      string `json:"key,omitempty"`
+	Count    int    `json:"count"`
+	MinPrice int64  `json:"min_price"`
+	AvgPrice int64  `json:"avg_price"`
+	MaxPrice int64  `json:"max_price"`
+}
+
+type CylinderCount struct {
+	Cylinders int64 `json:"cylinders"`
+	Count     int   `json:"count"`
+}
+
+type DisplacementBucket struct {
+	Bucket string `json:"bucket"`
+	Count  int    `json:"count"`
+}
+
+// CarStats is the inventory summary served by GET /stats/cars. Engine
+// distributions count cars, not engines.
+type CarStats struct {
+	Currency       string               `json:"currency"`
+	Overall        PriceStats           `json:"overall"`
+	ByBrand        []PriceStats         `json:"by_brand"`
+	ByFuelType     []PriceStats         `json:"by_fuel_type"`
+	ByYear         []PriceStats         `json:"by_year"`
+	ByCylinders    []CylinderCount      `json:"by_cylinders"`
+	ByDisplacement []DisplacementBucket `json:"by_displacement"`
+}
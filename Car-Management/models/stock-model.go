@@ -0,0 +1,76 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Stock unit statuses. A unit is received in stock, may be reserved for a
+// customer, and ends up sold.
+const (
+	StockInStock  = "in_stock"
+	StockReserved = "reserved"
+	StockSold     = "sold"
+)
+
+// StockUnit is one physical vehicle of a car listing.
+type StockUnit struct {
+	ID        uuid.UUID `json:"id"`
+	CarID     uuid.UUID `json:"car_id"`
+	VIN       string    `json:"vin"`
+	Colour    string    `json:"colour"`
+	Location  string    `json:"location"`
+	Lot       string    `json:"lot"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Availability counts a car's stock units by status.
+type Availability struct {
+	InStock  int `json:"in_stock"`
+	Reserved int `json:"reserved"`
+	Sold     int `json:"sold"`
+}
+
+type ReceiveStockRequest struct {
+	VIN      string `json:"vin"`
+	Colour   string `json:"colour"`
+	Location string `json:"location"`
+	Lot      string `json:"lot"`
+}
+
+type MoveStockRequest struct {
+	Location string `json:"location"`
+	Lot      string `json:"lot"`
+}
+
+func ValidateReceiveStockRequest(request ReceiveStockRequest) error {
+	if err := validateVIN(request.VIN); err != nil {
+		return err
+	}
+	if request.Colour == "" {
+		return errors.New("colour is required")
+	}
+	if request.Location == "" {
+		return errors.New("location is required")
+	}
+	return nil
+}
+
+func ValidateMoveStockRequest(request MoveStockRequest) error {
+	if request.Location == "" {
+		return errors.New("location is required")
+	}
+	return nil
+}
+
+// validateVIN checks a stock unit's VIN the way checkVIN checks a car's.
+func validateVIN(vin string) error {
+	if _, message := checkVIN(vin); message != "" {
+		return errors.New(message)
+	}
+	return nil
+}
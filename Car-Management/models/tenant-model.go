@@ -0,0 +1,32 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultTenantID is the dealership that owned every car and engine before
+// tenants were introduced. Tokens without a tenant claim are scoped to it.
+var DefaultTenantID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// Tenant is a dealership. Every car and engine belongs to exactly one tenant
+// and is invisible to the others.
+type Tenant struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type TenantRequest struct {
+	Name string `json:"name"`
+}
+
+func ValidateTenantRequest(tenantRequest TenantRequest) error {
+	if tenantRequest.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
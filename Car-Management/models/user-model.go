@@ -0,0 +1,48 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+)
+
+// MinPasswordLength is the shortest password a user can be given.
+const MinPasswordLength = 8
+
+// User is someone who can sign in. A user belongs to exactly one tenant and
+// only ever gets tokens for that tenant, with the user's role.
+type User struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	TenantID uuid.UUID `json:"tenant_id"`
+	Role     string    `json:"role"`
+	// PasswordHash is made by auth.HashPassword and never leaves the server.
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type UserRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// TenantID defaults to the caller's tenant. Only platform admins may
+	// name another one.
+	TenantID uuid.UUID `json:"tenant_id"`
+	Role     string    `json:"role"`
+}
+
+func ValidateUserRequest(userRequest UserRequest) error {
+	if strings.TrimSpace(userRequest.Username) == "" {
+		return errors.New("username is required")
+	}
+	if len(userRequest.Password) < MinPasswordLength {
+		return errors.New("password must be at least 8 characters")
+	}
+	if userRequest.Role != auth.RoleAdmin && userRequest.Role != auth.RoleUser {
+		return errors.New("role must be admin or user")
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"strings"
+)
+
+// Validation error codes. Clients should branch on these rather than on the
+// human-readable messages.
+const (
+	CodeRequired       = "required"
+	CodeNotANumber     = "not_a_number"
+	CodeOutOfRange     = "out_of_range"
+	CodeMustBePositive = "must_be_positive"
+	CodeInvalidChoice  = "invalid_choice"
+	CodeNotAllowed     = "not_allowed"
+	CodeInvalidFormat  = "invalid_format"
+	// CodeInvalidCheckDigit is a VIN whose check digit is wrong, and
+	// CodeMismatch a field contradicting what is decoded from another, such
+	// as a year that does not match the VIN's model year.
+	CodeInvalidCheckDigit = "invalid_check_digit"
+	CodeMismatch          = "mismatch"
+)
+
+// FieldError is one problem with one field of a request body. Pointer is the
+// RFC 6901 JSON pointer to the field, e.g. /engine/displacement.
+type FieldError struct {
+	Pointer string `json:"pointer"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) String() string {
+	return e.Pointer + ": " + e.Code
+}
+
+// ValidationError lists every problem found in a request body.
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, len(e.Errors))
+	for i, fieldError := range e.Errors {
+		problems[i] = fieldError.String()
+	}
+	return "validation failed: " + strings.Join(problems, ", ")
+}
+
+// add records a problem with the field at pointer.
+func (e *ValidationError) add(pointer, code, message string) {
+	e.Errors = append(e.Errors, FieldError{Pointer: pointer, Code: code, Message: message})
+}
+
+// err returns e, or nil when nothing was recorded, so that validators can
+// return it directly.
+func (e *ValidationError) err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
@@ -0,0 +1,244 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VIN regions, taken from the first character of the WMI.
+const (
+	RegionAfrica       = "Africa"
+	RegionAsia         = "Asia"
+	RegionEurope       = "Europe"
+	RegionNorthAmerica = "North America"
+	RegionOceania      = "Oceania"
+	RegionSouthAmerica = "South America"
+)
+
+// vinWeights weighs each VIN position in the check digit sum. Position 9,
+// the check digit itself, has no weight.
+var vinWeights = [17]int{8, 7, 6, 5, 4, 3, 2, 10, 0, 9, 8, 7, 6, 5, 4, 3, 2}
+
+// modelYearCodes are the model year codes at position 10, from 1980 on. The
+// sequence repeats every 30 years, so 'A' is 1980, 2010 or 2040.
+const modelYearCodes = "ABCDEFGHJKLMNPRSTVWXY123456789"
+
+// manufacturers maps the world manufacturer identifiers (WMI) of common
+// makes to the brand they build. The decoder works offline, so WMIs not
+// listed here are decoded without a manufacturer.
+var manufacturers = map[string]string{
+	"1HG": "Honda", "2HG": "Honda", "5FN": "Honda", "5J6": "Honda", "JHM": "Honda", "SHH": "Honda",
+	"JH4": "Acura", "19U": "Acura",
+	"4T1": "Toyota", "4T3": "Toyota", "5TD": "Toyota", "5YF": "Toyota", "2T1": "Toyota", "JTD": "Toyota", "JTE": "Toyota", "JTM": "Toyota", "JTN": "Toyota", "SB1": "Toyota",
+	"JTH": "Lexus", "2T2": "Lexus",
+	"1N4": "Nissan", "1N6": "Nissan", "5N1": "Nissan", "JN1": "Nissan", "JN8": "Nissan", "SJN": "Nissan",
+	"JM1": "Mazda", "JM3": "Mazda",
+	"JF1": "Subaru", "JF2": "Subaru", "4S3": "Subaru", "4S4": "Subaru",
+	"JA3": "Mitsubishi", "JA4": "Mitsubishi", "ML3": "Mitsubishi",
+	"JS2": "Suzuki", "JS3": "Suzuki",
+	"KMH": "Hyundai", "5NP": "Hyundai", "TMA": "Hyundai",
+	"KNA": "Kia", "KND": "Kia", "5XY": "Kia",
+	"1FA": "Ford", "1FM": "Ford", "1FT": "Ford", "3FA": "Ford", "WF0": "Ford",
+	"1G1": "Chevrolet", "1GC": "Chevrolet", "1GN": "Chevrolet", "2G1": "Chevrolet", "3GN": "Chevrolet",
+	"1GT": "GMC", "1G6": "Cadillac", "1GY": "Cadillac",
+	"1C4": "Jeep", "1J4": "Jeep",
+	"5YJ": "Tesla", "7SA": "Tesla", "LRW": "Tesla", "XP7": "Tesla",
+	"WBA": "BMW", "WBS": "BMW", "WBY": "BMW", "5UX": "BMW", "4US": "BMW",
+	"WMW": "MINI",
+	"WDB": "Mercedes-Benz", "WDD": "Mercedes-Benz", "WDC": "Mercedes-Benz", "W1K": "Mercedes-Benz", "W1N": "Mercedes-Benz", "4JG": "Mercedes-Benz",
+	"WAU": "Audi", "WA1": "Audi", "WUA": "Audi",
+	"WVW": "Volkswagen", "WV1": "Volkswagen", "WV2": "Volkswagen", "1VW": "Volkswagen", "3VW": "Volkswagen",
+	"WP0": "Porsche", "WP1": "Porsche",
+	"YV1": "Volvo", "YV4": "Volvo", "LYV": "Volvo",
+	"SAJ": "Jaguar", "SAL": "Land Rover", "SCC": "Lotus", "SCF": "Aston Martin",
+	"ZFF": "Ferrari", "ZHW": "Lamborghini", "ZAM": "Maserati", "ZAR": "Alfa Romeo", "ZFA": "Fiat",
+	"VF1": "Renault", "VF3": "Peugeot", "VF7": "Citroen",
+	"TMB": "Skoda", "VSS": "SEAT",
+	"LFV": "Volkswagen", "LSV": "Volkswagen",
+	"LGX": "BYD", "LC0": "BYD",
+	"MAL": "Hyundai", "MA3": "Suzuki",
+}
+
+// manufacturerAliases lists other names a car's brand may use for a
+// manufacturer.
+var manufacturerAliases = map[string][]string{
+	"Volkswagen":    {"VW"},
+	"Mercedes-Benz": {"Mercedes", "Mercedes Benz"},
+	"Chevrolet":     {"Chevy"},
+	"Citroen":       {"Citroën"},
+	"Skoda":         {"Škoda"},
+}
+
+// VINInfo is what the offline decoder reads from a VIN.
+type VINInfo struct {
+	VIN    string `json:"vin"`
+	WMI    string `json:"wmi"`
+	Region string `json:"region"`
+	// Manufacturer is empty when the WMI is not one the decoder knows.
+	Manufacturer string `json:"manufacturer,omitempty"`
+	// ModelYear is the most likely year of the model year code, or 0 if
+	// position 10 is not a model year code. Outside North America the code
+	// cannot tell 30-year cycles apart, and the latest possible year is used.
+	ModelYear int `json:"model_year,omitempty"`
+	// CheckDigitRequired reports whether position 9 must be the check digit,
+	// as in North America and China. Elsewhere it may be any character.
+	CheckDigitRequired bool `json:"check_digit_required"`
+}
+
+// DecodeVIN validates a VIN and decodes it. An invalid VIN returns a
+// *ValidationError.
+func DecodeVIN(vin string) (VINInfo, error) {
+	v := &ValidationError{}
+	if code, message := checkVIN(vin); code != "" {
+		v.add("/vin", code, message)
+		return VINInfo{}, v.err()
+	}
+	return decodeVIN(vin), nil
+}
+
+// VINCheckDigit computes the check digit of a VIN that has the right shape:
+// the weighted sum of the transliterated characters modulo 11, with 10
+// written as X.
+func VINCheckDigit(vin string) byte {
+	sum := 0
+	for i := 0; i < len(vinWeights); i++ {
+		sum += vinValue(vin[i]) * vinWeights[i]
+	}
+	if sum%11 == 10 {
+		return 'X'
+	}
+	return byte('0' + sum%11)
+}
+
+// checkVIN returns the code and message of the problem with vin, or empty
+// strings if there is none. A VIN is 17 characters from the VIN alphabet,
+// which excludes I, O and Q, and carries a valid check digit where one is
+// required.
+func checkVIN(vin string) (code, message string) {
+	if len(vin) != 17 {
+		return CodeInvalidFormat, "vin must be 17 characters"
+	}
+	for _, c := range vin {
+		if !(c >= '0' && c <= '9' || c >= 'A' && c <= 'Z') || strings.ContainsRune("IOQ", c) {
+			return CodeInvalidFormat, "vin may only contain digits and capital letters other than I, O and Q"
+		}
+	}
+	if checkDigitRequired(vin) {
+		if want := VINCheckDigit(vin); vin[8] != want {
+			return CodeInvalidCheckDigit, fmt.Sprintf("vin check digit is %c but should be %c", vin[8], want)
+		}
+	}
+	return "", ""
+}
+
+// validateCarVIN records the problems with a car's vin and, if it decodes,
+// whether it agrees with the car's year. The brand may be an alias the
+// stores know about, so ValidateVINBrand checks it once it is resolved.
+func validateCarVIN(v *ValidationError, car Car) {
+	if code, message := checkVIN(car.VIN); code != "" {
+		v.add("/vin", code, message)
+		return
+	}
+	info := decodeVIN(car.VIN)
+	if year, err := strconv.Atoi(car.Year); err == nil && info.ModelYear != 0 && (year-info.ModelYear)%30 != 0 {
+		v.add("/year", CodeMismatch, fmt.Sprintf("year %d does not match the vin's model year code %c, which stands for %d",
+			year, car.VIN[9], info.ModelYear))
+	}
+}
+
+// ValidateVINBrand returns a *ValidationError if vin, which ValidateCar has
+// accepted, was issued to a manufacturer other than brand. Stores call it
+// with the brand's canonical name, so that the brand's aliases match too.
+func ValidateVINBrand(vin, brand string) error {
+	if vin == "" || brand == "" {
+		return nil
+	}
+	v := &ValidationError{}
+	if manufacturer := decodeVIN(vin).Manufacturer; manufacturer != "" && !madeBy(brand, manufacturer) {
+		v.add("/brand", CodeMismatch, fmt.Sprintf("brand %s does not match the vin's manufacturer %s", brand, manufacturer))
+	}
+	return v.err()
+}
+
+// decodeVIN decodes a VIN that checkVIN accepts.
+func decodeVIN(vin string) VINInfo {
+	info := VINInfo{
+		VIN:                vin,
+		WMI:                vin[:3],
+		Region:             vinRegion(vin[0]),
+		Manufacturer:       manufacturers[vin[:3]],
+		CheckDigitRequired: checkDigitRequired(vin),
+	}
+	if i := strings.IndexByte(modelYearCodes, vin[9]); i >= 0 {
+		year := 1980 + i
+		if info.Region == RegionNorthAmerica {
+			// North American VINs have a letter at position 7 from 2010.
+			if c := vin[6]; c >= 'A' && c <= 'Z' {
+				year += 30
+			}
+		} else {
+			for year+30 <= time.Now().Year()+1 {
+				year += 30
+			}
+		}
+		info.ModelYear = year
+	}
+	return info
+}
+
+// checkDigitRequired reports whether position 9 of vin must be the check
+// digit, which is the case for vehicles made for North America and China.
+func checkDigitRequired(vin string) bool {
+	return vinRegion(vin[0]) == RegionNorthAmerica || vin[0] == 'L'
+}
+
+func vinRegion(c byte) string {
+	switch {
+	case c >= 'A' && c <= 'H':
+		return RegionAfrica
+	case c >= 'J' && c <= 'R':
+		return RegionAsia
+	case c >= 'S' && c <= 'Z':
+		return RegionEurope
+	case c >= '1' && c <= '5':
+		return RegionNorthAmerica
+	case c == '6' || c == '7':
+		return RegionOceania
+	default:
+		return RegionSouthAmerica
+	}
+}
+
+// vinValue transliterates a VIN character for the check digit.
+func vinValue(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'A' && c <= 'H':
+		return int(c-'A') + 1
+	case c >= 'J' && c <= 'N':
+		return int(c-'J') + 1
+	case c == 'P':
+		return 7
+	case c == 'R':
+		return 9
+	default:
+		return int(c-'S') + 2
+	}
+}
+
+// madeBy reports whether brand names manufacturer, ignoring case.
+func madeBy(brand, manufacturer string) bool {
+	brand = strings.TrimSpace(brand)
+	if strings.EqualFold(brand, manufacturer) {
+		return true
+	}
+	for _, alias := range manufacturerAliases[manufacturer] {
+		if strings.EqualFold(brand, alias) {
+			return true
+		}
+	}
+	return false
+}
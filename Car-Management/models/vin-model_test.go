@@ -0,0 +1,117 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+// withCheckDigit returns vin with position 9 set to its check digit.
+func withCheckDigit(vin string) string {
+	return vin[:8] + string(VINCheckDigit(vin)) + vin[9:]
+}
+
+func TestVINCheckDigit(t *testing.T) {
+	for vin, want := range map[string]byte{
+		"1HGCM82633A004352": '3',
+		"1M8GDM9AXKP042788": 'X',
+		"5YJ3E1EA0KF317000": '2',
+	} {
+		if got := VINCheckDigit(vin); got != want {
+			t.Errorf("VINCheckDigit(%s) = %c, want %c", vin, got, want)
+		}
+	}
+}
+
+func TestDecodeVIN(t *testing.T) {
+	tests := []struct {
+		vin  string
+		want VINInfo
+	}{
+		{"1HGCM82633A004352", VINInfo{WMI: "1HG", Region: RegionNorthAmerica, Manufacturer: "Honda", ModelYear: 2003, CheckDigitRequired: true}},
+		// A letter at position 7 puts a North American VIN in the 2010 cycle.
+		{"5YJ3E1EA2KF317000", VINInfo{WMI: "5YJ", Region: RegionNorthAmerica, Manufacturer: "Tesla", ModelYear: 2019, CheckDigitRequired: true}},
+		{"1M8GDM9AXKP042788", VINInfo{WMI: "1M8", Region: RegionNorthAmerica, ModelYear: 1989, CheckDigitRequired: true}},
+		// European VINs need no check digit, and the latest cycle is used.
+		{"WVWZZZ1JZ3W386752", VINInfo{WMI: "WVW", Region: RegionEurope, Manufacturer: "Volkswagen", ModelYear: 2003}},
+		{"JHMZZZ1JZUW386752", VINInfo{WMI: "JHM", Region: RegionAsia, Manufacturer: "Honda"}},
+	}
+	for _, tt := range tests {
+		got, err := DecodeVIN(tt.vin)
+		if err != nil {
+			t.Errorf("DecodeVIN(%s): %v", tt.vin, err)
+			continue
+		}
+		tt.want.VIN = tt.vin
+		if got != tt.want {
+			t.Errorf("DecodeVIN(%s) = %+v, want %+v", tt.vin, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeVINRejects(t *testing.T) {
+	chinese := withCheckDigit("LFV2A21K057123456")
+	wrong := chinese[:8] + "0" + chinese[9:]
+	if wrong == chinese {
+		wrong = chinese[:8] + "1" + chinese[9:]
+	}
+	if _, err := DecodeVIN(chinese); err != nil {
+		t.Errorf("DecodeVIN(%s): %v", chinese, err)
+	}
+	for vin, want := range map[string]string{
+		"1HGCM82643A004352":  "/vin: " + CodeInvalidCheckDigit,
+		wrong:                "/vin: " + CodeInvalidCheckDigit,
+		"1HGCM82633A00435":   "/vin: " + CodeInvalidFormat,
+		"1HGCM82633A0043521": "/vin: " + CodeInvalidFormat,
+		"1HGCM82633A0O4352":  "/vin: " + CodeInvalidFormat,
+		"1hgcm82633a004352":  "/vin: " + CodeInvalidFormat,
+	} {
+		_, err := DecodeVIN(vin)
+		if got := fieldErrors(t, err); !slices.Equal(got, []string{want}) {
+			t.Errorf("DecodeVIN(%s) = %v, want %s", vin, got, want)
+		}
+	}
+}
+
+func TestValidateCarVIN(t *testing.T) {
+	car := validCar()
+	car.VIN = "1HGCM82633A004352"
+	car.Year = "2003"
+	if err := ValidateCar(car); err != nil {
+		t.Errorf("ValidateCar with a matching VIN = %v, want nil", err)
+	}
+
+	car.Year = "2004"
+	if got := fieldErrors(t, ValidateCar(car)); !slices.Equal(got, []string{"/year: " + CodeMismatch}) {
+		t.Errorf("year not matching the VIN = %v, want /year mismatch", got)
+	}
+
+	car.VIN = "1HGCM82643A004352"
+	if got := fieldErrors(t, ValidateCar(car)); !slices.Equal(got, []string{"/vin: " + CodeInvalidCheckDigit}) {
+		t.Errorf("bad check digit = %v, want only the /vin error", got)
+	}
+}
+
+func TestValidateVINBrand(t *testing.T) {
+	for _, tt := range []struct {
+		vin, brand string
+		ok         bool
+	}{
+		{"1HGCM82633A004352", "Honda", true},
+		{"1HGCM82633A004352", " honda ", true},
+		{"1HGCM82633A004352", "Toyota", false},
+		{"WVWZZZ1JZ3W386752", "VW", true},
+		{"WVWZZZ1JZ3W386752", "vw", true},
+		// The decoder does not know every manufacturer.
+		{"1M8GDM9AXKP042788", "Motor Coach", true},
+		{"", "Honda", true},
+		{"1HGCM82633A004352", "", true},
+	} {
+		err := ValidateVINBrand(tt.vin, tt.brand)
+		if tt.ok && err != nil {
+			t.Errorf("ValidateVINBrand(%s, %q) = %v, want nil", tt.vin, tt.brand, err)
+		}
+		if !tt.ok && !slices.Equal(fieldErrors(t, err), []string{"/brand: " + CodeMismatch}) {
+			t.Errorf("ValidateVINBrand(%s, %q) = %v, want /brand mismatch", tt.vin, tt.brand, err)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package attachmentService
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/blob"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+// maxFilenameLength is the longest filename kept; longer names are cut.
+const maxFilenameLength = 255
+
+type AttachmentService struct {
+	store    store.AttachmentStoreInterface
+	blobs    blob.BlobStore
+	maxBytes int64
+}
+
+// NewAttachmentService returns a service that keeps attachment records in
+// store and their content in blobs, accepting files of up to maxBytes.
+func NewAttachmentService(store store.AttachmentStoreInterface, blobs blob.BlobStore, maxBytes int64) *AttachmentService {
+	return &AttachmentService{
+		store:    store,
+		blobs:    blobs,
+		maxBytes: maxBytes,
+	}
+}
+
+// AddAttachment uploads a file for a car and appends it to the car's
+// gallery. The file's type is sniffed from its content rather than trusted
+// from the client, and images get a JPEG thumbnail.
+func (s *AttachmentService) AddAttachment(ctx context.Context, carID uuid.UUID, filename string, content io.Reader) (models.Attachment, error) {
+	tracer := otel.Tracer("attachment-service")
+	ctx, span := tracer.Start(ctx, "AddAttachment-Service")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(content, s.maxBytes+1))
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	if int64(len(data)) > s.maxBytes {
+		return models.Attachment{}, store.ErrAttachmentTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	kind, ok := models.AttachmentKind(contentType)
+	if !ok {
+		return models.Attachment{}, store.ErrUnsupportedAttachment
+	}
+
+	key := path.Join(tenantID.String(), carID.String(), uuid.NewString())
+	attachment := models.Attachment{
+		CarID:       carID,
+		Kind:        kind,
+		Filename:    cleanFilename(filename),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		BlobKey:     key,
+	}
+
+	var thumb []byte
+	if kind == models.AttachmentImage {
+		thumb, err = thumbnail(data)
+		if err != nil {
+			return models.Attachment{}, err
+		}
+		attachment.ThumbnailKey = key + ".thumb.jpg"
+	}
+
+	if err := s.blobs.Put(ctx, attachment.BlobKey, bytes.NewReader(data)); err != nil {
+		return models.Attachment{}, err
+	}
+	if thumb != nil {
+		if err := s.blobs.Put(ctx, attachment.ThumbnailKey, bytes.NewReader(thumb)); err != nil {
+			s.deleteBlobs(ctx, attachment)
+			return models.Attachment{}, err
+		}
+	}
+
+	createdAttachment, err := s.store.CreateAttachment(ctx, attachment)
+	if err != nil {
+		s.deleteBlobs(ctx, attachment)
+		return models.Attachment{}, err
+	}
+	return createdAttachment, nil
+}
+
+func (s *AttachmentService) GetAttachmentById(ctx context.Context, attachmentID string) (models.Attachment, error) {
+	tracer := otel.Tracer("attachment-service")
+	ctx, span := tracer.Start(ctx, "GetAttachmentById-Service")
+	defer span.End()
+	attachment, err := s.store.GetAttachmentById(ctx, attachmentID)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	return attachment, nil
+}
+
+func (s *AttachmentService) GetAttachmentsByCar(ctx context.Context, carID string) ([]models.Attachment, error) {
+	tracer := otel.Tracer("attachment-service")
+	ctx, span := tracer.Start(ctx, "GetAttachmentsByCar-Service")
+	defer span.End()
+	attachments, err := s.store.GetAttachmentsByCar(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// OpenAttachment returns an attachment with its content, which the caller
+// must close.
+func (s *AttachmentService) OpenAttachment(ctx context.Context, attachmentID string) (models.Attachment, io.ReadCloser, error) {
+	tracer := otel.Tracer("attachment-service")
+	ctx, span := tracer.Start(ctx, "OpenAttachment-Service")
+	defer span.End()
+	attachment, err := s.store.GetAttachmentById(ctx, attachmentID)
+	if err != nil {
+		return models.Attachment{}, nil, err
+	}
+	content, err := s.blobs.Open(ctx, attachment.BlobKey)
+	if err != nil {
+		return models.Attachment{}, nil, err
+	}
+	return attachment, content, nil
+}
+
+// OpenThumbnail returns an image attachment with its JPEG thumbnail, which
+// the caller must close.
+func (s *AttachmentService) OpenThumbnail(ctx context.Context, attachmentID string) (models.Attachment, io.ReadCloser, error) {
+	tracer := otel.Tracer("attachment-service")
+	ctx, span := tracer.Start(ctx, "OpenThumbnail-Service")
+	defer span.End()
+	attachment, err := s.store.GetAttachmentById(ctx, attachmentID)
+	if err != nil {
+		return models.Attachment{}, nil, err
+	}
+	if attachment.ThumbnailKey == "" {
+		return models.Attachment{}, nil, store.ErrNoThumbnail
+	}
+	content, err := s.blobs.Open(ctx, attachment.ThumbnailKey)
+	if err != nil {
+		return models.Attachment{}, nil, err
+	}
+	return attachment, content, nil
+}
+
+func (s *AttachmentService) ReorderAttachments(ctx context.Context, carID string, ids []uuid.UUID) ([]models.Attachment, error) {
+	tracer := otel.Tracer("attachment-service")
+	ctx, span := tracer.Start(ctx, "ReorderAttachments-Service")
+	defer span.End()
+	attachments, err := s.store.ReorderAttachments(ctx, carID, ids)
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// DeleteAttachment removes an attachment and then its content. A file that
+// cannot be removed only wastes space, so that does not fail the delete.
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, attachmentID string) error {
+	tracer := otel.Tracer("attachment-service")
+	ctx, span := tracer.Start(ctx, "DeleteAttachment-Service")
+	defer span.End()
+	attachment, err := s.store.DeleteAttachment(ctx, attachmentID)
+	if err != nil {
+		return err
+	}
+	s.deleteBlobs(ctx, attachment)
+	return nil
+}
+
+// deleteBlobs removes an attachment's content and thumbnail, if any.
+func (s *AttachmentService) deleteBlobs(ctx context.Context, attachment models.Attachment) {
+	s.blobs.Delete(ctx, attachment.BlobKey)
+	if attachment.ThumbnailKey != "" {
+		s.blobs.Delete(ctx, attachment.ThumbnailKey)
+	}
+}
+
+// DeleteQueuedBlobs removes the files of attachments that were deleted with
+// their car. Keys whose files could not be removed stay queued and are tried
+// again on the next call.
+func (s *AttachmentService) DeleteQueuedBlobs(ctx context.Context) error {
+	tracer := otel.Tracer("attachment-service")
+	ctx, span := tracer.Start(ctx, "DeleteQueuedBlobs-Service")
+	defer span.End()
+	keys, err := s.store.GetQueuedBlobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, key := range keys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := s.store.ForgetQueuedBlob(ctx, key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// cleanFilename keeps the last element of a client-supplied filename, which
+// some browsers send with a Windows path.
+func cleanFilename(filename string) string {
+	filename = path.Base(strings.ReplaceAll(filename, `\`, "/"))
+	if filename == "." || filename == "/" {
+		return "attachment"
+	}
+	if len(filename) > maxFilenameLength {
+		filename = strings.ToValidUTF8(filename[:maxFilenameLength], "")
+	}
+	return filename
+}
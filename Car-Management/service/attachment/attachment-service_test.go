@@ -0,0 +1,186 @@
+package attachmentService
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/blob"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"github.com/nitesh111sinha/car-management/store/attachment"
+	"github.com/nitesh111sinha/car-management/store/storetest"
+)
+
+const testMaxBytes = 1 << 20
+
+type fixture struct {
+	service *AttachmentService
+	blobs   *blob.LocalStore
+	stores  storetest.Stores
+	ctx     context.Context
+	car     models.Car
+}
+
+func newFixture(t *testing.T) fixture {
+	t.Helper()
+	db := storetest.OpenSQLite(t)
+	s := storetest.SQLStores(db)
+	blobs, err := blob.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := storetest.NewTenant(t, s)
+	car := storetest.NewCar(ctx, t, s, "Honda", storetest.NewEngine(ctx, t, s))
+	return fixture{
+		service: NewAttachmentService(attachment.NewAttachmentStore(db), blobs, testMaxBytes),
+		blobs:   blobs,
+		stores:  s,
+		ctx:     ctx,
+		car:     car,
+	}
+}
+
+func (f fixture) add(t *testing.T, filename string, data []byte) models.Attachment {
+	t.Helper()
+	added, err := f.service.AddAttachment(f.ctx, f.car.ID, filename, bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("AddAttachment(%s): %v", filename, err)
+	}
+	return added
+}
+
+func readAll(t *testing.T, content io.ReadCloser) []byte {
+	t.Helper()
+	defer content.Close()
+	data, err := io.ReadAll(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestAddImage(t *testing.T) {
+	f := newFixture(t)
+	photo := encodePNG(t, image.NewGray(image.Rect(0, 0, 640, 480)))
+
+	added := f.add(t, `C:\Users\me\photo.png`, photo)
+	if added.Kind != models.AttachmentImage || added.ContentType != "image/png" || added.Filename != "photo.png" {
+		t.Errorf("attachment = %+v, want a PNG image named photo.png", added)
+	}
+	if added.Size != int64(len(photo)) || added.Position != 1 || added.ThumbnailKey != added.BlobKey+".thumb.jpg" {
+		t.Errorf("attachment = %+v, want the first image with a thumbnail", added)
+	}
+
+	_, content, err := f.service.OpenAttachment(f.ctx, added.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(readAll(t, content), photo) {
+		t.Error("OpenAttachment returned different content from what was uploaded")
+	}
+	_, thumb, err := f.service.OpenThumbnail(f.ctx, added.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, _, err := image.Decode(bytes.NewReader(readAll(t, thumb)))
+	if err != nil {
+		t.Fatalf("thumbnail: %v", err)
+	}
+	if got := img.Bounds().Size(); got != image.Pt(320, 240) {
+		t.Errorf("thumbnail is %v, want 320x240", got)
+	}
+}
+
+func TestAddDocument(t *testing.T) {
+	f := newFixture(t)
+	f.add(t, "photo.png", encodePNG(t, image.NewGray(image.Rect(0, 0, 10, 10))))
+
+	added := f.add(t, "spec.pdf", []byte("%PDF-1.7\n"))
+	if added.Kind != models.AttachmentDocument || added.ThumbnailKey != "" || added.Position != 2 {
+		t.Errorf("attachment = %+v, want the second attachment, a document without a thumbnail", added)
+	}
+	if _, _, err := f.service.OpenThumbnail(f.ctx, added.ID.String()); !errors.Is(err, store.ErrNoThumbnail) {
+		t.Errorf("OpenThumbnail(document) = %v, want ErrNoThumbnail", err)
+	}
+}
+
+func TestAddAttachmentRejects(t *testing.T) {
+	f := newFixture(t)
+	tests := []struct {
+		name  string
+		carID uuid.UUID
+		data  []byte
+		want  error
+	}{
+		{"too large", f.car.ID, bytes.Repeat([]byte("%PDF"), testMaxBytes/4+1), store.ErrAttachmentTooLarge},
+		{"text", f.car.ID, []byte("just some text"), store.ErrUnsupportedAttachment},
+		{"html", f.car.ID, []byte("<html><body>hi</body></html>"), store.ErrUnsupportedAttachment},
+		{"unknown car", uuid.New(), []byte("%PDF-1.7\n"), store.ErrCarNotFound},
+	}
+	for _, tt := range tests {
+		if _, err := f.service.AddAttachment(f.ctx, tt.carID, "file", bytes.NewReader(tt.data)); !errors.Is(err, tt.want) {
+			t.Errorf("%s: AddAttachment = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+	attachments, err := f.service.GetAttachmentsByCar(f.ctx, f.car.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attachments) != 0 {
+		t.Errorf("rejected uploads left %d attachments", len(attachments))
+	}
+}
+
+func TestReorderAttachments(t *testing.T) {
+	f := newFixture(t)
+	first := f.add(t, "a.pdf", []byte("%PDF-1.7\n"))
+	second := f.add(t, "b.pdf", []byte("%PDF-1.7\n"))
+
+	reordered, err := f.service.ReorderAttachments(f.ctx, f.car.ID.String(), []uuid.UUID{second.ID, first.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reordered) != 2 || reordered[0].ID != second.ID || reordered[0].Position != 1 || reordered[1].ID != first.ID {
+		t.Errorf("reordered = %+v, want b then a", reordered)
+	}
+
+	for _, ids := range [][]uuid.UUID{
+		{first.ID},
+		{first.ID, first.ID},
+		{first.ID, uuid.New()},
+	} {
+		if _, err := f.service.ReorderAttachments(f.ctx, f.car.ID.String(), ids); !errors.Is(err, store.ErrInvalidAttachmentOrder) {
+			t.Errorf("ReorderAttachments(%v) = %v, want ErrInvalidAttachmentOrder", ids, err)
+		}
+	}
+}
+
+func TestDeleteAttachment(t *testing.T) {
+	f := newFixture(t)
+	first := f.add(t, "photo.png", encodePNG(t, image.NewGray(image.Rect(0, 0, 10, 10))))
+	second := f.add(t, "spec.pdf", []byte("%PDF-1.7\n"))
+
+	if err := f.service.DeleteAttachment(f.ctx, first.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{first.BlobKey, first.ThumbnailKey} {
+		if _, err := f.blobs.Open(f.ctx, key); !errors.Is(err, blob.ErrNotFound) {
+			t.Errorf("blob %s after delete = %v, want ErrNotFound", key, err)
+		}
+	}
+	remaining, err := f.service.GetAttachmentById(f.ctx, second.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remaining.Position != 1 {
+		t.Errorf("position after deleting the first attachment = %d, want 1", remaining.Position)
+	}
+	if err := f.service.DeleteAttachment(f.ctx, first.ID.String()); !errors.Is(err, store.ErrAttachmentNotFound) {
+		t.Errorf("deleting twice = %v, want ErrAttachmentNotFound", err)
+	}
+}
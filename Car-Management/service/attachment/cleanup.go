@@ -0,0 +1,52 @@
+package attachmentService
+
+import (
+	"context"
+
+	"github.com/nitesh111sinha/car-management/service"
+)
+
+// CarService removes the files of a car's attachments once the wrapped
+// service has deleted the car.
+type CarService struct {
+	service.CarServiceInterface
+	attachments *AttachmentService
+}
+
+func NewCarService(next service.CarServiceInterface, attachments *AttachmentService) *CarService {
+	return &CarService{
+		CarServiceInterface: next,
+		attachments:         attachments,
+	}
+}
+
+func (s *CarService) DeleteCar(ctx context.Context, carID string) error {
+	if err := s.CarServiceInterface.DeleteCar(ctx, carID); err != nil {
+		return err
+	}
+	// The car is gone either way; files left behind stay queued.
+	s.attachments.DeleteQueuedBlobs(ctx)
+	return nil
+}
+
+// EngineService removes the files of the attachments of cars deleted along
+// with their engine.
+type EngineService struct {
+	service.EngineServiceInterface
+	attachments *AttachmentService
+}
+
+func NewEngineService(next service.EngineServiceInterface, attachments *AttachmentService) *EngineService {
+	return &EngineService{
+		EngineServiceInterface: next,
+		attachments:            attachments,
+	}
+}
+
+func (s *EngineService) DeleteEngine(ctx context.Context, engineID string, cascade bool) error {
+	if err := s.EngineServiceInterface.DeleteEngine(ctx, engineID, cascade); err != nil {
+		return err
+	}
+	s.attachments.DeleteQueuedBlobs(ctx)
+	return nil
+}
@@ -0,0 +1,77 @@
+package attachmentService
+
+import (
+	"errors"
+	"image"
+	"testing"
+
+	"github.com/nitesh111sinha/car-management/blob"
+	"github.com/nitesh111sinha/car-management/models"
+	carService "github.com/nitesh111sinha/car-management/service/car"
+	engineService "github.com/nitesh111sinha/car-management/service/engine"
+)
+
+func (f fixture) assertBlobsDeleted(t *testing.T, attachments ...models.Attachment) {
+	t.Helper()
+	for _, attachment := range attachments {
+		for _, key := range []string{attachment.BlobKey, attachment.ThumbnailKey} {
+			if key == "" {
+				continue
+			}
+			if _, err := f.blobs.Open(f.ctx, key); !errors.Is(err, blob.ErrNotFound) {
+				t.Errorf("blob %s after delete = %v, want ErrNotFound", key, err)
+			}
+		}
+	}
+	queued, err := f.service.store.GetQueuedBlobs(f.ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queued) != 0 {
+		t.Errorf("queued blobs after delete = %v, want none", queued)
+	}
+}
+
+func TestDeleteCarDeletesAttachmentFiles(t *testing.T) {
+	f := newFixture(t)
+	photo := f.add(t, "photo.png", encodePNG(t, image.NewGray(image.Rect(0, 0, 10, 10))))
+	spec := f.add(t, "spec.pdf", []byte("%PDF-1.7\n"))
+
+	cars := NewCarService(carService.NewCarService(f.stores.Cars, nil), f.service)
+	if err := cars.DeleteCar(f.ctx, f.car.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+	f.assertBlobsDeleted(t, photo, spec)
+}
+
+func TestCascadingEngineDeleteDeletesAttachmentFiles(t *testing.T) {
+	f := newFixture(t)
+	photo := f.add(t, "photo.png", encodePNG(t, image.NewGray(image.Rect(0, 0, 10, 10))))
+
+	engines := NewEngineService(engineService.NewEngineService(f.stores.Engines), f.service)
+	if err := engines.DeleteEngine(f.ctx, f.car.Engine.EngineID.String(), true); err != nil {
+		t.Fatal(err)
+	}
+	f.assertBlobsDeleted(t, photo)
+}
+
+func TestDeleteQueuedBlobsRetriesLeftovers(t *testing.T) {
+	f := newFixture(t)
+	photo := f.add(t, "photo.png", encodePNG(t, image.NewGray(image.Rect(0, 0, 10, 10))))
+
+	// Delete the car without the decorator, as a crash after the commit would.
+	if err := f.stores.Cars.DeleteCar(f.ctx, f.car.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+	queued, err := f.service.store.GetQueuedBlobs(f.ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queued) != 2 {
+		t.Fatalf("queued blobs = %v, want the file and its thumbnail", queued)
+	}
+	if err := f.service.DeleteQueuedBlobs(f.ctx); err != nil {
+		t.Fatal(err)
+	}
+	f.assertBlobsDeleted(t, photo)
+}
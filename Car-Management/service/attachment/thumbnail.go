@@ -0,0 +1,83 @@
+package attachmentService
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+
+	"github.com/nitesh111sinha/car-management/store"
+)
+
+const (
+	// thumbnailSize is the longest side of a thumbnail, in pixels.
+	thumbnailSize = 320
+	// maxImagePixels bounds the images that are decoded, since a small
+	// compressed file can expand to an enormous bitmap.
+	maxImagePixels = 50_000_000
+)
+
+// errImageTooLarge is returned for images with more than maxImagePixels.
+var errImageTooLarge = fmt.Errorf("%w: images may have at most %d pixels", store.ErrAttachmentTooLarge, maxImagePixels)
+
+// thumbnail decodes a JPEG, PNG or GIF image and returns a JPEG no larger
+// than thumbnailSize on either side. Each thumbnail pixel averages the image
+// pixels it covers, and transparent areas become white.
+func thumbnail(data []byte) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", store.ErrUnsupportedAttachment, err)
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return nil, errImageTooLarge
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", store.ErrUnsupportedAttachment, err)
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return nil, fmt.Errorf("%w: image is empty", store.ErrUnsupportedAttachment)
+	}
+	thumbWidth, thumbHeight := width, height
+	if width >= height && width > thumbnailSize {
+		thumbWidth, thumbHeight = thumbnailSize, max(1, height*thumbnailSize/width)
+	} else if height > width && height > thumbnailSize {
+		thumbWidth, thumbHeight = max(1, width*thumbnailSize/height), thumbnailSize
+	}
+
+	scaled := image.NewRGBA64(image.Rect(0, 0, thumbWidth, thumbHeight))
+	for y := 0; y < thumbHeight; y++ {
+		y0 := bounds.Min.Y + y*height/thumbHeight
+		y1 := bounds.Min.Y + (y+1)*height/thumbHeight
+		for x := 0; x < thumbWidth; x++ {
+			x0 := bounds.Min.X + x*width/thumbWidth
+			x1 := bounds.Min.X + (x+1)*width/thumbWidth
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			scaled.SetRGBA64(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+
+	thumb := image.NewRGBA(scaled.Bounds())
+	draw.Draw(thumb, thumb.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(thumb, thumb.Bounds(), scaled, image.Point{}, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
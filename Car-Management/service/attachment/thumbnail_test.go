@@ -0,0 +1,102 @@
+package attachmentService
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/nitesh111sinha/car-management/store"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func decodeThumbnail(t *testing.T, data []byte) image.Image {
+	t.Helper()
+	thumb, err := thumbnail(data)
+	if err != nil {
+		t.Fatalf("thumbnail: %v", err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(thumb))
+	if err != nil {
+		t.Fatalf("thumbnail is not a JPEG: %v", err)
+	}
+	return img
+}
+
+func TestThumbnailSize(t *testing.T) {
+	for _, tt := range []struct {
+		width, height int
+		wantW, wantH  int
+	}{
+		{640, 320, 320, 160},
+		{100, 800, 40, 320},
+		{1000, 1000, 320, 320},
+		{3000, 2, 320, 1},
+		{50, 30, 50, 30},
+	} {
+		img := decodeThumbnail(t, encodePNG(t, image.NewGray(image.Rect(0, 0, tt.width, tt.height))))
+		if got := img.Bounds().Size(); got != image.Pt(tt.wantW, tt.wantH) {
+			t.Errorf("%dx%d scaled to %v, want %dx%d", tt.width, tt.height, got, tt.wantW, tt.wantH)
+		}
+	}
+}
+
+func TestThumbnailAveragesPixels(t *testing.T) {
+	checkers := image.NewGray(image.Rect(0, 0, 640, 640))
+	for y := 0; y < 640; y++ {
+		for x := 0; x < 640; x++ {
+			if (x+y)%2 == 0 {
+				checkers.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+	img := decodeThumbnail(t, encodePNG(t, checkers))
+	if y := color.GrayModel.Convert(img.At(100, 100)).(color.Gray).Y; y < 118 || y > 138 {
+		t.Errorf("a checkerboard scaled down is %d, want mid grey", y)
+	}
+}
+
+func TestThumbnailFlattensTransparencyOnWhite(t *testing.T) {
+	img := decodeThumbnail(t, encodePNG(t, image.NewNRGBA(image.Rect(0, 0, 10, 10))))
+	if y := color.GrayModel.Convert(img.At(5, 5)).(color.Gray).Y; y < 245 {
+		t.Errorf("a transparent image became %d, want white", y)
+	}
+}
+
+// pngHeader returns the signature and header chunk of a PNG claiming the
+// given size, which is all that is read to learn an image's size.
+func pngHeader(width, height uint32) []byte {
+	ihdr := make([]byte, 17)
+	copy(ihdr, "IHDR")
+	binary.BigEndian.PutUint32(ihdr[4:], width)
+	binary.BigEndian.PutUint32(ihdr[8:], height)
+	ihdr[12], ihdr[13] = 8, 2 // 8-bit RGB
+	data := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0d")
+	data = append(data, ihdr...)
+	return binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(ihdr))
+}
+
+func TestThumbnailRejects(t *testing.T) {
+	if _, err := thumbnail(pngHeader(10000, 10000)); !errors.Is(err, store.ErrAttachmentTooLarge) {
+		t.Errorf("100 megapixel image = %v, want ErrAttachmentTooLarge", err)
+	}
+	if _, err := thumbnail([]byte("%PDF-1.7")); !errors.Is(err, store.ErrUnsupportedAttachment) {
+		t.Errorf("not an image = %v, want ErrUnsupportedAttachment", err)
+	}
+	if _, err := thumbnail(pngHeader(10, 10)); !errors.Is(err, store.ErrUnsupportedAttachment) {
+		t.Errorf("truncated image = %v, want ErrUnsupportedAttachment", err)
+	}
+}
@@ -0,0 +1,73 @@
+package brandService
+
+import (
+	"context"
+
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+type BrandService struct {
+	store store.BrandStoreInterface
+}
+
+func NewBrandService(store store.BrandStoreInterface) *BrandService {
+	return &BrandService{
+		store: store,
+	}
+}
+
+func (s *BrandService) GetBrandById(ctx context.Context, brandID string) (models.Brand, error) {
+	tracer := otel.Tracer("brand-service")
+	ctx, span := tracer.Start(ctx, "GetBrandById-Service")
+	defer span.End()
+	brand, err := s.store.GetBrandById(ctx, brandID)
+	if err != nil {
+		return models.Brand{}, err
+	}
+	return brand, nil
+}
+
+func (s *BrandService) GetBrands(ctx context.Context) ([]models.Brand, error) {
+	tracer := otel.Tracer("brand-service")
+	ctx, span := tracer.Start(ctx, "GetBrands-Service")
+	defer span.End()
+	brands, err := s.store.GetBrands(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return brands, nil
+}
+
+func (s *BrandService) CreateBrand(ctx context.Context, brand models.Brand) (models.Brand, error) {
+	tracer := otel.Tracer("brand-service")
+	ctx, span := tracer.Start(ctx, "CreateBrand-Service")
+	defer span.End()
+	createdBrand, err := s.store.CreateBrand(ctx, brand)
+	if err != nil {
+		return models.Brand{}, err
+	}
+	return createdBrand, nil
+}
+
+func (s *BrandService) UpdateBrand(ctx context.Context, brand models.Brand) (models.Brand, error) {
+	tracer := otel.Tracer("brand-service")
+	ctx, span := tracer.Start(ctx, "UpdateBrand-Service")
+	defer span.End()
+	updatedBrand, err := s.store.UpdateBrand(ctx, brand)
+	if err != nil {
+		return models.Brand{}, err
+	}
+	return updatedBrand, nil
+}
+
+func (s *BrandService) DeleteBrand(ctx context.Context, brandID string) error {
+	tracer := otel.Tracer("brand-service")
+	ctx, span := tracer.Start(ctx, "DeleteBrand-Service")
+	defer span.End()
+	if err := s.store.DeleteBrand(ctx, brandID); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package cachedService
+
+import (
+	"context"
+
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+)
+
+// BrandService does not cache brands, which are rarely read, but its
+// mutations invalidate every cached car: renaming a brand renames it on its
+// cars, and changing aliases changes which cars a brand lookup returns.
+type BrandService struct {
+	next  service.BrandServiceInterface
+	cache *Cache
+}
+
+func NewBrandService(next service.BrandServiceInterface, cache *Cache) *BrandService {
+	return &BrandService{
+		next:  next,
+		cache: cache,
+	}
+}
+
+func (s *BrandService) GetBrandById(ctx context.Context, brandID string) (models.Brand, error) {
+	return s.next.GetBrandById(ctx, brandID)
+}
+
+func (s *BrandService) GetBrands(ctx context.Context) ([]models.Brand, error) {
+	return s.next.GetBrands(ctx)
+}
+
+func (s *BrandService) CreateBrand(ctx context.Context, brand models.Brand) (models.Brand, error) {
+	defer s.cache.removePrefix(ctx, allCarsKeyPrefix)
+	return s.next.CreateBrand(ctx, brand)
+}
+
+func (s *BrandService) UpdateBrand(ctx context.Context, brand models.Brand) (models.Brand, error) {
+	defer s.cache.removePrefix(ctx, allCarsKeyPrefix)
+	return s.next.UpdateBrand(ctx, brand)
+}
+
+func (s *BrandService) DeleteBrand(ctx context.Context, brandID string) error {
+	defer s.cache.removePrefix(ctx, allCarsKeyPrefix)
+	return s.next.DeleteBrand(ctx, brandID)
+}
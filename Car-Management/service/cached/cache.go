@@ -0,0 +1,121 @@
+// Package cachedService provides read-through caching decorators for the car
+// and engine services. Both decorators share one Cache so that engine
+// mutations can also invalidate cached cars, which embed their engine.
+//
+// Every key is scoped to the tenant in the request context, so tenants never
+// see each other's entries and a mutation only invalidates its own tenant.
+package cachedService
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/cache"
+	"github.com/nitesh111sinha/car-management/driver"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	cacheHits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "service_cache_hits_total",
+			Help: "Total number of service cache hits",
+		},
+		[]string{"operation"},
+	)
+
+	cacheMisses = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "service_cache_misses_total",
+			Help: "Total number of service cache misses",
+		},
+		[]string{"operation"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(cacheHits)
+	prometheus.MustRegister(cacheMisses)
+}
+
+type Cache struct {
+	lru   *cache.LRU
+	group singleflight.Group
+}
+
+func NewCache(size int, ttl time.Duration) *Cache {
+	return &Cache{lru: cache.NewLRU(size, ttl)}
+}
+
+// load returns the cached value for key or calls fn to produce it. Concurrent
+// misses for the same key share a single call to fn. The load runs detached
+// from the caller's cancellation so one cancelled request does not fail the
+// others waiting on it. Results for which store returns false are not cached.
+//
+// Reads that must go to the primary bypass the cache: after a write, an entry
+// refilled from a lagging replica would otherwise hide it from the writer.
+func (c *Cache) load(ctx context.Context, operation, key string, fn func(ctx context.Context) (any, error), store func(any) bool) (any, error) {
+	key, ok := scope(ctx, key)
+	if !ok || driver.UsesPrimary(ctx) {
+		return fn(ctx)
+	}
+	if v, ok := c.lru.Get(key); ok {
+		cacheHits.WithLabelValues(operation).Inc()
+		return v, nil
+	}
+	cacheMisses.WithLabelValues(operation).Inc()
+
+	// Keying the flight by generation keeps callers that arrive after an
+	// invalidation from joining a load that may have read stale data.
+	generation := c.lru.Generation()
+	flightKey := strconv.FormatUint(generation, 10) + "|" + key
+	v, err, _ := c.group.Do(flightKey, func() (any, error) {
+		v, err := fn(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+		if store == nil || store(v) {
+			c.lru.SetIfGeneration(key, v, generation)
+		}
+		return v, nil
+	})
+	return v, err
+}
+
+// remove drops key for the tenant in ctx.
+func (c *Cache) remove(ctx context.Context, key string) {
+	if key, ok := scope(ctx, key); ok {
+		c.lru.Remove(key)
+		return
+	}
+	c.lru.Purge()
+}
+
+// removePrefix drops every key with prefix for the tenant in ctx. Without a
+// tenant nothing can be targeted, so everything is dropped.
+func (c *Cache) removePrefix(ctx context.Context, prefix string) {
+	if prefix, ok := scope(ctx, prefix); ok {
+		c.lru.RemovePrefix(prefix)
+		return
+	}
+	c.lru.Purge()
+}
+
+// purge drops every tenant's entries, for changes to data that all tenants
+// share.
+func (c *Cache) purge() {
+	c.lru.Purge()
+}
+
+// scope prefixes key with the tenant in ctx. Requests without a tenant are
+// not cached.
+func scope(ctx context.Context, key string) (string, bool) {
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return "", false
+	}
+	return tenantID.String() + "/" + key, true
+}
@@ -0,0 +1,152 @@
+package cachedService
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	carKeyPrefix  = "car:"
+	carsKeyPrefix = "cars"
+)
+
+// CarService caches car reads from the wrapped service and invalidates them on
+// every mutation.
+type CarService struct {
+	next  service.CarServiceInterface
+	cache *Cache
+}
+
+func NewCarService(next service.CarServiceInterface, cache *Cache) *CarService {
+	return &CarService{
+		next:  next,
+		cache: cache,
+	}
+}
+
+func (s *CarService) GetCarById(ctx context.Context, carID string) (models.Car, error) {
+	tracer := otel.Tracer("car-cache")
+	ctx, span := tracer.Start(ctx, "GetCarById-Cache")
+	defer span.End()
+	v, err := s.cache.load(ctx, "GetCarById", carKeyPrefix+carID,
+		func(ctx context.Context) (any, error) {
+			return s.next.GetCarById(ctx, carID)
+		},
+		// Unknown ids come back as a zero car; don't cache those.
+		func(v any) bool {
+			return v.(models.Car).ID != uuid.Nil
+		})
+	if err != nil {
+		return models.Car{}, err
+	}
+	return v.(models.Car), nil
+}
+
+// GetCarByVIN is cached as a listing, so that any car mutation drops it: a
+// VIN can move from one car to another.
+func (s *CarService) GetCarByVIN(ctx context.Context, vin string) (models.Car, error) {
+	tracer := otel.Tracer("car-cache")
+	ctx, span := tracer.Start(ctx, "GetCarByVIN-Cache")
+	defer span.End()
+	v, err := s.cache.load(ctx, "GetCarByVIN", carsKeyPrefix+":vin:"+vin,
+		func(ctx context.Context) (any, error) {
+			return s.next.GetCarByVIN(ctx, vin)
+		}, nil)
+	if err != nil {
+		return models.Car{}, err
+	}
+	return v.(models.Car), nil
+}
+
+func (s *CarService) GetCars(ctx context.Context) ([]models.Car, error) {
+	tracer := otel.Tracer("car-cache")
+	ctx, span := tracer.Start(ctx, "GetCars-Cache")
+	defer span.End()
+	v, err := s.cache.load(ctx, "GetCars", carsKeyPrefix,
+		func(ctx context.Context) (any, error) {
+			return s.next.GetCars(ctx)
+		}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return copyCars(v.([]models.Car)), nil
+}
+
+func (s *CarService) GetCarByBrand(ctx context.Context, brand string, isEngine bool) ([]models.Car, error) {
+	tracer := otel.Tracer("car-cache")
+	ctx, span := tracer.Start(ctx, "GetCarByBrand-Cache")
+	defer span.End()
+	key := carsKeyPrefix + ":brand:" + strconv.FormatBool(isEngine) + ":" + brand
+	v, err := s.cache.load(ctx, "GetCarByBrand", key,
+		func(ctx context.Context) (any, error) {
+			return s.next.GetCarByBrand(ctx, brand, isEngine)
+		}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return copyCars(v.([]models.Car)), nil
+}
+
+func (s *CarService) GetCarsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Car, error) {
+	tracer := otel.Tracer("car-cache")
+	ctx, span := tracer.Start(ctx, "GetCarsByIds-Cache")
+	defer span.End()
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = id.String()
+	}
+	v, err := s.cache.load(ctx, "GetCarsByIds", carsKeyPrefix+":ids:"+strings.Join(keys, ","),
+		func(ctx context.Context) (any, error) {
+			return s.next.GetCarsByIds(ctx, ids)
+		}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return copyCars(v.([]models.Car)), nil
+}
+
+// GetSimilarCars is not cached: sold-out cars are left out, and stock
+// changes through orders and stock units without passing through here.
+func (s *CarService) GetSimilarCars(ctx context.Context, carID string, weights models.SimilarityWeights, limit int) ([]models.SimilarCar, error) {
+	return s.next.GetSimilarCars(ctx, carID, weights, limit)
+}
+
+func (s *CarService) CreateCar(ctx context.Context, car models.Car) (models.Car, error) {
+	defer s.invalidate(ctx, "")
+	return s.next.CreateCar(ctx, car)
+}
+
+func (s *CarService) UpdateCar(ctx context.Context, car models.Car) (models.Car, error) {
+	defer s.invalidate(ctx, car.ID.String())
+	return s.next.UpdateCar(ctx, car)
+}
+
+func (s *CarService) DeleteCar(ctx context.Context, carID string) error {
+	defer s.invalidate(ctx, carID)
+	return s.next.DeleteCar(ctx, carID)
+}
+
+// invalidate drops the car's own entry and every cached listing. It runs after
+// the mutation, successful or not, so readers never see the old value once
+// the call has returned.
+func (s *CarService) invalidate(ctx context.Context, carID string) {
+	if carID != "" {
+		s.cache.remove(ctx, carKeyPrefix+carID)
+	}
+	s.cache.removePrefix(ctx, carsKeyPrefix)
+}
+
+// copyCars returns a copy of a cached slice so callers cannot modify the
+// cached value.
+func copyCars(cars []models.Car) []models.Car {
+	if cars == nil {
+		return nil
+	}
+	return append([]models.Car(nil), cars...)
+}
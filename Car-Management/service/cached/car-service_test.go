@@ -0,0 +1,184 @@
+package cachedService
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/driver"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"github.com/nitesh111sinha/car-management/store/storetest"
+)
+
+// fakeCars serves a fixed set of cars and counts the calls that reach it.
+type fakeCars struct {
+	service.CarServiceInterface
+	cars  map[string]models.Car
+	calls int
+}
+
+func (f *fakeCars) GetCarById(ctx context.Context, carID string) (models.Car, error) {
+	f.calls++
+	return f.cars[carID], nil
+}
+
+func (f *fakeCars) GetCars(ctx context.Context) ([]models.Car, error) {
+	f.calls++
+	cars := []models.Car{}
+	for _, car := range f.cars {
+		cars = append(cars, car)
+	}
+	return cars, nil
+}
+
+func (f *fakeCars) UpdateCar(ctx context.Context, car models.Car) (models.Car, error) {
+	f.cars[car.ID.String()] = car
+	return car, nil
+}
+
+type fakeEngines struct {
+	service.EngineServiceInterface
+}
+
+func (f *fakeEngines) UpdateEngine(ctx context.Context, engineID string, engine models.Engine) (models.Engine, error) {
+	return engine, nil
+}
+
+func newCachedCars(t *testing.T) (*CarService, *EngineService, *fakeCars, models.Car) {
+	t.Helper()
+	car := models.Car{ID: uuid.New(), Name: "Civic"}
+	fake := &fakeCars{cars: map[string]models.Car{car.ID.String(): car}}
+	cache := NewCache(100, time.Minute)
+	return NewCarService(fake, cache), NewEngineService(&fakeEngines{}, cache), fake, car
+}
+
+func TestCarReadsAreCachedUntilAMutation(t *testing.T) {
+	cars, _, fake, car := newCachedCars(t)
+	ctx := auth.WithTenant(context.Background(), uuid.New())
+
+	for range 2 {
+		if got, err := cars.GetCarById(ctx, car.ID.String()); err != nil || got.Name != "Civic" {
+			t.Fatalf("GetCarById = %+v, %v", got, err)
+		}
+		if _, err := cars.GetCars(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if fake.calls != 2 {
+		t.Fatalf("calls = %d, want 2: one per read before caching", fake.calls)
+	}
+
+	car.Name = "Accord"
+	if _, err := cars.UpdateCar(ctx, car); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := cars.GetCarById(ctx, car.ID.String()); got.Name != "Accord" {
+		t.Errorf("GetCarById after UpdateCar = %q, want Accord", got.Name)
+	}
+	if list, _ := cars.GetCars(ctx); len(list) != 1 || list[0].Name != "Accord" {
+		t.Errorf("GetCars after UpdateCar = %+v, want the updated car", list)
+	}
+}
+
+func TestCarCacheIsScopedToTenants(t *testing.T) {
+	cars, _, fake, car := newCachedCars(t)
+	tenantA := auth.WithTenant(context.Background(), uuid.New())
+	tenantB := auth.WithTenant(context.Background(), uuid.New())
+
+	cars.GetCarById(tenantA, car.ID.String())
+	cars.GetCarById(tenantB, car.ID.String())
+	if fake.calls != 2 {
+		t.Errorf("calls = %d, want 2: tenants must not share entries", fake.calls)
+	}
+
+	// Without a tenant nothing is cached.
+	cars.GetCarById(context.Background(), car.ID.String())
+	cars.GetCarById(context.Background(), car.ID.String())
+	if fake.calls != 4 {
+		t.Errorf("calls = %d, want 4: reads without a tenant are not cached", fake.calls)
+	}
+}
+
+func TestUnknownCarsAreNotCached(t *testing.T) {
+	cars, _, fake, _ := newCachedCars(t)
+	ctx := auth.WithTenant(context.Background(), uuid.New())
+	missing := uuid.NewString()
+
+	cars.GetCarById(ctx, missing)
+	cars.GetCarById(ctx, missing)
+	if fake.calls != 2 {
+		t.Errorf("calls = %d, want 2: a car created later must not be hidden", fake.calls)
+	}
+}
+
+func TestEngineMutationsDropCachedCars(t *testing.T) {
+	cars, engines, fake, car := newCachedCars(t)
+	ctx := auth.WithTenant(context.Background(), uuid.New())
+
+	cars.GetCarById(ctx, car.ID.String())
+	cars.GetCars(ctx)
+	if _, err := engines.UpdateEngine(ctx, uuid.NewString(), models.Engine{}); err != nil {
+		t.Fatal(err)
+	}
+	cars.GetCarById(ctx, car.ID.String())
+	cars.GetCars(ctx)
+	if fake.calls != 4 {
+		t.Errorf("calls = %d, want 4: cars embed their engine", fake.calls)
+	}
+}
+
+// laggingCars reads from a replica that has not seen any write yet, unless the
+// context sends reads to the primary.
+type laggingCars struct {
+	service.CarServiceInterface
+	db      *driver.DB
+	primary map[string]models.Car
+	replica map[string]models.Car
+}
+
+func (f *laggingCars) GetCarById(ctx context.Context, carID string) (models.Car, error) {
+	if driver.UsesPrimary(ctx) {
+		return f.primary[carID], nil
+	}
+	return f.replica[carID], nil
+}
+
+func (f *laggingCars) UpdateCar(ctx context.Context, car models.Car) (models.Car, error) {
+	if _, err := f.db.ExecContext(ctx, `SELECT 1`); err != nil {
+		return models.Car{}, err
+	}
+	f.primary[car.ID.String()] = car
+	return car, nil
+}
+
+func TestWriterReadsItsWriteThroughTheCache(t *testing.T) {
+	car := models.Car{ID: uuid.New(), Name: "Civic"}
+	fake := &laggingCars{
+		db:      storetest.OpenSQLite(t),
+		primary: map[string]models.Car{car.ID.String(): car},
+		replica: map[string]models.Car{car.ID.String(): car},
+	}
+	cars := NewCarService(fake, NewCache(100, time.Minute))
+	tenant := auth.WithTenant(context.Background(), uuid.New())
+	writer := driver.WithReadYourWrites(tenant)
+
+	car.Name = "Accord"
+	if _, err := cars.UpdateCar(writer, car); err != nil {
+		t.Fatal(err)
+	}
+	// Another request refills the entry from the lagging replica.
+	if got, _ := cars.GetCarById(tenant, car.ID.String()); got.Name != "Civic" {
+		t.Fatalf("read from the replica = %q, want the stale Civic", got.Name)
+	}
+
+	if got, _ := cars.GetCarById(writer, car.ID.String()); got.Name != "Accord" {
+		t.Errorf("writer read = %q, want its own write", got.Name)
+	}
+	// A later request of the writer is sent to the primary by its cookie.
+	if got, _ := cars.GetCarById(driver.WithPrimary(tenant), car.ID.String()); got.Name != "Accord" {
+		t.Errorf("read with the cookie = %q, want the write", got.Name)
+	}
+}
@@ -0,0 +1,92 @@
+package cachedService
+
+import (
+	"context"
+
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	engineKeyPrefix  = "engine:"
+	enginesKeyPrefix = "engines"
+	// allCarsKeyPrefix matches both single-car and car listing keys.
+	allCarsKeyPrefix = "car"
+)
+
+// EngineService caches engine reads from the wrapped service. Engine mutations
+// also invalidate every cached car, since cars embed their engine and deleting
+// an engine cascades to its cars.
+type EngineService struct {
+	next  service.EngineServiceInterface
+	cache *Cache
+}
+
+func NewEngineService(next service.EngineServiceInterface, cache *Cache) *EngineService {
+	return &EngineService{
+		next:  next,
+		cache: cache,
+	}
+}
+
+func (s *EngineService) GetEngineById(ctx context.Context, engineID string) (models.Engine, error) {
+	tracer := otel.Tracer("engine-cache")
+	ctx, span := tracer.Start(ctx, "GetEngineById-Cache")
+	defer span.End()
+	v, err := s.cache.load(ctx, "GetEngineById", engineKeyPrefix+engineID,
+		func(ctx context.Context) (any, error) {
+			return s.next.GetEngineById(ctx, engineID)
+		}, nil)
+	if err != nil {
+		return models.Engine{}, err
+	}
+	return v.(models.Engine), nil
+}
+
+func (s *EngineService) GetEngines(ctx context.Context) ([]models.Engine, error) {
+	tracer := otel.Tracer("engine-cache")
+	ctx, span := tracer.Start(ctx, "GetEngines-Cache")
+	defer span.End()
+	v, err := s.cache.load(ctx, "GetEngines", enginesKeyPrefix,
+		func(ctx context.Context) (any, error) {
+			return s.next.GetEngines(ctx)
+		}, nil)
+	if err != nil {
+		return nil, err
+	}
+	engines := v.([]models.Engine)
+	if engines == nil {
+		return nil, nil
+	}
+	return append([]models.Engine(nil), engines...), nil
+}
+
+func (s *EngineService) CreateEngine(ctx context.Context, engine models.Engine) (models.Engine, error) {
+	defer s.invalidate(ctx, "")
+	return s.next.CreateEngine(ctx, engine)
+}
+
+func (s *EngineService) UpdateEngine(ctx context.Context, engineID string, engine models.Engine) (models.Engine, error) {
+	defer s.invalidate(ctx, engineID)
+	return s.next.UpdateEngine(ctx, engineID, engine)
+}
+
+func (s *EngineService) DeleteEngine(ctx context.Context, engineID string, cascade bool) error {
+	defer s.invalidate(ctx, engineID)
+	return s.next.DeleteEngine(ctx, engineID, cascade)
+}
+
+// GetEngineCars is not cached; it is used to inspect an engine before
+// deleting it and should reflect the current state.
+func (s *EngineService) GetEngineCars(ctx context.Context, engineID string) ([]models.Car, error) {
+	return s.next.GetEngineCars(ctx, engineID)
+}
+
+func (s *EngineService) invalidate(ctx context.Context, engineID string) {
+	if engineID != "" {
+		s.cache.remove(ctx, engineKeyPrefix+engineID)
+		s.cache.removePrefix(ctx, allCarsKeyPrefix)
+	}
+	s.cache.removePrefix(ctx, enginesKeyPrefix)
+}
@@ -0,0 +1,41 @@
+package cachedService
+
+import (
+	"context"
+
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+)
+
+// ExchangeRateService does not cache rates, but its mutations drop the whole
+// cache: statistics are reported in the base currency, and rates are shared
+// by every tenant.
+type ExchangeRateService struct {
+	next  service.ExchangeRateServiceInterface
+	cache *Cache
+}
+
+func NewExchangeRateService(next service.ExchangeRateServiceInterface, cache *Cache) *ExchangeRateService {
+	return &ExchangeRateService{
+		next:  next,
+		cache: cache,
+	}
+}
+
+func (s *ExchangeRateService) GetExchangeRates(ctx context.Context) ([]models.ExchangeRate, error) {
+	return s.next.GetExchangeRates(ctx)
+}
+
+func (s *ExchangeRateService) SetExchangeRate(ctx context.Context, rate models.ExchangeRate) (models.ExchangeRate, error) {
+	defer s.cache.purge()
+	return s.next.SetExchangeRate(ctx, rate)
+}
+
+func (s *ExchangeRateService) DeleteExchangeRate(ctx context.Context, currency string) error {
+	defer s.cache.purge()
+	return s.next.DeleteExchangeRate(ctx, currency)
+}
+
+func (s *ExchangeRateService) ConvertCars(ctx context.Context, cars []models.Car, currency string) ([]models.Car, error) {
+	return s.next.ConvertCars(ctx, cars, currency)
+}
@@ -0,0 +1,41 @@
+package cachedService
+
+import (
+	"context"
+
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/service"
+	"go.opentelemetry.io/otel"
+)
+
+// statsKeyPrefix starts with carsKeyPrefix, so every car or engine mutation
+// that invalidates car listings also invalidates cached statistics.
+const statsKeyPrefix = carsKeyPrefix + ":stats:"
+
+// StatsService caches inventory statistics per filter.
+type StatsService struct {
+	next  service.StatsServiceInterface
+	cache *Cache
+}
+
+func NewStatsService(next service.StatsServiceInterface, cache *Cache) *StatsService {
+	return &StatsService{
+		next:  next,
+		cache: cache,
+	}
+}
+
+func (s *StatsService) GetCarStats(ctx context.Context, filter models.CarStatsFilter) (models.CarStats, error) {
+	tracer := otel.Tracer("stats-cache")
+	ctx, span := tracer.Start(ctx, "GetCarStats-Cache")
+	defer span.End()
+	v, err := s.cache.load(ctx, "GetCarStats", statsKeyPrefix+filter.Key(),
+		func(ctx context.Context) (any, error) {
+			return s.next.GetCarStats(ctx, filter)
+		}, nil)
+	if err != nil {
+		return models.CarStats{}, err
+	}
+	// The slices are shared with the cache; callers only encode them.
+	return v.(models.CarStats), nil
+}
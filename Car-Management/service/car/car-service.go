@@ -3,6 +3,7 @@ package carService
 import (
 	"context"
 
+	"github.com/google/uuid"
 	"github.com/nitesh111sinha/car-management/models"
 	"github.com/nitesh111sinha/car-management/store"
 	"go.opentelemetry.io/otel"
@@ -10,11 +11,16 @@ import (
 
 type CarService struct {
 	store store.CarStoreInterface
+	// rates is nil without the sql backend.
+	rates store.ExchangeRateStoreInterface
 }
 
-func NewCarService(store store.CarStoreInterface) *CarService {
+// NewCarService returns a car service. rates may be nil, in which case
+// similar cars priced in different currencies are not alike in price.
+func NewCarService(store store.CarStoreInterface, rates store.ExchangeRateStoreInterface) *CarService {
 	return &CarService{
 		store: store,
+		rates: rates,
 	}
 }
 
@@ -29,6 +35,17 @@ func (s *CarService) GetCarById(ctx context.Context, carID string) (models.Car,
 	return car, nil
 }
 
+func (s *CarService) GetCarByVIN(ctx context.Context, vin string) (models.Car, error) {
+	tracer := otel.Tracer("car-service")
+	ctx, span := tracer.Start(ctx, "GetCarByVIN-Service")
+	defer span.End()
+	car, err := s.store.GetCarByVIN(ctx, vin)
+	if err != nil {
+		return models.Car{}, err
+	}
+	return car, nil
+}
+
 func (s *CarService) GetCars(ctx context.Context) ([]models.Car, error) {
 	tracer := otel.Tracer("car-service")
 	ctx, span := tracer.Start(ctx, "GetCars-Service")
@@ -40,10 +57,55 @@ func (s *CarService) GetCars(ctx context.Context) ([]models.Car, error) {
 	return cars, nil
 }
 
+func (s *CarService) GetCarsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Car, error) {
+	tracer := otel.Tracer("car-service")
+	ctx, span := tracer.Start(ctx, "GetCarsByIds-Service")
+	defer span.End()
+	cars, err := s.store.GetCarsByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return cars, nil
+}
+
+// GetSimilarCars scores the tenant's other cars by their likeness to the car
+// and returns the best limit of them. Prices are compared in the base
+// currency.
+func (s *CarService) GetSimilarCars(ctx context.Context, carID string, weights models.SimilarityWeights, limit int) ([]models.SimilarCar, error) {
+	tracer := otel.Tracer("car-service")
+	ctx, span := tracer.Start(ctx, "GetSimilarCars-Service")
+	defer span.End()
+	car, err := s.store.GetCarById(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+	if car.ID == uuid.Nil {
+		return nil, store.ErrCarNotFound
+	}
+	candidates, err := s.store.GetCarsWithEngines(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rates := map[string]models.ExchangeRate{}
+	if s.rates != nil {
+		all, err := s.rates.GetExchangeRates(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, rate := range all {
+			rates[rate.Currency] = rate
+		}
+	}
+	return models.SimilarCars(car, candidates, weights, limit, rates), nil
+}
+
 func (s *CarService) UpdateCar(ctx context.Context, car models.Car) (models.Car, error) {
 	tracer := otel.Tracer("car-service")
 	ctx, span := tracer.Start(ctx, "UpdateCar-Service")
 	defer span.End()
+	if err := models.ValidateCar(car); err != nil {
+		return models.Car{}, err
+	}
 	updatedCar, err := s.store.UpdateCar(ctx, car)
 	if err != nil {
 		return models.Car{}, err
@@ -76,6 +138,9 @@ func (s *CarService) CreateCar(ctx context.Context, car models.Car) (models.Car,
 	tracer := otel.Tracer("car-service")
 	ctx, span := tracer.Start(ctx, "CreateCar-Service")
 	defer span.End()	
+	if err := models.ValidateCar(car); err != nil {
+		return models.Car{}, err
+	}
 	createdCar, err := s.store.CreateCar(ctx, car)
 	if err != nil {
 		return models.Car{}, err
@@ -0,0 +1,69 @@
+package carService
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"github.com/nitesh111sinha/car-management/store/exchangerate"
+	"github.com/nitesh111sinha/car-management/store/stock"
+	"github.com/nitesh111sinha/car-management/store/storetest"
+)
+
+var equalWeights = models.SimilarityWeights{Price: 1, Year: 1, FuelType: 1, Brand: 1, Engine: 1}
+
+func TestGetSimilarCars(t *testing.T) {
+	db := storetest.OpenSQLite(t)
+	s := storetest.SQLStores(db)
+	ctx := storetest.NewTenant(t, s)
+	rates := exchangerate.NewExchangeRateStore(db)
+	if _, err := rates.SetExchangeRate(ctx, models.ExchangeRate{Currency: "EUR", Rate: 0.5}); err != nil {
+		t.Fatal(err)
+	}
+	engine := storetest.NewEngine(ctx, t, s)
+	car := storetest.NewCar(ctx, t, s, "Honda", engine)
+	pricier := storetest.NewCar(ctx, t, s, "Honda", engine)
+	pricier.Price = models.Money{Amount: 3000000, Currency: "USD"}
+	if _, err := s.Cars.UpdateCar(ctx, pricier); err != nil {
+		t.Fatal(err)
+	}
+	euros := storetest.NewCar(ctx, t, s, "Honda", engine)
+	euros.Price = models.Money{Amount: 1250000, Currency: "EUR"}
+	if _, err := s.Cars.UpdateCar(ctx, euros); err != nil {
+		t.Fatal(err)
+	}
+	soldOut := storetest.NewCar(ctx, t, s, "Honda", engine)
+	units := stock.NewStockStore(db)
+	unit, err := units.ReceiveUnit(ctx, models.StockUnit{CarID: soldOut.ID, VIN: "1HGCM82633A004352"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := units.SellUnit(ctx, unit.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+
+	similar, err := NewCarService(s.Cars, rates).GetSimilarCars(ctx, car.ID.String(), equalWeights, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(similar) != 2 || similar[0].Car.ID != euros.ID || similar[1].Car.ID != pricier.ID {
+		t.Fatalf("similar = %+v, want the car priced in EUR, then the pricier one, without the sold out car", similar)
+	}
+	if similar[0].Score != 1 {
+		t.Errorf("12,500 EUR at 2 USD each scores %v, want 1", similar[0].Score)
+	}
+
+	withoutRates, err := NewCarService(s.Cars, nil).GetSimilarCars(ctx, car.ID.String(), equalWeights, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if withoutRates[0].Car.ID != pricier.ID {
+		t.Errorf("without rates the first car = %s, want the one priced in USD", withoutRates[0].Car.ID)
+	}
+
+	if _, err := NewCarService(s.Cars, rates).GetSimilarCars(ctx, uuid.NewString(), equalWeights, 5); !errors.Is(err, store.ErrCarNotFound) {
+		t.Errorf("unknown car = %v, want ErrCarNotFound", err)
+	}
+}
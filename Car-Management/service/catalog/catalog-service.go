@@ -0,0 +1,127 @@
+package catalogService
+
+import (
+	"context"
+
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+type CatalogService struct {
+	store store.CatalogStoreInterface
+}
+
+func NewCatalogService(store store.CatalogStoreInterface) *CatalogService {
+	return &CatalogService{
+		store: store,
+	}
+}
+
+func (s *CatalogService) GetModelsByBrand(ctx context.Context, brandID string) ([]models.CarModel, error) {
+	tracer := otel.Tracer("catalog-service")
+	ctx, span := tracer.Start(ctx, "GetModelsByBrand-Service")
+	defer span.End()
+	carModels, err := s.store.GetModelsByBrand(ctx, brandID)
+	if err != nil {
+		return nil, err
+	}
+	return carModels, nil
+}
+
+func (s *CatalogService) GetModelById(ctx context.Context, modelID string) (models.CarModel, error) {
+	tracer := otel.Tracer("catalog-service")
+	ctx, span := tracer.Start(ctx, "GetModelById-Service")
+	defer span.End()
+	model, err := s.store.GetModelById(ctx, modelID)
+	if err != nil {
+		return models.CarModel{}, err
+	}
+	return model, nil
+}
+
+func (s *CatalogService) CreateModel(ctx context.Context, model models.CarModel) (models.CarModel, error) {
+	tracer := otel.Tracer("catalog-service")
+	ctx, span := tracer.Start(ctx, "CreateModel-Service")
+	defer span.End()
+	createdModel, err := s.store.CreateModel(ctx, model)
+	if err != nil {
+		return models.CarModel{}, err
+	}
+	return createdModel, nil
+}
+
+func (s *CatalogService) UpdateModel(ctx context.Context, model models.CarModel) (models.CarModel, error) {
+	tracer := otel.Tracer("catalog-service")
+	ctx, span := tracer.Start(ctx, "UpdateModel-Service")
+	defer span.End()
+	updatedModel, err := s.store.UpdateModel(ctx, model)
+	if err != nil {
+		return models.CarModel{}, err
+	}
+	return updatedModel, nil
+}
+
+func (s *CatalogService) DeleteModel(ctx context.Context, modelID string) error {
+	tracer := otel.Tracer("catalog-service")
+	ctx, span := tracer.Start(ctx, "DeleteModel-Service")
+	defer span.End()
+	if err := s.store.DeleteModel(ctx, modelID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *CatalogService) GetTrimsByModel(ctx context.Context, modelID string) ([]models.Trim, error) {
+	tracer := otel.Tracer("catalog-service")
+	ctx, span := tracer.Start(ctx, "GetTrimsByModel-Service")
+	defer span.End()
+	trims, err := s.store.GetTrimsByModel(ctx, modelID)
+	if err != nil {
+		return nil, err
+	}
+	return trims, nil
+}
+
+func (s *CatalogService) GetTrimById(ctx context.Context, trimID string) (models.Trim, error) {
+	tracer := otel.Tracer("catalog-service")
+	ctx, span := tracer.Start(ctx, "GetTrimById-Service")
+	defer span.End()
+	trim, err := s.store.GetTrimById(ctx, trimID)
+	if err != nil {
+		return models.Trim{}, err
+	}
+	return trim, nil
+}
+
+func (s *CatalogService) CreateTrim(ctx context.Context, trim models.Trim) (models.Trim, error) {
+	tracer := otel.Tracer("catalog-service")
+	ctx, span := tracer.Start(ctx, "CreateTrim-Service")
+	defer span.End()
+	createdTrim, err := s.store.CreateTrim(ctx, trim)
+	if err != nil {
+		return models.Trim{}, err
+	}
+	return createdTrim, nil
+}
+
+func (s *CatalogService) UpdateTrim(ctx context.Context, trim models.Trim) (models.Trim, error) {
+	tracer := otel.Tracer("catalog-service")
+	ctx, span := tracer.Start(ctx, "UpdateTrim-Service")
+	defer span.End()
+	updatedTrim, err := s.store.UpdateTrim(ctx, trim)
+	if err != nil {
+		return models.Trim{}, err
+	}
+	return updatedTrim, nil
+}
+
+func (s *CatalogService) DeleteTrim(ctx context.Context, trimID string) error {
+	tracer := otel.Tracer("catalog-service")
+	ctx, span := tracer.Start(ctx, "DeleteTrim-Service")
+	defer span.End()
+	if err := s.store.DeleteTrim(ctx, trimID); err != nil {
+		return err
+	}
+	return nil
+}
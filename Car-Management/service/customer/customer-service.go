@@ -0,0 +1,121 @@
+package customerService
+
+import (
+	"context"
+
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+type CustomerService struct {
+	store store.CustomerStoreInterface
+}
+
+func NewCustomerService(store store.CustomerStoreInterface) *CustomerService {
+	return &CustomerService{
+		store: store,
+	}
+}
+
+func (s *CustomerService) CreateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error) {
+	tracer := otel.Tracer("customer-service")
+	ctx, span := tracer.Start(ctx, "CreateCustomer-Service")
+	defer span.End()
+	createdCustomer, err := s.store.CreateCustomer(ctx, customer)
+	if err != nil {
+		return models.Customer{}, err
+	}
+	return createdCustomer, nil
+}
+
+func (s *CustomerService) GetCustomerById(ctx context.Context, customerID string) (models.Customer, error) {
+	tracer := otel.Tracer("customer-service")
+	ctx, span := tracer.Start(ctx, "GetCustomerById-Service")
+	defer span.End()
+	customer, err := s.store.GetCustomerById(ctx, customerID)
+	if err != nil {
+		return models.Customer{}, err
+	}
+	return customer, nil
+}
+
+func (s *CustomerService) SearchCustomers(ctx context.Context, query string) ([]models.Customer, error) {
+	tracer := otel.Tracer("customer-service")
+	ctx, span := tracer.Start(ctx, "SearchCustomers-Service")
+	defer span.End()
+	customers, err := s.store.SearchCustomers(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return customers, nil
+}
+
+func (s *CustomerService) UpdateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error) {
+	tracer := otel.Tracer("customer-service")
+	ctx, span := tracer.Start(ctx, "UpdateCustomer-Service")
+	defer span.End()
+	updatedCustomer, err := s.store.UpdateCustomer(ctx, customer)
+	if err != nil {
+		return models.Customer{}, err
+	}
+	return updatedCustomer, nil
+}
+
+func (s *CustomerService) CreateEnquiry(ctx context.Context, enquiry models.Enquiry) (models.Enquiry, error) {
+	tracer := otel.Tracer("customer-service")
+	ctx, span := tracer.Start(ctx, "CreateEnquiry-Service")
+	defer span.End()
+	createdEnquiry, err := s.store.CreateEnquiry(ctx, enquiry)
+	if err != nil {
+		return models.Enquiry{}, err
+	}
+	return createdEnquiry, nil
+}
+
+func (s *CustomerService) GetEnquiriesByCustomer(ctx context.Context, customerID string) ([]models.Enquiry, error) {
+	tracer := otel.Tracer("customer-service")
+	ctx, span := tracer.Start(ctx, "GetEnquiriesByCustomer-Service")
+	defer span.End()
+	enquiries, err := s.store.GetEnquiriesByCustomer(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	return enquiries, nil
+}
+
+// GetTimeline lists the customer's reservations, order status changes and
+// enquiries, newest first.
+func (s *CustomerService) GetTimeline(ctx context.Context, customerID string) ([]models.TimelineEvent, error) {
+	tracer := otel.Tracer("customer-service")
+	ctx, span := tracer.Start(ctx, "GetTimeline-Service")
+	defer span.End()
+	record, err := s.store.GetCustomerRecord(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	return models.Timeline(record), nil
+}
+
+// ExportCustomer returns everything held about the customer.
+func (s *CustomerService) ExportCustomer(ctx context.Context, customerID string) (models.CustomerRecord, error) {
+	tracer := otel.Tracer("customer-service")
+	ctx, span := tracer.Start(ctx, "ExportCustomer-Service")
+	defer span.End()
+	record, err := s.store.GetCustomerRecord(ctx, customerID)
+	if err != nil {
+		return models.CustomerRecord{}, err
+	}
+	return record, nil
+}
+
+func (s *CustomerService) EraseCustomer(ctx context.Context, customerID string) (models.Customer, error) {
+	tracer := otel.Tracer("customer-service")
+	ctx, span := tracer.Start(ctx, "EraseCustomer-Service")
+	defer span.End()
+	erasedCustomer, err := s.store.EraseCustomer(ctx, customerID)
+	if err != nil {
+		return models.Customer{}, err
+	}
+	return erasedCustomer, nil
+}
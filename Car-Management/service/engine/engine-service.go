@@ -44,6 +44,9 @@ func (s *EngineService) UpdateEngine(ctx context.Context, engineID string, engin
 	tracer := otel.Tracer("engine-service")
 	ctx, span := tracer.Start(ctx, "UpdateEngine-Service")
 	defer span.End()
+	if err := models.ValidateEngine(engine); err != nil {
+		return models.Engine{}, err
+	}
 	updatedEngine, err := s.store.UpdateEngine(ctx, engineID, engine)
 	if err != nil {
 		return models.Engine{}, err
@@ -51,20 +54,34 @@ func (s *EngineService) UpdateEngine(ctx context.Context, engineID string, engin
 	return updatedEngine, nil
 }
 
-func (s *EngineService) DeleteEngine(ctx context.Context, engineID string) error {
+func (s *EngineService) DeleteEngine(ctx context.Context, engineID string, cascade bool) error {
 	tracer := otel.Tracer("engine-service")
 	ctx, span := tracer.Start(ctx, "DeleteEngine-Service")
 	defer span.End()
-	if err := s.store.DeleteEngine(ctx, engineID); err != nil {
+	if err := s.store.DeleteEngine(ctx, engineID, cascade); err != nil {
 		return err
 	}
 	return nil
 }
 
+func (s *EngineService) GetEngineCars(ctx context.Context, engineID string) ([]models.Car, error) {
+	tracer := otel.Tracer("engine-service")
+	ctx, span := tracer.Start(ctx, "GetEngineCars-Service")
+	defer span.End()
+	cars, err := s.store.GetEngineCars(ctx, engineID)
+	if err != nil {
+		return nil, err
+	}
+	return cars, nil
+}
+
 func (s *EngineService) CreateEngine(ctx context.Context, engine models.Engine) (models.Engine, error) {
 	tracer := otel.Tracer("engine-service")
 	ctx, span := tracer.Start(ctx, "CreateEngine-Service")
 	defer span.End()
+	if err := models.ValidateEngine(engine); err != nil {
+		return models.Engine{}, err
+	}
 	createdEngine, err := s.store.CreateEngine(ctx, engine)
 	if err != nil {
 		return models.Engine{}, err
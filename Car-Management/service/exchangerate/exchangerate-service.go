@@ -0,0 +1,98 @@
+package exchangeRateService
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+type ExchangeRateService struct {
+	store store.ExchangeRateStoreInterface
+}
+
+func NewExchangeRateService(store store.ExchangeRateStoreInterface) *ExchangeRateService {
+	return &ExchangeRateService{
+		store: store,
+	}
+}
+
+func (s *ExchangeRateService) GetExchangeRates(ctx context.Context) ([]models.ExchangeRate, error) {
+	tracer := otel.Tracer("exchangerate-service")
+	ctx, span := tracer.Start(ctx, "GetExchangeRates-Service")
+	defer span.End()
+	rates, err := s.store.GetExchangeRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// SetExchangeRate creates or replaces a currency's rate. The base currency's
+// rate cannot change.
+func (s *ExchangeRateService) SetExchangeRate(ctx context.Context, rate models.ExchangeRate) (models.ExchangeRate, error) {
+	tracer := otel.Tracer("exchangerate-service")
+	ctx, span := tracer.Start(ctx, "SetExchangeRate-Service")
+	defer span.End()
+	if err := models.ValidateExchangeRate(rate.Currency, models.ExchangeRateRequest{Rate: rate.Rate}); err != nil {
+		return models.ExchangeRate{}, err
+	}
+	if rate.Currency == models.BaseCurrency {
+		return models.ExchangeRate{}, store.ErrBaseCurrencyRate
+	}
+	savedRate, err := s.store.SetExchangeRate(ctx, rate)
+	if err != nil {
+		return models.ExchangeRate{}, err
+	}
+	return savedRate, nil
+}
+
+func (s *ExchangeRateService) DeleteExchangeRate(ctx context.Context, currency string) error {
+	tracer := otel.Tracer("exchangerate-service")
+	ctx, span := tracer.Start(ctx, "DeleteExchangeRate-Service")
+	defer span.End()
+	if currency == models.BaseCurrency {
+		return store.ErrBaseCurrencyRate
+	}
+	if err := s.store.DeleteExchangeRate(ctx, currency); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ConvertCars returns copies of cars with their prices converted to currency.
+// It fails if currency, or the currency of any car, has no exchange rate.
+func (s *ExchangeRateService) ConvertCars(ctx context.Context, cars []models.Car, currency string) ([]models.Car, error) {
+	tracer := otel.Tracer("exchangerate-service")
+	ctx, span := tracer.Start(ctx, "ConvertCars-Service")
+	defer span.End()
+	rates, err := s.store.GetExchangeRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byCurrency := make(map[string]models.ExchangeRate, len(rates))
+	for _, rate := range rates {
+		byCurrency[rate.Currency] = rate
+	}
+
+	to, ok := byCurrency[currency]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", store.ErrExchangeRateNotFound, currency)
+	}
+	converted := make([]models.Car, len(cars))
+	for i, car := range cars {
+		if car.Price.Currency == currency {
+			converted[i] = car
+			continue
+		}
+		from, ok := byCurrency[car.Price.Currency]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", store.ErrExchangeRateNotFound, car.Price.Currency)
+		}
+		car.Price = models.Convert(car.Price, from, to)
+		converted[i] = car
+	}
+	return converted, nil
+}
@@ -0,0 +1,76 @@
+package exchangeRateService
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"github.com/nitesh111sinha/car-management/store/exchangerate"
+	"github.com/nitesh111sinha/car-management/store/storetest"
+)
+
+func newService(t *testing.T) (*ExchangeRateService, context.Context) {
+	t.Helper()
+	s := NewExchangeRateService(exchangerate.NewExchangeRateStore(storetest.OpenSQLite(t)))
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{Username: "admin", TenantID: models.DefaultTenantID, Role: auth.RoleAdmin})
+	if _, err := s.SetExchangeRate(ctx, models.ExchangeRate{Currency: "EUR", Rate: 0.5}); err != nil {
+		t.Fatal(err)
+	}
+	return s, ctx
+}
+
+func TestSetExchangeRate(t *testing.T) {
+	s, ctx := newService(t)
+
+	rate, err := s.SetExchangeRate(ctx, models.ExchangeRate{Currency: "EUR", Rate: 0.9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate.Rate != 0.9 || rate.UpdatedBy != "admin" {
+		t.Errorf("rate = %+v, want 0.9 set by admin", rate)
+	}
+
+	var invalid *models.ValidationError
+	if _, err := s.SetExchangeRate(ctx, models.ExchangeRate{Currency: "EUR", Rate: -1}); !errors.As(err, &invalid) {
+		t.Errorf("negative rate = %v, want a *ValidationError", err)
+	}
+	if _, err := s.SetExchangeRate(ctx, models.ExchangeRate{Currency: "USD", Rate: 2}); !errors.Is(err, store.ErrBaseCurrencyRate) {
+		t.Errorf("setting the base currency = %v, want ErrBaseCurrencyRate", err)
+	}
+	if err := s.DeleteExchangeRate(ctx, "USD"); !errors.Is(err, store.ErrBaseCurrencyRate) {
+		t.Errorf("deleting the base currency = %v, want ErrBaseCurrencyRate", err)
+	}
+	if err := s.DeleteExchangeRate(ctx, "GBP"); !errors.Is(err, store.ErrExchangeRateNotFound) {
+		t.Errorf("deleting an unset rate = %v, want ErrExchangeRateNotFound", err)
+	}
+}
+
+func TestConvertCars(t *testing.T) {
+	s, ctx := newService(t)
+	cars := []models.Car{
+		{Name: "Civic", Price: models.Money{Amount: 2000000, Currency: "USD"}},
+		{Name: "Golf", Price: models.Money{Amount: 1500000, Currency: "EUR"}},
+	}
+
+	converted, err := s.ConvertCars(ctx, cars, "EUR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if converted[0].Price != (models.Money{Amount: 1000000, Currency: "EUR"}) || converted[1].Price != cars[1].Price {
+		t.Errorf("converted = %+v, want both in EUR", converted)
+	}
+	if cars[0].Price.Currency != "USD" {
+		t.Error("ConvertCars changed the cars it was given")
+	}
+
+	if _, err := s.ConvertCars(ctx, cars, "GBP"); !errors.Is(err, store.ErrExchangeRateNotFound) {
+		t.Errorf("converting to a currency without a rate = %v, want ErrExchangeRateNotFound", err)
+	}
+	cars = append(cars, models.Car{Name: "Mini", Price: models.Money{Amount: 1800000, Currency: "GBP"}})
+	if _, err := s.ConvertCars(ctx, cars, "USD"); !errors.Is(err, store.ErrExchangeRateNotFound) {
+		t.Errorf("converting a car priced without a rate = %v, want ErrExchangeRateNotFound", err)
+	}
+}
@@ -2,12 +2,19 @@ package service
 
 import (
 	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/nitesh111sinha/car-management/models"
 )
 
 type CarServiceInterface interface {
 	GetCarById(ctx context.Context, carID string) (models.Car, error)
+	GetCarByVIN(ctx context.Context, vin string) (models.Car, error)
 	GetCars(ctx context.Context) ([]models.Car, error)
+	GetCarsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Car, error)
+	GetSimilarCars(ctx context.Context, carID string, weights models.SimilarityWeights, limit int) ([]models.SimilarCar, error)
 	UpdateCar(ctx context.Context, car models.Car) (models.Car, error)
 	DeleteCar(ctx context.Context, carID string) error
 	GetCarByBrand(ctx context.Context, brand string, isEngine bool) ([]models.Car, error)
@@ -18,6 +25,115 @@ type EngineServiceInterface interface {
 	GetEngineById(ctx context.Context, engineID string) (models.Engine, error)
 	GetEngines(ctx context.Context) ([]models.Engine, error)
 	UpdateEngine(ctx context.Context, engineID string, engine models.Engine) (models.Engine, error)
-	DeleteEngine(ctx context.Context, engineID string) error
+	DeleteEngine(ctx context.Context, engineID string, cascade bool) error
+	GetEngineCars(ctx context.Context, engineID string) ([]models.Car, error)
 	CreateEngine(ctx context.Context, engine models.Engine) (models.Engine, error)
-}	
\ No newline at end of file
+}
+type TenantServiceInterface interface {
+	GetTenantById(ctx context.Context, tenantID string) (models.Tenant, error)
+	GetTenants(ctx context.Context) ([]models.Tenant, error)
+	CreateTenant(ctx context.Context, tenant models.Tenant) (models.Tenant, error)
+	UpdateTenant(ctx context.Context, tenant models.Tenant) (models.Tenant, error)
+	DeleteTenant(ctx context.Context, tenantID string) error
+}
+
+type UserServiceInterface interface {
+	// Authenticate returns the user with the username and password, or
+	// store.ErrInvalidCredentials.
+	Authenticate(ctx context.Context, username, password string) (models.User, error)
+	GetUsers(ctx context.Context) ([]models.User, error)
+	CreateUser(ctx context.Context, request models.UserRequest) (models.User, error)
+	// EnsureUser creates the user unless one with the username exists.
+	EnsureUser(ctx context.Context, request models.UserRequest) error
+	DeleteUser(ctx context.Context, userID string) error
+}
+
+type BrandServiceInterface interface {
+	GetBrandById(ctx context.Context, brandID string) (models.Brand, error)
+	GetBrands(ctx context.Context) ([]models.Brand, error)
+	CreateBrand(ctx context.Context, brand models.Brand) (models.Brand, error)
+	UpdateBrand(ctx context.Context, brand models.Brand) (models.Brand, error)
+	DeleteBrand(ctx context.Context, brandID string) error
+}
+
+type CatalogServiceInterface interface {
+	GetModelsByBrand(ctx context.Context, brandID string) ([]models.CarModel, error)
+	GetModelById(ctx context.Context, modelID string) (models.CarModel, error)
+	CreateModel(ctx context.Context, model models.CarModel) (models.CarModel, error)
+	UpdateModel(ctx context.Context, model models.CarModel) (models.CarModel, error)
+	DeleteModel(ctx context.Context, modelID string) error
+	GetTrimsByModel(ctx context.Context, modelID string) ([]models.Trim, error)
+	GetTrimById(ctx context.Context, trimID string) (models.Trim, error)
+	CreateTrim(ctx context.Context, trim models.Trim) (models.Trim, error)
+	UpdateTrim(ctx context.Context, trim models.Trim) (models.Trim, error)
+	DeleteTrim(ctx context.Context, trimID string) error
+}
+
+type StockServiceInterface interface {
+	ReceiveUnit(ctx context.Context, unit models.StockUnit) (models.StockUnit, error)
+	GetUnitById(ctx context.Context, unitID string) (models.StockUnit, error)
+	GetUnitsByCar(ctx context.Context, carID string) ([]models.StockUnit, error)
+	MoveUnit(ctx context.Context, unitID, location, lot string) (models.StockUnit, error)
+	SellUnit(ctx context.Context, unitID string) (models.StockUnit, error)
+	GetAvailability(ctx context.Context, carID string) (models.Availability, error)
+}
+
+type PriceServiceInterface interface {
+	GetPriceHistory(ctx context.Context, carID string) ([]models.PriceChange, error)
+	GetPriceMovements(ctx context.Context, window time.Duration) (models.PriceMovementReport, error)
+}
+
+type StatsServiceInterface interface {
+	GetCarStats(ctx context.Context, filter models.CarStatsFilter) (models.CarStats, error)
+}
+
+type ExchangeRateServiceInterface interface {
+	GetExchangeRates(ctx context.Context) ([]models.ExchangeRate, error)
+	SetExchangeRate(ctx context.Context, rate models.ExchangeRate) (models.ExchangeRate, error)
+	DeleteExchangeRate(ctx context.Context, currency string) error
+	ConvertCars(ctx context.Context, cars []models.Car, currency string) ([]models.Car, error)
+}
+
+type AttachmentServiceInterface interface {
+	AddAttachment(ctx context.Context, carID uuid.UUID, filename string, content io.Reader) (models.Attachment, error)
+	GetAttachmentById(ctx context.Context, attachmentID string) (models.Attachment, error)
+	GetAttachmentsByCar(ctx context.Context, carID string) ([]models.Attachment, error)
+	OpenAttachment(ctx context.Context, attachmentID string) (models.Attachment, io.ReadCloser, error)
+	OpenThumbnail(ctx context.Context, attachmentID string) (models.Attachment, io.ReadCloser, error)
+	ReorderAttachments(ctx context.Context, carID string, ids []uuid.UUID) ([]models.Attachment, error)
+	DeleteAttachment(ctx context.Context, attachmentID string) error
+}
+
+type ReservationServiceInterface interface {
+	GetLocations(ctx context.Context) ([]models.LocationHours, error)
+	GetLocationHours(ctx context.Context, location string) (models.LocationHours, error)
+	SetLocationHours(ctx context.Context, hours models.LocationHours) (models.LocationHours, error)
+	BookTestDrive(ctx context.Context, reservation models.Reservation) (models.Reservation, error)
+	GetReservationById(ctx context.Context, reservationID string) (models.Reservation, error)
+	GetReservationsByCar(ctx context.Context, carID string) ([]models.Reservation, error)
+	RescheduleReservation(ctx context.Context, reservationID string, request models.RescheduleRequest) (models.Reservation, error)
+	CancelReservation(ctx context.Context, reservationID string) (models.Reservation, error)
+	GetAvailability(ctx context.Context, carID uuid.UUID, location string, from time.Time, days int, slot time.Duration) (models.SlotAvailability, error)
+}
+
+type OrderServiceInterface interface {
+	CreateOrder(ctx context.Context, order models.Order) (models.Order, error)
+	GetOrderById(ctx context.Context, orderID string) (models.Order, error)
+	GetOrders(ctx context.Context, status string) ([]models.Order, error)
+	GetOrdersByCar(ctx context.Context, carID string) ([]models.Order, error)
+	UpdateOrder(ctx context.Context, order models.Order) (models.Order, error)
+	TransitionOrder(ctx context.Context, orderID, status string, request models.TransitionRequest) (models.Order, error)
+	GetOrderTransitions(ctx context.Context, orderID string) ([]models.OrderTransition, error)
+}
+
+type CustomerServiceInterface interface {
+	CreateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error)
+	GetCustomerById(ctx context.Context, customerID string) (models.Customer, error)
+	SearchCustomers(ctx context.Context, query string) ([]models.Customer, error)
+	UpdateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error)
+	CreateEnquiry(ctx context.Context, enquiry models.Enquiry) (models.Enquiry, error)
+	GetEnquiriesByCustomer(ctx context.Context, customerID string) ([]models.Enquiry, error)
+	GetTimeline(ctx context.Context, customerID string) ([]models.TimelineEvent, error)
+	ExportCustomer(ctx context.Context, customerID string) (models.CustomerRecord, error)
+	EraseCustomer(ctx context.Context, customerID string) (models.Customer, error)
+}
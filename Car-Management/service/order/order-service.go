@@ -0,0 +1,96 @@
+package orderService
+
+import (
+	"context"
+
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+type OrderService struct {
+	store store.OrderStoreInterface
+}
+
+func NewOrderService(store store.OrderStoreInterface) *OrderService {
+	return &OrderService{
+		store: store,
+	}
+}
+
+func (s *OrderService) CreateOrder(ctx context.Context, order models.Order) (models.Order, error) {
+	tracer := otel.Tracer("order-service")
+	ctx, span := tracer.Start(ctx, "CreateOrder-Service")
+	defer span.End()
+	createdOrder, err := s.store.CreateOrder(ctx, order)
+	if err != nil {
+		return models.Order{}, err
+	}
+	return createdOrder, nil
+}
+
+func (s *OrderService) GetOrderById(ctx context.Context, orderID string) (models.Order, error) {
+	tracer := otel.Tracer("order-service")
+	ctx, span := tracer.Start(ctx, "GetOrderById-Service")
+	defer span.End()
+	order, err := s.store.GetOrderById(ctx, orderID)
+	if err != nil {
+		return models.Order{}, err
+	}
+	return order, nil
+}
+
+func (s *OrderService) GetOrders(ctx context.Context, status string) ([]models.Order, error) {
+	tracer := otel.Tracer("order-service")
+	ctx, span := tracer.Start(ctx, "GetOrders-Service")
+	defer span.End()
+	orders, err := s.store.GetOrders(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (s *OrderService) GetOrdersByCar(ctx context.Context, carID string) ([]models.Order, error) {
+	tracer := otel.Tracer("order-service")
+	ctx, span := tracer.Start(ctx, "GetOrdersByCar-Service")
+	defer span.End()
+	orders, err := s.store.GetOrdersByCar(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (s *OrderService) UpdateOrder(ctx context.Context, order models.Order) (models.Order, error) {
+	tracer := otel.Tracer("order-service")
+	ctx, span := tracer.Start(ctx, "UpdateOrder-Service")
+	defer span.End()
+	updatedOrder, err := s.store.UpdateOrder(ctx, order)
+	if err != nil {
+		return models.Order{}, err
+	}
+	return updatedOrder, nil
+}
+
+func (s *OrderService) TransitionOrder(ctx context.Context, orderID, status string, request models.TransitionRequest) (models.Order, error) {
+	tracer := otel.Tracer("order-service")
+	ctx, span := tracer.Start(ctx, "TransitionOrder-Service")
+	defer span.End()
+	changedOrder, err := s.store.TransitionOrder(ctx, orderID, status, request)
+	if err != nil {
+		return models.Order{}, err
+	}
+	return changedOrder, nil
+}
+
+func (s *OrderService) GetOrderTransitions(ctx context.Context, orderID string) ([]models.OrderTransition, error) {
+	tracer := otel.Tracer("order-service")
+	ctx, span := tracer.Start(ctx, "GetOrderTransitions-Service")
+	defer span.End()
+	transitions, err := s.store.GetOrderTransitions(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
@@ -0,0 +1,53 @@
+package priceService
+
+import (
+	"context"
+	"time"
+
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+type PriceService struct {
+	store store.PriceStoreInterface
+}
+
+func NewPriceService(store store.PriceStoreInterface) *PriceService {
+	return &PriceService{
+		store: store,
+	}
+}
+
+func (s *PriceService) GetPriceHistory(ctx context.Context, carID string) ([]models.PriceChange, error) {
+	tracer := otel.Tracer("price-service")
+	ctx, span := tracer.Start(ctx, "GetPriceHistory-Service")
+	defer span.End()
+	changes, err := s.store.GetPriceHistory(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// GetPriceMovements reports price drops and increases per brand over the
+// window ending now.
+func (s *PriceService) GetPriceMovements(ctx context.Context, window time.Duration) (models.PriceMovementReport, error) {
+	tracer := otel.Tracer("price-service")
+	ctx, span := tracer.Start(ctx, "GetPriceMovements-Service")
+	defer span.End()
+	until := time.Now().UTC()
+	since := until.Add(-window)
+	movements, err := s.store.GetPriceMovements(ctx, since)
+	if err != nil {
+		return models.PriceMovementReport{}, err
+	}
+	if movements == nil {
+		movements = []models.BrandPriceMovement{}
+	}
+	return models.PriceMovementReport{
+		Since:  since,
+		Until:  until,
+		Brands: movements,
+	}, nil
+}
@@ -0,0 +1,171 @@
+package reservationService
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+type ReservationService struct {
+	store store.ReservationStoreInterface
+}
+
+func NewReservationService(store store.ReservationStoreInterface) *ReservationService {
+	return &ReservationService{
+		store: store,
+	}
+}
+
+func (s *ReservationService) GetLocations(ctx context.Context) ([]models.LocationHours, error) {
+	tracer := otel.Tracer("reservation-service")
+	ctx, span := tracer.Start(ctx, "GetLocations-Service")
+	defer span.End()
+	locations, err := s.store.GetLocations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+func (s *ReservationService) GetLocationHours(ctx context.Context, location string) (models.LocationHours, error) {
+	tracer := otel.Tracer("reservation-service")
+	ctx, span := tracer.Start(ctx, "GetLocationHours-Service")
+	defer span.End()
+	hours, err := s.store.GetLocationHours(ctx, location)
+	if err != nil {
+		return models.LocationHours{}, err
+	}
+	return hours, nil
+}
+
+func (s *ReservationService) SetLocationHours(ctx context.Context, hours models.LocationHours) (models.LocationHours, error) {
+	tracer := otel.Tracer("reservation-service")
+	ctx, span := tracer.Start(ctx, "SetLocationHours-Service")
+	defer span.End()
+	savedHours, err := s.store.SetLocationHours(ctx, hours)
+	if err != nil {
+		return models.LocationHours{}, err
+	}
+	return savedHours, nil
+}
+
+// BookTestDrive reserves the car for a test drive, which must fall within
+// the opening hours of the location.
+func (s *ReservationService) BookTestDrive(ctx context.Context, reservation models.Reservation) (models.Reservation, error) {
+	tracer := otel.Tracer("reservation-service")
+	ctx, span := tracer.Start(ctx, "BookTestDrive-Service")
+	defer span.End()
+	hours, err := s.store.GetLocationHours(ctx, reservation.Location)
+	if err != nil {
+		return models.Reservation{}, err
+	}
+	if err := models.ValidateOpeningHours(hours, reservation.StartsAt, reservation.EndsAt); err != nil {
+		return models.Reservation{}, err
+	}
+	bookedReservation, err := s.store.CreateReservation(ctx, reservation)
+	if err != nil {
+		return models.Reservation{}, err
+	}
+	return bookedReservation, nil
+}
+
+func (s *ReservationService) GetReservationById(ctx context.Context, reservationID string) (models.Reservation, error) {
+	tracer := otel.Tracer("reservation-service")
+	ctx, span := tracer.Start(ctx, "GetReservationById-Service")
+	defer span.End()
+	reservation, err := s.store.GetReservationById(ctx, reservationID)
+	if err != nil {
+		return models.Reservation{}, err
+	}
+	return reservation, nil
+}
+
+func (s *ReservationService) GetReservationsByCar(ctx context.Context, carID string) ([]models.Reservation, error) {
+	tracer := otel.Tracer("reservation-service")
+	ctx, span := tracer.Start(ctx, "GetReservationsByCar-Service")
+	defer span.End()
+	reservations, err := s.store.GetReservationsByCar(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// RescheduleReservation moves a test drive to another time and, when
+// request.Location is set, another location. The new time must fall within
+// the opening hours of the location.
+func (s *ReservationService) RescheduleReservation(ctx context.Context, reservationID string, request models.RescheduleRequest) (models.Reservation, error) {
+	tracer := otel.Tracer("reservation-service")
+	ctx, span := tracer.Start(ctx, "RescheduleReservation-Service")
+	defer span.End()
+	location := request.Location
+	if location == "" {
+		reservation, err := s.store.GetReservationById(ctx, reservationID)
+		if err != nil {
+			return models.Reservation{}, err
+		}
+		location = reservation.Location
+	}
+	hours, err := s.store.GetLocationHours(ctx, location)
+	if err != nil {
+		return models.Reservation{}, err
+	}
+	if err := models.ValidateOpeningHours(hours, request.StartsAt, request.EndsAt); err != nil {
+		return models.Reservation{}, err
+	}
+	rescheduledReservation, err := s.store.RescheduleReservation(ctx, reservationID, location, request.StartsAt, request.EndsAt)
+	if err != nil {
+		return models.Reservation{}, err
+	}
+	return rescheduledReservation, nil
+}
+
+func (s *ReservationService) CancelReservation(ctx context.Context, reservationID string) (models.Reservation, error) {
+	tracer := otel.Tracer("reservation-service")
+	ctx, span := tracer.Start(ctx, "CancelReservation-Service")
+	defer span.End()
+	cancelledReservation, err := s.store.CancelReservation(ctx, reservationID)
+	if err != nil {
+		return models.Reservation{}, err
+	}
+	return cancelledReservation, nil
+}
+
+// GetAvailability lists the free test drive slots of the car at the
+// location for the given number of days, starting on the date of `from`, or
+// today in the location's time zone when from is zero. Slots that have
+// already begun are left out.
+func (s *ReservationService) GetAvailability(ctx context.Context, carID uuid.UUID, location string, from time.Time, days int, slot time.Duration) (models.SlotAvailability, error) {
+	tracer := otel.Tracer("reservation-service")
+	ctx, span := tracer.Start(ctx, "GetAvailability-Service")
+	defer span.End()
+	hours, err := s.store.GetLocationHours(ctx, location)
+	if err != nil {
+		return models.SlotAvailability{}, err
+	}
+	// The time zone was checked when the hours were set.
+	tz, err := time.LoadLocation(hours.TimeZone)
+	if err != nil {
+		return models.SlotAvailability{}, err
+	}
+	if from.IsZero() {
+		from = time.Now().In(tz)
+	}
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, tz)
+	end := start.AddDate(0, 0, days)
+	booked, err := s.store.GetBookedReservations(ctx, carID.String(), start, end)
+	if err != nil {
+		return models.SlotAvailability{}, err
+	}
+	return models.SlotAvailability{
+		CarID:       carID,
+		Location:    hours.Location,
+		TimeZone:    hours.TimeZone,
+		SlotMinutes: int(slot / time.Minute),
+		Slots:       models.FreeSlots(hours, booked, start, end, time.Now(), slot),
+	}, nil
+}
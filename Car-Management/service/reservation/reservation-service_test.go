@@ -0,0 +1,157 @@
+package reservationService
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"github.com/nitesh111sinha/car-management/store/reservation"
+	"github.com/nitesh111sinha/car-management/store/storetest"
+)
+
+type fixture struct {
+	service *ReservationService
+	ctx     context.Context
+	car     models.Car
+	// day is midnight UTC a week from now; the showroom is open from 09:00
+	// to 17:00 UTC every day.
+	day time.Time
+}
+
+func newFixture(t *testing.T) fixture {
+	t.Helper()
+	db := storetest.OpenSQLite(t)
+	s := storetest.SQLStores(db)
+	ctx := storetest.NewTenant(t, s)
+	f := fixture{
+		service: NewReservationService(reservation.NewReservationStore(db)),
+		ctx:     ctx,
+		car:     storetest.NewCar(ctx, t, s, "Honda", storetest.NewEngine(ctx, t, s)),
+		day:     time.Now().UTC().AddDate(0, 0, 7).Truncate(24 * time.Hour),
+	}
+	hours := models.LocationHours{Location: "Showroom", TimeZone: "UTC"}
+	for day := 0; day <= 6; day++ {
+		hours.Hours = append(hours.Hours, models.OpeningHours{Weekday: day, Opens: "09:00", Closes: "17:00"})
+	}
+	if _, err := f.service.SetLocationHours(ctx, hours); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func (f fixture) at(hour int) time.Time {
+	return f.day.Add(time.Duration(hour) * time.Hour)
+}
+
+func (f fixture) book(from, to int) (models.Reservation, error) {
+	return f.service.BookTestDrive(f.ctx, models.Reservation{
+		CarID:        f.car.ID,
+		Location:     "Showroom",
+		CustomerName: "Alex",
+		StartsAt:     f.at(from),
+		EndsAt:       f.at(to),
+	})
+}
+
+func TestBookTestDrive(t *testing.T) {
+	f := newFixture(t)
+
+	booked, err := f.book(10, 11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if booked.Status != models.ReservationBooked || !booked.StartsAt.Equal(f.at(10)) {
+		t.Errorf("reservation = %+v, want booked from 10:00", booked)
+	}
+	if _, err := f.book(11, 12); err != nil {
+		t.Errorf("booking straight after another = %v, want nil", err)
+	}
+
+	if _, err := f.book(10, 12); !errors.Is(err, store.ErrReservationConflict) {
+		t.Errorf("overlapping booking = %v, want ErrReservationConflict", err)
+	}
+	var invalid *models.ValidationError
+	if _, err := f.book(16, 18); !errors.As(err, &invalid) {
+		t.Errorf("booking past closing = %v, want a *ValidationError", err)
+	}
+	if _, err := f.service.BookTestDrive(f.ctx, models.Reservation{CarID: f.car.ID, Location: "Depot", StartsAt: f.at(10), EndsAt: f.at(11)}); !errors.Is(err, store.ErrLocationNotFound) {
+		t.Errorf("unknown location = %v, want ErrLocationNotFound", err)
+	}
+	if _, err := f.service.BookTestDrive(f.ctx, models.Reservation{CarID: uuid.New(), Location: "Showroom", CustomerName: "Alex", StartsAt: f.at(13), EndsAt: f.at(14)}); !errors.Is(err, store.ErrCarNotFound) {
+		t.Errorf("unknown car = %v, want ErrCarNotFound", err)
+	}
+	if _, err := f.service.BookTestDrive(f.ctx, models.Reservation{CarID: f.car.ID, Location: "Showroom", CustomerID: uuid.NullUUID{UUID: uuid.New(), Valid: true}, StartsAt: f.at(13), EndsAt: f.at(14)}); !errors.Is(err, store.ErrInvalidCustomer) {
+		t.Errorf("unknown customer = %v, want ErrInvalidCustomer", err)
+	}
+}
+
+func TestRescheduleAndCancel(t *testing.T) {
+	f := newFixture(t)
+	first, err := f.book(10, 11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.book(12, 13); err != nil {
+		t.Fatal(err)
+	}
+
+	moved, err := f.service.RescheduleReservation(f.ctx, first.ID.String(), models.RescheduleRequest{StartsAt: f.at(10).Add(30 * time.Minute), EndsAt: f.at(11).Add(30 * time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.Location != "Showroom" || !moved.StartsAt.Equal(f.at(10).Add(30*time.Minute)) {
+		t.Errorf("rescheduled = %+v, want 10:30 at the showroom", moved)
+	}
+	if _, err := f.service.RescheduleReservation(f.ctx, first.ID.String(), models.RescheduleRequest{StartsAt: f.at(12), EndsAt: f.at(13)}); !errors.Is(err, store.ErrReservationConflict) {
+		t.Errorf("moving onto another booking = %v, want ErrReservationConflict", err)
+	}
+	if _, err := f.service.RescheduleReservation(f.ctx, uuid.NewString(), models.RescheduleRequest{StartsAt: f.at(14), EndsAt: f.at(15)}); !errors.Is(err, store.ErrReservationNotFound) {
+		t.Errorf("unknown reservation = %v, want ErrReservationNotFound", err)
+	}
+
+	cancelled, err := f.service.CancelReservation(f.ctx, first.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cancelled.Status != models.ReservationCancelled {
+		t.Errorf("status = %q, want cancelled", cancelled.Status)
+	}
+	if _, err := f.service.CancelReservation(f.ctx, first.ID.String()); !errors.Is(err, store.ErrReservationCancelled) {
+		t.Errorf("cancelling twice = %v, want ErrReservationCancelled", err)
+	}
+	if _, err := f.service.RescheduleReservation(f.ctx, first.ID.String(), models.RescheduleRequest{StartsAt: f.at(14), EndsAt: f.at(15)}); !errors.Is(err, store.ErrReservationCancelled) {
+		t.Errorf("rescheduling a cancelled reservation = %v, want ErrReservationCancelled", err)
+	}
+	if _, err := f.book(10, 11); err != nil {
+		t.Errorf("booking a cancelled slot = %v, want nil", err)
+	}
+}
+
+func TestGetAvailability(t *testing.T) {
+	f := newFixture(t)
+	if _, err := f.book(9, 11); err != nil {
+		t.Fatal(err)
+	}
+
+	availability, err := f.service.GetAvailability(f.ctx, f.car.ID, "Showroom", f.day, 1, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if availability.TimeZone != "UTC" || availability.SlotMinutes != 60 {
+		t.Errorf("availability = %+v, want hourly slots in UTC", availability)
+	}
+	if len(availability.Slots) != 6 || !availability.Slots[0].StartsAt.Equal(f.at(11)) {
+		t.Errorf("slots = %+v, want six from 11:00", availability.Slots)
+	}
+
+	if _, err := f.service.GetAvailability(f.ctx, f.car.ID, "Depot", f.day, 1, time.Hour); !errors.Is(err, store.ErrLocationNotFound) {
+		t.Errorf("unknown location = %v, want ErrLocationNotFound", err)
+	}
+	if _, err := f.service.GetAvailability(f.ctx, uuid.New(), "Showroom", f.day, 1, time.Hour); !errors.Is(err, store.ErrCarNotFound) {
+		t.Errorf("unknown car = %v, want ErrCarNotFound", err)
+	}
+}
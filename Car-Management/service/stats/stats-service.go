@@ -0,0 +1,30 @@
+package statsService
+
+import (
+	"context"
+
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+type StatsService struct {
+	store store.StatsStoreInterface
+}
+
+func NewStatsService(store store.StatsStoreInterface) *StatsService {
+	return &StatsService{
+		store: store,
+	}
+}
+
+func (s *StatsService) GetCarStats(ctx context.Context, filter models.CarStatsFilter) (models.CarStats, error) {
+	tracer := otel.Tracer("stats-service")
+	ctx, span := tracer.Start(ctx, "GetCarStats-Service")
+	defer span.End()
+	stats, err := s.store.GetCarStats(ctx, filter)
+	if err != nil {
+		return models.CarStats{}, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,85 @@
+package stockService
+
+import (
+	"context"
+
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+type StockService struct {
+	store store.StockStoreInterface
+}
+
+func NewStockService(store store.StockStoreInterface) *StockService {
+	return &StockService{
+		store: store,
+	}
+}
+
+func (s *StockService) ReceiveUnit(ctx context.Context, unit models.StockUnit) (models.StockUnit, error) {
+	tracer := otel.Tracer("stock-service")
+	ctx, span := tracer.Start(ctx, "ReceiveUnit-Service")
+	defer span.End()
+	receivedUnit, err := s.store.ReceiveUnit(ctx, unit)
+	if err != nil {
+		return models.StockUnit{}, err
+	}
+	return receivedUnit, nil
+}
+
+func (s *StockService) GetUnitById(ctx context.Context, unitID string) (models.StockUnit, error) {
+	tracer := otel.Tracer("stock-service")
+	ctx, span := tracer.Start(ctx, "GetUnitById-Service")
+	defer span.End()
+	unit, err := s.store.GetUnitById(ctx, unitID)
+	if err != nil {
+		return models.StockUnit{}, err
+	}
+	return unit, nil
+}
+
+func (s *StockService) GetUnitsByCar(ctx context.Context, carID string) ([]models.StockUnit, error) {
+	tracer := otel.Tracer("stock-service")
+	ctx, span := tracer.Start(ctx, "GetUnitsByCar-Service")
+	defer span.End()
+	units, err := s.store.GetUnitsByCar(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+	return units, nil
+}
+
+func (s *StockService) MoveUnit(ctx context.Context, unitID, location, lot string) (models.StockUnit, error) {
+	tracer := otel.Tracer("stock-service")
+	ctx, span := tracer.Start(ctx, "MoveUnit-Service")
+	defer span.End()
+	movedUnit, err := s.store.MoveUnit(ctx, unitID, location, lot)
+	if err != nil {
+		return models.StockUnit{}, err
+	}
+	return movedUnit, nil
+}
+
+func (s *StockService) SellUnit(ctx context.Context, unitID string) (models.StockUnit, error) {
+	tracer := otel.Tracer("stock-service")
+	ctx, span := tracer.Start(ctx, "SellUnit-Service")
+	defer span.End()
+	soldUnit, err := s.store.SellUnit(ctx, unitID)
+	if err != nil {
+		return models.StockUnit{}, err
+	}
+	return soldUnit, nil
+}
+
+func (s *StockService) GetAvailability(ctx context.Context, carID string) (models.Availability, error) {
+	tracer := otel.Tracer("stock-service")
+	ctx, span := tracer.Start(ctx, "GetAvailability-Service")
+	defer span.End()
+	availability, err := s.store.GetAvailability(ctx, carID)
+	if err != nil {
+		return models.Availability{}, err
+	}
+	return availability, nil
+}
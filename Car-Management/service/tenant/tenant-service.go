@@ -0,0 +1,73 @@
+package tenantService
+
+import (
+	"context"
+
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+type TenantService struct {
+	store store.TenantStoreInterface
+}
+
+func NewTenantService(store store.TenantStoreInterface) *TenantService {
+	return &TenantService{
+		store: store,
+	}
+}
+
+func (s *TenantService) GetTenantById(ctx context.Context, tenantID string) (models.Tenant, error) {
+	tracer := otel.Tracer("tenant-service")
+	ctx, span := tracer.Start(ctx, "GetTenantById-Service")
+	defer span.End()
+	tenant, err := s.store.GetTenantById(ctx, tenantID)
+	if err != nil {
+		return models.Tenant{}, err
+	}
+	return tenant, nil
+}
+
+func (s *TenantService) GetTenants(ctx context.Context) ([]models.Tenant, error) {
+	tracer := otel.Tracer("tenant-service")
+	ctx, span := tracer.Start(ctx, "GetTenants-Service")
+	defer span.End()
+	tenants, err := s.store.GetTenants(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+func (s *TenantService) CreateTenant(ctx context.Context, tenant models.Tenant) (models.Tenant, error) {
+	tracer := otel.Tracer("tenant-service")
+	ctx, span := tracer.Start(ctx, "CreateTenant-Service")
+	defer span.End()
+	createdTenant, err := s.store.CreateTenant(ctx, tenant)
+	if err != nil {
+		return models.Tenant{}, err
+	}
+	return createdTenant, nil
+}
+
+func (s *TenantService) UpdateTenant(ctx context.Context, tenant models.Tenant) (models.Tenant, error) {
+	tracer := otel.Tracer("tenant-service")
+	ctx, span := tracer.Start(ctx, "UpdateTenant-Service")
+	defer span.End()
+	updatedTenant, err := s.store.UpdateTenant(ctx, tenant)
+	if err != nil {
+		return models.Tenant{}, err
+	}
+	return updatedTenant, nil
+}
+
+func (s *TenantService) DeleteTenant(ctx context.Context, tenantID string) error {
+	tracer := otel.Tracer("tenant-service")
+	ctx, span := tracer.Start(ctx, "DeleteTenant-Service")
+	defer span.End()
+	if err := s.store.DeleteTenant(ctx, tenantID); err != nil {
+		return err
+	}
+	return nil
+}
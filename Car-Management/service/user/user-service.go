@@ -0,0 +1,128 @@
+package userService
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+// dummyHash is checked against when the username is unknown, so that login
+// takes as long for unknown users as for wrong passwords.
+var dummyHash = sync.OnceValue(func() string {
+	hash, _ := auth.HashPassword("not a password")
+	return hash
+})
+
+type UserService struct {
+	store store.UserStoreInterface
+}
+
+func NewUserService(store store.UserStoreInterface) *UserService {
+	return &UserService{
+		store: store,
+	}
+}
+
+func (s *UserService) Authenticate(ctx context.Context, username, password string) (models.User, error) {
+	tracer := otel.Tracer("user-service")
+	ctx, span := tracer.Start(ctx, "Authenticate-Service")
+	defer span.End()
+	user, err := s.store.GetUserByUsername(ctx, username)
+	if errors.Is(err, store.ErrUserNotFound) {
+		auth.CheckPassword(dummyHash(), password)
+		return models.User{}, store.ErrInvalidCredentials
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+	if !auth.CheckPassword(user.PasswordHash, password) {
+		return models.User{}, store.ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+func (s *UserService) GetUsers(ctx context.Context) ([]models.User, error) {
+	tracer := otel.Tracer("user-service")
+	ctx, span := tracer.Start(ctx, "GetUsers-Service")
+	defer span.End()
+	users, err := s.store.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// CreateUser adds a user to the caller's tenant, or to request.TenantID if
+// the caller is a platform admin setting up another tenant.
+func (s *UserService) CreateUser(ctx context.Context, request models.UserRequest) (models.User, error) {
+	tracer := otel.Tracer("user-service")
+	ctx, span := tracer.Start(ctx, "CreateUser-Service")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return models.User{}, err
+	}
+	principal, _ := auth.PrincipalFromContext(ctx)
+	if request.TenantID == uuid.Nil {
+		request.TenantID = tenantID
+	}
+	if request.TenantID != tenantID && principal.Role != auth.RolePlatformAdmin {
+		return models.User{}, store.ErrForeignTenant
+	}
+	return s.createUser(ctx, request)
+}
+
+func (s *UserService) createUser(ctx context.Context, request models.UserRequest) (models.User, error) {
+	hash, err := auth.HashPassword(request.Password)
+	if err != nil {
+		return models.User{}, err
+	}
+	createdUser, err := s.store.CreateUser(ctx, models.User{
+		Username:     request.Username,
+		TenantID:     request.TenantID,
+		Role:         request.Role,
+		PasswordHash: hash,
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+	return createdUser, nil
+}
+
+// EnsureUser creates the user unless one with the username already exists,
+// whatever its password, tenant and role. It bootstraps the platform admin
+// at startup, outside of any request, so it is not scoped to a tenant.
+func (s *UserService) EnsureUser(ctx context.Context, request models.UserRequest) error {
+	tracer := otel.Tracer("user-service")
+	ctx, span := tracer.Start(ctx, "EnsureUser-Service")
+	defer span.End()
+	_, err := s.store.GetUserByUsername(ctx, request.Username)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, store.ErrUserNotFound) {
+		return err
+	}
+	_, err = s.createUser(ctx, request)
+	if errors.Is(err, store.ErrDuplicateUsername) {
+		// Another instance created it first.
+		return nil
+	}
+	return err
+}
+
+func (s *UserService) DeleteUser(ctx context.Context, userID string) error {
+	tracer := otel.Tracer("user-service")
+	ctx, span := tracer.Start(ctx, "DeleteUser-Service")
+	defer span.End()
+	if err := s.store.DeleteUser(ctx, userID); err != nil {
+		return err
+	}
+	return nil
+}
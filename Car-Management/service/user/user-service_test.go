@@ -0,0 +1,126 @@
+package userService
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"github.com/nitesh111sinha/car-management/store/memory"
+)
+
+func newService(t *testing.T) *UserService {
+	t.Helper()
+	s := NewUserService(memory.NewUserStore(memory.NewDB()))
+	err := s.EnsureUser(context.Background(), models.UserRequest{
+		Username: "admin",
+		Password: "secret123",
+		TenantID: models.DefaultTenantID,
+		Role:     auth.RoleAdmin,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestAuthenticate(t *testing.T) {
+	s := newService(t)
+	ctx := context.Background()
+
+	user, err := s.Authenticate(ctx, "admin", "secret123")
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if user.TenantID != models.DefaultTenantID || user.Role != auth.RoleAdmin {
+		t.Errorf("user = %+v, want the default tenant's admin", user)
+	}
+
+	for name, credentials := range map[string][2]string{
+		"wrong password": {"admin", "secret456"},
+		"unknown user":   {"nobody", "secret123"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := s.Authenticate(ctx, credentials[0], credentials[1]); !errors.Is(err, store.ErrInvalidCredentials) {
+				t.Errorf("Authenticate = %v, want ErrInvalidCredentials", err)
+			}
+		})
+	}
+}
+
+func TestEnsureUserKeepsExistingUser(t *testing.T) {
+	s := newService(t)
+	ctx := context.Background()
+
+	err := s.EnsureUser(ctx, models.UserRequest{
+		Username: "admin",
+		Password: "changed123",
+		TenantID: models.DefaultTenantID,
+		Role:     auth.RoleUser,
+	})
+	if err != nil {
+		t.Fatalf("EnsureUser: %v", err)
+	}
+	user, err := s.Authenticate(ctx, "admin", "secret123")
+	if err != nil {
+		t.Fatalf("the original password no longer works: %v", err)
+	}
+	if user.Role != auth.RoleAdmin {
+		t.Errorf("role = %q, want the existing user left as admin", user.Role)
+	}
+}
+
+func TestCreateUserDoesNotStorePassword(t *testing.T) {
+	s := newService(t)
+	user, err := s.CreateUser(auth.WithTenant(context.Background(), models.DefaultTenantID), models.UserRequest{
+		Username: "clerk",
+		Password: "secret123",
+		Role:     auth.RoleUser,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.PasswordHash == "secret123" || !auth.CheckPassword(user.PasswordHash, "secret123") {
+		t.Errorf("PasswordHash = %q, want a hash of the password", user.PasswordHash)
+	}
+}
+
+func TestUsersAreScopedToTheCallersTenant(t *testing.T) {
+	db := memory.NewDB()
+	s := NewUserService(memory.NewUserStore(db))
+	other, err := memory.NewTenantStore(db).CreateTenant(context.Background(), models.Tenant{Name: "Northside Motors"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin := auth.WithPrincipal(context.Background(), auth.Principal{Username: "admin", TenantID: models.DefaultTenantID, Role: auth.RoleAdmin})
+	platformAdmin := auth.WithPrincipal(context.Background(), auth.Principal{Username: "root", TenantID: models.DefaultTenantID, Role: auth.RolePlatformAdmin})
+	otherAdmin := auth.WithPrincipal(context.Background(), auth.Principal{Username: "north", TenantID: other.ID, Role: auth.RoleAdmin})
+
+	_, err = s.CreateUser(admin, models.UserRequest{Username: "intruder", Password: "secret123", TenantID: other.ID, Role: auth.RoleAdmin})
+	if !errors.Is(err, store.ErrForeignTenant) {
+		t.Fatalf("admin creating a user in another tenant = %v, want ErrForeignTenant", err)
+	}
+	clerk, err := s.CreateUser(platformAdmin, models.UserRequest{Username: "clerk", Password: "secret123", TenantID: other.ID, Role: auth.RoleUser})
+	if err != nil {
+		t.Fatalf("platform admin creating a user in another tenant: %v", err)
+	}
+	if clerk.TenantID != other.ID {
+		t.Errorf("tenant = %s, want %s", clerk.TenantID, other.ID)
+	}
+
+	users, err := s.GetUsers(admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 0 {
+		t.Errorf("GetUsers = %+v, want none of the other tenant's users", users)
+	}
+	if err := s.DeleteUser(admin, clerk.ID.String()); !errors.Is(err, store.ErrUserNotFound) {
+		t.Errorf("deleting another tenant's user = %v, want ErrUserNotFound", err)
+	}
+	if err := s.DeleteUser(otherAdmin, clerk.ID.String()); err != nil {
+		t.Errorf("deleting a user of the own tenant: %v", err)
+	}
+}
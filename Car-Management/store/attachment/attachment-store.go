@@ -0,0 +1,375 @@
+package attachment
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/driver"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+const attachmentColumns = `id, car_id, kind, filename, content_type, size_bytes, position, blob_key, thumbnail_key, created_by, created_at`
+
+const selectAttachmentQuery = `SELECT ` + attachmentColumns + ` FROM attachment WHERE id=$1 AND tenant_id=$2`
+
+const selectAttachmentsByCarQuery = `SELECT ` + attachmentColumns + ` FROM attachment WHERE car_id=$1 AND tenant_id=$2 ORDER BY position, created_at, id`
+
+type AttachmentStore struct {
+	db *driver.DB
+}
+
+func NewAttachmentStore(db *driver.DB) *AttachmentStore {
+	return &AttachmentStore{db: db}
+}
+
+// CreateAttachment records an attachment whose content is already in the
+// blob store, placing it last in the car's gallery.
+func (s AttachmentStore) CreateAttachment(ctx context.Context, attachment models.Attachment) (models.Attachment, error) {
+	tracer := otel.Tracer("attachment-store")
+	ctx, span := tracer.Start(ctx, "CreateAttachment-Store")
+	defer span.End()
+	var createdAttachment models.Attachment
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return createdAttachment, err
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return createdAttachment, err
+	}
+
+	// Lock the car so concurrent uploads do not take the same position.
+	// SQLite transactions already hold the database write lock.
+	if err := lockCar(ctx, tx, attachment.CarID.String(), tenantID); err != nil {
+		tx.Rollback()
+		return createdAttachment, err
+	}
+
+	var position int
+	err = tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(position), 0) + 1 FROM attachment WHERE car_id=$1 AND tenant_id=$2`, attachment.CarID, tenantID).Scan(&position)
+	if err != nil {
+		tx.Rollback()
+		return createdAttachment, err
+	}
+
+	id := uuid.New()
+	query := `INSERT INTO attachment (id, tenant_id, car_id, kind, filename, content_type, size_bytes, position, blob_key, thumbnail_key, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	_, err = tx.ExecContext(ctx, query,
+		id,
+		tenantID,
+		attachment.CarID,
+		attachment.Kind,
+		attachment.Filename,
+		attachment.ContentType,
+		attachment.Size,
+		position,
+		attachment.BlobKey,
+		attachment.ThumbnailKey,
+		auth.Actor(ctx),
+		time.Now().UTC())
+	if err != nil {
+		tx.Rollback()
+		return createdAttachment, err
+	}
+
+	createdAttachment, err = scanAttachment(tx.QueryRowContext(ctx, selectAttachmentQuery, id, tenantID))
+	if err != nil {
+		tx.Rollback()
+		return createdAttachment, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return createdAttachment, err
+	}
+
+	return createdAttachment, nil
+}
+
+func (s AttachmentStore) GetAttachmentById(ctx context.Context, attachmentID string) (models.Attachment, error) {
+	tracer := otel.Tracer("attachment-store")
+	ctx, span := tracer.Start(ctx, "GetAttachmentById-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+
+	attachment, err := scanAttachment(s.db.QueryRowContext(ctx, selectAttachmentQuery, attachmentID, tenantID))
+	if err == sql.ErrNoRows {
+		return attachment, store.ErrAttachmentNotFound
+	}
+	return attachment, err
+}
+
+// GetAttachmentsByCar returns the car's attachments in gallery order.
+func (s AttachmentStore) GetAttachmentsByCar(ctx context.Context, carID string) ([]models.Attachment, error) {
+	tracer := otel.Tracer("attachment-store")
+	ctx, span := tracer.Start(ctx, "GetAttachmentsByCar-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachmentsByCar(ctx, s.db, carID, tenantID)
+}
+
+// ReorderAttachments puts the car's attachments in the order of ids, which
+// must list each of them exactly once.
+func (s AttachmentStore) ReorderAttachments(ctx context.Context, carID string, ids []uuid.UUID) ([]models.Attachment, error) {
+	tracer := otel.Tracer("attachment-store")
+	ctx, span := tracer.Start(ctx, "ReorderAttachments-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lockCar(ctx, tx, carID, tenantID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	current, err := attachmentsByCar(ctx, tx, carID, tenantID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if !sameAttachments(current, ids) {
+		tx.Rollback()
+		return nil, store.ErrInvalidAttachmentOrder
+	}
+
+	for i, id := range ids {
+		_, err := tx.ExecContext(ctx, `UPDATE attachment SET position=$3 WHERE id=$1 AND tenant_id=$2`, id, tenantID, i+1)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	reordered, err := attachmentsByCar(ctx, tx, carID, tenantID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return reordered, nil
+}
+
+// DeleteAttachment removes an attachment and closes the gap it leaves in the
+// gallery. It returns the deleted attachment so that the caller can remove
+// its content from the blob store.
+func (s AttachmentStore) DeleteAttachment(ctx context.Context, attachmentID string) (models.Attachment, error) {
+	tracer := otel.Tracer("attachment-store")
+	ctx, span := tracer.Start(ctx, "DeleteAttachment-Store")
+	defer span.End()
+	var deletedAttachment models.Attachment
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return deletedAttachment, err
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return deletedAttachment, err
+	}
+
+	deletedAttachment, err = scanAttachment(tx.QueryRowContext(ctx, selectAttachmentQuery, attachmentID, tenantID))
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return deletedAttachment, store.ErrAttachmentNotFound
+		}
+		return deletedAttachment, err
+	}
+
+	if err := lockCar(ctx, tx, deletedAttachment.CarID.String(), tenantID); err != nil {
+		tx.Rollback()
+		return deletedAttachment, err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM attachment WHERE id=$1 AND tenant_id=$2`, attachmentID, tenantID)
+	if err != nil {
+		tx.Rollback()
+		return deletedAttachment, err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE attachment SET position=position-1 WHERE car_id=$1 AND tenant_id=$2 AND position > $3`,
+		deletedAttachment.CarID, tenantID, deletedAttachment.Position)
+	if err != nil {
+		tx.Rollback()
+		return deletedAttachment, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return deletedAttachment, err
+	}
+
+	return deletedAttachment, nil
+}
+
+// GetQueuedBlobs returns the blob keys of attachments that were deleted
+// together with their car and whose files are still to be removed.
+func (s AttachmentStore) GetQueuedBlobs(ctx context.Context) ([]string, error) {
+	tracer := otel.Tracer("attachment-store")
+	ctx, span := tracer.Start(ctx, "GetQueuedBlobs-Store")
+	defer span.End()
+	keys := []string{}
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return keys, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT blob_key FROM attachment_blob_deletion WHERE tenant_id=$1 ORDER BY queued_at, blob_key`, tenantID)
+	if err != nil {
+		return keys, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return keys, err
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return keys, err
+	}
+
+	return keys, nil
+}
+
+// ForgetQueuedBlob takes a blob key off the deletion queue once its file has
+// been removed.
+func (s AttachmentStore) ForgetQueuedBlob(ctx context.Context, key string) error {
+	tracer := otel.Tracer("attachment-store")
+	ctx, span := tracer.Start(ctx, "ForgetQueuedBlob-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, `DELETE FROM attachment_blob_deletion WHERE tenant_id=$1 AND blob_key=$2`, tenantID, key)
+	return err
+}
+
+// QueueCarBlobs queues the files of a car's attachments for deletion. The car
+// and engine stores call it in the transaction that deletes the car, since
+// the attachment records go with the car and the files would be lost track of.
+func QueueCarBlobs(ctx context.Context, tx *driver.Tx, carID string, tenantID uuid.UUID) error {
+	now := time.Now()
+	_, err := tx.ExecContext(ctx, `INSERT INTO attachment_blob_deletion (tenant_id, blob_key, queued_at)
+		SELECT tenant_id, blob_key, $3 FROM attachment WHERE car_id=$1 AND tenant_id=$2`, carID, tenantID, now)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO attachment_blob_deletion (tenant_id, blob_key, queued_at)
+		SELECT tenant_id, thumbnail_key, $3 FROM attachment WHERE car_id=$1 AND tenant_id=$2 AND thumbnail_key <> ''`, carID, tenantID, now)
+	return err
+}
+
+// lockCar checks that the car exists, locking its row on Postgres so that
+// gallery positions are assigned one change at a time.
+func lockCar(ctx context.Context, tx *driver.Tx, carID string, tenantID uuid.UUID) error {
+	query := `SELECT id FROM car WHERE id=$1 AND tenant_id=$2`
+	if tx.Dialect() == driver.Postgres {
+		query += ` FOR UPDATE`
+	}
+	var id uuid.UUID
+	err := tx.QueryRowContext(ctx, query, carID, tenantID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return store.ErrCarNotFound
+	}
+	return err
+}
+
+func attachmentsByCar(ctx context.Context, db store.Queryer, carID string, tenantID uuid.UUID) ([]models.Attachment, error) {
+	attachments := []models.Attachment{}
+
+	rows, err := db.QueryContext(ctx, selectAttachmentsByCarQuery, carID, tenantID)
+	if err != nil {
+		return attachments, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return attachments, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return attachments, err
+	}
+
+	return attachments, nil
+}
+
+// sameAttachments reports whether ids names every attachment in current
+// exactly once, and nothing else.
+func sameAttachments(current []models.Attachment, ids []uuid.UUID) bool {
+	if len(current) != len(ids) {
+		return false
+	}
+	remaining := make(map[uuid.UUID]bool, len(current))
+	for _, attachment := range current {
+		remaining[attachment.ID] = true
+	}
+	for _, id := range ids {
+		if !remaining[id] {
+			return false
+		}
+		delete(remaining, id)
+	}
+	return true
+}
+
+func scanAttachment(row store.Scanner) (models.Attachment, error) {
+	var attachment models.Attachment
+	err := row.Scan(&attachment.ID,
+		&attachment.CarID,
+		&attachment.Kind,
+		&attachment.Filename,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.Position,
+		&attachment.BlobKey,
+		&attachment.ThumbnailKey,
+		&attachment.CreatedBy,
+		&attachment.CreatedAt)
+	if err != nil {
+		return attachment, err
+	}
+	attachment.SetURLs()
+	return attachment, nil
+}
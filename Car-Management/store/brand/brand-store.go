@@ -0,0 +1,366 @@
+package brand
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/driver"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+const selectBrandQuery = `SELECT id, name, country, created_at, updated_at FROM brand WHERE id=$1 AND tenant_id=$2`
+
+// resolveBrandQuery finds the brand whose name or one of whose aliases
+// matches $2, ignoring case.
+const resolveBrandQuery = `SELECT id, name FROM brand WHERE tenant_id=$1 AND lower(name)=lower($2)
+UNION
+SELECT b.id, b.name FROM brand_alias a JOIN brand b ON b.id = a.brand_id WHERE a.tenant_id=$1 AND lower(a.alias)=lower($2)`
+
+type BrandStore struct {
+	db *driver.DB
+}
+
+func NewBrandStore(db *driver.DB) *BrandStore {
+	return &BrandStore{db: db}
+}
+
+func (s BrandStore) CreateBrand(ctx context.Context, brand models.Brand) (models.Brand, error) {
+	tracer := otel.Tracer("brand-store")
+	ctx, span := tracer.Start(ctx, "CreateBrand-Store")
+	defer span.End()
+	var createdBrand models.Brand
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return createdBrand, err
+	}
+
+	createdAt := time.Now()
+	newBrand := models.Brand{
+		ID:        uuid.New(),
+		Name:      strings.TrimSpace(brand.Name),
+		Aliases:   trimAll(brand.Aliases),
+		Country:   brand.Country,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return createdBrand, err
+	}
+
+	if err := checkNames(ctx, tx, tenantID, newBrand); err != nil {
+		tx.Rollback()
+		return createdBrand, err
+	}
+
+	query := `INSERT INTO brand (id, tenant_id, name, country, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err = tx.ExecContext(ctx, query, newBrand.ID, tenantID, newBrand.Name, newBrand.Country, newBrand.CreatedAt, newBrand.UpdatedAt)
+	if err != nil {
+		tx.Rollback()
+		return createdBrand, err
+	}
+
+	if err := insertAliases(ctx, tx, tenantID, newBrand.ID, newBrand.Aliases); err != nil {
+		tx.Rollback()
+		return createdBrand, err
+	}
+
+	createdBrand, err = getBrand(ctx, tx, newBrand.ID.String(), tenantID)
+	if err != nil {
+		tx.Rollback()
+		return createdBrand, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return createdBrand, err
+	}
+
+	return createdBrand, nil
+}
+
+func (s BrandStore) GetBrandById(ctx context.Context, brandID string) (models.Brand, error) {
+	tracer := otel.Tracer("brand-store")
+	ctx, span := tracer.Start(ctx, "GetBrandById-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return models.Brand{}, err
+	}
+
+	return getBrand(ctx, s.db, brandID, tenantID)
+}
+
+func (s BrandStore) GetBrands(ctx context.Context) ([]models.Brand, error) {
+	tracer := otel.Tracer("brand-store")
+	ctx, span := tracer.Start(ctx, "GetBrands-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := make(map[uuid.UUID][]string)
+	rows, err := s.db.QueryContext(ctx, `SELECT brand_id, alias FROM brand_alias WHERE tenant_id=$1 ORDER BY alias`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var brandID uuid.UUID
+		var alias string
+		if err := rows.Scan(&brandID, &alias); err != nil {
+			return nil, err
+		}
+		aliases[brandID] = append(aliases[brandID], alias)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT id, name, country, created_at, updated_at FROM brand WHERE tenant_id=$1 ORDER BY name`
+	rows, err = s.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var brands []models.Brand
+	for rows.Next() {
+		var brand models.Brand
+		err := rows.Scan(&brand.ID, &brand.Name, &brand.Country, &brand.CreatedAt, &brand.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		brand.Aliases = aliases[brand.ID]
+		if brand.Aliases == nil {
+			brand.Aliases = []string{}
+		}
+		brands = append(brands, brand)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return brands, nil
+}
+
+// UpdateBrand replaces the brand's name, country and aliases. Renaming a brand
+// renames it on all of its cars.
+func (s BrandStore) UpdateBrand(ctx context.Context, brand models.Brand) (models.Brand, error) {
+	tracer := otel.Tracer("brand-store")
+	ctx, span := tracer.Start(ctx, "UpdateBrand-Store")
+	defer span.End()
+	var updatedBrand models.Brand
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return updatedBrand, err
+	}
+
+	brand.Name = strings.TrimSpace(brand.Name)
+	brand.Aliases = trimAll(brand.Aliases)
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return updatedBrand, err
+	}
+
+	if err := checkNames(ctx, tx, tenantID, brand); err != nil {
+		tx.Rollback()
+		return updatedBrand, err
+	}
+
+	// Update Brand
+	query := `UPDATE brand SET name=$2, country=$3, updated_at=$4 WHERE id=$1 AND tenant_id=$5`
+
+	result, err := tx.ExecContext(ctx, query, brand.ID, brand.Name, brand.Country, time.Now(), tenantID)
+	if err != nil {
+		tx.Rollback()
+		return updatedBrand, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return updatedBrand, err
+	}
+	if rowsAffected == 0 {
+		tx.Rollback()
+		return updatedBrand, store.ErrBrandNotFound
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM brand_alias WHERE brand_id=$1 AND tenant_id=$2`, brand.ID, tenantID)
+	if err != nil {
+		tx.Rollback()
+		return updatedBrand, err
+	}
+	if err := insertAliases(ctx, tx, tenantID, brand.ID, brand.Aliases); err != nil {
+		tx.Rollback()
+		return updatedBrand, err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE car SET brand=$1 WHERE brand_id=$2 AND tenant_id=$3`, brand.Name, brand.ID, tenantID)
+	if err != nil {
+		tx.Rollback()
+		return updatedBrand, err
+	}
+
+	updatedBrand, err = getBrand(ctx, tx, brand.ID.String(), tenantID)
+	if err != nil {
+		tx.Rollback()
+		return updatedBrand, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return updatedBrand, err
+	}
+
+	return updatedBrand, nil
+}
+
+// DeleteBrand refuses to delete a brand that cars are still filed under.
+func (s BrandStore) DeleteBrand(ctx context.Context, brandID string) error {
+	tracer := otel.Tracer("brand-store")
+	ctx, span := tracer.Start(ctx, "DeleteBrand-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var cars int
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM car WHERE brand_id=$1 AND tenant_id=$2`, brandID, tenantID).Scan(&cars)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if cars > 0 {
+		tx.Rollback()
+		return store.ErrBrandInUse
+	}
+
+	// Delete Brand; its aliases cascade
+	result, err := tx.ExecContext(ctx, `DELETE FROM brand WHERE id=$1 AND tenant_id=$2`, brandID, tenantID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if rowsAffected == 0 {
+		tx.Rollback()
+		return store.ErrBrandNotFound
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Resolve returns the id and canonical name of the brand that name or one of
+// its aliases matches, ignoring case. An unknown name is registered as a new
+// brand so cars can still be created with brands nobody has set up yet.
+func Resolve(ctx context.Context, tx *driver.Tx, tenantID uuid.UUID, name string) (uuid.UUID, string, error) {
+	name = strings.TrimSpace(name)
+	var id uuid.UUID
+	var canonical string
+	err := tx.QueryRowContext(ctx, resolveBrandQuery, tenantID, name).Scan(&id, &canonical)
+	if err != sql.ErrNoRows {
+		return id, canonical, err
+	}
+
+	// A concurrent request may register the same brand first; the insert
+	// then does nothing and the lookup below finds that brand.
+	now := time.Now()
+	query := `INSERT INTO brand (id, tenant_id, name, country, created_at, updated_at) VALUES ($1, $2, $3, '', $4, $5) ON CONFLICT DO NOTHING`
+	if _, err := tx.ExecContext(ctx, query, uuid.New(), tenantID, name, now, now); err != nil {
+		return id, canonical, err
+	}
+	err = tx.QueryRowContext(ctx, resolveBrandQuery, tenantID, name).Scan(&id, &canonical)
+	return id, canonical, err
+}
+
+// checkNames returns store.ErrDuplicateBrand if the brand's name or any of its
+// aliases is already the name or an alias of another brand of the tenant.
+func checkNames(ctx context.Context, tx *driver.Tx, tenantID uuid.UUID, brand models.Brand) error {
+	query := `SELECT (SELECT COUNT(*) FROM brand WHERE tenant_id=$1 AND id<>$2 AND lower(name)=lower($3)) + (SELECT COUNT(*) FROM brand_alias WHERE tenant_id=$1 AND brand_id<>$2 AND lower(alias)=lower($3))`
+	for _, name := range append([]string{brand.Name}, brand.Aliases...) {
+		var taken int
+		if err := tx.QueryRowContext(ctx, query, tenantID, brand.ID, name).Scan(&taken); err != nil {
+			return err
+		}
+		if taken > 0 {
+			return store.ErrDuplicateBrand
+		}
+	}
+	return nil
+}
+
+func insertAliases(ctx context.Context, tx *driver.Tx, tenantID, brandID uuid.UUID, aliases []string) error {
+	query := `INSERT INTO brand_alias (tenant_id, brand_id, alias) VALUES ($1, $2, $3)`
+	for _, alias := range aliases {
+		if _, err := tx.ExecContext(ctx, query, tenantID, brandID, alias); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getBrand reads a brand together with its aliases.
+func getBrand(ctx context.Context, q store.Queryer, brandID string, tenantID uuid.UUID) (models.Brand, error) {
+	var brand models.Brand
+	err := q.QueryRowContext(ctx, selectBrandQuery, brandID, tenantID).Scan(&brand.ID, &brand.Name, &brand.Country, &brand.CreatedAt, &brand.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return brand, store.ErrBrandNotFound
+	}
+	if err != nil {
+		return brand, err
+	}
+
+	rows, err := q.QueryContext(ctx, `SELECT alias FROM brand_alias WHERE brand_id=$1 AND tenant_id=$2 ORDER BY alias`, brandID, tenantID)
+	if err != nil {
+		return brand, err
+	}
+	defer rows.Close()
+
+	brand.Aliases = []string{}
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return brand, err
+		}
+		brand.Aliases = append(brand.Aliases, alias)
+	}
+	return brand, rows.Err()
+}
+
+func trimAll(values []string) []string {
+	trimmed := make([]string, 0, len(values))
+	for _, value := range values {
+		trimmed = append(trimmed, strings.TrimSpace(value))
+	}
+	return trimmed
+}
@@ -0,0 +1,81 @@
+package brand_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"github.com/nitesh111sinha/car-management/store/brand"
+	"github.com/nitesh111sinha/car-management/store/storetest"
+)
+
+func TestBrandAliases(t *testing.T) {
+	db := storetest.OpenSQLite(t)
+	s := storetest.SQLStores(db)
+	ctx := storetest.NewTenant(t, s)
+	brands := brand.NewBrandStore(db)
+
+	vw, err := brands.CreateBrand(ctx, models.Brand{Name: " Volkswagen ", Aliases: []string{"VW", " Volkswagen AG"}, Country: "DE"})
+	if err != nil {
+		t.Fatalf("CreateBrand: %v", err)
+	}
+	if vw.Name != "Volkswagen" || len(vw.Aliases) != 2 {
+		t.Errorf("brand = %+v, want trimmed name and two aliases", vw)
+	}
+
+	for _, clash := range []models.Brand{
+		{Name: "VOLKSWAGEN"},
+		{Name: "vw"},
+		{Name: "Skoda", Aliases: []string{"volkswagen ag"}},
+	} {
+		if _, err := brands.CreateBrand(ctx, clash); !errors.Is(err, store.ErrDuplicateBrand) {
+			t.Errorf("CreateBrand(%+v) = %v, want ErrDuplicateBrand", clash, err)
+		}
+	}
+	if _, err := brands.CreateBrand(storetest.NewTenant(t, s), models.Brand{Name: "Volkswagen"}); err != nil {
+		t.Errorf("another tenant cannot use the name: %v", err)
+	}
+
+	car := storetest.NewCar(ctx, t, s, "vw", storetest.NewEngine(ctx, t, s))
+	if car.Brand != "Volkswagen" {
+		t.Errorf("car brand = %q, want the canonical Volkswagen", car.Brand)
+	}
+	byAlias, err := s.Cars.GetCarByBrand(ctx, "VW", false)
+	if err != nil || len(byAlias) != 1 {
+		t.Errorf("cars by alias = %d, %v; want the car", len(byAlias), err)
+	}
+
+	vw.Aliases = []string{"V-Dub"}
+	updated, err := brands.UpdateBrand(ctx, vw)
+	if err != nil {
+		t.Fatalf("UpdateBrand: %v", err)
+	}
+	if len(updated.Aliases) != 1 || updated.Aliases[0] != "V-Dub" {
+		t.Errorf("aliases = %v, want them replaced", updated.Aliases)
+	}
+	if byAlias, err := s.Cars.GetCarByBrand(ctx, "VW", false); err != nil || len(byAlias) != 0 {
+		t.Errorf("cars by a removed alias = %d, %v; want none", len(byAlias), err)
+	}
+
+	if err := brands.DeleteBrand(ctx, vw.ID.String()); !errors.Is(err, store.ErrBrandInUse) {
+		t.Errorf("deleting a brand with cars = %v, want ErrBrandInUse", err)
+	}
+}
+
+func TestBrandNotFound(t *testing.T) {
+	db := storetest.OpenSQLite(t)
+	ctx := storetest.NewTenant(t, storetest.SQLStores(db))
+	brands := brand.NewBrandStore(db)
+
+	if _, err := brands.GetBrandById(ctx, uuid.NewString()); !errors.Is(err, store.ErrBrandNotFound) {
+		t.Errorf("GetBrandById = %v, want ErrBrandNotFound", err)
+	}
+	if _, err := brands.UpdateBrand(ctx, models.Brand{ID: uuid.New(), Name: "Skoda"}); !errors.Is(err, store.ErrBrandNotFound) {
+		t.Errorf("UpdateBrand = %v, want ErrBrandNotFound", err)
+	}
+	if err := brands.DeleteBrand(ctx, uuid.NewString()); !errors.Is(err, store.ErrBrandNotFound) {
+		t.Errorf("DeleteBrand = %v, want ErrBrandNotFound", err)
+	}
+}
@@ -3,19 +3,34 @@ package car
 import (
 	"context"
 	"database/sql"
-	"errors"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/driver"
 	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"github.com/nitesh111sinha/car-management/store/attachment"
+	brandStore "github.com/nitesh111sinha/car-management/store/brand"
+	engineStore "github.com/nitesh111sinha/car-management/store/engine"
 	"go.opentelemetry.io/otel"
 )
 
+// Every query filters on tenant_id; the tenant comes from the request
+// context, never from the caller's input.
+const selectCarQuery = `SELECT id, name, year, brand, fuel_type, engine_id, price_amount, price_currency, COALESCE(vin, ''), trim_id, created_at, updated_at FROM car WHERE id=$1 AND tenant_id=$2`
+
+// selectCarWithEngineQuery selects a car with its engine's specs; callers
+// append the WHERE clause.
+const selectCarWithEngineQuery = `SELECT c.id, c.name, c.year, c.brand, c.fuel_type, c.engine_id, c.price_amount, c.price_currency, COALESCE(c.vin, ''), c.trim_id, c.created_at, c.updated_at, e.id, e.type, e.displacement, e.no_of_cylinders, e.car_range, e.battery_kwh, e.charging_power_kw, e.efficiency_wh_per_km FROM car c LEFT JOIN engine e ON c.engine_id = e.id`
+
 type Store struct {
-	db *sql.DB
+	db *driver.DB
 }
 
-func NewCarStore(db *sql.DB) Store {
+func NewCarStore(db *driver.DB) Store {
 	return Store{db: db}
 }
 
@@ -24,24 +39,12 @@ func (s Store) GetCarById(ctx context.Context, id string) (models.Car, error) {
 	ctx, span := tracer.Start(ctx, "GetCarById-Store")
 	defer span.End()
 	var car models.Car
-	query := `SELECT c.id, c.name, c.year, c.brand, c.fuel_type, c.engine_id, c.price, c.created_at, c.updated_at, e.id, e.displacement, e.no_of_cylinders, e.car_range FROM car c LEFT JOIN engine e ON c.engine_id = e.id WHERE c.id=$1`
-
-	row := s.db.QueryRowContext(ctx, query, id)
-
-	err := row.Scan(&car.ID,
-		&car.Name,
-		&car.Year,
-		&car.Brand,
-		&car.FuelType,
-		&car.Engine.EngineID,
-		&car.Price,
-		&car.CreatedAt,
-		&car.UpdatedAt,
-		&car.Engine.EngineID,
-		&car.Engine.Displacement,
-		&car.Engine.NoOfCylinders,
-		&car.Engine.CarRange)
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return car, err
+	}
 
+	car, err = scanCarWithEngine(s.db.QueryRowContext(ctx, selectCarWithEngineQuery+` WHERE c.id=$1 AND c.tenant_id=$2`, id, tenantID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return car, nil
@@ -51,19 +54,45 @@ func (s Store) GetCarById(ctx context.Context, id string) (models.Car, error) {
 	return car, nil
 }
 
+// GetCarByVIN returns store.ErrCarNotFound when no car of the tenant has the
+// VIN.
+func (s Store) GetCarByVIN(ctx context.Context, vin string) (models.Car, error) {
+	tracer := otel.Tracer("car-store")
+	ctx, span := tracer.Start(ctx, "GetCarByVIN-Store")
+	defer span.End()
+	var car models.Car
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return car, err
+	}
+
+	car, err = scanCarWithEngine(s.db.QueryRowContext(ctx, selectCarWithEngineQuery+` WHERE c.vin=$1 AND c.tenant_id=$2`, vin, tenantID))
+	if err == sql.ErrNoRows {
+		return car, store.ErrCarNotFound
+	}
+	return car, err
+}
+
 func (s Store) GetCarByBrand(ctx context.Context, brand string, isEngine bool) ([]models.Car, error) {
 	tracer := otel.Tracer("car-store")
 	ctx, span := tracer.Start(ctx, "GetCarByBrand-Store")
-	defer span.End()	
+	defer span.End()
 	var cars []models.Car
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return cars, err
+	}
+	// The brand in the path may be the canonical name or any alias, in any
+	// case.
+	byBrand := `c.brand_id IN (SELECT id FROM brand WHERE tenant_id=$2 AND lower(name)=lower($1) UNION SELECT brand_id FROM brand_alias WHERE tenant_id=$2 AND lower(alias)=lower($1)) AND c.tenant_id=$2`
 	var query string
 	if isEngine {
-		query = `SELECT c.id, c.name, c.year, c.brand, c.fuel_type, c.engine_id, c.price, c.created_at, c.updated_at, e.id, e.displacement, e.no_of_cylinders, e.car_range FROM car c LEFT JOIN engine e ON c.engine_id = e.id WHERE c.brand=$1`
+		query = selectCarWithEngineQuery + ` WHERE ` + byBrand
 	} else {
-		query = `SELECT c.id, c.name, c.year, c.brand, c.fuel_type, c.engine_id, c.price, c.created_at, c.updated_at FROM car c WHERE c.brand=$1`
+		query = `SELECT c.id, c.name, c.year, c.brand, c.fuel_type, c.engine_id, c.price_amount, c.price_currency, COALESCE(c.vin, ''), c.trim_id, c.created_at, c.updated_at FROM car c WHERE ` + byBrand
 	}
 
-	rows, err := s.db.QueryContext(ctx, query, brand)
+	rows, err := s.db.QueryContext(ctx, query, strings.TrimSpace(brand), tenantID)
 	if err != nil {
 		return cars, err
 	}
@@ -73,22 +102,31 @@ func (s Store) GetCarByBrand(ctx context.Context, brand string, isEngine bool) (
 		var car models.Car
 		if isEngine {
 			var engine models.Engine
+			var battery, chargingPower, efficiency sql.NullFloat64
 			err := rows.Scan(&car.ID,
 				&car.Name,
 				&car.Year,
 				&car.Brand,
 				&car.FuelType,
 				&car.Engine.EngineID,
-				&car.Price,
+				&car.Price.Amount,
+				&car.Price.Currency,
+				&car.VIN,
+				&car.TrimID,
 				&car.CreatedAt,
 				&car.UpdatedAt,
 				&engine.EngineID,
+				&engine.Type,
 				&engine.Displacement,
 				&engine.NoOfCylinders,
-				&engine.CarRange)
+				&engine.CarRange,
+				&battery,
+				&chargingPower,
+				&efficiency)
 			if err != nil {
 				return nil, err
 			}
+			engine.Motor = engineStore.Motor(battery, chargingPower, efficiency)
 			car.Engine = engine
 			cars = append(cars, car)
 		} else {
@@ -98,7 +136,10 @@ func (s Store) GetCarByBrand(ctx context.Context, brand string, isEngine bool) (
 				&car.Brand,
 				&car.FuelType,
 				&car.Engine.EngineID,
-				&car.Price,
+				&car.Price.Amount,
+				&car.Price.Currency,
+				&car.VIN,
+				&car.TrimID,
 				&car.CreatedAt,
 				&car.UpdatedAt)
 			if err != nil {
@@ -118,14 +159,11 @@ func (s Store) GetCarByBrand(ctx context.Context, brand string, isEngine bool) (
 func (s Store) CreateCar(ctx context.Context, car models.Car) (models.Car, error) {
 	tracer := otel.Tracer("car-store")
 	ctx, span := tracer.Start(ctx, "CreateCar-Store")
-	defer span.End()	
+	defer span.End()
 	var createdCar models.Car
-	var engineId uuid.UUID
-
-	engineRow := s.db.QueryRowContext(ctx, `SELECT id FROM engine WHERE id=$1`, car.Engine.EngineID)
-
-	if err := engineRow.Scan(&engineId); err != nil {
-		return createdCar, errors.New("engine id is required and must be a valid uuid")
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return createdCar, err
 	}
 
 	carId := uuid.New()
@@ -140,6 +178,8 @@ func (s Store) CreateCar(ctx context.Context, car models.Car) (models.Car, error
 		FuelType:  car.FuelType,
 		Engine:    car.Engine,
 		Price:     car.Price,
+		VIN:       car.VIN,
+		TrimID:    car.TrimID,
 		CreatedAt: createdAt,
 		UpdatedAt: updatedAt,
 	}
@@ -150,31 +190,66 @@ func (s Store) CreateCar(ctx context.Context, car models.Car) (models.Car, error
 		return createdCar, err
 	}
 
+	// The engine is checked inside the transaction so the check reads from
+	// the primary and sees engines created moments ago.
+	if err := checkEngine(ctx, tx, car.Engine.EngineID, car.FuelType, tenantID); err != nil {
+		tx.Rollback()
+		return createdCar, err
+	}
+
+	// Cars are filed under the brand's canonical name
+	brandID, brandName, err := brandStore.Resolve(ctx, tx, tenantID, car.Brand)
+	if err != nil {
+		tx.Rollback()
+		return createdCar, err
+	}
+	newCar.Brand = brandName
+
+	if err := models.ValidateVINBrand(newCar.VIN, brandName); err != nil {
+		tx.Rollback()
+		return createdCar, err
+	}
+
+	if err := checkTrim(ctx, tx, car.TrimID, brandID, car.Engine.EngineID, tenantID); err != nil {
+		tx.Rollback()
+		return createdCar, err
+	}
+
+	if err := checkVIN(ctx, tx, newCar.VIN, newCar.ID, tenantID); err != nil {
+		tx.Rollback()
+		return createdCar, err
+	}
+
 	// Insert Car
-	query := `INSERT INTO car (id, name, year, brand, fuel_type, engine_id, price, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, name, year, brand, fuel_type, engine_id, price, created_at, updated_at`
+	query := `INSERT INTO car (id, name, year, brand, brand_id, fuel_type, engine_id, price_amount, price_currency, vin, trim_id, created_at, updated_at, tenant_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
 
-	err = tx.QueryRowContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
 		newCar.ID,
 		newCar.Name,
 		newCar.Year,
 		newCar.Brand,
+		brandID,
 		newCar.FuelType,
 		newCar.Engine.EngineID,
-		newCar.Price,
+		newCar.Price.Amount,
+		newCar.Price.Currency,
+		nullVIN(newCar.VIN),
+		newCar.TrimID,
 		newCar.CreatedAt,
-		newCar.UpdatedAt).Scan(
-		&createdCar.ID,
-		&createdCar.Name,
-		&createdCar.Year,
-		&createdCar.Brand,
-		&createdCar.FuelType,
-		&createdCar.Engine.EngineID,
-		&createdCar.Price,
-		&createdCar.CreatedAt,
-		&createdCar.UpdatedAt)
-
+		newCar.UpdatedAt,
+		tenantID)
 	if err != nil {
+		tx.Rollback()
+		return createdCar, err
+	}
 
+	if err := recordPriceChange(ctx, tx, tenantID, newCar.ID, nil, newCar.Price); err != nil {
+		tx.Rollback()
+		return createdCar, err
+	}
+
+	createdCar, err = scanCar(tx.QueryRowContext(ctx, selectCarQuery, newCar.ID, tenantID))
+	if err != nil {
 		tx.Rollback()
 		return createdCar, err
 	}
@@ -187,17 +262,16 @@ func (s Store) CreateCar(ctx context.Context, car models.Car) (models.Car, error
 	return createdCar, nil
 }
 
+// UpdateCar returns store.ErrCarNotFound when the tenant has no car with the
+// ID.
 func (s Store) UpdateCar(ctx context.Context, car models.Car) (models.Car, error) {
 	tracer := otel.Tracer("car-store")
 	ctx, span := tracer.Start(ctx, "UpdateCar-Store")
 	defer span.End()
 	var updatedCar models.Car
-	var engineId uuid.UUID
-
-	engineRow := s.db.QueryRowContext(ctx, "SELECT id FROM engine WHERE id=$1", car.Engine.EngineID)
-
-	if err := engineRow.Scan(&engineId); err != nil {
-		return updatedCar, errors.New("engine id is required and must be a valid uuid")
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return updatedCar, err
 	}
 
 	car.UpdatedAt = time.Now()
@@ -208,30 +282,84 @@ func (s Store) UpdateCar(ctx context.Context, car models.Car) (models.Car, error
 		return updatedCar, err
 	}
 
+	if err := checkEngine(ctx, tx, car.Engine.EngineID, car.FuelType, tenantID); err != nil {
+		tx.Rollback()
+		return updatedCar, err
+	}
+
+	brandID, brandName, err := brandStore.Resolve(ctx, tx, tenantID, car.Brand)
+	if err != nil {
+		tx.Rollback()
+		return updatedCar, err
+	}
+	car.Brand = brandName
+
+	if err := models.ValidateVINBrand(car.VIN, brandName); err != nil {
+		tx.Rollback()
+		return updatedCar, err
+	}
+
+	if err := checkTrim(ctx, tx, car.TrimID, brandID, car.Engine.EngineID, tenantID); err != nil {
+		tx.Rollback()
+		return updatedCar, err
+	}
+
+	if err := checkVIN(ctx, tx, car.VIN, car.ID, tenantID); err != nil {
+		tx.Rollback()
+		return updatedCar, err
+	}
+
+	var oldPrice models.Money
+	err = tx.QueryRowContext(ctx, `SELECT price_amount, price_currency FROM car WHERE id=$1 AND tenant_id=$2`, car.ID, tenantID).Scan(&oldPrice.Amount, &oldPrice.Currency)
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		return updatedCar, store.ErrCarNotFound
+	}
+	if err != nil {
+		tx.Rollback()
+		return updatedCar, err
+	}
+
 	// Update Car
-	query := `UPDATE car SET name=$2, year=$3, brand=$4, fuel_type=$5, engine_id=$6, price=$7, updated_at=$8 WHERE id=$1 RETURNING id, name, year, brand, fuel_type, engine_id, price, created_at, updated_at`
+	query := `UPDATE car SET name=$2, year=$3, brand=$4, brand_id=$5, fuel_type=$6, engine_id=$7, price_amount=$8, price_currency=$9, vin=$10, trim_id=$11, updated_at=$12 WHERE id=$1 AND tenant_id=$13`
 
-	err = tx.QueryRowContext(ctx, query,
+	result, err := tx.ExecContext(ctx, query,
 		car.ID,
 		car.Name,
 		car.Year,
 		car.Brand,
+		brandID,
 		car.FuelType,
 		car.Engine.EngineID,
-		car.Price,
-		car.UpdatedAt).Scan(
-		&updatedCar.ID,
-		&updatedCar.Name,
-		&updatedCar.Year,
-		&updatedCar.Brand,
-		&updatedCar.FuelType,
-		&updatedCar.Engine.EngineID,
-		&updatedCar.Price,
-		&updatedCar.CreatedAt,
-		&updatedCar.UpdatedAt)
-
+		car.Price.Amount,
+		car.Price.Currency,
+		nullVIN(car.VIN),
+		car.TrimID,
+		car.UpdatedAt,
+		tenantID)
+	if err != nil {
+		tx.Rollback()
+		return updatedCar, err
+	}
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
+		tx.Rollback()
+		return updatedCar, err
+	}
+	if rowsAffected == 0 {
+		tx.Rollback()
+		return updatedCar, store.ErrCarNotFound
+	}
 
+	if car.Price != oldPrice {
+		if err := recordPriceChange(ctx, tx, tenantID, car.ID, &oldPrice, car.Price); err != nil {
+			tx.Rollback()
+			return updatedCar, err
+		}
+	}
+
+	updatedCar, err = scanCar(tx.QueryRowContext(ctx, selectCarQuery, car.ID, tenantID))
+	if err != nil {
 		tx.Rollback()
 		return updatedCar, err
 	}
@@ -247,16 +375,38 @@ func (s Store) UpdateCar(ctx context.Context, car models.Car) (models.Car, error
 func (s Store) DeleteCar(ctx context.Context, id string) error {
 	tracer := otel.Tracer("car-store")
 	ctx, span := tracer.Start(ctx, "DeleteCar-Store")
-	defer span.End()	
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
+	var orders int
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM sales_order WHERE car_id=$1 AND tenant_id=$2`, id, tenantID).Scan(&orders)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if orders > 0 {
+		tx.Rollback()
+		return store.ErrCarHasOrders
+	}
+
+	// The attachment records go with the car; queue their files for deletion.
+	if err := attachment.QueueCarBlobs(ctx, tx, id, tenantID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// Delete Car
-	query := `DELETE FROM car WHERE id=$1`
+	query := `DELETE FROM car WHERE id=$1 AND tenant_id=$2`
 
-	result, err := tx.ExecContext(ctx, query, id)
+	result, err := tx.ExecContext(ctx, query, id, tenantID)
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -268,7 +418,7 @@ func (s Store) DeleteCar(ctx context.Context, id string) error {
 	}
 	if rowsAffected == 0 {
 		tx.Rollback()
-		return errors.New("car not found")
+		return store.ErrCarNotFound
 	}
 
 	// Commit Transaction
@@ -282,10 +432,14 @@ func (s Store) DeleteCar(ctx context.Context, id string) error {
 func (s Store) GetCars(ctx context.Context) ([]models.Car, error) {
 	tracer := otel.Tracer("car-store")
 	ctx, span := tracer.Start(ctx, "GetCars-Store")
-	defer span.End()		
+	defer span.End()
 	var cars []models.Car
-	query := `SELECT id, name, year, brand, fuel_type, engine_id, price, created_at, updated_at FROM car`
-	rows, err := s.db.QueryContext(ctx, query)
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return cars, err
+	}
+	query := `SELECT id, name, year, brand, fuel_type, engine_id, price_amount, price_currency, COALESCE(vin, ''), trim_id, created_at, updated_at FROM car WHERE tenant_id=$1`
+	rows, err := s.db.QueryContext(ctx, query, tenantID)
 	if err != nil {
 		return cars, err
 	}
@@ -299,7 +453,10 @@ func (s Store) GetCars(ctx context.Context) ([]models.Car, error) {
 			&car.Brand,
 			&car.FuelType,
 			&car.Engine.EngineID,
-			&car.Price,
+			&car.Price.Amount,
+			&car.Price.Currency,
+			&car.VIN,
+			&car.TrimID,
 			&car.CreatedAt,
 			&car.UpdatedAt)
 		if err != nil {
@@ -310,3 +467,244 @@ func (s Store) GetCars(ctx context.Context) ([]models.Car, error) {
 
 	return cars, nil
 }
+
+// GetCarsByIds loads the cars with their engines in a single query.
+func (s Store) GetCarsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Car, error) {
+	tracer := otel.Tracer("car-store")
+	ctx, span := tracer.Start(ctx, "GetCarsByIds-Store")
+	defer span.End()
+	cars := []models.Car{}
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return cars, err
+	}
+	if len(ids) == 0 {
+		return cars, nil
+	}
+
+	args := []any{tenantID}
+	placeholders := make([]string, len(ids))
+	for i, id := range ids {
+		args = append(args, id)
+		placeholders[i] = "$" + strconv.Itoa(i+2)
+	}
+	query := selectCarWithEngineQuery + ` WHERE c.tenant_id=$1 AND c.id IN (` + strings.Join(placeholders, ", ") + `)`
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return cars, err
+	}
+	defer rows.Close()
+
+	byID := make(map[uuid.UUID]models.Car, len(ids))
+	for rows.Next() {
+		car, err := scanCarWithEngine(rows)
+		if err != nil {
+			return cars, err
+		}
+		byID[car.ID] = car
+	}
+
+	if err := rows.Err(); err != nil {
+		return cars, err
+	}
+
+	for _, id := range ids {
+		if car, ok := byID[id]; ok {
+			cars = append(cars, car)
+		}
+	}
+	return cars, nil
+}
+
+func (s Store) GetCarsWithEngines(ctx context.Context) ([]models.Car, error) {
+	tracer := otel.Tracer("car-store")
+	ctx, span := tracer.Start(ctx, "GetCarsWithEngines-Store")
+	defer span.End()
+	cars := []models.Car{}
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return cars, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, selectCarWithEngineQuery+` WHERE c.tenant_id=$1 ORDER BY c.created_at, c.id`, tenantID)
+	if err != nil {
+		return cars, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		car, err := scanCarWithEngine(rows)
+		if err != nil {
+			return cars, err
+		}
+		car.Availability = &models.Availability{}
+		cars = append(cars, car)
+	}
+
+	if err := rows.Err(); err != nil {
+		return cars, err
+	}
+
+	if err := s.loadAvailability(ctx, tenantID, cars); err != nil {
+		return cars, err
+	}
+
+	return cars, nil
+}
+
+// loadAvailability counts the stock units of each of cars by status.
+func (s Store) loadAvailability(ctx context.Context, tenantID uuid.UUID, cars []models.Car) error {
+	byID := make(map[uuid.UUID]*models.Availability, len(cars))
+	for _, car := range cars {
+		byID[car.ID] = car.Availability
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT car_id, status, COUNT(*) FROM stock_unit WHERE tenant_id=$1 GROUP BY car_id, status`, tenantID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var carID uuid.UUID
+		var status string
+		var count int
+		if err := rows.Scan(&carID, &status, &count); err != nil {
+			return err
+		}
+		availability, ok := byID[carID]
+		if !ok {
+			continue
+		}
+		switch status {
+		case models.StockInStock:
+			availability.InStock = count
+		case models.StockReserved:
+			availability.Reserved = count
+		case models.StockSold:
+			availability.Sold = count
+		}
+	}
+	return rows.Err()
+}
+
+// checkEngine returns store.ErrInvalidEngine unless the engine exists and
+// belongs to the tenant, and store.ErrPowertrainMismatch unless its
+// powertrain fits the car's fuel type.
+func checkEngine(ctx context.Context, tx *driver.Tx, engineID uuid.UUID, fuelType string, tenantID uuid.UUID) error {
+	var powertrain string
+	err := tx.QueryRowContext(ctx, `SELECT type FROM engine WHERE id=$1 AND tenant_id=$2`, engineID, tenantID).Scan(&powertrain)
+	if err == sql.ErrNoRows {
+		return store.ErrInvalidEngine
+	}
+	if err != nil {
+		return err
+	}
+	if !models.FuelTypeFits(fuelType, powertrain) {
+		return store.ErrPowertrainMismatch
+	}
+	return nil
+}
+
+// checkTrim returns store.ErrInvalidTrim unless the car has no trim, or the
+// trim belongs to the tenant and the car's brand and offers the car's engine.
+func checkTrim(ctx context.Context, tx *driver.Tx, trimID uuid.NullUUID, brandID, engineID, tenantID uuid.UUID) error {
+	if !trimID.Valid {
+		return nil
+	}
+	var matches int
+	query := `SELECT COUNT(*) FROM car_trim t JOIN car_model m ON m.id = t.model_id JOIN car_trim_engine te ON te.trim_id = t.id WHERE t.id=$1 AND t.tenant_id=$2 AND m.brand_id=$3 AND te.engine_id=$4`
+	if err := tx.QueryRowContext(ctx, query, trimID.UUID, tenantID, brandID, engineID).Scan(&matches); err != nil {
+		return err
+	}
+	if matches == 0 {
+		return store.ErrInvalidTrim
+	}
+	return nil
+}
+
+// checkVIN returns store.ErrDuplicateCarVIN if another car of the tenant
+// already has the vin. Cars without a VIN never conflict.
+func checkVIN(ctx context.Context, tx *driver.Tx, vin string, carID, tenantID uuid.UUID) error {
+	if vin == "" {
+		return nil
+	}
+	var id uuid.UUID
+	err := tx.QueryRowContext(ctx, `SELECT id FROM car WHERE vin=$1 AND tenant_id=$2 AND id<>$3`, vin, tenantID, carID).Scan(&id)
+	if err == nil {
+		return store.ErrDuplicateCarVIN
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+	return nil
+}
+
+// nullVIN stores a missing VIN as NULL, so that the unique index only
+// applies to cars that have one.
+func nullVIN(vin string) sql.NullString {
+	return sql.NullString{String: vin, Valid: vin != ""}
+}
+
+// recordPriceChange adds an entry to the car's price history, attributed to
+// the caller. oldPrice is nil when the car is first listed.
+func recordPriceChange(ctx context.Context, tx *driver.Tx, tenantID, carID uuid.UUID, oldPrice *models.Money, newPrice models.Money) error {
+	var oldAmount sql.NullInt64
+	var oldCurrency sql.NullString
+	if oldPrice != nil {
+		oldAmount = sql.NullInt64{Int64: oldPrice.Amount, Valid: true}
+		oldCurrency = sql.NullString{String: oldPrice.Currency, Valid: true}
+	}
+	query := `INSERT INTO price_change (id, tenant_id, car_id, old_amount, old_currency, new_amount, new_currency, changed_by, changed_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err := tx.ExecContext(ctx, query, uuid.New(), tenantID, carID, oldAmount, oldCurrency, newPrice.Amount, newPrice.Currency, auth.Actor(ctx), time.Now().UTC())
+	return err
+}
+
+// scanCarWithEngine reads a row produced by selectCarWithEngineQuery.
+func scanCarWithEngine(row store.Scanner) (models.Car, error) {
+	var car models.Car
+	var battery, chargingPower, efficiency sql.NullFloat64
+	err := row.Scan(&car.ID,
+		&car.Name,
+		&car.Year,
+		&car.Brand,
+		&car.FuelType,
+		&car.Engine.EngineID,
+		&car.Price.Amount,
+		&car.Price.Currency,
+		&car.VIN,
+		&car.TrimID,
+		&car.CreatedAt,
+		&car.UpdatedAt,
+		&car.Engine.EngineID,
+		&car.Engine.Type,
+		&car.Engine.Displacement,
+		&car.Engine.NoOfCylinders,
+		&car.Engine.CarRange,
+		&battery,
+		&chargingPower,
+		&efficiency)
+	if err != nil {
+		return car, err
+	}
+	car.Engine.Motor = engineStore.Motor(battery, chargingPower, efficiency)
+	return car, nil
+}
+
+// scanCar reads a row produced by selectCarQuery.
+func scanCar(row *sql.Row) (models.Car, error) {
+	var car models.Car
+	err := row.Scan(&car.ID,
+		&car.Name,
+		&car.Year,
+		&car.Brand,
+		&car.FuelType,
+		&car.Engine.EngineID,
+		&car.Price.Amount,
+		&car.Price.Currency,
+		&car.VIN,
+		&car.TrimID,
+		&car.CreatedAt,
+		&car.UpdatedAt)
+	return car, err
+}
@@ -0,0 +1,33 @@
+package car_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/nitesh111sinha/car-management/config"
+	"github.com/nitesh111sinha/car-management/driver"
+	"github.com/nitesh111sinha/car-management/store/storetest"
+)
+
+func run(t *testing.T, db *driver.DB) {
+	storetest.Run(t, func(t *testing.T) storetest.Stores {
+		return storetest.SQLStores(db)
+	})
+}
+
+func TestSQLiteConformance(t *testing.T) {
+	run(t, storetest.OpenSQLite(t))
+}
+
+// TestPostgresConformance runs against the database named by
+// TEST_POSTGRES_DSN. Connect as an ordinary role, not a superuser, so that
+// the row-level security policies apply.
+func TestPostgresConformance(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	cfg := config.Default().Database
+	cfg.DSN = dsn
+	run(t, storetest.OpenDB(t, cfg))
+}
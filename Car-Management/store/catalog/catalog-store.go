@@ -0,0 +1,588 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/driver"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	engineStore "github.com/nitesh111sinha/car-management/store/engine"
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	selectModelQuery = `SELECT id, brand_id, name, created_at, updated_at FROM car_model WHERE id=$1 AND tenant_id=$2`
+	selectTrimQuery  = `SELECT id, model_id, name, year, created_at, updated_at FROM car_trim WHERE id=$1 AND tenant_id=$2`
+)
+
+// CatalogStore stores the brand > model > trim hierarchy.
+type CatalogStore struct {
+	db *driver.DB
+}
+
+func NewCatalogStore(db *driver.DB) *CatalogStore {
+	return &CatalogStore{db: db}
+}
+
+func (s CatalogStore) GetModelsByBrand(ctx context.Context, brandID string) ([]models.CarModel, error) {
+	tracer := otel.Tracer("catalog-store")
+	ctx, span := tracer.Start(ctx, "GetModelsByBrand-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := exists(ctx, s.db, `SELECT id FROM brand WHERE id=$1 AND tenant_id=$2`, brandID, tenantID, store.ErrBrandNotFound); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT id, brand_id, name, created_at, updated_at FROM car_model WHERE brand_id=$1 AND tenant_id=$2 ORDER BY name`
+	rows, err := s.db.QueryContext(ctx, query, brandID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	carModels := []models.CarModel{}
+	for rows.Next() {
+		var model models.CarModel
+		if err := rows.Scan(&model.ID, &model.BrandID, &model.Name, &model.CreatedAt, &model.UpdatedAt); err != nil {
+			return nil, err
+		}
+		carModels = append(carModels, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return carModels, nil
+}
+
+func (s CatalogStore) GetModelById(ctx context.Context, modelID string) (models.CarModel, error) {
+	tracer := otel.Tracer("catalog-store")
+	ctx, span := tracer.Start(ctx, "GetModelById-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return models.CarModel{}, err
+	}
+
+	return scanModel(s.db.QueryRowContext(ctx, selectModelQuery, modelID, tenantID))
+}
+
+func (s CatalogStore) CreateModel(ctx context.Context, model models.CarModel) (models.CarModel, error) {
+	tracer := otel.Tracer("catalog-store")
+	ctx, span := tracer.Start(ctx, "CreateModel-Store")
+	defer span.End()
+	var createdModel models.CarModel
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return createdModel, err
+	}
+
+	createdAt := time.Now()
+	newModel := models.CarModel{
+		ID:        uuid.New(),
+		BrandID:   model.BrandID,
+		Name:      strings.TrimSpace(model.Name),
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return createdModel, err
+	}
+
+	if err := exists(ctx, tx, `SELECT id FROM brand WHERE id=$1 AND tenant_id=$2`, newModel.BrandID, tenantID, store.ErrBrandNotFound); err != nil {
+		tx.Rollback()
+		return createdModel, err
+	}
+	if err := checkModelName(ctx, tx, newModel); err != nil {
+		tx.Rollback()
+		return createdModel, err
+	}
+
+	query := `INSERT INTO car_model (id, tenant_id, brand_id, name, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err = tx.ExecContext(ctx, query, newModel.ID, tenantID, newModel.BrandID, newModel.Name, newModel.CreatedAt, newModel.UpdatedAt)
+	if err != nil {
+		tx.Rollback()
+		return createdModel, err
+	}
+
+	createdModel, err = scanModel(tx.QueryRowContext(ctx, selectModelQuery, newModel.ID, tenantID))
+	if err != nil {
+		tx.Rollback()
+		return createdModel, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return createdModel, err
+	}
+
+	return createdModel, nil
+}
+
+// UpdateModel renames a model. A model cannot move to another brand.
+func (s CatalogStore) UpdateModel(ctx context.Context, model models.CarModel) (models.CarModel, error) {
+	tracer := otel.Tracer("catalog-store")
+	ctx, span := tracer.Start(ctx, "UpdateModel-Store")
+	defer span.End()
+	var updatedModel models.CarModel
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return updatedModel, err
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return updatedModel, err
+	}
+
+	existing, err := scanModel(tx.QueryRowContext(ctx, selectModelQuery, model.ID, tenantID))
+	if err != nil {
+		tx.Rollback()
+		return updatedModel, err
+	}
+	existing.Name = strings.TrimSpace(model.Name)
+	if err := checkModelName(ctx, tx, existing); err != nil {
+		tx.Rollback()
+		return updatedModel, err
+	}
+
+	// Update Model
+	query := `UPDATE car_model SET name=$2, updated_at=$3 WHERE id=$1 AND tenant_id=$4`
+
+	_, err = tx.ExecContext(ctx, query, existing.ID, existing.Name, time.Now(), tenantID)
+	if err != nil {
+		tx.Rollback()
+		return updatedModel, err
+	}
+
+	updatedModel, err = scanModel(tx.QueryRowContext(ctx, selectModelQuery, existing.ID, tenantID))
+	if err != nil {
+		tx.Rollback()
+		return updatedModel, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return updatedModel, err
+	}
+
+	return updatedModel, nil
+}
+
+// DeleteModel deletes a model and its trims. It refuses while any car is one
+// of its trims.
+func (s CatalogStore) DeleteModel(ctx context.Context, modelID string) error {
+	tracer := otel.Tracer("catalog-store")
+	ctx, span := tracer.Start(ctx, "DeleteModel-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var cars int
+	query := `SELECT COUNT(*) FROM car c JOIN car_trim t ON t.id = c.trim_id WHERE t.model_id=$1 AND c.tenant_id=$2`
+	if err := tx.QueryRowContext(ctx, query, modelID, tenantID).Scan(&cars); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if cars > 0 {
+		tx.Rollback()
+		return store.ErrModelInUse
+	}
+
+	// Delete Model; its trims cascade
+	if err := deleteRow(ctx, tx, `DELETE FROM car_model WHERE id=$1 AND tenant_id=$2`, modelID, tenantID, store.ErrModelNotFound); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s CatalogStore) GetTrimsByModel(ctx context.Context, modelID string) ([]models.Trim, error) {
+	tracer := otel.Tracer("catalog-store")
+	ctx, span := tracer.Start(ctx, "GetTrimsByModel-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := exists(ctx, s.db, `SELECT id FROM car_model WHERE id=$1 AND tenant_id=$2`, modelID, tenantID, store.ErrModelNotFound); err != nil {
+		return nil, err
+	}
+
+	engines, err := trimEngines(ctx, s.db, `t.model_id=$1 AND t.tenant_id=$2`, modelID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT id, model_id, name, year, created_at, updated_at FROM car_trim WHERE model_id=$1 AND tenant_id=$2 ORDER BY year DESC, name`
+	rows, err := s.db.QueryContext(ctx, query, modelID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	trims := []models.Trim{}
+	for rows.Next() {
+		var trim models.Trim
+		if err := rows.Scan(&trim.ID, &trim.ModelID, &trim.Name, &trim.Year, &trim.CreatedAt, &trim.UpdatedAt); err != nil {
+			return nil, err
+		}
+		trim.Engines = engines[trim.ID]
+		trims = append(trims, trim)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return trims, nil
+}
+
+func (s CatalogStore) GetTrimById(ctx context.Context, trimID string) (models.Trim, error) {
+	tracer := otel.Tracer("catalog-store")
+	ctx, span := tracer.Start(ctx, "GetTrimById-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return models.Trim{}, err
+	}
+
+	return getTrim(ctx, s.db, trimID, tenantID)
+}
+
+func (s CatalogStore) CreateTrim(ctx context.Context, trim models.Trim) (models.Trim, error) {
+	tracer := otel.Tracer("catalog-store")
+	ctx, span := tracer.Start(ctx, "CreateTrim-Store")
+	defer span.End()
+	var createdTrim models.Trim
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return createdTrim, err
+	}
+
+	createdAt := time.Now()
+	newTrim := models.Trim{
+		ID:        uuid.New(),
+		ModelID:   trim.ModelID,
+		Name:      strings.TrimSpace(trim.Name),
+		Year:      trim.Year,
+		Engines:   trim.Engines,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return createdTrim, err
+	}
+
+	if err := exists(ctx, tx, `SELECT id FROM car_model WHERE id=$1 AND tenant_id=$2`, newTrim.ModelID, tenantID, store.ErrModelNotFound); err != nil {
+		tx.Rollback()
+		return createdTrim, err
+	}
+	if err := checkTrimName(ctx, tx, newTrim); err != nil {
+		tx.Rollback()
+		return createdTrim, err
+	}
+
+	query := `INSERT INTO car_trim (id, tenant_id, model_id, name, year, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err = tx.ExecContext(ctx, query, newTrim.ID, tenantID, newTrim.ModelID, newTrim.Name, newTrim.Year, newTrim.CreatedAt, newTrim.UpdatedAt)
+	if err != nil {
+		tx.Rollback()
+		return createdTrim, err
+	}
+
+	if err := insertTrimEngines(ctx, tx, tenantID, newTrim); err != nil {
+		tx.Rollback()
+		return createdTrim, err
+	}
+
+	createdTrim, err = getTrim(ctx, tx, newTrim.ID.String(), tenantID)
+	if err != nil {
+		tx.Rollback()
+		return createdTrim, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return createdTrim, err
+	}
+
+	return createdTrim, nil
+}
+
+// UpdateTrim replaces a trim's name, year and engines. An engine cannot be
+// dropped while cars of the trim still use it.
+func (s CatalogStore) UpdateTrim(ctx context.Context, trim models.Trim) (models.Trim, error) {
+	tracer := otel.Tracer("catalog-store")
+	ctx, span := tracer.Start(ctx, "UpdateTrim-Store")
+	defer span.End()
+	var updatedTrim models.Trim
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return updatedTrim, err
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return updatedTrim, err
+	}
+
+	existing, err := scanTrim(tx.QueryRowContext(ctx, selectTrimQuery, trim.ID, tenantID))
+	if err != nil {
+		tx.Rollback()
+		return updatedTrim, err
+	}
+	existing.Name = strings.TrimSpace(trim.Name)
+	existing.Year = trim.Year
+	existing.Engines = trim.Engines
+	if err := checkTrimName(ctx, tx, existing); err != nil {
+		tx.Rollback()
+		return updatedTrim, err
+	}
+
+	// Update Trim
+	query := `UPDATE car_trim SET name=$2, year=$3, updated_at=$4 WHERE id=$1 AND tenant_id=$5`
+
+	_, err = tx.ExecContext(ctx, query, existing.ID, existing.Name, existing.Year, time.Now(), tenantID)
+	if err != nil {
+		tx.Rollback()
+		return updatedTrim, err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM car_trim_engine WHERE trim_id=$1 AND tenant_id=$2`, existing.ID, tenantID)
+	if err != nil {
+		tx.Rollback()
+		return updatedTrim, err
+	}
+	if err := insertTrimEngines(ctx, tx, tenantID, existing); err != nil {
+		tx.Rollback()
+		return updatedTrim, err
+	}
+
+	var stranded int
+	query = `SELECT COUNT(*) FROM car c WHERE c.trim_id=$1 AND c.tenant_id=$2 AND NOT EXISTS (SELECT 1 FROM car_trim_engine te WHERE te.trim_id = c.trim_id AND te.engine_id = c.engine_id)`
+	if err := tx.QueryRowContext(ctx, query, existing.ID, tenantID).Scan(&stranded); err != nil {
+		tx.Rollback()
+		return updatedTrim, err
+	}
+	if stranded > 0 {
+		tx.Rollback()
+		return updatedTrim, store.ErrTrimEngineInUse
+	}
+
+	updatedTrim, err = getTrim(ctx, tx, existing.ID.String(), tenantID)
+	if err != nil {
+		tx.Rollback()
+		return updatedTrim, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return updatedTrim, err
+	}
+
+	return updatedTrim, nil
+}
+
+// DeleteTrim refuses to delete a trim that cars still are.
+func (s CatalogStore) DeleteTrim(ctx context.Context, trimID string) error {
+	tracer := otel.Tracer("catalog-store")
+	ctx, span := tracer.Start(ctx, "DeleteTrim-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var cars int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM car WHERE trim_id=$1 AND tenant_id=$2`, trimID, tenantID).Scan(&cars); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if cars > 0 {
+		tx.Rollback()
+		return store.ErrTrimInUse
+	}
+
+	// Delete Trim; its engine list cascades
+	if err := deleteRow(ctx, tx, `DELETE FROM car_trim WHERE id=$1 AND tenant_id=$2`, trimID, tenantID, store.ErrTrimNotFound); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// exists returns notFound unless query, which selects by id and tenant,
+// finds a row.
+func exists(ctx context.Context, q store.Queryer, query string, id any, tenantID uuid.UUID, notFound error) error {
+	var found uuid.UUID
+	err := q.QueryRowContext(ctx, query, id, tenantID).Scan(&found)
+	if err == sql.ErrNoRows {
+		return notFound
+	}
+	return err
+}
+
+// deleteRow runs a delete by id and tenant and returns notFound if it removed
+// nothing.
+func deleteRow(ctx context.Context, tx *driver.Tx, query string, id string, tenantID uuid.UUID, notFound error) error {
+	result, err := tx.ExecContext(ctx, query, id, tenantID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return notFound
+	}
+	return nil
+}
+
+// checkModelName returns store.ErrDuplicateModel if another model of the
+// brand has the same name, ignoring case.
+func checkModelName(ctx context.Context, tx *driver.Tx, model models.CarModel) error {
+	var taken int
+	query := `SELECT COUNT(*) FROM car_model WHERE brand_id=$1 AND id<>$2 AND lower(name)=lower($3)`
+	if err := tx.QueryRowContext(ctx, query, model.BrandID, model.ID, model.Name).Scan(&taken); err != nil {
+		return err
+	}
+	if taken > 0 {
+		return store.ErrDuplicateModel
+	}
+	return nil
+}
+
+// checkTrimName returns store.ErrDuplicateTrim if another trim of the model
+// has the same name, ignoring case, and year.
+func checkTrimName(ctx context.Context, tx *driver.Tx, trim models.Trim) error {
+	var taken int
+	query := `SELECT COUNT(*) FROM car_trim WHERE model_id=$1 AND id<>$2 AND lower(name)=lower($3) AND year=$4`
+	if err := tx.QueryRowContext(ctx, query, trim.ModelID, trim.ID, trim.Name, trim.Year).Scan(&taken); err != nil {
+		return err
+	}
+	if taken > 0 {
+		return store.ErrDuplicateTrim
+	}
+	return nil
+}
+
+// insertTrimEngines links the trim to its engines, each of which must belong
+// to the tenant.
+func insertTrimEngines(ctx context.Context, tx *driver.Tx, tenantID uuid.UUID, trim models.Trim) error {
+	query := `INSERT INTO car_trim_engine (tenant_id, trim_id, engine_id) VALUES ($1, $2, $3)`
+	for _, engine := range trim.Engines {
+		err := exists(ctx, tx, `SELECT id FROM engine WHERE id=$1 AND tenant_id=$2`, engine.EngineID, tenantID, store.ErrInvalidEngine)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, query, tenantID, trim.ID, engine.EngineID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trimEngines returns the engines of the trims matching where, keyed by trim.
+func trimEngines(ctx context.Context, q store.Queryer, where string, args ...any) (map[uuid.UUID][]models.Engine, error) {
+	query := `SELECT t.id, e.id, e.type, e.displacement, e.no_of_cylinders, e.car_range, e.battery_kwh, e.charging_power_kw, e.efficiency_wh_per_km FROM car_trim t JOIN car_trim_engine te ON te.trim_id = t.id JOIN engine e ON e.id = te.engine_id WHERE ` + where + ` ORDER BY e.displacement, e.id`
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	engines := make(map[uuid.UUID][]models.Engine)
+	for rows.Next() {
+		var trimID uuid.UUID
+		var engine models.Engine
+		var battery, chargingPower, efficiency sql.NullFloat64
+		if err := rows.Scan(&trimID, &engine.EngineID, &engine.Type, &engine.Displacement, &engine.NoOfCylinders, &engine.CarRange, &battery, &chargingPower, &efficiency); err != nil {
+			return nil, err
+		}
+		engine.Motor = engineStore.Motor(battery, chargingPower, efficiency)
+		engines[trimID] = append(engines[trimID], engine)
+	}
+	return engines, rows.Err()
+}
+
+// getTrim reads a trim together with its engines.
+func getTrim(ctx context.Context, q store.Queryer, trimID string, tenantID uuid.UUID) (models.Trim, error) {
+	trim, err := scanTrim(q.QueryRowContext(ctx, selectTrimQuery, trimID, tenantID))
+	if err != nil {
+		return trim, err
+	}
+	engines, err := trimEngines(ctx, q, `t.id=$1 AND t.tenant_id=$2`, trimID, tenantID)
+	if err != nil {
+		return trim, err
+	}
+	trim.Engines = engines[trim.ID]
+	return trim, nil
+}
+
+// scanModel reads a row produced by selectModelQuery.
+func scanModel(row *sql.Row) (models.CarModel, error) {
+	var model models.CarModel
+	err := row.Scan(&model.ID, &model.BrandID, &model.Name, &model.CreatedAt, &model.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return model, store.ErrModelNotFound
+	}
+	return model, err
+}
+
+// scanTrim reads a row produced by selectTrimQuery.
+func scanTrim(row *sql.Row) (models.Trim, error) {
+	var trim models.Trim
+	err := row.Scan(&trim.ID, &trim.ModelID, &trim.Name, &trim.Year, &trim.CreatedAt, &trim.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return trim, store.ErrTrimNotFound
+	}
+	return trim, err
+}
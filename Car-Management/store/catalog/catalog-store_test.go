@@ -0,0 +1,163 @@
+package catalog_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"github.com/nitesh111sinha/car-management/store/brand"
+	"github.com/nitesh111sinha/car-management/store/catalog"
+	"github.com/nitesh111sinha/car-management/store/storetest"
+)
+
+type fixture struct {
+	ctx     context.Context
+	stores  storetest.Stores
+	catalog *catalog.CatalogStore
+	brands  *brand.BrandStore
+}
+
+func setup(t *testing.T) fixture {
+	t.Helper()
+	db := storetest.OpenSQLite(t)
+	s := storetest.SQLStores(db)
+	return fixture{
+		ctx:     storetest.NewTenant(t, s),
+		stores:  s,
+		catalog: catalog.NewCatalogStore(db),
+		brands:  brand.NewBrandStore(db),
+	}
+}
+
+func (f fixture) newBrand(t *testing.T, name string) models.Brand {
+	t.Helper()
+	b, err := f.brands.CreateBrand(f.ctx, models.Brand{Name: name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func (f fixture) newModel(t *testing.T, brandID uuid.UUID, name string) models.CarModel {
+	t.Helper()
+	model, err := f.catalog.CreateModel(f.ctx, models.CarModel{BrandID: brandID, Name: name})
+	if err != nil {
+		t.Fatalf("CreateModel: %v", err)
+	}
+	return model
+}
+
+func (f fixture) newTrim(t *testing.T, modelID uuid.UUID, engines ...models.Engine) models.Trim {
+	t.Helper()
+	trim, err := f.catalog.CreateTrim(f.ctx, models.Trim{ModelID: modelID, Name: "EX", Year: "2023", Engines: engines})
+	if err != nil {
+		t.Fatalf("CreateTrim: %v", err)
+	}
+	return trim
+}
+
+func TestModels(t *testing.T) {
+	f := setup(t)
+	honda := f.newBrand(t, "Honda")
+	civic := f.newModel(t, honda.ID, "Civic")
+
+	if _, err := f.catalog.CreateModel(f.ctx, models.CarModel{BrandID: honda.ID, Name: "CIVIC"}); !errors.Is(err, store.ErrDuplicateModel) {
+		t.Errorf("CreateModel with a taken name = %v, want ErrDuplicateModel", err)
+	}
+	if _, err := f.catalog.CreateModel(f.ctx, models.CarModel{BrandID: f.newBrand(t, "Toyota").ID, Name: "Civic"}); err != nil {
+		t.Errorf("another brand cannot use the model name: %v", err)
+	}
+	if _, err := f.catalog.CreateModel(f.ctx, models.CarModel{BrandID: uuid.New(), Name: "Civic"}); !errors.Is(err, store.ErrBrandNotFound) {
+		t.Errorf("CreateModel for an unknown brand = %v, want ErrBrandNotFound", err)
+	}
+
+	civic.Name = "Civic Type R"
+	if renamed, err := f.catalog.UpdateModel(f.ctx, civic); err != nil || renamed.Name != "Civic Type R" || renamed.BrandID != honda.ID {
+		t.Errorf("UpdateModel = %+v, %v; want renamed under Honda", renamed, err)
+	}
+	listed, err := f.catalog.GetModelsByBrand(f.ctx, honda.ID.String())
+	if err != nil || len(listed) != 1 {
+		t.Errorf("GetModelsByBrand = %+v, %v; want the one model", listed, err)
+	}
+	if _, err := f.catalog.GetModelById(f.ctx, uuid.NewString()); !errors.Is(err, store.ErrModelNotFound) {
+		t.Errorf("GetModelById = %v, want ErrModelNotFound", err)
+	}
+}
+
+func TestTrims(t *testing.T) {
+	f := setup(t)
+	petrol := storetest.NewEngine(f.ctx, t, f.stores)
+	hybrid := storetest.NewEngine(f.ctx, t, f.stores)
+	civic := f.newModel(t, f.newBrand(t, "Honda").ID, "Civic")
+
+	trim := f.newTrim(t, civic.ID, petrol, hybrid)
+	if len(trim.Engines) != 2 {
+		t.Errorf("trim engines = %+v, want both", trim.Engines)
+	}
+	if _, err := f.catalog.CreateTrim(f.ctx, models.Trim{ModelID: civic.ID, Name: "ex", Year: "2023", Engines: []models.Engine{petrol}}); !errors.Is(err, store.ErrDuplicateTrim) {
+		t.Errorf("CreateTrim with a taken name and year = %v, want ErrDuplicateTrim", err)
+	}
+	if _, err := f.catalog.CreateTrim(f.ctx, models.Trim{ModelID: civic.ID, Name: "EX", Year: "2024", Engines: []models.Engine{petrol}}); err != nil {
+		t.Errorf("CreateTrim for the next model year: %v", err)
+	}
+	if _, err := f.catalog.CreateTrim(f.ctx, models.Trim{ModelID: civic.ID, Name: "LX", Year: "2023", Engines: []models.Engine{{EngineID: uuid.New()}}}); !errors.Is(err, store.ErrInvalidEngine) {
+		t.Errorf("CreateTrim with an unknown engine = %v, want ErrInvalidEngine", err)
+	}
+	if _, err := f.catalog.CreateTrim(f.ctx, models.Trim{ModelID: uuid.New(), Name: "LX", Year: "2023", Engines: []models.Engine{petrol}}); !errors.Is(err, store.ErrModelNotFound) {
+		t.Errorf("CreateTrim for an unknown model = %v, want ErrModelNotFound", err)
+	}
+}
+
+func TestCarsOfTrims(t *testing.T) {
+	f := setup(t)
+	petrol := storetest.NewEngine(f.ctx, t, f.stores)
+	hybrid := storetest.NewEngine(f.ctx, t, f.stores)
+	civic := f.newModel(t, f.newBrand(t, "Honda").ID, "Civic")
+	trim := f.newTrim(t, civic.ID, petrol, hybrid)
+
+	newCar := func(brand string, engine models.Engine) (models.Car, error) {
+		return f.stores.Cars.CreateCar(f.ctx, models.Car{
+			Name:     "Civic",
+			Year:     "2023",
+			Brand:    brand,
+			FuelType: "Petrol",
+			Engine:   models.Engine{EngineID: engine.EngineID},
+			Price:    models.Money{Amount: 2500000, Currency: "USD"},
+			TrimID:   uuid.NullUUID{UUID: trim.ID, Valid: true},
+		})
+	}
+	if _, err := newCar("Toyota", petrol); !errors.Is(err, store.ErrInvalidTrim) {
+		t.Errorf("car of another brand's trim = %v, want ErrInvalidTrim", err)
+	}
+	if _, err := newCar("Honda", storetest.NewEngine(f.ctx, t, f.stores)); !errors.Is(err, store.ErrInvalidTrim) {
+		t.Errorf("car with an engine the trim does not offer = %v, want ErrInvalidTrim", err)
+	}
+	car, err := newCar("Honda", hybrid)
+	if err != nil {
+		t.Fatalf("CreateCar with a trim: %v", err)
+	}
+
+	trim.Engines = []models.Engine{petrol}
+	if _, err := f.catalog.UpdateTrim(f.ctx, trim); !errors.Is(err, store.ErrTrimEngineInUse) {
+		t.Errorf("dropping an engine a car uses = %v, want ErrTrimEngineInUse", err)
+	}
+	if err := f.catalog.DeleteTrim(f.ctx, trim.ID.String()); !errors.Is(err, store.ErrTrimInUse) {
+		t.Errorf("DeleteTrim with cars = %v, want ErrTrimInUse", err)
+	}
+	if err := f.catalog.DeleteModel(f.ctx, civic.ID.String()); !errors.Is(err, store.ErrModelInUse) {
+		t.Errorf("DeleteModel with cars = %v, want ErrModelInUse", err)
+	}
+
+	if err := f.stores.Cars.DeleteCar(f.ctx, car.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.catalog.DeleteModel(f.ctx, civic.ID.String()); err != nil {
+		t.Fatalf("DeleteModel: %v", err)
+	}
+	if _, err := f.catalog.GetTrimById(f.ctx, trim.ID.String()); !errors.Is(err, store.ErrTrimNotFound) {
+		t.Errorf("trim of a deleted model = %v, want ErrTrimNotFound", err)
+	}
+}
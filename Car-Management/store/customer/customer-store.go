@@ -0,0 +1,609 @@
+package customer
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/driver"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+const customerColumns = `id, name, email, phone, consent_email, consent_sms, consent_phone, notes, erased_at, created_by, created_at, updated_at`
+
+const selectCustomerQuery = `SELECT ` + customerColumns + ` FROM customer WHERE id=$1 AND tenant_id=$2`
+
+const enquiryColumns = `id, customer_id, car_id, channel, message, created_by, created_at`
+
+type CustomerStore struct {
+	db *driver.DB
+}
+
+func NewCustomerStore(db *driver.DB) *CustomerStore {
+	return &CustomerStore{db: db}
+}
+
+func (s CustomerStore) CreateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error) {
+	tracer := otel.Tracer("customer-store")
+	ctx, span := tracer.Start(ctx, "CreateCustomer-Store")
+	defer span.End()
+	var createdCustomer models.Customer
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return createdCustomer, err
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return createdCustomer, err
+	}
+
+	id := uuid.New()
+	if err := checkEmail(ctx, tx, id, customer.Email, tenantID); err != nil {
+		tx.Rollback()
+		return createdCustomer, err
+	}
+
+	now := time.Now().UTC()
+	query := `INSERT INTO customer (id, tenant_id, name, email, phone, phone_digits, consent_email, consent_sms, consent_phone, notes, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+
+	_, err = tx.ExecContext(ctx, query,
+		id,
+		tenantID,
+		customer.Name,
+		customer.Email,
+		customer.Phone,
+		models.PhoneDigits(customer.Phone),
+		customer.Consent.Email,
+		customer.Consent.SMS,
+		customer.Consent.Phone,
+		customer.Notes,
+		auth.Actor(ctx),
+		now,
+		now)
+	if err != nil {
+		tx.Rollback()
+		return createdCustomer, err
+	}
+
+	createdCustomer, err = scanCustomer(tx.QueryRowContext(ctx, selectCustomerQuery, id, tenantID))
+	if err != nil {
+		tx.Rollback()
+		return createdCustomer, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return createdCustomer, err
+	}
+
+	return createdCustomer, nil
+}
+
+func (s CustomerStore) GetCustomerById(ctx context.Context, customerID string) (models.Customer, error) {
+	tracer := otel.Tracer("customer-store")
+	ctx, span := tracer.Start(ctx, "GetCustomerById-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	customer, err := scanCustomer(s.db.QueryRowContext(ctx, selectCustomerQuery, customerID, tenantID))
+	if err == sql.ErrNoRows {
+		return customer, store.ErrCustomerNotFound
+	}
+	return customer, err
+}
+
+// SearchCustomers returns the customers whose name or email address contains
+// query, ignoring case, or whose phone number contains the digits in query.
+// An empty query returns every customer. Erased customers are left out.
+func (s CustomerStore) SearchCustomers(ctx context.Context, query string) ([]models.Customer, error) {
+	tracer := otel.Tracer("customer-store")
+	ctx, span := tracer.Start(ctx, "SearchCustomers-Store")
+	defer span.End()
+	customers := []models.Customer{}
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return customers, err
+	}
+
+	text := "%" + escapeLike(strings.ToLower(query)) + "%"
+	// Without digits to look for, match no phone number rather than all.
+	digits := "-"
+	if d := models.PhoneDigits(query); d != "" {
+		digits = "%" + d + "%"
+	}
+	search := `SELECT ` + customerColumns + ` FROM customer
+		WHERE tenant_id=$1 AND erased_at IS NULL
+		AND (lower(name) LIKE $2 ESCAPE '\' OR lower(email) LIKE $2 ESCAPE '\' OR phone_digits LIKE $3)
+		ORDER BY lower(name), id`
+
+	rows, err := s.db.QueryContext(ctx, search, tenantID, text, digits)
+	if err != nil {
+		return customers, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		customer, err := scanCustomer(rows)
+		if err != nil {
+			return customers, err
+		}
+		customers = append(customers, customer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return customers, err
+	}
+
+	return customers, nil
+}
+
+// UpdateCustomer replaces a customer's contact details, consent and notes.
+// Erased customers cannot be changed.
+func (s CustomerStore) UpdateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error) {
+	tracer := otel.Tracer("customer-store")
+	ctx, span := tracer.Start(ctx, "UpdateCustomer-Store")
+	defer span.End()
+	var updatedCustomer models.Customer
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return updatedCustomer, err
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return updatedCustomer, err
+	}
+
+	if _, err := lockCustomer(ctx, tx, customer.ID.String(), tenantID); err != nil {
+		tx.Rollback()
+		return updatedCustomer, err
+	}
+
+	if err := checkEmail(ctx, tx, customer.ID, customer.Email, tenantID); err != nil {
+		tx.Rollback()
+		return updatedCustomer, err
+	}
+
+	query := `UPDATE customer SET name=$3, email=$4, phone=$5, phone_digits=$6, consent_email=$7, consent_sms=$8, consent_phone=$9, notes=$10, updated_at=$11
+		WHERE id=$1 AND tenant_id=$2`
+	_, err = tx.ExecContext(ctx, query,
+		customer.ID,
+		tenantID,
+		customer.Name,
+		customer.Email,
+		customer.Phone,
+		models.PhoneDigits(customer.Phone),
+		customer.Consent.Email,
+		customer.Consent.SMS,
+		customer.Consent.Phone,
+		customer.Notes,
+		time.Now().UTC())
+	if err != nil {
+		tx.Rollback()
+		return updatedCustomer, err
+	}
+
+	updatedCustomer, err = scanCustomer(tx.QueryRowContext(ctx, selectCustomerQuery, customer.ID, tenantID))
+	if err != nil {
+		tx.Rollback()
+		return updatedCustomer, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return updatedCustomer, err
+	}
+
+	return updatedCustomer, nil
+}
+
+// CreateEnquiry records a question of a customer who has not been erased.
+func (s CustomerStore) CreateEnquiry(ctx context.Context, enquiry models.Enquiry) (models.Enquiry, error) {
+	tracer := otel.Tracer("customer-store")
+	ctx, span := tracer.Start(ctx, "CreateEnquiry-Store")
+	defer span.End()
+	var createdEnquiry models.Enquiry
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return createdEnquiry, err
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return createdEnquiry, err
+	}
+
+	if _, err := lockCustomer(ctx, tx, enquiry.CustomerID.String(), tenantID); err != nil {
+		tx.Rollback()
+		return createdEnquiry, err
+	}
+
+	if enquiry.CarID.Valid {
+		var carID uuid.UUID
+		err := tx.QueryRowContext(ctx, `SELECT id FROM car WHERE id=$1 AND tenant_id=$2`, enquiry.CarID, tenantID).Scan(&carID)
+		if err != nil {
+			tx.Rollback()
+			if err == sql.ErrNoRows {
+				return createdEnquiry, store.ErrCarNotFound
+			}
+			return createdEnquiry, err
+		}
+	}
+
+	id := uuid.New()
+	query := `INSERT INTO enquiry (id, tenant_id, customer_id, car_id, channel, message, created_by, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err = tx.ExecContext(ctx, query,
+		id,
+		tenantID,
+		enquiry.CustomerID,
+		enquiry.CarID,
+		enquiry.Channel,
+		enquiry.Message,
+		auth.Actor(ctx),
+		time.Now().UTC())
+	if err != nil {
+		tx.Rollback()
+		return createdEnquiry, err
+	}
+
+	createdEnquiry, err = scanEnquiry(tx.QueryRowContext(ctx, `SELECT `+enquiryColumns+` FROM enquiry WHERE id=$1 AND tenant_id=$2`, id, tenantID))
+	if err != nil {
+		tx.Rollback()
+		return createdEnquiry, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return createdEnquiry, err
+	}
+
+	return createdEnquiry, nil
+}
+
+func (s CustomerStore) GetEnquiriesByCustomer(ctx context.Context, customerID string) ([]models.Enquiry, error) {
+	tracer := otel.Tracer("customer-store")
+	ctx, span := tracer.Start(ctx, "GetEnquiriesByCustomer-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var id uuid.UUID
+	err = s.db.QueryRowContext(ctx, `SELECT id FROM customer WHERE id=$1 AND tenant_id=$2`, customerID, tenantID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, store.ErrCustomerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return enquiries(ctx, s.db, customerID, tenantID)
+}
+
+// GetCustomerRecord returns the customer together with every reservation,
+// order and enquiry linked to them.
+func (s CustomerStore) GetCustomerRecord(ctx context.Context, customerID string) (models.CustomerRecord, error) {
+	tracer := otel.Tracer("customer-store")
+	ctx, span := tracer.Start(ctx, "GetCustomerRecord-Store")
+	defer span.End()
+	var record models.CustomerRecord
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return record, err
+	}
+
+	record.Customer, err = scanCustomer(s.db.QueryRowContext(ctx, selectCustomerQuery, customerID, tenantID))
+	if err == sql.ErrNoRows {
+		return record, store.ErrCustomerNotFound
+	}
+	if err != nil {
+		return record, err
+	}
+
+	record.Reservations, err = reservations(ctx, s.db, customerID, tenantID)
+	if err != nil {
+		return record, err
+	}
+	record.Orders, err = orders(ctx, s.db, customerID, tenantID)
+	if err != nil {
+		return record, err
+	}
+	record.OrderTransitions, err = orderTransitions(ctx, s.db, customerID, tenantID)
+	if err != nil {
+		return record, err
+	}
+	record.Enquiries, err = enquiries(ctx, s.db, customerID, tenantID)
+	if err != nil {
+		return record, err
+	}
+
+	return record, nil
+}
+
+// EraseCustomer removes a customer's personal data. The customer's contact
+// details, consent and notes are blanked and their enquiries deleted. Their
+// reservations and orders are kept for the dealership's records, with the
+// customer's name replaced and the notes, which may mention them, cleared.
+func (s CustomerStore) EraseCustomer(ctx context.Context, customerID string) (models.Customer, error) {
+	tracer := otel.Tracer("customer-store")
+	ctx, span := tracer.Start(ctx, "EraseCustomer-Store")
+	defer span.End()
+	var erasedCustomer models.Customer
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return erasedCustomer, err
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return erasedCustomer, err
+	}
+
+	if _, err := lockCustomer(ctx, tx, customerID, tenantID); err != nil {
+		tx.Rollback()
+		return erasedCustomer, err
+	}
+
+	now := time.Now().UTC()
+	statements := []struct {
+		query string
+		args  []any
+	}{
+		{`UPDATE customer SET name='', email='', phone='', phone_digits='', consent_email=FALSE, consent_sms=FALSE, consent_phone=FALSE, notes='', erased_at=$3, updated_at=$3
+			WHERE id=$1 AND tenant_id=$2`, []any{now}},
+		{`DELETE FROM enquiry WHERE customer_id=$1 AND tenant_id=$2`, nil},
+		{`UPDATE reservation SET customer_name=$3, notes='', updated_at=$4 WHERE customer_id=$1 AND tenant_id=$2`, []any{models.ErasedCustomerName, now}},
+		{`UPDATE sales_order_transition SET note='' WHERE tenant_id=$2 AND order_id IN (SELECT id FROM sales_order WHERE customer_id=$1 AND tenant_id=$2)`, nil},
+		{`UPDATE sales_order SET customer_name=$3, notes='', updated_at=$4 WHERE customer_id=$1 AND tenant_id=$2`, []any{models.ErasedCustomerName, now}},
+	}
+	for _, statement := range statements {
+		_, err := tx.ExecContext(ctx, statement.query, append([]any{customerID, tenantID}, statement.args...)...)
+		if err != nil {
+			tx.Rollback()
+			return erasedCustomer, err
+		}
+	}
+
+	erasedCustomer, err = scanCustomer(tx.QueryRowContext(ctx, selectCustomerQuery, customerID, tenantID))
+	if err != nil {
+		tx.Rollback()
+		return erasedCustomer, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return erasedCustomer, err
+	}
+
+	return erasedCustomer, nil
+}
+
+// lockCustomer reads a customer who has not been erased, locking the row on
+// Postgres. SQLite transactions already hold the database write lock.
+func lockCustomer(ctx context.Context, tx *driver.Tx, customerID string, tenantID uuid.UUID) (models.Customer, error) {
+	query := selectCustomerQuery
+	if tx.Dialect() == driver.Postgres {
+		query += ` FOR UPDATE`
+	}
+	customer, err := scanCustomer(tx.QueryRowContext(ctx, query, customerID, tenantID))
+	if err == sql.ErrNoRows {
+		return customer, store.ErrCustomerNotFound
+	}
+	if err != nil {
+		return customer, err
+	}
+	if customer.ErasedAt != nil {
+		return customer, store.ErrCustomerErased
+	}
+	return customer, nil
+}
+
+// checkEmail fails with store.ErrDuplicateCustomerEmail if a customer other
+// than customerID already has the email address.
+func checkEmail(ctx context.Context, tx *driver.Tx, customerID uuid.UUID, email string, tenantID uuid.UUID) error {
+	if email == "" {
+		return nil
+	}
+	var id uuid.UUID
+	err := tx.QueryRowContext(ctx, `SELECT id FROM customer WHERE tenant_id=$1 AND lower(email)=lower($2) AND id<>$3`, tenantID, email, customerID).Scan(&id)
+	if err == nil {
+		return store.ErrDuplicateCustomerEmail
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+	return nil
+}
+
+func reservations(ctx context.Context, db store.Queryer, customerID string, tenantID uuid.UUID) ([]models.Reservation, error) {
+	reservations := []models.Reservation{}
+
+	query := `SELECT id, car_id, location, customer_name, customer_id, notes, status, starts_at, ends_at, created_by, created_at, updated_at
+		FROM reservation WHERE customer_id=$1 AND tenant_id=$2 ORDER BY created_at, id`
+	rows, err := db.QueryContext(ctx, query, customerID, tenantID)
+	if err != nil {
+		return reservations, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var reservation models.Reservation
+		err := rows.Scan(&reservation.ID,
+			&reservation.CarID,
+			&reservation.Location,
+			&reservation.CustomerName,
+			&reservation.CustomerID,
+			&reservation.Notes,
+			&reservation.Status,
+			&reservation.StartsAt,
+			&reservation.EndsAt,
+			&reservation.CreatedBy,
+			&reservation.CreatedAt,
+			&reservation.UpdatedAt)
+		if err != nil {
+			return reservations, err
+		}
+		reservation.StartsAt = reservation.StartsAt.UTC()
+		reservation.EndsAt = reservation.EndsAt.UTC()
+		reservations = append(reservations, reservation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return reservations, err
+	}
+
+	return reservations, nil
+}
+
+func orders(ctx context.Context, db store.Queryer, customerID string, tenantID uuid.UUID) ([]models.Order, error) {
+	orders := []models.Order{}
+
+	query := `SELECT id, car_id, customer_name, customer_id, price_amount, price_currency, deposit_amount, notes, status, stock_unit_id, created_by, created_at, updated_at
+		FROM sales_order WHERE customer_id=$1 AND tenant_id=$2 ORDER BY created_at, id`
+	rows, err := db.QueryContext(ctx, query, customerID, tenantID)
+	if err != nil {
+		return orders, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var order models.Order
+		err := rows.Scan(&order.ID,
+			&order.CarID,
+			&order.CustomerName,
+			&order.CustomerID,
+			&order.AgreedPrice.Amount,
+			&order.AgreedPrice.Currency,
+			&order.Deposit.Amount,
+			&order.Notes,
+			&order.Status,
+			&order.StockUnitID,
+			&order.CreatedBy,
+			&order.CreatedAt,
+			&order.UpdatedAt)
+		if err != nil {
+			return orders, err
+		}
+		order.Deposit.Currency = order.AgreedPrice.Currency
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return orders, err
+	}
+
+	return orders, nil
+}
+
+func orderTransitions(ctx context.Context, db store.Queryer, customerID string, tenantID uuid.UUID) ([]models.OrderTransition, error) {
+	transitions := []models.OrderTransition{}
+
+	query := `SELECT t.id, t.order_id, t.from_status, t.to_status, t.note, t.changed_by, t.changed_at
+		FROM sales_order_transition t JOIN sales_order o ON o.id = t.order_id
+		WHERE o.customer_id=$1 AND o.tenant_id=$2 ORDER BY t.changed_at, t.id`
+	rows, err := db.QueryContext(ctx, query, customerID, tenantID)
+	if err != nil {
+		return transitions, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transition models.OrderTransition
+		err := rows.Scan(&transition.ID,
+			&transition.OrderID,
+			&transition.FromStatus,
+			&transition.ToStatus,
+			&transition.Note,
+			&transition.ChangedBy,
+			&transition.ChangedAt)
+		if err != nil {
+			return transitions, err
+		}
+		transitions = append(transitions, transition)
+	}
+
+	if err := rows.Err(); err != nil {
+		return transitions, err
+	}
+
+	return transitions, nil
+}
+
+func enquiries(ctx context.Context, db store.Queryer, customerID string, tenantID uuid.UUID) ([]models.Enquiry, error) {
+	enquiries := []models.Enquiry{}
+
+	query := `SELECT ` + enquiryColumns + ` FROM enquiry WHERE customer_id=$1 AND tenant_id=$2 ORDER BY created_at, id`
+	rows, err := db.QueryContext(ctx, query, customerID, tenantID)
+	if err != nil {
+		return enquiries, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		enquiry, err := scanEnquiry(rows)
+		if err != nil {
+			return enquiries, err
+		}
+		enquiries = append(enquiries, enquiry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return enquiries, err
+	}
+
+	return enquiries, nil
+}
+
+// escapeLike escapes the LIKE wildcards in s, using \ as the escape
+// character.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func scanCustomer(row store.Scanner) (models.Customer, error) {
+	var customer models.Customer
+	var erasedAt sql.NullTime
+	err := row.Scan(&customer.ID,
+		&customer.Name,
+		&customer.Email,
+		&customer.Phone,
+		&customer.Consent.Email,
+		&customer.Consent.SMS,
+		&customer.Consent.Phone,
+		&customer.Notes,
+		&erasedAt,
+		&customer.CreatedBy,
+		&customer.CreatedAt,
+		&customer.UpdatedAt)
+	if erasedAt.Valid {
+		customer.ErasedAt = &erasedAt.Time
+	}
+	return customer, err
+}
+
+func scanEnquiry(row store.Scanner) (models.Enquiry, error) {
+	var enquiry models.Enquiry
+	err := row.Scan(&enquiry.ID,
+		&enquiry.CustomerID,
+		&enquiry.CarID,
+		&enquiry.Channel,
+		&enquiry.Message,
+		&enquiry.CreatedBy,
+		&enquiry.CreatedAt)
+	return enquiry, err
+}
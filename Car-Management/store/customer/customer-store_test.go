@@ -0,0 +1,200 @@
+package customer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"github.com/nitesh111sinha/car-management/store/customer"
+	"github.com/nitesh111sinha/car-management/store/order"
+	"github.com/nitesh111sinha/car-management/store/reservation"
+	"github.com/nitesh111sinha/car-management/store/storetest"
+)
+
+type fixture struct {
+	ctx          context.Context
+	customers    *customer.CustomerStore
+	reservations *reservation.ReservationStore
+	orders       *order.OrderStore
+	stores       storetest.Stores
+	car          models.Car
+}
+
+func setup(t *testing.T) fixture {
+	t.Helper()
+	db := storetest.OpenSQLite(t)
+	s := storetest.SQLStores(db)
+	ctx := storetest.NewTenant(t, s)
+	f := fixture{
+		ctx:          ctx,
+		customers:    customer.NewCustomerStore(db),
+		reservations: reservation.NewReservationStore(db),
+		orders:       order.NewOrderStore(db),
+		stores:       s,
+		car:          storetest.NewCar(ctx, t, s, "Honda", storetest.NewEngine(ctx, t, s)),
+	}
+	if _, err := f.reservations.SetLocationHours(ctx, models.LocationHours{Location: "Showroom", TimeZone: "UTC"}); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func (f fixture) create(t *testing.T, name, email, phone string) models.Customer {
+	t.Helper()
+	created, err := f.customers.CreateCustomer(f.ctx, models.Customer{
+		Name:    name,
+		Email:   email,
+		Phone:   phone,
+		Consent: models.Consent{Email: email != "", SMS: phone != ""},
+		Notes:   "prefers mornings",
+	})
+	if err != nil {
+		t.Fatalf("CreateCustomer: %v", err)
+	}
+	return created
+}
+
+func names(customers []models.Customer) []string {
+	var names []string
+	for _, c := range customers {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func TestCreateAndSearchCustomers(t *testing.T) {
+	f := setup(t)
+	f.create(t, "Ann Lee", "ann@example.com", "+49 30 123456")
+	f.create(t, "Bob 100% Real", "bob@example.org", "")
+
+	if _, err := f.customers.CreateCustomer(f.ctx, models.Customer{Name: "Ann Again", Email: "ann@example.com"}); !errors.Is(err, store.ErrDuplicateCustomerEmail) {
+		t.Errorf("reusing an email address = %v, want ErrDuplicateCustomerEmail", err)
+	}
+	if _, err := f.customers.CreateCustomer(storetest.NewTenant(t, f.stores), models.Customer{Name: "Ann Elsewhere", Email: "ann@example.com"}); err != nil {
+		t.Errorf("the same email address in another tenant = %v, want nil", err)
+	}
+
+	for query, want := range map[string]int{
+		"":            2,
+		"ann":         1,
+		"LEE":         1,
+		"example.org": 1,
+		"30-123":      1,
+		"100%":        1,
+		"%":           1,
+		"nobody":      0,
+	} {
+		found, err := f.customers.SearchCustomers(f.ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(found) != want {
+			t.Errorf("SearchCustomers(%q) = %v, want %d customers", query, names(found), want)
+		}
+	}
+	if _, err := f.customers.GetCustomerById(f.ctx, uuid.NewString()); !errors.Is(err, store.ErrCustomerNotFound) {
+		t.Errorf("GetCustomerById(missing) = %v, want ErrCustomerNotFound", err)
+	}
+}
+
+func TestEnquiries(t *testing.T) {
+	f := setup(t)
+	ann := f.create(t, "Ann Lee", "ann@example.com", "")
+
+	enquiry, err := f.customers.CreateEnquiry(f.ctx, models.Enquiry{CustomerID: ann.ID, CarID: uuid.NullUUID{UUID: f.car.ID, Valid: true}, Channel: models.EnquiryEmail, Message: "Price?"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enquiry.CustomerID != ann.ID || enquiry.Message != "Price?" {
+		t.Errorf("enquiry = %+v", enquiry)
+	}
+	if _, err := f.customers.CreateEnquiry(f.ctx, models.Enquiry{CustomerID: ann.ID, CarID: uuid.NullUUID{UUID: uuid.New(), Valid: true}, Channel: models.EnquiryEmail, Message: "?"}); !errors.Is(err, store.ErrCarNotFound) {
+		t.Errorf("enquiry about an unknown car = %v, want ErrCarNotFound", err)
+	}
+	if _, err := f.customers.CreateEnquiry(f.ctx, models.Enquiry{CustomerID: uuid.New(), Channel: models.EnquiryEmail, Message: "?"}); !errors.Is(err, store.ErrCustomerNotFound) {
+		t.Errorf("enquiry of an unknown customer = %v, want ErrCustomerNotFound", err)
+	}
+	if _, err := f.customers.GetEnquiriesByCustomer(f.ctx, uuid.NewString()); !errors.Is(err, store.ErrCustomerNotFound) {
+		t.Errorf("enquiries of an unknown customer = %v, want ErrCustomerNotFound", err)
+	}
+}
+
+func TestEraseCustomer(t *testing.T) {
+	f := setup(t)
+	ann := f.create(t, "Ann Lee", "ann@example.com", "+49 30 123456")
+	bob := f.create(t, "Bob", "bob@example.com", "")
+	customerID := uuid.NullUUID{UUID: ann.ID, Valid: true}
+
+	if _, err := f.customers.CreateEnquiry(f.ctx, models.Enquiry{CustomerID: ann.ID, Channel: models.EnquiryPhone, Message: "Call me on my mobile"}); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Hour)
+	booked, err := f.reservations.CreateReservation(f.ctx, models.Reservation{CarID: f.car.ID, Location: "Showroom", CustomerID: customerID, Notes: "bringing her son", StartsAt: start, EndsAt: start.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if booked.CustomerName != "Ann Lee" {
+		t.Errorf("reservation customer name = %q, want the customer's", booked.CustomerName)
+	}
+	drafted, err := f.orders.CreateOrder(f.ctx, models.Order{CarID: f.car.ID, CustomerID: customerID, AgreedPrice: models.Money{Amount: 100, Currency: "USD"}, Deposit: models.Money{Currency: "USD"}, Notes: "pays cash"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.orders.TransitionOrder(f.ctx, drafted.ID.String(), models.OrderCancelled, models.TransitionRequest{Note: "Ann changed her mind"}); err != nil {
+		t.Fatal(err)
+	}
+
+	erased, err := f.customers.EraseCustomer(f.ctx, ann.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if erased.ErasedAt == nil || erased.Name != "" || erased.Email != "" || erased.Phone != "" || erased.Notes != "" || erased.Consent != (models.Consent{}) {
+		t.Errorf("erased customer = %+v, want every personal field blank", erased)
+	}
+
+	record, err := f.customers.GetCustomerRecord(f.ctx, ann.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(record.Enquiries) != 0 {
+		t.Errorf("enquiries after erasure = %+v, want none", record.Enquiries)
+	}
+	if len(record.Reservations) != 1 || record.Reservations[0].CustomerName != models.ErasedCustomerName || record.Reservations[0].Notes != "" {
+		t.Errorf("reservations after erasure = %+v, want kept without name or notes", record.Reservations)
+	}
+	if len(record.Orders) != 1 || record.Orders[0].CustomerName != models.ErasedCustomerName || record.Orders[0].Notes != "" {
+		t.Errorf("orders after erasure = %+v, want kept without name or notes", record.Orders)
+	}
+	for _, transition := range record.OrderTransitions {
+		if transition.Note != "" {
+			t.Errorf("order transition note %q survived erasure", transition.Note)
+		}
+	}
+
+	// The erased customer can no longer be found, changed or linked to.
+	if found, _ := f.customers.SearchCustomers(f.ctx, ""); len(found) != 1 || found[0].ID != bob.ID {
+		t.Errorf("search after erasure = %v, want only Bob", names(found))
+	}
+	if _, err := f.customers.EraseCustomer(f.ctx, ann.ID.String()); !errors.Is(err, store.ErrCustomerErased) {
+		t.Errorf("erasing twice = %v, want ErrCustomerErased", err)
+	}
+	if _, err := f.customers.UpdateCustomer(f.ctx, models.Customer{ID: ann.ID, Name: "Ann"}); !errors.Is(err, store.ErrCustomerErased) {
+		t.Errorf("updating an erased customer = %v, want ErrCustomerErased", err)
+	}
+	if _, err := f.customers.CreateEnquiry(f.ctx, models.Enquiry{CustomerID: ann.ID, Channel: models.EnquiryWeb, Message: "?"}); !errors.Is(err, store.ErrCustomerErased) {
+		t.Errorf("enquiry of an erased customer = %v, want ErrCustomerErased", err)
+	}
+	if _, err := f.orders.CreateOrder(f.ctx, models.Order{CarID: f.car.ID, CustomerID: customerID, AgreedPrice: models.Money{Amount: 100, Currency: "USD"}}); !errors.Is(err, store.ErrInvalidCustomer) {
+		t.Errorf("order for an erased customer = %v, want ErrInvalidCustomer", err)
+	}
+
+	// Erasing frees the email address for someone else.
+	f.create(t, "Ann Lee", "ann@example.com", "")
+	if _, err := f.customers.EraseCustomer(f.ctx, uuid.NewString()); !errors.Is(err, store.ErrCustomerNotFound) {
+		t.Errorf("erasing an unknown customer = %v, want ErrCustomerNotFound", err)
+	}
+}
@@ -3,21 +3,32 @@ package engine
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/driver"
 	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"github.com/nitesh111sinha/car-management/store/attachment"
 )
 
+const selectEngineQuery = `SELECT id, type, displacement, no_of_cylinders, car_range, battery_kwh, charging_power_kw, efficiency_wh_per_km FROM engine WHERE id=$1 AND tenant_id=$2`
+
 type EngineStore struct {
-	db *sql.DB
+	db *driver.DB
 }
 
-func NewEngineStore(db *sql.DB) *EngineStore {
+func NewEngineStore(db *driver.DB) *EngineStore {
 	return &EngineStore{db: db}
 }
 
 func (s EngineStore) CreateEngine(ctx context.Context, engine models.Engine) (models.Engine, error) {
 	var createdEngine models.Engine
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return createdEngine, err
+	}
 
 	// Begin Transaction
 	tx, err := s.db.BeginTx(ctx, nil)
@@ -29,19 +40,23 @@ func (s EngineStore) CreateEngine(ctx context.Context, engine models.Engine) (mo
 
 	newEngine := models.Engine{
 		EngineID:      engineId,
+		Type:          models.PowertrainType(engine),
 		Displacement:  engine.Displacement,
 		NoOfCylinders: engine.NoOfCylinders,
 		CarRange:      engine.CarRange,
+		Motor:         engine.Motor,
 	}
+	battery, chargingPower, efficiency := motorColumns(newEngine)
 
-	query := `INSERT INTO engine (id, displacement, no_of_cylinders, car_range) VALUES ($1, $2, $3, $4) RETURNING id, displacement, no_of_cylinders, car_range`
+	query := `INSERT INTO engine (id, type, displacement, no_of_cylinders, car_range, battery_kwh, charging_power_kw, efficiency_wh_per_km, tenant_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
 
-	err = tx.QueryRowContext(ctx, query, newEngine.EngineID, newEngine.Displacement, newEngine.NoOfCylinders, newEngine.CarRange).Scan(
-		&createdEngine.EngineID,
-		&createdEngine.Displacement,
-		&createdEngine.NoOfCylinders,
-		&createdEngine.CarRange)
+	_, err = tx.ExecContext(ctx, query, newEngine.EngineID, newEngine.Type, newEngine.Displacement, newEngine.NoOfCylinders, newEngine.CarRange, battery, chargingPower, efficiency, tenantID)
+	if err != nil {
+		tx.Rollback()
+		return createdEngine, err
+	}
 
+	createdEngine, err = scanEngine(tx.QueryRowContext(ctx, selectEngineQuery, newEngine.EngineID, tenantID))
 	if err != nil {
 		tx.Rollback()
 		return createdEngine, err
@@ -57,6 +72,10 @@ func (s EngineStore) CreateEngine(ctx context.Context, engine models.Engine) (mo
 
 func (s EngineStore) UpdateEngine(ctx context.Context, engineId string, engine models.Engine) (models.Engine, error) {
 	var updatedEngine models.Engine
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return updatedEngine, err
+	}
 
 	// Begin Transaction
 	tx, err := s.db.BeginTx(ctx, nil)
@@ -65,14 +84,38 @@ func (s EngineStore) UpdateEngine(ctx context.Context, engineId string, engine m
 	}
 
 	// Update Engine
-	query := `UPDATE engine SET displacement=$2, no_of_cylinders=$3, car_range=$4 WHERE id=$1 RETURNING id, displacement, no_of_cylinders, car_range`
+	query := `UPDATE engine SET type=$2, displacement=$3, no_of_cylinders=$4, car_range=$5, battery_kwh=$6, charging_power_kw=$7, efficiency_wh_per_km=$8, updated_at=$9 WHERE id=$1 AND tenant_id=$10`
+
+	battery, chargingPower, efficiency := motorColumns(engine)
+	result, err := tx.ExecContext(ctx, query, engineId, models.PowertrainType(engine), engine.Displacement, engine.NoOfCylinders, engine.CarRange, battery, chargingPower, efficiency, time.Now(), tenantID)
+	if err != nil {
+		tx.Rollback()
+		return updatedEngine, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return updatedEngine, err
+	}
+	if rowsAffected == 0 {
+		tx.Rollback()
+		return updatedEngine, sql.ErrNoRows
+	}
 
-	err = tx.QueryRowContext(ctx, query, engineId, engine.Displacement, engine.NoOfCylinders, engine.CarRange).Scan(
-		&updatedEngine.EngineID,
-		&updatedEngine.Displacement,
-		&updatedEngine.NoOfCylinders,
-		&updatedEngine.CarRange)
+	// The cars using the engine must still fit its powertrain
+	cars, err := engineCars(ctx, tx, engineId, tenantID)
+	if err != nil {
+		tx.Rollback()
+		return updatedEngine, err
+	}
+	for _, car := range cars {
+		if !models.FuelTypeFits(car.FuelType, models.PowertrainType(engine)) {
+			tx.Rollback()
+			return updatedEngine, store.ErrPowertrainMismatch
+		}
+	}
 
+	updatedEngine, err = scanEngine(tx.QueryRowContext(ctx, selectEngineQuery, engineId, tenantID))
 	if err != nil {
 		tx.Rollback()
 		return updatedEngine, err
@@ -87,15 +130,12 @@ func (s EngineStore) UpdateEngine(ctx context.Context, engineId string, engine m
 }
 
 func (s EngineStore) GetEngineById(ctx context.Context, engineId string) (models.Engine, error) {
-	var engine models.Engine
-
-	query := `SELECT id, displacement, no_of_cylinders, car_range FROM engine WHERE id=$1`
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return models.Engine{}, err
+	}
 
-	err := s.db.QueryRowContext(ctx, query, engineId).Scan(
-		&engine.EngineID,
-		&engine.Displacement,
-		&engine.NoOfCylinders,
-		&engine.CarRange)
+	engine, err := scanEngine(s.db.QueryRowContext(ctx, selectEngineQuery, engineId, tenantID))
 	if err != nil {
 		return engine, err
 	}
@@ -103,17 +143,74 @@ func (s EngineStore) GetEngineById(ctx context.Context, engineId string) (models
 	return engine, nil
 }
 
-func (s EngineStore) DeleteEngine(ctx context.Context, engineId string) error {
+// DeleteEngine deletes an engine. Unless cascade is set it refuses while cars
+// use the engine and returns them in a *store.EngineInUseError. Cascading
+// still refuses with store.ErrCarHasOrders while any of those cars has sales
+// orders.
+func (s EngineStore) DeleteEngine(ctx context.Context, engineId string, cascade bool) error {
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
 	// Begin Transaction
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
-	// Delete Engine
-	query := `DELETE FROM engine WHERE id=$1`
+	// Lock the engine so no car can start using it between the check and the
+	// delete. SQLite transactions already hold the database write lock.
+	query := `SELECT id FROM engine WHERE id=$1 AND tenant_id=$2`
+	if tx.Dialect() == driver.Postgres {
+		query += ` FOR UPDATE`
+	}
+	var id uuid.UUID
+	if err := tx.QueryRowContext(ctx, query, engineId, tenantID).Scan(&id); err != nil {
+		tx.Rollback()
+		return err
+	}
 
-	result, err := tx.ExecContext(ctx, query, engineId)
+	if !cascade {
+		cars, err := engineCars(ctx, tx, engineId, tenantID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if len(cars) > 0 {
+			tx.Rollback()
+			return &store.EngineInUseError{Cars: cars}
+		}
+	} else {
+		var orders int
+		err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM sales_order o JOIN car c ON c.id = o.car_id WHERE c.engine_id=$1 AND c.tenant_id=$2`, engineId, tenantID).Scan(&orders)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if orders > 0 {
+			tx.Rollback()
+			return store.ErrCarHasOrders
+		}
+
+		// The cars' attachment records cascade too; queue their files for deletion.
+		cars, err := engineCars(ctx, tx, engineId, tenantID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		for _, car := range cars {
+			if err := attachment.QueueCarBlobs(ctx, tx, car.ID.String(), tenantID); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+
+	// Delete Engine; cars using it cascade
+	query = `DELETE FROM engine WHERE id=$1 AND tenant_id=$2`
+
+	result, err := tx.ExecContext(ctx, query, engineId, tenantID)
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -136,24 +233,37 @@ func (s EngineStore) DeleteEngine(ctx context.Context, engineId string) error {
 	return nil
 }
 
+// GetEngineCars lists the cars that use an engine, oldest first.
+func (s EngineStore) GetEngineCars(ctx context.Context, engineId string) ([]models.Car, error) {
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := scanEngine(s.db.QueryRowContext(ctx, selectEngineQuery, engineId, tenantID)); err != nil {
+		return nil, err
+	}
+
+	return engineCars(ctx, s.db, engineId, tenantID)
+}
+
 func (s EngineStore) GetEngines(ctx context.Context) ([]models.Engine, error) {
 	var engines []models.Engine
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return engines, err
+	}
 
-	query := `SELECT id, displacement, no_of_cylinders, car_range FROM engine`
+	query := `SELECT id, type, displacement, no_of_cylinders, car_range, battery_kwh, charging_power_kw, efficiency_wh_per_km FROM engine WHERE tenant_id=$1`
 
-	rows, err := s.db.QueryContext(ctx, query)
+	rows, err := s.db.QueryContext(ctx, query, tenantID)
 	if err != nil {
 		return engines, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var engine models.Engine
-		err := rows.Scan(
-			&engine.EngineID,
-			&engine.Displacement,
-			&engine.NoOfCylinders,
-			&engine.CarRange)
+		engine, err := scanEngine(rows)
 		if err != nil {
 			return engines, err
 		}
@@ -166,3 +276,81 @@ func (s EngineStore) GetEngines(ctx context.Context) ([]models.Engine, error) {
 
 	return engines, nil
 }
+
+// engineCars returns the tenant's cars that use the engine.
+func engineCars(ctx context.Context, q store.Queryer, engineId string, tenantID uuid.UUID) ([]models.Car, error) {
+	query := `SELECT id, name, year, brand, fuel_type, engine_id, price_amount, price_currency, COALESCE(vin, ''), trim_id, created_at, updated_at FROM car WHERE engine_id=$1 AND tenant_id=$2 ORDER BY created_at, id`
+	rows, err := q.QueryContext(ctx, query, engineId, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cars := []models.Car{}
+	for rows.Next() {
+		var car models.Car
+		err := rows.Scan(&car.ID,
+			&car.Name,
+			&car.Year,
+			&car.Brand,
+			&car.FuelType,
+			&car.Engine.EngineID,
+			&car.Price.Amount,
+			&car.Price.Currency,
+			&car.VIN,
+			&car.TrimID,
+			&car.CreatedAt,
+			&car.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		cars = append(cars, car)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return cars, nil
+}
+
+// scanEngine reads a row produced by selectEngineQuery.
+func scanEngine(row store.Scanner) (models.Engine, error) {
+	var engine models.Engine
+	var battery, chargingPower, efficiency sql.NullFloat64
+	err := row.Scan(
+		&engine.EngineID,
+		&engine.Type,
+		&engine.Displacement,
+		&engine.NoOfCylinders,
+		&engine.CarRange,
+		&battery,
+		&chargingPower,
+		&efficiency)
+	engine.Motor = Motor(battery, chargingPower, efficiency)
+	return engine, err
+}
+
+// Motor builds the electric motor from the engine table's motor columns,
+// which are NULL for combustion engines. Other stores that join engine use
+// it too.
+func Motor(battery, chargingPower, efficiency sql.NullFloat64) *models.ElectricMotor {
+	if !battery.Valid {
+		return nil
+	}
+	return &models.ElectricMotor{
+		BatteryKWh:        battery.Float64,
+		ChargingPowerKW:   chargingPower.Float64,
+		EfficiencyWhPerKm: efficiency.Float64,
+	}
+}
+
+// motorColumns returns the values of the motor columns for engine.
+func motorColumns(engine models.Engine) (battery, chargingPower, efficiency sql.NullFloat64) {
+	if engine.Motor == nil || models.PowertrainType(engine) == models.PowertrainICE {
+		return
+	}
+	return sql.NullFloat64{Float64: engine.Motor.BatteryKWh, Valid: true},
+		sql.NullFloat64{Float64: engine.Motor.ChargingPowerKW, Valid: true},
+		sql.NullFloat64{Float64: engine.Motor.EfficiencyWhPerKm, Valid: true}
+}
@@ -0,0 +1,118 @@
+package exchangerate
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/driver"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+const selectExchangeRateQuery = `SELECT currency, rate, updated_by, updated_at FROM exchange_rate WHERE currency=$1`
+
+// ExchangeRateStore keeps one rate per currency, shared by every tenant, so
+// none of its queries filter on tenant_id.
+type ExchangeRateStore struct {
+	db *driver.DB
+}
+
+func NewExchangeRateStore(db *driver.DB) *ExchangeRateStore {
+	return &ExchangeRateStore{db: db}
+}
+
+func (s ExchangeRateStore) GetExchangeRates(ctx context.Context) ([]models.ExchangeRate, error) {
+	tracer := otel.Tracer("exchangerate-store")
+	ctx, span := tracer.Start(ctx, "GetExchangeRates-Store")
+	defer span.End()
+	rates := []models.ExchangeRate{}
+
+	query := `SELECT currency, rate, updated_by, updated_at FROM exchange_rate ORDER BY currency`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return rates, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rate models.ExchangeRate
+		if err := rows.Scan(&rate.Currency, &rate.Rate, &rate.UpdatedBy, &rate.UpdatedAt); err != nil {
+			return rates, err
+		}
+		rates = append(rates, rate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return rates, err
+	}
+
+	return rates, nil
+}
+
+// SetExchangeRate creates or replaces the rate of a currency, attributed to
+// the caller.
+func (s ExchangeRateStore) SetExchangeRate(ctx context.Context, rate models.ExchangeRate) (models.ExchangeRate, error) {
+	tracer := otel.Tracer("exchangerate-store")
+	ctx, span := tracer.Start(ctx, "SetExchangeRate-Store")
+	defer span.End()
+	var savedRate models.ExchangeRate
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return savedRate, err
+	}
+
+	query := `INSERT INTO exchange_rate (currency, rate, base_factor, updated_by, updated_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (currency) DO UPDATE SET rate=excluded.rate, base_factor=excluded.base_factor, updated_by=excluded.updated_by, updated_at=excluded.updated_at`
+
+	_, err = tx.ExecContext(ctx, query, rate.Currency, rate.Rate, rate.BaseFactor(), auth.Actor(ctx), time.Now().UTC())
+	if err != nil {
+		tx.Rollback()
+		return savedRate, err
+	}
+
+	savedRate, err = scanExchangeRate(tx.QueryRowContext(ctx, selectExchangeRateQuery, rate.Currency))
+	if err != nil {
+		tx.Rollback()
+		return savedRate, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return savedRate, err
+	}
+
+	return savedRate, nil
+}
+
+func (s ExchangeRateStore) DeleteExchangeRate(ctx context.Context, currency string) error {
+	tracer := otel.Tracer("exchangerate-store")
+	ctx, span := tracer.Start(ctx, "DeleteExchangeRate-Store")
+	defer span.End()
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM exchange_rate WHERE currency=$1`, currency)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return store.ErrExchangeRateNotFound
+	}
+
+	return nil
+}
+
+// scanExchangeRate reads a row produced by selectExchangeRateQuery.
+func scanExchangeRate(row *sql.Row) (models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	err := row.Scan(&rate.Currency, &rate.Rate, &rate.UpdatedBy, &rate.UpdatedAt)
+	return rate, err
+}
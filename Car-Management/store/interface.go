@@ -2,13 +2,102 @@ package store
 
 import (
 	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/nitesh111sinha/car-management/models"
 )
 
+var (
+	ErrCarNotFound    = errors.New("car not found")
+	ErrInvalidEngine  = errors.New("engine id is required and must be a valid uuid")
+	ErrTenantNotFound = errors.New("tenant not found")
+	ErrTenantInUse    = errors.New("tenant still owns cars or engines")
+	ErrEngineInUse    = errors.New("engine is still used by cars")
+	// ErrDuplicateCarVIN is returned when a car is given a VIN another car
+	// of the tenant already has.
+	ErrDuplicateCarVIN = errors.New("another car already has this vin")
+
+	ErrUserNotFound = errors.New("user not found")
+	// ErrDuplicateUsername is returned when a user is given a username
+	// another user already has; usernames are unique across tenants.
+	ErrDuplicateUsername = errors.New("another user already has this username")
+	// ErrInvalidCredentials is returned for an unknown username and for a
+	// wrong password alike, so login does not reveal which usernames exist.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrForeignTenant is returned when an admin creates a user in another
+	// tenant; only platform admins may.
+	ErrForeignTenant = errors.New("users can only be created in your own tenant")
+
+	ErrStockUnitNotFound      = errors.New("stock unit not found")
+	ErrDuplicateVIN           = errors.New("a stock unit with this vin already exists")
+	ErrInvalidStockTransition = errors.New("stock unit status does not allow this change")
+
+	ErrBrandNotFound  = errors.New("brand not found")
+	ErrDuplicateBrand = errors.New("another brand already uses this name or alias")
+	ErrBrandInUse     = errors.New("brand still has cars")
+
+	ErrModelNotFound  = errors.New("model not found")
+	ErrDuplicateModel = errors.New("the brand already has a model with this name")
+	ErrModelInUse     = errors.New("model still has cars")
+	ErrTrimNotFound   = errors.New("trim not found")
+	ErrDuplicateTrim  = errors.New("the model already has a trim with this name and year")
+	ErrTrimInUse      = errors.New("trim still has cars")
+	// ErrTrimEngineInUse is returned when a trim update drops an engine that
+	// cars of the trim still use.
+	ErrTrimEngineInUse = errors.New("cars of this trim still use an engine the update removes")
+	ErrInvalidTrim     = errors.New("trim must exist, belong to the car's brand and offer the car's engine")
+	// ErrPowertrainMismatch is returned when a car's fuel type does not fit
+	// its engine's powertrain, e.g. an Electric car with a combustion engine.
+	ErrPowertrainMismatch = errors.New("the car's fuel type does not fit the engine's powertrain")
+
+	ErrExchangeRateNotFound = errors.New("no exchange rate for this currency")
+	ErrBaseCurrencyRate     = errors.New("the base currency's exchange rate is fixed at 1")
+
+	ErrAttachmentNotFound     = errors.New("attachment not found")
+	ErrNoThumbnail            = errors.New("attachment has no thumbnail")
+	ErrInvalidAttachmentOrder = errors.New("ids must list every attachment of the car exactly once")
+	ErrAttachmentTooLarge     = errors.New("attachment is larger than the upload limit")
+	ErrUnsupportedAttachment  = errors.New("attachments must be JPEG, PNG or GIF images or PDF documents")
+
+	ErrLocationNotFound    = errors.New("location not found")
+	ErrReservationNotFound = errors.New("reservation not found")
+	// ErrReservationConflict is returned when a test drive would overlap
+	// another booked test drive of the same car.
+	ErrReservationConflict  = errors.New("the car is already booked for a test drive at this time")
+	ErrReservationCancelled = errors.New("reservation is cancelled")
+
+	ErrOrderNotFound          = errors.New("order not found")
+	ErrOrderNotDraft          = errors.New("only draft orders can be changed")
+	ErrInvalidOrderTransition = errors.New("order status does not allow this change")
+	// ErrNoStockAvailable is returned when an order is confirmed but every
+	// unit of its car is already reserved or sold.
+	ErrNoStockAvailable     = errors.New("no unit of the car is in stock")
+	ErrStockUnitUnavailable = errors.New("the stock unit is not an in-stock unit of the order's car")
+	// ErrCarHasOrders is returned when a car with sales orders is deleted;
+	// orders are kept for the books, whatever their status.
+	ErrCarHasOrders = errors.New("car still has sales orders")
+
+	ErrCustomerNotFound       = errors.New("customer not found")
+	ErrDuplicateCustomerEmail = errors.New("another customer already has this email address")
+	ErrCustomerErased         = errors.New("customer's personal data has been erased")
+	// ErrInvalidCustomer is returned when a reservation, order or enquiry
+	// names a customer that does not exist or has been erased.
+	ErrInvalidCustomer = errors.New("customer id must name an existing customer that has not been erased")
+)
+
 type CarStoreInterface interface {
 	CreateCar(ctx context.Context, car models.Car) (models.Car, error)
 	GetCarById(ctx context.Context, carID string) (models.Car, error)
+	GetCarByVIN(ctx context.Context, vin string) (models.Car, error)
 	GetCars(ctx context.Context) ([]models.Car, error)
+	// GetCarsByIds returns the tenant's cars with their engines, in the
+	// order of ids. Ids of unknown cars are skipped.
+	GetCarsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Car, error)
+	// GetCarsWithEngines returns all of the tenant's cars with their engines
+	// and, where the backend tracks stock, their availability.
+	GetCarsWithEngines(ctx context.Context) ([]models.Car, error)
 	UpdateCar(ctx context.Context, car models.Car) (models.Car, error)
 	DeleteCar(ctx context.Context, carID string) error
 	GetCarByBrand(ctx context.Context, brand string, isEngine bool) ([]models.Car, error)
@@ -19,5 +108,148 @@ type EngineStoreInterface interface {
 	GetEngineById(ctx context.Context, engineID string) (models.Engine, error)
 	GetEngines(ctx context.Context) ([]models.Engine, error)
 	UpdateEngine(ctx context.Context, engineId string, engine models.Engine) (models.Engine, error)
-	DeleteEngine(ctx context.Context, engineID string) error
+	// DeleteEngine returns an *EngineInUseError while cars use the engine,
+	// unless cascade is set, in which case those cars are deleted with it.
+	DeleteEngine(ctx context.Context, engineID string, cascade bool) error
+	GetEngineCars(ctx context.Context, engineID string) ([]models.Car, error)
+}
+
+// EngineInUseError lists the cars that keep an engine from being deleted. It
+// matches ErrEngineInUse with errors.Is.
+type EngineInUseError struct {
+	Cars []models.Car
+}
+
+func (e *EngineInUseError) Error() string {
+	return ErrEngineInUse.Error()
+}
+
+func (e *EngineInUseError) Unwrap() error {
+	return ErrEngineInUse
+}
+
+// TenantStoreInterface manages tenants themselves. Unlike the other stores it
+// is not scoped to the tenant in the request context.
+type TenantStoreInterface interface {
+	CreateTenant(ctx context.Context, tenant models.Tenant) (models.Tenant, error)
+	GetTenantById(ctx context.Context, tenantID string) (models.Tenant, error)
+	GetTenants(ctx context.Context) ([]models.Tenant, error)
+	UpdateTenant(ctx context.Context, tenant models.Tenant) (models.Tenant, error)
+	DeleteTenant(ctx context.Context, tenantID string) error
+}
+
+// UserStoreInterface manages the users who can sign in. GetUserByUsername is
+// not scoped to the tenant in the request context, since login has to find a
+// user before it knows their tenant; GetUsers and DeleteUser are.
+type UserStoreInterface interface {
+	CreateUser(ctx context.Context, user models.User) (models.User, error)
+	GetUserByUsername(ctx context.Context, username string) (models.User, error)
+	GetUsers(ctx context.Context) ([]models.User, error)
+	DeleteUser(ctx context.Context, userID string) error
+}
+
+// BrandStoreInterface manages the brands cars are filed under.
+type BrandStoreInterface interface {
+	CreateBrand(ctx context.Context, brand models.Brand) (models.Brand, error)
+	GetBrandById(ctx context.Context, brandID string) (models.Brand, error)
+	GetBrands(ctx context.Context) ([]models.Brand, error)
+	UpdateBrand(ctx context.Context, brand models.Brand) (models.Brand, error)
+	DeleteBrand(ctx context.Context, brandID string) error
+}
+
+// CatalogStoreInterface manages the model and trim hierarchy beneath brands.
+type CatalogStoreInterface interface {
+	GetModelsByBrand(ctx context.Context, brandID string) ([]models.CarModel, error)
+	GetModelById(ctx context.Context, modelID string) (models.CarModel, error)
+	CreateModel(ctx context.Context, model models.CarModel) (models.CarModel, error)
+	UpdateModel(ctx context.Context, model models.CarModel) (models.CarModel, error)
+	DeleteModel(ctx context.Context, modelID string) error
+	GetTrimsByModel(ctx context.Context, modelID string) ([]models.Trim, error)
+	GetTrimById(ctx context.Context, trimID string) (models.Trim, error)
+	CreateTrim(ctx context.Context, trim models.Trim) (models.Trim, error)
+	UpdateTrim(ctx context.Context, trim models.Trim) (models.Trim, error)
+	DeleteTrim(ctx context.Context, trimID string) error
+}
+
+// StockStoreInterface tracks the physical units of each car listing.
+type StockStoreInterface interface {
+	ReceiveUnit(ctx context.Context, unit models.StockUnit) (models.StockUnit, error)
+	GetUnitById(ctx context.Context, unitID string) (models.StockUnit, error)
+	GetUnitsByCar(ctx context.Context, carID string) ([]models.StockUnit, error)
+	MoveUnit(ctx context.Context, unitID, location, lot string) (models.StockUnit, error)
+	SellUnit(ctx context.Context, unitID string) (models.StockUnit, error)
+	GetAvailability(ctx context.Context, carID string) (models.Availability, error)
+}
+
+// PriceStoreInterface reads the price history the car store records.
+type PriceStoreInterface interface {
+	GetPriceHistory(ctx context.Context, carID string) ([]models.PriceChange, error)
+	GetPriceMovements(ctx context.Context, since time.Time) ([]models.BrandPriceMovement, error)
+}
+
+// StatsStoreInterface computes aggregate statistics in the database.
+type StatsStoreInterface interface {
+	GetCarStats(ctx context.Context, filter models.CarStatsFilter) (models.CarStats, error)
+}
+
+// ExchangeRateStoreInterface manages the exchange rates prices are converted
+// with. Unlike the other stores it is not scoped to a tenant: every tenant
+// shares the same rates.
+type ExchangeRateStoreInterface interface {
+	GetExchangeRates(ctx context.Context) ([]models.ExchangeRate, error)
+	SetExchangeRate(ctx context.Context, rate models.ExchangeRate) (models.ExchangeRate, error)
+	DeleteExchangeRate(ctx context.Context, currency string) error
+}
+
+// AttachmentStoreInterface keeps the records of files attached to cars. The
+// files themselves are kept in a blob.BlobStore. Files of attachments that
+// were deleted with their car wait in a queue until they are removed.
+type AttachmentStoreInterface interface {
+	CreateAttachment(ctx context.Context, attachment models.Attachment) (models.Attachment, error)
+	GetAttachmentById(ctx context.Context, attachmentID string) (models.Attachment, error)
+	GetAttachmentsByCar(ctx context.Context, carID string) ([]models.Attachment, error)
+	ReorderAttachments(ctx context.Context, carID string, ids []uuid.UUID) ([]models.Attachment, error)
+	DeleteAttachment(ctx context.Context, attachmentID string) (models.Attachment, error)
+	GetQueuedBlobs(ctx context.Context) ([]string, error)
+	ForgetQueuedBlob(ctx context.Context, key string) error
+}
+
+// ReservationStoreInterface books test drives at locations with opening
+// hours. Booked test drives of a car never overlap.
+type ReservationStoreInterface interface {
+	GetLocations(ctx context.Context) ([]models.LocationHours, error)
+	GetLocationHours(ctx context.Context, location string) (models.LocationHours, error)
+	SetLocationHours(ctx context.Context, hours models.LocationHours) (models.LocationHours, error)
+	CreateReservation(ctx context.Context, reservation models.Reservation) (models.Reservation, error)
+	GetReservationById(ctx context.Context, reservationID string) (models.Reservation, error)
+	GetReservationsByCar(ctx context.Context, carID string) ([]models.Reservation, error)
+	GetBookedReservations(ctx context.Context, carID string, from, to time.Time) ([]models.Reservation, error)
+	RescheduleReservation(ctx context.Context, reservationID, location string, startsAt, endsAt time.Time) (models.Reservation, error)
+	CancelReservation(ctx context.Context, reservationID string) (models.Reservation, error)
+}
+
+// OrderStoreInterface manages sales orders and their status changes.
+// Confirming an order reserves a stock unit of its car, delivering it sells
+// the unit and cancelling it releases the unit, in the same transaction.
+type OrderStoreInterface interface {
+	CreateOrder(ctx context.Context, order models.Order) (models.Order, error)
+	GetOrderById(ctx context.Context, orderID string) (models.Order, error)
+	GetOrders(ctx context.Context, status string) ([]models.Order, error)
+	GetOrdersByCar(ctx context.Context, carID string) ([]models.Order, error)
+	UpdateOrder(ctx context.Context, order models.Order) (models.Order, error)
+	TransitionOrder(ctx context.Context, orderID, status string, request models.TransitionRequest) (models.Order, error)
+	GetOrderTransitions(ctx context.Context, orderID string) ([]models.OrderTransition, error)
+}
+
+// CustomerStoreInterface manages customers and their enquiries. Erasing a
+// customer blanks their personal data everywhere it is held.
+type CustomerStoreInterface interface {
+	CreateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error)
+	GetCustomerById(ctx context.Context, customerID string) (models.Customer, error)
+	SearchCustomers(ctx context.Context, query string) ([]models.Customer, error)
+	UpdateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error)
+	CreateEnquiry(ctx context.Context, enquiry models.Enquiry) (models.Enquiry, error)
+	GetEnquiriesByCustomer(ctx context.Context, customerID string) ([]models.Enquiry, error)
+	GetCustomerRecord(ctx context.Context, customerID string) (models.CustomerRecord, error)
+	EraseCustomer(ctx context.Context, customerID string) (models.Customer, error)
 }
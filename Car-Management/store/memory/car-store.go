@@ -0,0 +1,255 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+)
+
+type CarStore struct {
+	db *DB
+}
+
+func NewCarStore(db *DB) *CarStore {
+	return &CarStore{db: db}
+}
+
+func (s *CarStore) GetCarById(ctx context.Context, id string) (models.Car, error) {
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return models.Car{}, err
+	}
+
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	carID, ok := parseID(id)
+	if !ok {
+		return models.Car{}, nil
+	}
+	car, ok := s.db.car(carID, tenantID)
+	if !ok {
+		return models.Car{}, nil
+	}
+	if engine, ok := s.db.engines[car.Engine.EngineID]; ok {
+		car.Engine = engine
+	}
+	return car, nil
+}
+
+func (s *CarStore) GetCarByVIN(ctx context.Context, vin string) (models.Car, error) {
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return models.Car{}, err
+	}
+
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	car, ok := s.db.carByVIN(vin, tenantID)
+	if !ok {
+		return models.Car{}, store.ErrCarNotFound
+	}
+	if engine, ok := s.db.engines[car.Engine.EngineID]; ok {
+		car.Engine = engine
+	}
+	return car, nil
+}
+
+// GetCarByBrand matches brands ignoring case. The in-memory store has no
+// brand table, so aliases are not resolved.
+func (s *CarStore) GetCarByBrand(ctx context.Context, brand string, isEngine bool) ([]models.Car, error) {
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	var cars []models.Car
+	for _, car := range s.db.sortedCars(tenantID) {
+		if !strings.EqualFold(car.Brand, strings.TrimSpace(brand)) {
+			continue
+		}
+		if isEngine {
+			car.Engine = s.db.engines[car.Engine.EngineID]
+		}
+		cars = append(cars, car)
+	}
+	return cars, nil
+}
+
+func (s *CarStore) GetCarsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Car, error) {
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	cars := []models.Car{}
+	for _, id := range ids {
+		car, ok := s.db.car(id, tenantID)
+		if !ok {
+			continue
+		}
+		if engine, ok := s.db.engines[car.Engine.EngineID]; ok {
+			car.Engine = engine
+		}
+		cars = append(cars, car)
+	}
+	return cars, nil
+}
+
+func (s *CarStore) GetCarsWithEngines(ctx context.Context) ([]models.Car, error) {
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	cars := s.db.sortedCars(tenantID)
+	for i, car := range cars {
+		if engine, ok := s.db.engines[car.Engine.EngineID]; ok {
+			cars[i].Engine = engine
+		}
+	}
+	return cars, nil
+}
+
+func (s *CarStore) CreateCar(ctx context.Context, car models.Car) (models.Car, error) {
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return models.Car{}, err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	engine, ok := s.db.engine(car.Engine.EngineID, tenantID)
+	if !ok {
+		return models.Car{}, store.ErrInvalidEngine
+	}
+	if !models.FuelTypeFits(car.FuelType, engine.Type) {
+		return models.Car{}, store.ErrPowertrainMismatch
+	}
+	// There is no in-memory catalogue, so no trim can exist.
+	if car.TrimID.Valid {
+		return models.Car{}, store.ErrInvalidTrim
+	}
+	// Nor are there brand aliases, so the brand is already canonical.
+	if err := models.ValidateVINBrand(car.VIN, car.Brand); err != nil {
+		return models.Car{}, err
+	}
+
+	if other, ok := s.db.carByVIN(car.VIN, tenantID); ok && other.ID != car.ID {
+		return models.Car{}, store.ErrDuplicateCarVIN
+	}
+
+	createdAt := time.Now()
+	newCar := models.Car{
+		ID:        uuid.New(),
+		Name:      car.Name,
+		Year:      car.Year,
+		Brand:     car.Brand,
+		FuelType:  car.FuelType,
+		Engine:    models.Engine{EngineID: car.Engine.EngineID},
+		Price:     car.Price,
+		VIN:       car.VIN,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	s.db.cars[newCar.ID] = newCar
+	s.db.owners[newCar.ID] = tenantID
+
+	return newCar, nil
+}
+
+func (s *CarStore) UpdateCar(ctx context.Context, car models.Car) (models.Car, error) {
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return models.Car{}, err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	engine, ok := s.db.engine(car.Engine.EngineID, tenantID)
+	if !ok {
+		return models.Car{}, store.ErrInvalidEngine
+	}
+	if !models.FuelTypeFits(car.FuelType, engine.Type) {
+		return models.Car{}, store.ErrPowertrainMismatch
+	}
+	// There is no in-memory catalogue, so no trim can exist.
+	if car.TrimID.Valid {
+		return models.Car{}, store.ErrInvalidTrim
+	}
+	// Nor are there brand aliases, so the brand is already canonical.
+	if err := models.ValidateVINBrand(car.VIN, car.Brand); err != nil {
+		return models.Car{}, err
+	}
+
+	existing, ok := s.db.car(car.ID, tenantID)
+	if !ok {
+		return models.Car{}, store.ErrCarNotFound
+	}
+
+	if other, ok := s.db.carByVIN(car.VIN, tenantID); ok && other.ID != car.ID {
+		return models.Car{}, store.ErrDuplicateCarVIN
+	}
+
+	existing.Name = car.Name
+	existing.Year = car.Year
+	existing.Brand = car.Brand
+	existing.FuelType = car.FuelType
+	existing.Engine = models.Engine{EngineID: car.Engine.EngineID}
+	existing.Price = car.Price
+	existing.VIN = car.VIN
+	existing.UpdatedAt = time.Now()
+	s.db.cars[existing.ID] = existing
+
+	return existing, nil
+}
+
+func (s *CarStore) DeleteCar(ctx context.Context, id string) error {
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	carID, ok := parseID(id)
+	if !ok {
+		return store.ErrCarNotFound
+	}
+	if _, ok := s.db.car(carID, tenantID); !ok {
+		return store.ErrCarNotFound
+	}
+	delete(s.db.cars, carID)
+	delete(s.db.owners, carID)
+	return nil
+}
+
+func (s *CarStore) GetCars(ctx context.Context) ([]models.Car, error) {
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	return s.db.sortedCars(tenantID), nil
+}
@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+)
+
+type EngineStore struct {
+	db *DB
+}
+
+func NewEngineStore(db *DB) *EngineStore {
+	return &EngineStore{db: db}
+}
+
+func (s *EngineStore) CreateEngine(ctx context.Context, engine models.Engine) (models.Engine, error) {
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return models.Engine{}, err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	newEngine := models.Engine{
+		EngineID:      uuid.New(),
+		Type:          models.PowertrainType(engine),
+		Displacement:  engine.Displacement,
+		NoOfCylinders: engine.NoOfCylinders,
+		CarRange:      engine.CarRange,
+		Motor:         motor(engine),
+	}
+	s.db.engines[newEngine.EngineID] = newEngine
+	s.db.owners[newEngine.EngineID] = tenantID
+
+	return newEngine, nil
+}
+
+func (s *EngineStore) UpdateEngine(ctx context.Context, engineId string, engine models.Engine) (models.Engine, error) {
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return models.Engine{}, err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	id, ok := parseID(engineId)
+	if !ok {
+		return models.Engine{}, sql.ErrNoRows
+	}
+	if _, ok := s.db.engine(id, tenantID); !ok {
+		return models.Engine{}, sql.ErrNoRows
+	}
+
+	updatedEngine := models.Engine{
+		EngineID:      id,
+		Type:          models.PowertrainType(engine),
+		Displacement:  engine.Displacement,
+		NoOfCylinders: engine.NoOfCylinders,
+		CarRange:      engine.CarRange,
+		Motor:         motor(engine),
+	}
+	for _, car := range s.db.engineCars(id, tenantID) {
+		if !models.FuelTypeFits(car.FuelType, updatedEngine.Type) {
+			return models.Engine{}, store.ErrPowertrainMismatch
+		}
+	}
+	s.db.engines[id] = updatedEngine
+
+	return updatedEngine, nil
+}
+
+func (s *EngineStore) GetEngineById(ctx context.Context, engineId string) (models.Engine, error) {
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return models.Engine{}, err
+	}
+
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	id, ok := parseID(engineId)
+	if !ok {
+		return models.Engine{}, sql.ErrNoRows
+	}
+	engine, ok := s.db.engine(id, tenantID)
+	if !ok {
+		return models.Engine{}, sql.ErrNoRows
+	}
+	return engine, nil
+}
+
+// DeleteEngine removes the engine and, like the ON DELETE CASCADE foreign key
+// in the SQL schema, every car that references it.
+func (s *EngineStore) DeleteEngine(ctx context.Context, engineId string, cascade bool) error {
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	id, ok := parseID(engineId)
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if _, ok := s.db.engine(id, tenantID); !ok {
+		return sql.ErrNoRows
+	}
+
+	cars := s.db.engineCars(id, tenantID)
+	if len(cars) > 0 && !cascade {
+		return &store.EngineInUseError{Cars: cars}
+	}
+
+	delete(s.db.engines, id)
+	delete(s.db.owners, id)
+	for _, car := range cars {
+		delete(s.db.cars, car.ID)
+		delete(s.db.owners, car.ID)
+	}
+	return nil
+}
+
+func (s *EngineStore) GetEngineCars(ctx context.Context, engineId string) ([]models.Car, error) {
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	id, ok := parseID(engineId)
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	if _, ok := s.db.engine(id, tenantID); !ok {
+		return nil, sql.ErrNoRows
+	}
+	return s.db.engineCars(id, tenantID), nil
+}
+
+func (s *EngineStore) GetEngines(ctx context.Context) ([]models.Engine, error) {
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	var engines []models.Engine
+	for id, engine := range s.db.engines {
+		if s.db.owners[id] == tenantID {
+			engines = append(engines, engine)
+		}
+	}
+	sortEngines(engines)
+	return engines, nil
+}
+
+// motor copies the engine's electric motor, which combustion engines do not
+// have, so that callers cannot change stored engines through the pointer.
+func motor(engine models.Engine) *models.ElectricMotor {
+	if engine.Motor == nil || models.PowertrainType(engine) == models.PowertrainICE {
+		return nil
+	}
+	motor := *engine.Motor
+	return &motor
+}
@@ -0,0 +1,136 @@
+// Package memory provides in-process implementations of the store interfaces.
+// They mirror the behaviour of the SQL stores (tenant scoping, engine
+// existence checks, cascading engine deletes, not-found errors) and are meant
+// for local development and tests where running Postgres is not practical.
+package memory
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/models"
+)
+
+// DB is the shared backing state for the in-memory stores. Stores created
+// from the same DB behave like tables in one database.
+type DB struct {
+	mu      sync.RWMutex
+	tenants map[uuid.UUID]models.Tenant
+	users   map[uuid.UUID]models.User
+	cars    map[uuid.UUID]models.Car
+	engines map[uuid.UUID]models.Engine
+	// owners maps every car and engine id to its tenant.
+	owners map[uuid.UUID]uuid.UUID
+}
+
+// NewDB returns an empty database holding only the default tenant, like a
+// freshly migrated SQL database.
+func NewDB() *DB {
+	now := time.Now()
+	return &DB{
+		tenants: map[uuid.UUID]models.Tenant{
+			models.DefaultTenantID: {
+				ID:        models.DefaultTenantID,
+				Name:      "Default dealership",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		},
+		users:   make(map[uuid.UUID]models.User),
+		cars:    make(map[uuid.UUID]models.Car),
+		engines: make(map[uuid.UUID]models.Engine),
+		owners:  make(map[uuid.UUID]uuid.UUID),
+	}
+}
+
+// sortedCars returns the tenant's cars ordered by creation time so listings
+// are stable between calls. Callers must hold db.mu.
+func (db *DB) sortedCars(tenantID uuid.UUID) []models.Car {
+	var cars []models.Car
+	for id, car := range db.cars {
+		if db.owners[id] == tenantID {
+			cars = append(cars, car)
+		}
+	}
+	sort.Slice(cars, func(i, j int) bool {
+		if cars[i].CreatedAt.Equal(cars[j].CreatedAt) {
+			return cars[i].ID.String() < cars[j].ID.String()
+		}
+		return cars[i].CreatedAt.Before(cars[j].CreatedAt)
+	})
+	return cars
+}
+
+// engineCars returns the tenant's cars that use the engine, oldest first.
+// Callers must hold db.mu.
+func (db *DB) engineCars(engineID, tenantID uuid.UUID) []models.Car {
+	cars := []models.Car{}
+	for _, car := range db.sortedCars(tenantID) {
+		if car.Engine.EngineID == engineID {
+			cars = append(cars, car)
+		}
+	}
+	return cars
+}
+
+// car returns the car if it exists and belongs to the tenant. Callers must
+// hold db.mu.
+func (db *DB) car(id, tenantID uuid.UUID) (models.Car, bool) {
+	car, ok := db.cars[id]
+	if !ok || db.owners[id] != tenantID {
+		return models.Car{}, false
+	}
+	return car, true
+}
+
+// carByVIN returns the tenant's car with the VIN, if there is one. No car
+// matches an empty VIN. Callers must hold db.mu.
+func (db *DB) carByVIN(vin string, tenantID uuid.UUID) (models.Car, bool) {
+	if vin == "" {
+		return models.Car{}, false
+	}
+	for id, car := range db.cars {
+		if car.VIN == vin && db.owners[id] == tenantID {
+			return car, true
+		}
+	}
+	return models.Car{}, false
+}
+
+// engine returns the engine if it exists and belongs to the tenant. Callers
+// must hold db.mu.
+func (db *DB) engine(id, tenantID uuid.UUID) (models.Engine, bool) {
+	engine, ok := db.engines[id]
+	if !ok || db.owners[id] != tenantID {
+		return models.Engine{}, false
+	}
+	return engine, true
+}
+
+// userByUsername returns the user with the username, ignoring case. Callers
+// must hold db.mu.
+func (db *DB) userByUsername(username string) (models.User, bool) {
+	for _, user := range db.users {
+		if strings.EqualFold(user.Username, username) {
+			return user, true
+		}
+	}
+	return models.User{}, false
+}
+
+func parseID(id string) (uuid.UUID, bool) {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return parsed, true
+}
+
+func sortEngines(engines []models.Engine) {
+	sort.Slice(engines, func(i, j int) bool {
+		return engines[i].EngineID.String() < engines[j].EngineID.String()
+	})
+}
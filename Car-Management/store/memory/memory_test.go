@@ -0,0 +1,19 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/nitesh111sinha/car-management/store/memory"
+	"github.com/nitesh111sinha/car-management/store/storetest"
+)
+
+func TestConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Stores {
+		db := memory.NewDB()
+		return storetest.Stores{
+			Cars:    memory.NewCarStore(db),
+			Engines: memory.NewEngineStore(db),
+			Tenants: memory.NewTenantStore(db),
+		}
+	})
+}
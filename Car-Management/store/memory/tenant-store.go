@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+)
+
+type TenantStore struct {
+	db *DB
+}
+
+func NewTenantStore(db *DB) *TenantStore {
+	return &TenantStore{db: db}
+}
+
+func (s *TenantStore) CreateTenant(ctx context.Context, tenant models.Tenant) (models.Tenant, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	createdAt := time.Now()
+	newTenant := models.Tenant{
+		ID:        uuid.New(),
+		Name:      tenant.Name,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	s.db.tenants[newTenant.ID] = newTenant
+
+	return newTenant, nil
+}
+
+func (s *TenantStore) GetTenantById(ctx context.Context, tenantID string) (models.Tenant, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	id, ok := parseID(tenantID)
+	if !ok {
+		return models.Tenant{}, store.ErrTenantNotFound
+	}
+	tenant, ok := s.db.tenants[id]
+	if !ok {
+		return models.Tenant{}, store.ErrTenantNotFound
+	}
+	return tenant, nil
+}
+
+func (s *TenantStore) GetTenants(ctx context.Context) ([]models.Tenant, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	tenants := make([]models.Tenant, 0, len(s.db.tenants))
+	for _, tenant := range s.db.tenants {
+		tenants = append(tenants, tenant)
+	}
+	sort.Slice(tenants, func(i, j int) bool {
+		if tenants[i].Name == tenants[j].Name {
+			return tenants[i].ID.String() < tenants[j].ID.String()
+		}
+		return tenants[i].Name < tenants[j].Name
+	})
+	return tenants, nil
+}
+
+func (s *TenantStore) UpdateTenant(ctx context.Context, tenant models.Tenant) (models.Tenant, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	existing, ok := s.db.tenants[tenant.ID]
+	if !ok {
+		return models.Tenant{}, store.ErrTenantNotFound
+	}
+	existing.Name = tenant.Name
+	existing.UpdatedAt = time.Now()
+	s.db.tenants[existing.ID] = existing
+
+	return existing, nil
+}
+
+// DeleteTenant refuses to delete a tenant that still owns data, like the
+// foreign keys in the SQL schema. The tenant's users go with it.
+func (s *TenantStore) DeleteTenant(ctx context.Context, tenantID string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	id, ok := parseID(tenantID)
+	if !ok {
+		return store.ErrTenantNotFound
+	}
+	if _, ok := s.db.tenants[id]; !ok {
+		return store.ErrTenantNotFound
+	}
+	for _, owner := range s.db.owners {
+		if owner == id {
+			return store.ErrTenantInUse
+		}
+	}
+	delete(s.db.tenants, id)
+	for userID, user := range s.db.users {
+		if user.TenantID == id {
+			delete(s.db.users, userID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+)
+
+type UserStore struct {
+	db *DB
+}
+
+func NewUserStore(db *DB) *UserStore {
+	return &UserStore{db: db}
+}
+
+func (s *UserStore) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if _, ok := s.db.tenants[user.TenantID]; !ok {
+		return models.User{}, store.ErrTenantNotFound
+	}
+	username := strings.TrimSpace(user.Username)
+	if _, ok := s.db.userByUsername(username); ok {
+		return models.User{}, store.ErrDuplicateUsername
+	}
+
+	createdAt := time.Now().UTC()
+	newUser := models.User{
+		ID:           uuid.New(),
+		Username:     username,
+		TenantID:     user.TenantID,
+		Role:         user.Role,
+		PasswordHash: user.PasswordHash,
+		CreatedAt:    createdAt,
+		UpdatedAt:    createdAt,
+	}
+	s.db.users[newUser.ID] = newUser
+
+	return newUser, nil
+}
+
+func (s *UserStore) GetUserByUsername(ctx context.Context, username string) (models.User, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	user, ok := s.db.userByUsername(strings.TrimSpace(username))
+	if !ok {
+		return models.User{}, store.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (s *UserStore) GetUsers(ctx context.Context) ([]models.User, error) {
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	users := []models.User{}
+	for _, user := range s.db.users {
+		if user.TenantID == tenantID {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		a, b := strings.ToLower(users[i].Username), strings.ToLower(users[j].Username)
+		if a == b {
+			return users[i].ID.String() < users[j].ID.String()
+		}
+		return a < b
+	})
+	return users, nil
+}
+
+func (s *UserStore) DeleteUser(ctx context.Context, userID string) error {
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	id, ok := parseID(userID)
+	if !ok {
+		return store.ErrUserNotFound
+	}
+	if user, ok := s.db.users[id]; !ok || user.TenantID != tenantID {
+		return store.ErrUserNotFound
+	}
+	delete(s.db.users, id)
+	return nil
+}
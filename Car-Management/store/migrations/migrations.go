@@ -0,0 +1,150 @@
+// Package migrations applies the versioned SQL schema for each supported
+// dialect. Migration files live in a directory per dialect and are applied in
+// lexical order; applied versions are recorded in schema_migrations.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nitesh111sinha/car-management/driver"
+)
+
+//go:embed postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// lockID is the Postgres advisory lock key that serialises migrations when
+// several instances start at once.
+const lockID = 72707369
+
+const createMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version VARCHAR(255) PRIMARY KEY,
+    applied_at TIMESTAMP NOT NULL
+)`
+
+type Migration struct {
+	Version   string     `json:"version"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+func (m Migration) Applied() bool {
+	return m.AppliedAt != nil
+}
+
+// Migrate applies every pending migration for the database's dialect. Each
+// migration runs in its own transaction together with its bookkeeping row.
+func Migrate(ctx context.Context, db *driver.DB) error {
+	if _, err := db.ExecContext(ctx, createMigrationsTable); err != nil {
+		return fmt.Errorf("creating schema_migrations: %w", err)
+	}
+
+	versions, err := available(db.Dialect())
+	if err != nil {
+		return err
+	}
+
+	for _, version := range versions {
+		if err := apply(ctx, db, version); err != nil {
+			return fmt.Errorf("applying migration %s: %w", version, err)
+		}
+	}
+	return nil
+}
+
+// Status reports every known migration and when it was applied. Migrations
+// that have not run yet have a nil AppliedAt.
+func Status(ctx context.Context, db *driver.DB) ([]Migration, error) {
+	versions, err := available(db.Dialect())
+	if err != nil {
+		return nil, err
+	}
+
+	// Replicas may lag behind a migration that just ran on the primary.
+	ctx = driver.WithPrimary(ctx)
+	applied := make(map[string]time.Time)
+	rows, err := db.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version string
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	migrations := make([]Migration, 0, len(versions))
+	for _, version := range versions {
+		migration := Migration{Version: version}
+		if appliedAt, ok := applied[version]; ok {
+			migration.AppliedAt = &appliedAt
+		}
+		migrations = append(migrations, migration)
+	}
+	return migrations, nil
+}
+
+func apply(ctx context.Context, db *driver.DB, version string) error {
+	script, err := fs.ReadFile(files, path.Join(string(db.Dialect()), version+".sql"))
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if db.Dialect() == driver.Postgres {
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, lockID); err != nil {
+			return err
+		}
+	}
+
+	var exists string
+	err = tx.QueryRowContext(ctx, `SELECT version FROM schema_migrations WHERE version=$1`, version).Scan(&exists)
+	if err == nil {
+		return nil
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, string(script)); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, applied_at) VALUES ($1, $2)`, version, time.Now().UTC()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func available(dialect driver.Dialect) ([]string, error) {
+	entries, err := fs.ReadDir(files, string(dialect))
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for dialect %s: %w", dialect, err)
+	}
+
+	var versions []string
+	for _, entry := range entries {
+		if name := entry.Name(); strings.HasSuffix(name, ".sql") {
+			versions = append(versions, strings.TrimSuffix(name, ".sql"))
+		}
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
@@ -0,0 +1,136 @@
+package migrations
+
+import (
+	"context"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/nitesh111sinha/car-management/config"
+	"github.com/nitesh111sinha/car-management/driver"
+)
+
+func TestMigrateSQLite(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Default().Database
+	cfg.Driver = "sqlite"
+	cfg.SQLitePath = filepath.Join(t.TempDir(), "car-management.db")
+	db, err := driver.InitDB(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Applied migrations are skipped the second time.
+	for range 2 {
+		if err := Migrate(ctx, db); err != nil {
+			t.Fatal(err)
+		}
+	}
+	status, err := Status(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status) == 0 {
+		t.Fatal("Status lists no migrations")
+	}
+	for _, migration := range status {
+		if !migration.Applied() {
+			t.Errorf("migration %s is not applied", migration.Version)
+		}
+	}
+	if !slices.IsSortedFunc(status, func(a, b Migration) int { return strings.Compare(a.Version, b.Version) }) {
+		t.Errorf("Status is not in version order: %+v", status)
+	}
+}
+
+// TestDialectsAgree checks that every migration exists for both dialects
+// under the same version.
+func TestDialectsAgree(t *testing.T) {
+	postgres, err := available(driver.Postgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlite, err := available(driver.SQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(postgres, sqlite) {
+		t.Errorf("postgres migrations %q differ from sqlite migrations %q", postgres, sqlite)
+	}
+}
+
+// TestBrandsMergeSpellings checks that 0005 makes one brand of spellings that
+// differ in case, spaces or punctuation, keeping the others as aliases.
+func TestBrandsMergeSpellings(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Default().Database
+	cfg.Driver = "sqlite"
+	cfg.SQLitePath = filepath.Join(t.TempDir(), "car-management.db")
+	db, err := driver.InitDB(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.ExecContext(ctx, createMigrationsTable); err != nil {
+		t.Fatal(err)
+	}
+	versions, err := available(db.Dialect())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, version := range versions {
+		if version >= "0005" {
+			break
+		}
+		if err := apply(ctx, db, version); err != nil {
+			t.Fatalf("applying %s: %v", version, err)
+		}
+	}
+	// The seed data already has a BMW.
+	for i, brand := range []string{"BMW", " B.M.W.", "Mercedes-Benz", "Mercedes-Benz", "Mercedes Benz", "mercedes-benz"} {
+		if _, err := db.ExecContext(ctx, `INSERT INTO car (id, name, year, brand, fuel_type, engine_id, price)
+			VALUES ($1, 'Car', '2023', $2, 'Petrol', 'e1f86b1a-0873-4c19-bae2-fc60329d0140', 30000)`,
+			"00000000-0000-0000-0000-00000000010"+strconv.Itoa(i), brand); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Migrate(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+
+	brands := map[string]string{}
+	rows, err := db.QueryContext(ctx, `SELECT b.name, COALESCE(group_concat(a.alias, '|'), '') FROM brand b
+		LEFT JOIN (SELECT * FROM brand_alias ORDER BY alias) a ON a.brand_id = b.id GROUP BY b.id, b.name`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, aliases string
+		if err := rows.Scan(&name, &aliases); err != nil {
+			t.Fatal(err)
+		}
+		brands[name] = aliases
+	}
+	want := map[string]string{"BMW": "B.M.W.", "Mercedes-Benz": "Mercedes Benz", "Honda": "", "Toyota": "", "Ford": ""}
+	if !maps.Equal(brands, want) {
+		t.Errorf("brands and aliases = %q, want %q", brands, want)
+	}
+
+	var unmatched int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM car c JOIN brand b ON b.id = c.brand_id WHERE c.brand <> b.name`).Scan(&unmatched); err != nil {
+		t.Fatal(err)
+	}
+	var cars int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM car c JOIN brand b ON b.id = c.brand_id WHERE b.name IN ('BMW', 'Mercedes-Benz')`).Scan(&cars); err != nil {
+		t.Fatal(err)
+	}
+	if unmatched != 0 || cars != 7 {
+		t.Errorf("%d cars differ from their brand's name and %d belong to BMW or Mercedes-Benz, want 0 and 7", unmatched, cars)
+	}
+}
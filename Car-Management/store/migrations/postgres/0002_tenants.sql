@@ -0,0 +1,40 @@
+-- Create tenant table; a tenant is a dealership
+CREATE TABLE tenant (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Existing data belongs to the default tenant
+INSERT INTO tenant (id, name)
+VALUES ('00000000-0000-0000-0000-000000000001', 'Default dealership');
+
+ALTER TABLE engine ADD COLUMN tenant_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenant(id);
+ALTER TABLE engine ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE engine ADD CONSTRAINT uq_engine_tenant UNIQUE (id, tenant_id);
+CREATE INDEX idx_engine_tenant ON engine (tenant_id);
+
+ALTER TABLE car ADD COLUMN tenant_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenant(id);
+ALTER TABLE car ALTER COLUMN tenant_id DROP DEFAULT;
+CREATE INDEX idx_car_tenant_brand ON car (tenant_id, brand);
+
+-- A car may only use an engine of its own tenant
+ALTER TABLE car ADD CONSTRAINT fk_engine_tenant FOREIGN KEY (engine_id, tenant_id) REFERENCES engine(id, tenant_id) ON DELETE CASCADE;
+
+-- Row-level security is a backstop behind the tenant filter in every store
+-- query. The application sets app.tenant_id on the connection before every
+-- statement, reads and writes alike, to the tenant the statement is for.
+-- Sessions without a tenant see no rows at all. FORCE applies the policies to
+-- the table owner as well; superusers always bypass row-level security.
+-- Migrations that rewrite existing rows lift FORCE on the tables they touch
+-- for the duration of their transaction.
+ALTER TABLE engine ENABLE ROW LEVEL SECURITY;
+ALTER TABLE engine FORCE ROW LEVEL SECURITY;
+CREATE POLICY engine_tenant_isolation ON engine
+    USING (tenant_id::text = current_setting('app.tenant_id', true));
+
+ALTER TABLE car ENABLE ROW LEVEL SECURITY;
+ALTER TABLE car FORCE ROW LEVEL SECURITY;
+CREATE POLICY car_tenant_isolation ON car
+    USING (tenant_id::text = current_setting('app.tenant_id', true));
@@ -0,0 +1,24 @@
+-- A car may be referenced together with its tenant
+ALTER TABLE car ADD CONSTRAINT uq_car_tenant UNIQUE (id, tenant_id);
+
+-- Create stock_unit table; every row is one physical vehicle of a car
+CREATE TABLE stock_unit (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenant(id),
+    car_id UUID NOT NULL,
+    vin VARCHAR(17) NOT NULL,
+    colour VARCHAR(50) NOT NULL,
+    location VARCHAR(255) NOT NULL,
+    lot VARCHAR(50) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL CHECK (status IN ('in_stock', 'reserved', 'sold')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_stock_car FOREIGN KEY (car_id, tenant_id) REFERENCES car(id, tenant_id) ON DELETE CASCADE,
+    CONSTRAINT uq_stock_vin UNIQUE (tenant_id, vin)
+);
+CREATE INDEX idx_stock_car_status ON stock_unit (car_id, status);
+
+ALTER TABLE stock_unit ENABLE ROW LEVEL SECURITY;
+ALTER TABLE stock_unit FORCE ROW LEVEL SECURITY;
+CREATE POLICY stock_unit_tenant_isolation ON stock_unit
+    USING (tenant_id::text = current_setting('app.tenant_id', true));
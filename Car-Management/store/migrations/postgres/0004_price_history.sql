@@ -0,0 +1,18 @@
+-- Create price_change table; one row per change of a car's price
+CREATE TABLE price_change (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenant(id),
+    car_id UUID NOT NULL,
+    old_price DECIMAL(10, 2),
+    new_price DECIMAL(10, 2) NOT NULL,
+    changed_by VARCHAR(255) NOT NULL,
+    changed_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_price_change_car FOREIGN KEY (car_id, tenant_id) REFERENCES car(id, tenant_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_price_change_car ON price_change (car_id, changed_at);
+CREATE INDEX idx_price_change_tenant_time ON price_change (tenant_id, changed_at);
+
+ALTER TABLE price_change ENABLE ROW LEVEL SECURITY;
+ALTER TABLE price_change FORCE ROW LEVEL SECURITY;
+CREATE POLICY price_change_tenant_isolation ON price_change
+    USING (tenant_id::text = current_setting('app.tenant_id', true));
@@ -0,0 +1,74 @@
+-- Create brand table; a brand is a manufacturer with one canonical name
+CREATE TABLE brand (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenant(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    country VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_brand_tenant UNIQUE (id, tenant_id)
+);
+-- Names are compared case-insensitively
+CREATE UNIQUE INDEX uq_brand_name ON brand (tenant_id, lower(name));
+
+-- Create brand_alias table; other spellings that resolve to the brand
+CREATE TABLE brand_alias (
+    tenant_id UUID NOT NULL,
+    brand_id UUID NOT NULL,
+    alias VARCHAR(255) NOT NULL,
+    CONSTRAINT fk_brand_alias_brand FOREIGN KEY (brand_id, tenant_id) REFERENCES brand(id, tenant_id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX uq_brand_alias ON brand_alias (tenant_id, lower(alias));
+CREATE INDEX idx_brand_alias_brand ON brand_alias (brand_id);
+
+-- Existing spellings that differ only in case, spaces or punctuation, such as
+-- "B.M.W." and "bmw", become one brand. It is named after the spelling most
+-- cars use, the first in sort order on a tie, and the other spellings become
+-- its aliases so that they keep resolving. Every tenant's cars are read, so
+-- the row-level security policy on car is lifted while they are converted.
+ALTER TABLE car NO FORCE ROW LEVEL SECURITY;
+CREATE TEMPORARY TABLE brand_spelling ON COMMIT DROP AS
+SELECT tenant_id, spelling, lower(regexp_replace(spelling, '[^[:alnum:]]', '', 'g')) AS merge_key, COUNT(*) AS cars
+FROM (SELECT tenant_id, TRIM(brand) AS spelling FROM car) c
+GROUP BY tenant_id, spelling;
+
+INSERT INTO brand (id, tenant_id, name)
+SELECT gen_random_uuid(), tenant_id, spelling
+FROM (
+    SELECT tenant_id, spelling, ROW_NUMBER() OVER (PARTITION BY tenant_id, merge_key ORDER BY cars DESC, spelling) AS preference
+    FROM brand_spelling
+) s
+WHERE preference = 1;
+
+ALTER TABLE brand_spelling ADD COLUMN brand_id UUID;
+UPDATE brand_spelling s SET brand_id = b.id
+FROM brand b
+WHERE b.tenant_id = s.tenant_id AND lower(regexp_replace(b.name, '[^[:alnum:]]', '', 'g')) = s.merge_key;
+
+INSERT INTO brand_alias (tenant_id, brand_id, alias)
+SELECT s.tenant_id, s.brand_id, MIN(s.spelling)
+FROM brand_spelling s JOIN brand b ON b.id = s.brand_id
+WHERE lower(s.spelling) <> lower(b.name)
+GROUP BY s.tenant_id, s.brand_id, lower(s.spelling);
+
+ALTER TABLE car ADD COLUMN brand_id UUID;
+UPDATE car SET brand_id = s.brand_id
+FROM brand_spelling s
+WHERE s.tenant_id = car.tenant_id AND s.spelling = TRIM(car.brand);
+UPDATE car SET brand = b.name
+FROM brand b
+WHERE b.id = car.brand_id;
+ALTER TABLE car ALTER COLUMN brand_id SET NOT NULL;
+ALTER TABLE car ADD CONSTRAINT fk_car_brand FOREIGN KEY (brand_id, tenant_id) REFERENCES brand(id, tenant_id);
+CREATE INDEX idx_car_brand ON car (brand_id);
+ALTER TABLE car FORCE ROW LEVEL SECURITY;
+
+ALTER TABLE brand ENABLE ROW LEVEL SECURITY;
+ALTER TABLE brand FORCE ROW LEVEL SECURITY;
+CREATE POLICY brand_tenant_isolation ON brand
+    USING (tenant_id::text = current_setting('app.tenant_id', true));
+
+ALTER TABLE brand_alias ENABLE ROW LEVEL SECURITY;
+ALTER TABLE brand_alias FORCE ROW LEVEL SECURITY;
+CREATE POLICY brand_alias_tenant_isolation ON brand_alias
+    USING (tenant_id::text = current_setting('app.tenant_id', true));
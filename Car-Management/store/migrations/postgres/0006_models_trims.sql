@@ -0,0 +1,57 @@
+-- Create car_model table; a model is a product line of a brand, e.g. Civic
+CREATE TABLE car_model (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    brand_id UUID NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_car_model_tenant UNIQUE (id, tenant_id),
+    CONSTRAINT fk_car_model_brand FOREIGN KEY (brand_id, tenant_id) REFERENCES brand(id, tenant_id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX uq_car_model_name ON car_model (brand_id, lower(name));
+
+-- Create car_trim table; a trim is one model year of a model, e.g. EX 2023
+CREATE TABLE car_trim (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    model_id UUID NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    year VARCHAR(4) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_car_trim_tenant UNIQUE (id, tenant_id),
+    CONSTRAINT fk_car_trim_model FOREIGN KEY (model_id, tenant_id) REFERENCES car_model(id, tenant_id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX uq_car_trim_name ON car_trim (model_id, lower(name), year);
+
+-- Create car_trim_engine table; the engines a trim is offered with
+CREATE TABLE car_trim_engine (
+    tenant_id UUID NOT NULL,
+    trim_id UUID NOT NULL,
+    engine_id UUID NOT NULL,
+    PRIMARY KEY (trim_id, engine_id),
+    CONSTRAINT fk_car_trim_engine_trim FOREIGN KEY (trim_id, tenant_id) REFERENCES car_trim(id, tenant_id) ON DELETE CASCADE,
+    CONSTRAINT fk_car_trim_engine_engine FOREIGN KEY (engine_id, tenant_id) REFERENCES engine(id, tenant_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_car_trim_engine_engine ON car_trim_engine (engine_id);
+
+-- A car may optionally be a specific trim
+ALTER TABLE car ADD COLUMN trim_id UUID;
+ALTER TABLE car ADD CONSTRAINT fk_car_trim FOREIGN KEY (trim_id, tenant_id) REFERENCES car_trim(id, tenant_id);
+CREATE INDEX idx_car_trim ON car (trim_id);
+
+ALTER TABLE car_model ENABLE ROW LEVEL SECURITY;
+ALTER TABLE car_model FORCE ROW LEVEL SECURITY;
+CREATE POLICY car_model_tenant_isolation ON car_model
+    USING (tenant_id::text = current_setting('app.tenant_id', true));
+
+ALTER TABLE car_trim ENABLE ROW LEVEL SECURITY;
+ALTER TABLE car_trim FORCE ROW LEVEL SECURITY;
+CREATE POLICY car_trim_tenant_isolation ON car_trim
+    USING (tenant_id::text = current_setting('app.tenant_id', true));
+
+ALTER TABLE car_trim_engine ENABLE ROW LEVEL SECURITY;
+ALTER TABLE car_trim_engine FORCE ROW LEVEL SECURITY;
+CREATE POLICY car_trim_engine_tenant_isolation ON car_trim_engine
+    USING (tenant_id::text = current_setting('app.tenant_id', true));
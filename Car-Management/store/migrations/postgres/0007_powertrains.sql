@@ -0,0 +1,12 @@
+-- Engines become powertrains: ice (combustion), bev (battery electric) or
+-- hybrid. Existing engines are combustion engines.
+ALTER TABLE engine ADD COLUMN type VARCHAR(16) NOT NULL DEFAULT 'ice';
+ALTER TABLE engine ADD CONSTRAINT chk_engine_type CHECK (type IN ('ice', 'bev', 'hybrid'));
+
+-- The electric motor of bev and hybrid powertrains; NULL for ice
+ALTER TABLE engine ADD COLUMN battery_kwh DOUBLE PRECISION;
+ALTER TABLE engine ADD COLUMN charging_power_kw DOUBLE PRECISION;
+ALTER TABLE engine ADD COLUMN efficiency_wh_per_km DOUBLE PRECISION;
+ALTER TABLE engine ADD CONSTRAINT chk_engine_motor CHECK (
+    (type = 'ice') = (battery_kwh IS NULL AND charging_power_kw IS NULL AND efficiency_wh_per_km IS NULL)
+);
@@ -0,0 +1,41 @@
+-- Prices become integer minor units plus an ISO 4217 currency code. Existing
+-- prices were US dollars. Every tenant's rows are converted, so the row-level
+-- security policies are lifted meanwhile.
+ALTER TABLE car NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE price_change NO FORCE ROW LEVEL SECURITY;
+
+ALTER TABLE car ADD COLUMN price_amount BIGINT;
+ALTER TABLE car ADD COLUMN price_currency CHAR(3) NOT NULL DEFAULT 'USD';
+UPDATE car SET price_amount = ROUND(price * 100);
+ALTER TABLE car ALTER COLUMN price_amount SET NOT NULL;
+ALTER TABLE car ALTER COLUMN price_currency DROP DEFAULT;
+ALTER TABLE car DROP COLUMN price;
+
+ALTER TABLE price_change ADD COLUMN old_amount BIGINT;
+ALTER TABLE price_change ADD COLUMN old_currency CHAR(3);
+ALTER TABLE price_change ADD COLUMN new_amount BIGINT;
+ALTER TABLE price_change ADD COLUMN new_currency CHAR(3) NOT NULL DEFAULT 'USD';
+UPDATE price_change SET
+    old_amount = ROUND(old_price * 100),
+    old_currency = CASE WHEN old_price IS NULL THEN NULL ELSE 'USD' END,
+    new_amount = ROUND(new_price * 100);
+ALTER TABLE price_change ALTER COLUMN new_amount SET NOT NULL;
+ALTER TABLE price_change ALTER COLUMN new_currency DROP DEFAULT;
+ALTER TABLE price_change DROP COLUMN old_price;
+ALTER TABLE price_change DROP COLUMN new_price;
+
+ALTER TABLE car FORCE ROW LEVEL SECURITY;
+ALTER TABLE price_change FORCE ROW LEVEL SECURITY;
+
+-- Create exchange_rate table; rate is how many units of the currency one US
+-- dollar buys. base_factor converts minor units of the currency to cents so
+-- that statistics can be computed in SQL. Rates are shared by all tenants.
+CREATE TABLE exchange_rate (
+    currency CHAR(3) PRIMARY KEY,
+    rate DOUBLE PRECISION NOT NULL CHECK (rate > 0),
+    base_factor DOUBLE PRECISION NOT NULL,
+    updated_by VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+INSERT INTO exchange_rate (currency, rate, base_factor, updated_by, updated_at)
+VALUES ('USD', 1, 1, 'system', CURRENT_TIMESTAMP);
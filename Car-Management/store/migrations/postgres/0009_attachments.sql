@@ -0,0 +1,22 @@
+-- Create attachment table; the files themselves are kept in the blob store
+CREATE TABLE attachment (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenant(id),
+    car_id UUID NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('image', 'document')),
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    position INTEGER NOT NULL,
+    blob_key VARCHAR(255) NOT NULL,
+    thumbnail_key VARCHAR(255) NOT NULL DEFAULT '',
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_attachment_car FOREIGN KEY (car_id, tenant_id) REFERENCES car(id, tenant_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_attachment_car_position ON attachment (car_id, position);
+
+ALTER TABLE attachment ENABLE ROW LEVEL SECURITY;
+ALTER TABLE attachment FORCE ROW LEVEL SECURITY;
+CREATE POLICY attachment_tenant_isolation ON attachment
+    USING (tenant_id::text = current_setting('app.tenant_id', true));
@@ -0,0 +1,58 @@
+-- btree_gist lets the exclusion constraint below compare car ids with =
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- Create location table; opening hours are local times in its time zone
+CREATE TABLE location (
+    tenant_id UUID NOT NULL REFERENCES tenant(id),
+    name VARCHAR(255) NOT NULL,
+    time_zone VARCHAR(64) NOT NULL,
+    PRIMARY KEY (tenant_id, name)
+);
+
+CREATE TABLE opening_hours (
+    tenant_id UUID NOT NULL,
+    location VARCHAR(255) NOT NULL,
+    weekday INTEGER NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    opens CHAR(5) NOT NULL,
+    closes CHAR(5) NOT NULL,
+    CHECK (closes > opens),
+    CONSTRAINT fk_opening_hours_location FOREIGN KEY (tenant_id, location) REFERENCES location(tenant_id, name) ON DELETE CASCADE
+);
+CREATE INDEX idx_opening_hours_location ON opening_hours (tenant_id, location);
+
+-- Create reservation table; starts_at and ends_at are UTC. Booked test
+-- drives of the same car can never overlap.
+CREATE TABLE reservation (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenant(id),
+    car_id UUID NOT NULL,
+    location VARCHAR(255) NOT NULL,
+    customer_name VARCHAR(255) NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL CHECK (status IN ('booked', 'cancelled')),
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CHECK (ends_at > starts_at),
+    CONSTRAINT fk_reservation_car FOREIGN KEY (car_id, tenant_id) REFERENCES car(id, tenant_id) ON DELETE CASCADE,
+    CONSTRAINT fk_reservation_location FOREIGN KEY (tenant_id, location) REFERENCES location(tenant_id, name),
+    CONSTRAINT ex_reservation_car_overlap EXCLUDE USING gist (car_id WITH =, tsrange(starts_at, ends_at) WITH &&) WHERE (status = 'booked')
+);
+CREATE INDEX idx_reservation_car_starts_at ON reservation (car_id, starts_at);
+
+ALTER TABLE location ENABLE ROW LEVEL SECURITY;
+ALTER TABLE location FORCE ROW LEVEL SECURITY;
+CREATE POLICY location_tenant_isolation ON location
+    USING (tenant_id::text = current_setting('app.tenant_id', true));
+
+ALTER TABLE opening_hours ENABLE ROW LEVEL SECURITY;
+ALTER TABLE opening_hours FORCE ROW LEVEL SECURITY;
+CREATE POLICY opening_hours_tenant_isolation ON opening_hours
+    USING (tenant_id::text = current_setting('app.tenant_id', true));
+
+ALTER TABLE reservation ENABLE ROW LEVEL SECURITY;
+ALTER TABLE reservation FORCE ROW LEVEL SECURITY;
+CREATE POLICY reservation_tenant_isolation ON reservation
+    USING (tenant_id::text = current_setting('app.tenant_id', true));
@@ -0,0 +1,47 @@
+-- Create sales_order table; "order" is a reserved word. Amounts are minor
+-- units of their currency.
+CREATE TABLE sales_order (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenant(id),
+    car_id UUID NOT NULL,
+    customer_name VARCHAR(255) NOT NULL,
+    price_amount BIGINT NOT NULL CHECK (price_amount > 0),
+    price_currency CHAR(3) NOT NULL,
+    deposit_amount BIGINT NOT NULL CHECK (deposit_amount >= 0),
+    notes TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL CHECK (status IN ('draft', 'confirmed', 'paid', 'delivered', 'cancelled')),
+    stock_unit_id UUID REFERENCES stock_unit(id),
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CHECK (deposit_amount <= price_amount),
+    CONSTRAINT fk_sales_order_car FOREIGN KEY (car_id, tenant_id) REFERENCES car(id, tenant_id) ON DELETE RESTRICT
+);
+CREATE INDEX idx_sales_order_car ON sales_order (car_id, created_at);
+CREATE INDEX idx_sales_order_tenant_status ON sales_order (tenant_id, status);
+-- A stock unit can be claimed by only one order that has not been cancelled
+CREATE UNIQUE INDEX uq_sales_order_stock_unit ON sales_order (stock_unit_id) WHERE status <> 'cancelled';
+
+-- Create sales_order_transition table; one row per status change of an
+-- order, starting with its creation as a draft
+CREATE TABLE sales_order_transition (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenant(id),
+    order_id UUID NOT NULL REFERENCES sales_order(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    changed_by VARCHAR(255) NOT NULL,
+    changed_at TIMESTAMP NOT NULL
+);
+CREATE INDEX idx_sales_order_transition_order ON sales_order_transition (order_id, changed_at);
+
+ALTER TABLE sales_order ENABLE ROW LEVEL SECURITY;
+ALTER TABLE sales_order FORCE ROW LEVEL SECURITY;
+CREATE POLICY sales_order_tenant_isolation ON sales_order
+    USING (tenant_id::text = current_setting('app.tenant_id', true));
+
+ALTER TABLE sales_order_transition ENABLE ROW LEVEL SECURITY;
+ALTER TABLE sales_order_transition FORCE ROW LEVEL SECURITY;
+CREATE POLICY sales_order_transition_tenant_isolation ON sales_order_transition
+    USING (tenant_id::text = current_setting('app.tenant_id', true));
@@ -0,0 +1,55 @@
+-- Create customer table. Erasing a customer blanks their personal data and
+-- sets erased_at, keeping the row for the reservations and orders that point
+-- at it. phone_digits is the phone number without formatting, for search.
+CREATE TABLE customer (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenant(id),
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    phone VARCHAR(50) NOT NULL DEFAULT '',
+    phone_digits VARCHAR(50) NOT NULL DEFAULT '',
+    consent_email BOOLEAN NOT NULL DEFAULT FALSE,
+    consent_sms BOOLEAN NOT NULL DEFAULT FALSE,
+    consent_phone BOOLEAN NOT NULL DEFAULT FALSE,
+    notes TEXT NOT NULL DEFAULT '',
+    erased_at TIMESTAMP,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CONSTRAINT uq_customer_tenant UNIQUE (id, tenant_id)
+);
+-- Email addresses are compared case-insensitively
+CREATE UNIQUE INDEX uq_customer_email ON customer (tenant_id, lower(email)) WHERE email <> '';
+CREATE INDEX idx_customer_name ON customer (tenant_id, lower(name));
+
+-- Create enquiry table; a question a customer asked, optionally about a car
+CREATE TABLE enquiry (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenant(id),
+    customer_id UUID NOT NULL,
+    car_id UUID REFERENCES car(id) ON DELETE SET NULL,
+    channel VARCHAR(20) NOT NULL CHECK (channel IN ('email', 'phone', 'walk_in', 'web')),
+    message TEXT NOT NULL,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_enquiry_customer FOREIGN KEY (customer_id, tenant_id) REFERENCES customer(id, tenant_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_enquiry_customer ON enquiry (customer_id, created_at);
+
+ALTER TABLE reservation ADD COLUMN customer_id UUID;
+ALTER TABLE reservation ADD CONSTRAINT fk_reservation_customer FOREIGN KEY (customer_id, tenant_id) REFERENCES customer(id, tenant_id);
+CREATE INDEX idx_reservation_customer ON reservation (customer_id);
+
+ALTER TABLE sales_order ADD COLUMN customer_id UUID;
+ALTER TABLE sales_order ADD CONSTRAINT fk_sales_order_customer FOREIGN KEY (customer_id, tenant_id) REFERENCES customer(id, tenant_id);
+CREATE INDEX idx_sales_order_customer ON sales_order (customer_id);
+
+ALTER TABLE customer ENABLE ROW LEVEL SECURITY;
+ALTER TABLE customer FORCE ROW LEVEL SECURITY;
+CREATE POLICY customer_tenant_isolation ON customer
+    USING (tenant_id::text = current_setting('app.tenant_id', true));
+
+ALTER TABLE enquiry ENABLE ROW LEVEL SECURITY;
+ALTER TABLE enquiry FORCE ROW LEVEL SECURITY;
+CREATE POLICY enquiry_tenant_isolation ON enquiry
+    USING (tenant_id::text = current_setting('app.tenant_id', true));
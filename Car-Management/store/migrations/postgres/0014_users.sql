@@ -0,0 +1,14 @@
+-- Create app_user table; "user" is a reserved word. A user signs in to
+-- exactly one tenant, always with the same role, and goes with the tenant.
+CREATE TABLE app_user (
+    id UUID PRIMARY KEY,
+    username VARCHAR(255) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    tenant_id UUID NOT NULL REFERENCES tenant(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('admin', 'user')),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+-- Usernames are compared case-insensitively, across every tenant
+CREATE UNIQUE INDEX uq_app_user_username ON app_user (lower(username));
+CREATE INDEX idx_app_user_tenant ON app_user (tenant_id);
@@ -0,0 +1,14 @@
+-- Create attachment_blob_deletion table; the files of attachments deleted
+-- along with their car, queued in the same transaction until the attachment
+-- service has deleted them from the blob store
+CREATE TABLE attachment_blob_deletion (
+    tenant_id UUID NOT NULL REFERENCES tenant(id),
+    blob_key VARCHAR(255) NOT NULL,
+    queued_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (tenant_id, blob_key)
+);
+
+ALTER TABLE attachment_blob_deletion ENABLE ROW LEVEL SECURITY;
+ALTER TABLE attachment_blob_deletion FORCE ROW LEVEL SECURITY;
+CREATE POLICY attachment_blob_deletion_tenant_isolation ON attachment_blob_deletion
+    USING (tenant_id::text = current_setting('app.tenant_id', true));
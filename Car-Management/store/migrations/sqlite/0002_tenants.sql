@@ -0,0 +1,19 @@
+-- Create tenant table; a tenant is a dealership
+CREATE TABLE tenant (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Existing data belongs to the default tenant
+INSERT INTO tenant (id, name)
+VALUES ('00000000-0000-0000-0000-000000000001', 'Default dealership');
+
+-- SQLite cannot add a foreign key with a non-null default to an existing
+-- table, so tenant ownership is enforced by the store queries alone.
+ALTER TABLE engine ADD COLUMN tenant_id TEXT NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001';
+CREATE INDEX idx_engine_tenant ON engine (tenant_id);
+
+ALTER TABLE car ADD COLUMN tenant_id TEXT NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001';
+CREATE INDEX idx_car_tenant_brand ON car (tenant_id, brand);
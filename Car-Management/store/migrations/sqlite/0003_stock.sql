@@ -0,0 +1,16 @@
+-- Create stock_unit table; every row is one physical vehicle of a car
+CREATE TABLE stock_unit (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenant(id),
+    car_id TEXT NOT NULL,
+    vin TEXT NOT NULL,
+    colour TEXT NOT NULL,
+    location TEXT NOT NULL,
+    lot TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL CHECK (status IN ('in_stock', 'reserved', 'sold')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_stock_car FOREIGN KEY (car_id) REFERENCES car(id) ON DELETE CASCADE,
+    CONSTRAINT uq_stock_vin UNIQUE (tenant_id, vin)
+);
+CREATE INDEX idx_stock_car_status ON stock_unit (car_id, status);
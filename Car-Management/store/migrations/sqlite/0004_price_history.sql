@@ -0,0 +1,13 @@
+-- Create price_change table; one row per change of a car's price
+CREATE TABLE price_change (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenant(id),
+    car_id TEXT NOT NULL,
+    old_price REAL,
+    new_price REAL NOT NULL,
+    changed_by TEXT NOT NULL,
+    changed_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_price_change_car FOREIGN KEY (car_id) REFERENCES car(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_price_change_car ON price_change (car_id, changed_at);
+CREATE INDEX idx_price_change_tenant_time ON price_change (tenant_id, changed_at);
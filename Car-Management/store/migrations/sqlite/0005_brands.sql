@@ -0,0 +1,65 @@
+-- Create brand table; a brand is a manufacturer with one canonical name
+CREATE TABLE brand (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenant(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    country TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+-- Names are compared case-insensitively
+CREATE UNIQUE INDEX uq_brand_name ON brand (tenant_id, lower(name));
+
+-- Create brand_alias table; other spellings that resolve to the brand
+CREATE TABLE brand_alias (
+    tenant_id TEXT NOT NULL,
+    brand_id TEXT NOT NULL,
+    alias TEXT NOT NULL,
+    CONSTRAINT fk_brand_alias_brand FOREIGN KEY (brand_id) REFERENCES brand(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX uq_brand_alias ON brand_alias (tenant_id, lower(alias));
+CREATE INDEX idx_brand_alias_brand ON brand_alias (brand_id);
+
+-- Existing spellings that differ only in case, spaces or punctuation, such as
+-- "B.M.W." and "bmw", become one brand. It is named after the spelling most
+-- cars use, the first in sort order on a tie, and the other spellings become
+-- its aliases so that they keep resolving. SQLite has no regexp_replace, so
+-- the usual punctuation is removed one character at a time.
+CREATE TEMP TABLE brand_spelling AS
+SELECT tenant_id, spelling, lower(replace(replace(replace(replace(replace(replace(replace(replace(spelling, ' ', ''), '.', ''), '-', ''), '_', ''), '''', ''), ',', ''), '/', ''), '&', '')) AS merge_key, COUNT(*) AS cars
+FROM (SELECT tenant_id, TRIM(brand) AS spelling FROM car)
+GROUP BY tenant_id, spelling;
+
+-- SQLite has no uuid function, so a random version 4 uuid is assembled from
+-- random bytes.
+INSERT INTO brand (id, tenant_id, name)
+SELECT lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' ||
+       substr(lower(hex(randomblob(2))), 2) || '-' ||
+       substr('89ab', 1 + (abs(random()) % 4), 1) || substr(lower(hex(randomblob(2))), 2) || '-' ||
+       lower(hex(randomblob(6))),
+       tenant_id, spelling
+FROM (
+    SELECT tenant_id, spelling, ROW_NUMBER() OVER (PARTITION BY tenant_id, merge_key ORDER BY cars DESC, spelling) AS preference
+    FROM brand_spelling
+)
+WHERE preference = 1;
+
+ALTER TABLE brand_spelling ADD COLUMN brand_id TEXT;
+UPDATE brand_spelling SET
+    brand_id = (SELECT b.id FROM brand b WHERE b.tenant_id = brand_spelling.tenant_id AND lower(replace(replace(replace(replace(replace(replace(replace(replace(b.name, ' ', ''), '.', ''), '-', ''), '_', ''), '''', ''), ',', ''), '/', ''), '&', '')) = brand_spelling.merge_key);
+
+INSERT INTO brand_alias (tenant_id, brand_id, alias)
+SELECT s.tenant_id, s.brand_id, MIN(s.spelling)
+FROM brand_spelling s JOIN brand b ON b.id = s.brand_id
+WHERE lower(s.spelling) <> lower(b.name)
+GROUP BY s.tenant_id, s.brand_id, lower(s.spelling);
+
+-- SQLite cannot add a NOT NULL column to an existing table; the car store
+-- always sets it.
+ALTER TABLE car ADD COLUMN brand_id TEXT REFERENCES brand(id);
+UPDATE car SET
+    brand_id = (SELECT s.brand_id FROM brand_spelling s WHERE s.tenant_id = car.tenant_id AND s.spelling = TRIM(car.brand));
+UPDATE car SET
+    brand = (SELECT b.name FROM brand b WHERE b.id = car.brand_id);
+DROP TABLE brand_spelling;
+CREATE INDEX idx_car_brand ON car (brand_id);
@@ -0,0 +1,39 @@
+-- Create car_model table; a model is a product line of a brand, e.g. Civic
+CREATE TABLE car_model (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL,
+    brand_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_car_model_brand FOREIGN KEY (brand_id) REFERENCES brand(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX uq_car_model_name ON car_model (brand_id, lower(name));
+
+-- Create car_trim table; a trim is one model year of a model, e.g. EX 2023
+CREATE TABLE car_trim (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL,
+    model_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    year TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_car_trim_model FOREIGN KEY (model_id) REFERENCES car_model(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX uq_car_trim_name ON car_trim (model_id, lower(name), year);
+
+-- Create car_trim_engine table; the engines a trim is offered with
+CREATE TABLE car_trim_engine (
+    tenant_id TEXT NOT NULL,
+    trim_id TEXT NOT NULL,
+    engine_id TEXT NOT NULL,
+    PRIMARY KEY (trim_id, engine_id),
+    CONSTRAINT fk_car_trim_engine_trim FOREIGN KEY (trim_id) REFERENCES car_trim(id) ON DELETE CASCADE,
+    CONSTRAINT fk_car_trim_engine_engine FOREIGN KEY (engine_id) REFERENCES engine(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_car_trim_engine_engine ON car_trim_engine (engine_id);
+
+-- A car may optionally be a specific trim
+ALTER TABLE car ADD COLUMN trim_id TEXT REFERENCES car_trim(id);
+CREATE INDEX idx_car_trim ON car (trim_id);
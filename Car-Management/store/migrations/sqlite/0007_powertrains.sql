@@ -0,0 +1,8 @@
+-- Engines become powertrains: ice (combustion), bev (battery electric) or
+-- hybrid. Existing engines are combustion engines.
+ALTER TABLE engine ADD COLUMN type TEXT NOT NULL DEFAULT 'ice' CHECK (type IN ('ice', 'bev', 'hybrid'));
+
+-- The electric motor of bev and hybrid powertrains; NULL for ice
+ALTER TABLE engine ADD COLUMN battery_kwh REAL;
+ALTER TABLE engine ADD COLUMN charging_power_kw REAL;
+ALTER TABLE engine ADD COLUMN efficiency_wh_per_km REAL;
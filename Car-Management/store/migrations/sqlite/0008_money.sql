@@ -0,0 +1,30 @@
+-- Prices become integer minor units plus an ISO 4217 currency code. Existing
+-- prices were US dollars.
+ALTER TABLE car ADD COLUMN price_amount INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE car ADD COLUMN price_currency TEXT NOT NULL DEFAULT 'USD';
+UPDATE car SET price_amount = CAST(ROUND(price * 100) AS INTEGER);
+ALTER TABLE car DROP COLUMN price;
+
+ALTER TABLE price_change ADD COLUMN old_amount INTEGER;
+ALTER TABLE price_change ADD COLUMN old_currency TEXT;
+ALTER TABLE price_change ADD COLUMN new_amount INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE price_change ADD COLUMN new_currency TEXT NOT NULL DEFAULT 'USD';
+UPDATE price_change SET
+    old_amount = CAST(ROUND(old_price * 100) AS INTEGER),
+    old_currency = CASE WHEN old_price IS NULL THEN NULL ELSE 'USD' END,
+    new_amount = CAST(ROUND(new_price * 100) AS INTEGER);
+ALTER TABLE price_change DROP COLUMN old_price;
+ALTER TABLE price_change DROP COLUMN new_price;
+
+-- Create exchange_rate table; rate is how many units of the currency one US
+-- dollar buys. base_factor converts minor units of the currency to cents so
+-- that statistics can be computed in SQL. Rates are shared by all tenants.
+CREATE TABLE exchange_rate (
+    currency TEXT PRIMARY KEY,
+    rate REAL NOT NULL CHECK (rate > 0),
+    base_factor REAL NOT NULL,
+    updated_by TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+INSERT INTO exchange_rate (currency, rate, base_factor, updated_by, updated_at)
+VALUES ('USD', 1, 1, 'system', CURRENT_TIMESTAMP);
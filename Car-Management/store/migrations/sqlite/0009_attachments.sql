@@ -0,0 +1,17 @@
+-- Create attachment table; the files themselves are kept in the blob store
+CREATE TABLE attachment (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenant(id),
+    car_id TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('image', 'document')),
+    filename TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    blob_key TEXT NOT NULL,
+    thumbnail_key TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_attachment_car FOREIGN KEY (car_id) REFERENCES car(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_attachment_car_position ON attachment (car_id, position);
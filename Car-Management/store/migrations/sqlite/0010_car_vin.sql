@@ -0,0 +1,3 @@
+-- A car may identify one vehicle by its VIN; cars without one stay NULL
+ALTER TABLE car ADD COLUMN vin TEXT;
+CREATE UNIQUE INDEX uq_car_vin ON car (tenant_id, vin);
@@ -0,0 +1,40 @@
+-- Create location table; opening hours are local times in its time zone
+CREATE TABLE location (
+    tenant_id TEXT NOT NULL REFERENCES tenant(id),
+    name TEXT NOT NULL,
+    time_zone TEXT NOT NULL,
+    PRIMARY KEY (tenant_id, name)
+);
+
+CREATE TABLE opening_hours (
+    tenant_id TEXT NOT NULL,
+    location TEXT NOT NULL,
+    weekday INTEGER NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    opens TEXT NOT NULL,
+    closes TEXT NOT NULL,
+    CHECK (closes > opens),
+    CONSTRAINT fk_opening_hours_location FOREIGN KEY (tenant_id, location) REFERENCES location(tenant_id, name) ON DELETE CASCADE
+);
+CREATE INDEX idx_opening_hours_location ON opening_hours (tenant_id, location);
+
+-- Create reservation table; starts_at and ends_at are UTC. SQLite has no
+-- exclusion constraints, so overlapping bookings are only prevented by the
+-- store's check, which runs under the database write lock.
+CREATE TABLE reservation (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenant(id),
+    car_id TEXT NOT NULL,
+    location TEXT NOT NULL,
+    customer_name TEXT NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL CHECK (status IN ('booked', 'cancelled')),
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CHECK (ends_at > starts_at),
+    CONSTRAINT fk_reservation_car FOREIGN KEY (car_id) REFERENCES car(id) ON DELETE CASCADE,
+    CONSTRAINT fk_reservation_location FOREIGN KEY (tenant_id, location) REFERENCES location(tenant_id, name)
+);
+CREATE INDEX idx_reservation_car_starts_at ON reservation (car_id, starts_at);
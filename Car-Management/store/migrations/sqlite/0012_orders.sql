@@ -0,0 +1,37 @@
+-- Create sales_order table; "order" is a reserved word. Amounts are minor
+-- units of their currency.
+CREATE TABLE sales_order (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenant(id),
+    car_id TEXT NOT NULL,
+    customer_name TEXT NOT NULL,
+    price_amount INTEGER NOT NULL CHECK (price_amount > 0),
+    price_currency TEXT NOT NULL,
+    deposit_amount INTEGER NOT NULL CHECK (deposit_amount >= 0),
+    notes TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL CHECK (status IN ('draft', 'confirmed', 'paid', 'delivered', 'cancelled')),
+    stock_unit_id TEXT REFERENCES stock_unit(id),
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CHECK (deposit_amount <= price_amount),
+    CONSTRAINT fk_sales_order_car FOREIGN KEY (car_id) REFERENCES car(id) ON DELETE RESTRICT
+);
+CREATE INDEX idx_sales_order_car ON sales_order (car_id, created_at);
+CREATE INDEX idx_sales_order_tenant_status ON sales_order (tenant_id, status);
+-- A stock unit can be claimed by only one order that has not been cancelled
+CREATE UNIQUE INDEX uq_sales_order_stock_unit ON sales_order (stock_unit_id) WHERE status <> 'cancelled';
+
+-- Create sales_order_transition table; one row per status change of an
+-- order, starting with its creation as a draft
+CREATE TABLE sales_order_transition (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenant(id),
+    order_id TEXT NOT NULL REFERENCES sales_order(id) ON DELETE CASCADE,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    changed_by TEXT NOT NULL,
+    changed_at TIMESTAMP NOT NULL
+);
+CREATE INDEX idx_sales_order_transition_order ON sales_order_transition (order_id, changed_at);
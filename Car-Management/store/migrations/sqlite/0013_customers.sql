@@ -0,0 +1,41 @@
+-- Create customer table. Erasing a customer blanks their personal data and
+-- sets erased_at, keeping the row for the reservations and orders that point
+-- at it. phone_digits is the phone number without formatting, for search.
+CREATE TABLE customer (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenant(id),
+    name TEXT NOT NULL,
+    email TEXT NOT NULL DEFAULT '',
+    phone TEXT NOT NULL DEFAULT '',
+    phone_digits TEXT NOT NULL DEFAULT '',
+    consent_email BOOLEAN NOT NULL DEFAULT FALSE,
+    consent_sms BOOLEAN NOT NULL DEFAULT FALSE,
+    consent_phone BOOLEAN NOT NULL DEFAULT FALSE,
+    notes TEXT NOT NULL DEFAULT '',
+    erased_at TIMESTAMP,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+-- Email addresses are compared case-insensitively
+CREATE UNIQUE INDEX uq_customer_email ON customer (tenant_id, lower(email)) WHERE email <> '';
+CREATE INDEX idx_customer_name ON customer (tenant_id, lower(name));
+
+-- Create enquiry table; a question a customer asked, optionally about a car
+CREATE TABLE enquiry (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenant(id),
+    customer_id TEXT NOT NULL REFERENCES customer(id) ON DELETE CASCADE,
+    car_id TEXT REFERENCES car(id) ON DELETE SET NULL,
+    channel TEXT NOT NULL CHECK (channel IN ('email', 'phone', 'walk_in', 'web')),
+    message TEXT NOT NULL,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+CREATE INDEX idx_enquiry_customer ON enquiry (customer_id, created_at);
+
+ALTER TABLE reservation ADD COLUMN customer_id TEXT REFERENCES customer(id);
+CREATE INDEX idx_reservation_customer ON reservation (customer_id);
+
+ALTER TABLE sales_order ADD COLUMN customer_id TEXT REFERENCES customer(id);
+CREATE INDEX idx_sales_order_customer ON sales_order (customer_id);
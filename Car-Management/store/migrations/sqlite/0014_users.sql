@@ -0,0 +1,14 @@
+-- Create app_user table; "user" is a reserved word. A user signs in to
+-- exactly one tenant, always with the same role, and goes with the tenant.
+CREATE TABLE app_user (
+    id TEXT PRIMARY KEY,
+    username TEXT NOT NULL,
+    password_hash TEXT NOT NULL,
+    tenant_id TEXT NOT NULL REFERENCES tenant(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('admin', 'user')),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+-- Usernames are compared case-insensitively, across every tenant
+CREATE UNIQUE INDEX uq_app_user_username ON app_user (lower(username));
+CREATE INDEX idx_app_user_tenant ON app_user (tenant_id);
@@ -0,0 +1,9 @@
+-- Create attachment_blob_deletion table; the files of attachments deleted
+-- along with their car, queued in the same transaction until the attachment
+-- service has deleted them from the blob store
+CREATE TABLE attachment_blob_deletion (
+    tenant_id TEXT NOT NULL REFERENCES tenant(id),
+    blob_key TEXT NOT NULL,
+    queued_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (tenant_id, blob_key)
+);
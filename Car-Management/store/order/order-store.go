@@ -0,0 +1,469 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/driver"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+const orderColumns = `id, car_id, customer_name, customer_id, price_amount, price_currency, deposit_amount, notes, status, stock_unit_id, created_by, created_at, updated_at`
+
+const selectOrderQuery = `SELECT ` + orderColumns + ` FROM sales_order WHERE id=$1 AND tenant_id=$2`
+
+type OrderStore struct {
+	db *driver.DB
+}
+
+func NewOrderStore(db *driver.DB) *OrderStore {
+	return &OrderStore{db: db}
+}
+
+// CreateOrder drafts an order for a car and records its creation in the
+// order's transitions.
+func (s OrderStore) CreateOrder(ctx context.Context, order models.Order) (models.Order, error) {
+	tracer := otel.Tracer("order-store")
+	ctx, span := tracer.Start(ctx, "CreateOrder-Store")
+	defer span.End()
+	var createdOrder models.Order
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return createdOrder, err
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return createdOrder, err
+	}
+
+	var carID uuid.UUID
+	err = tx.QueryRowContext(ctx, `SELECT id FROM car WHERE id=$1 AND tenant_id=$2`, order.CarID, tenantID).Scan(&carID)
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return createdOrder, store.ErrCarNotFound
+		}
+		return createdOrder, err
+	}
+
+	name, err := store.CustomerName(ctx, tx, order.CustomerID, order.CustomerName, tenantID)
+	if err != nil {
+		tx.Rollback()
+		return createdOrder, err
+	}
+
+	id := uuid.New()
+	now := time.Now().UTC()
+	query := `INSERT INTO sales_order (id, tenant_id, car_id, customer_name, customer_id, price_amount, price_currency, deposit_amount, notes, status, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+
+	_, err = tx.ExecContext(ctx, query,
+		id,
+		tenantID,
+		order.CarID,
+		name,
+		order.CustomerID,
+		order.AgreedPrice.Amount,
+		order.AgreedPrice.Currency,
+		order.Deposit.Amount,
+		order.Notes,
+		models.OrderDraft,
+		auth.Actor(ctx),
+		now,
+		now)
+	if err != nil {
+		tx.Rollback()
+		return createdOrder, err
+	}
+
+	if err := recordTransition(ctx, tx, id, tenantID, "", models.OrderDraft, "", now); err != nil {
+		tx.Rollback()
+		return createdOrder, err
+	}
+
+	createdOrder, err = scanOrder(tx.QueryRowContext(ctx, selectOrderQuery, id, tenantID))
+	if err != nil {
+		tx.Rollback()
+		return createdOrder, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return createdOrder, err
+	}
+
+	return createdOrder, nil
+}
+
+func (s OrderStore) GetOrderById(ctx context.Context, orderID string) (models.Order, error) {
+	tracer := otel.Tracer("order-store")
+	ctx, span := tracer.Start(ctx, "GetOrderById-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	order, err := scanOrder(s.db.QueryRowContext(ctx, selectOrderQuery, orderID, tenantID))
+	if err == sql.ErrNoRows {
+		return order, store.ErrOrderNotFound
+	}
+	return order, err
+}
+
+// GetOrders returns the tenant's orders, newest first, optionally only those
+// with the given status.
+func (s OrderStore) GetOrders(ctx context.Context, status string) ([]models.Order, error) {
+	tracer := otel.Tracer("order-store")
+	ctx, span := tracer.Start(ctx, "GetOrders-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if status == "" {
+		query := `SELECT ` + orderColumns + ` FROM sales_order WHERE tenant_id=$1 ORDER BY created_at DESC, id`
+		return orders(ctx, s.db, query, tenantID)
+	}
+	query := `SELECT ` + orderColumns + ` FROM sales_order WHERE tenant_id=$1 AND status=$2 ORDER BY created_at DESC, id`
+	return orders(ctx, s.db, query, tenantID, status)
+}
+
+func (s OrderStore) GetOrdersByCar(ctx context.Context, carID string) ([]models.Order, error) {
+	tracer := otel.Tracer("order-store")
+	ctx, span := tracer.Start(ctx, "GetOrdersByCar-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + orderColumns + ` FROM sales_order WHERE car_id=$1 AND tenant_id=$2 ORDER BY created_at DESC, id`
+	return orders(ctx, s.db, query, carID, tenantID)
+}
+
+// UpdateOrder changes the customer, price, deposit and notes of a draft.
+// Orders that have been confirmed are fixed.
+func (s OrderStore) UpdateOrder(ctx context.Context, order models.Order) (models.Order, error) {
+	tracer := otel.Tracer("order-store")
+	ctx, span := tracer.Start(ctx, "UpdateOrder-Store")
+	defer span.End()
+	var updatedOrder models.Order
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return updatedOrder, err
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return updatedOrder, err
+	}
+
+	current, err := lockOrder(ctx, tx, order.ID.String(), tenantID)
+	if err != nil {
+		tx.Rollback()
+		return updatedOrder, err
+	}
+	if current.Status != models.OrderDraft {
+		tx.Rollback()
+		return updatedOrder, store.ErrOrderNotDraft
+	}
+
+	name, err := store.CustomerName(ctx, tx, order.CustomerID, order.CustomerName, tenantID)
+	if err != nil {
+		tx.Rollback()
+		return updatedOrder, err
+	}
+
+	query := `UPDATE sales_order SET customer_name=$3, customer_id=$4, price_amount=$5, price_currency=$6, deposit_amount=$7, notes=$8, updated_at=$9 WHERE id=$1 AND tenant_id=$2`
+	_, err = tx.ExecContext(ctx, query,
+		order.ID,
+		tenantID,
+		name,
+		order.CustomerID,
+		order.AgreedPrice.Amount,
+		order.AgreedPrice.Currency,
+		order.Deposit.Amount,
+		order.Notes,
+		time.Now().UTC())
+	if err != nil {
+		tx.Rollback()
+		return updatedOrder, err
+	}
+
+	updatedOrder, err = scanOrder(tx.QueryRowContext(ctx, selectOrderQuery, order.ID, tenantID))
+	if err != nil {
+		tx.Rollback()
+		return updatedOrder, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return updatedOrder, err
+	}
+
+	return updatedOrder, nil
+}
+
+// TransitionOrder moves an order to status, which models.CanTransition must
+// allow, and records the change. Confirming reserves a stock unit of the car,
+// delivering sells it and cancelling puts it back in stock.
+func (s OrderStore) TransitionOrder(ctx context.Context, orderID, status string, request models.TransitionRequest) (models.Order, error) {
+	tracer := otel.Tracer("order-store")
+	ctx, span := tracer.Start(ctx, "TransitionOrder-Store")
+	defer span.End()
+	var changedOrder models.Order
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return changedOrder, err
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return changedOrder, err
+	}
+
+	order, err := lockOrder(ctx, tx, orderID, tenantID)
+	if err != nil {
+		tx.Rollback()
+		return changedOrder, err
+	}
+	if !models.CanTransition(order.Status, status) {
+		tx.Rollback()
+		return changedOrder, store.ErrInvalidOrderTransition
+	}
+
+	unitID := order.StockUnitID
+	switch {
+	case status == models.OrderConfirmed:
+		unitID, err = reserveUnit(ctx, tx, order.CarID, request.StockUnitID, tenantID)
+	case status == models.OrderDelivered:
+		err = sellUnit(ctx, tx, unitID, tenantID)
+	case status == models.OrderCancelled && unitID.Valid:
+		err = releaseUnit(ctx, tx, unitID, tenantID)
+	}
+	if err != nil {
+		tx.Rollback()
+		return changedOrder, err
+	}
+
+	now := time.Now().UTC()
+	query := `UPDATE sales_order SET status=$3, stock_unit_id=$4, updated_at=$5 WHERE id=$1 AND tenant_id=$2`
+	_, err = tx.ExecContext(ctx, query, orderID, tenantID, status, unitID, now)
+	if err != nil {
+		tx.Rollback()
+		return changedOrder, err
+	}
+
+	if err := recordTransition(ctx, tx, order.ID, tenantID, order.Status, status, request.Note, now); err != nil {
+		tx.Rollback()
+		return changedOrder, err
+	}
+
+	changedOrder, err = scanOrder(tx.QueryRowContext(ctx, selectOrderQuery, orderID, tenantID))
+	if err != nil {
+		tx.Rollback()
+		return changedOrder, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return changedOrder, err
+	}
+
+	return changedOrder, nil
+}
+
+// GetOrderTransitions returns the status changes of an order, oldest first.
+func (s OrderStore) GetOrderTransitions(ctx context.Context, orderID string) ([]models.OrderTransition, error) {
+	tracer := otel.Tracer("order-store")
+	ctx, span := tracer.Start(ctx, "GetOrderTransitions-Store")
+	defer span.End()
+	transitions := []models.OrderTransition{}
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return transitions, err
+	}
+
+	var id uuid.UUID
+	err = s.db.QueryRowContext(ctx, `SELECT id FROM sales_order WHERE id=$1 AND tenant_id=$2`, orderID, tenantID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return transitions, store.ErrOrderNotFound
+	}
+	if err != nil {
+		return transitions, err
+	}
+
+	query := `SELECT id, order_id, from_status, to_status, note, changed_by, changed_at FROM sales_order_transition
+		WHERE order_id=$1 AND tenant_id=$2 ORDER BY changed_at, id`
+
+	rows, err := s.db.QueryContext(ctx, query, orderID, tenantID)
+	if err != nil {
+		return transitions, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transition models.OrderTransition
+		err := rows.Scan(&transition.ID,
+			&transition.OrderID,
+			&transition.FromStatus,
+			&transition.ToStatus,
+			&transition.Note,
+			&transition.ChangedBy,
+			&transition.ChangedAt)
+		if err != nil {
+			return transitions, err
+		}
+		transitions = append(transitions, transition)
+	}
+
+	if err := rows.Err(); err != nil {
+		return transitions, err
+	}
+
+	return transitions, nil
+}
+
+// lockOrder reads an order, locking its row on Postgres so that its status
+// changes one step at a time. SQLite transactions already hold the database
+// write lock.
+func lockOrder(ctx context.Context, tx *driver.Tx, orderID string, tenantID uuid.UUID) (models.Order, error) {
+	query := selectOrderQuery
+	if tx.Dialect() == driver.Postgres {
+		query += ` FOR UPDATE`
+	}
+	order, err := scanOrder(tx.QueryRowContext(ctx, query, orderID, tenantID))
+	if err == sql.ErrNoRows {
+		return order, store.ErrOrderNotFound
+	}
+	return order, err
+}
+
+// reserveUnit reserves the requested stock unit of the car, or when none is
+// requested the unit that has been in stock longest. The conditional update
+// makes sure no other order has claimed the unit in the meantime.
+func reserveUnit(ctx context.Context, tx *driver.Tx, carID uuid.UUID, requested uuid.NullUUID, tenantID uuid.UUID) (uuid.NullUUID, error) {
+	unitID := requested
+	if !unitID.Valid {
+		query := `SELECT id FROM stock_unit WHERE car_id=$1 AND tenant_id=$2 AND status='in_stock' ORDER BY created_at, id LIMIT 1`
+		if tx.Dialect() == driver.Postgres {
+			// Skip units another confirmation is reserving right now
+			// rather than waiting for it and then finding them taken.
+			query += ` FOR UPDATE SKIP LOCKED`
+		}
+		err := tx.QueryRowContext(ctx, query, carID, tenantID).Scan(&unitID)
+		if err == sql.ErrNoRows {
+			return unitID, store.ErrNoStockAvailable
+		}
+		if err != nil {
+			return unitID, err
+		}
+	}
+
+	query := `UPDATE stock_unit SET status='reserved', updated_at=$4 WHERE id=$1 AND tenant_id=$2 AND car_id=$3 AND status='in_stock'`
+	result, err := tx.ExecContext(ctx, query, unitID, tenantID, carID, time.Now())
+	if err != nil {
+		return unitID, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return unitID, err
+	}
+	if rowsAffected == 0 {
+		if requested.Valid {
+			return unitID, store.ErrStockUnitUnavailable
+		}
+		return unitID, store.ErrNoStockAvailable
+	}
+	return unitID, nil
+}
+
+// sellUnit marks the order's reserved stock unit as sold. It fails if the
+// unit was changed outside the order, e.g. sold directly.
+func sellUnit(ctx context.Context, tx *driver.Tx, unitID uuid.NullUUID, tenantID uuid.UUID) error {
+	if !unitID.Valid {
+		return store.ErrStockUnitUnavailable
+	}
+	query := `UPDATE stock_unit SET status='sold', updated_at=$3 WHERE id=$1 AND tenant_id=$2 AND status='reserved'`
+	result, err := tx.ExecContext(ctx, query, unitID, tenantID, time.Now())
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return store.ErrStockUnitUnavailable
+	}
+	return nil
+}
+
+// releaseUnit puts the order's stock unit back in stock, unless it has been
+// sold outside the order in the meantime.
+func releaseUnit(ctx context.Context, tx *driver.Tx, unitID uuid.NullUUID, tenantID uuid.UUID) error {
+	query := `UPDATE stock_unit SET status='in_stock', updated_at=$3 WHERE id=$1 AND tenant_id=$2 AND status='reserved'`
+	_, err := tx.ExecContext(ctx, query, unitID, tenantID, time.Now())
+	return err
+}
+
+func recordTransition(ctx context.Context, tx *driver.Tx, orderID, tenantID uuid.UUID, from, to, note string, changedAt time.Time) error {
+	query := `INSERT INTO sales_order_transition (id, tenant_id, order_id, from_status, to_status, note, changed_by, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := tx.ExecContext(ctx, query, uuid.New(), tenantID, orderID, from, to, note, auth.Actor(ctx), changedAt)
+	return err
+}
+
+func orders(ctx context.Context, db store.Queryer, query string, args ...any) ([]models.Order, error) {
+	orders := []models.Order{}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return orders, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return orders, err
+		}
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return orders, err
+	}
+
+	return orders, nil
+}
+
+func scanOrder(row store.Scanner) (models.Order, error) {
+	var order models.Order
+	err := row.Scan(&order.ID,
+		&order.CarID,
+		&order.CustomerName,
+		&order.CustomerID,
+		&order.AgreedPrice.Amount,
+		&order.AgreedPrice.Currency,
+		&order.Deposit.Amount,
+		&order.Notes,
+		&order.Status,
+		&order.StockUnitID,
+		&order.CreatedBy,
+		&order.CreatedAt,
+		&order.UpdatedAt)
+	order.Deposit.Currency = order.AgreedPrice.Currency
+	return order, err
+}
@@ -0,0 +1,227 @@
+package order_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"github.com/nitesh111sinha/car-management/store/order"
+	"github.com/nitesh111sinha/car-management/store/stock"
+	"github.com/nitesh111sinha/car-management/store/storetest"
+)
+
+type fixture struct {
+	ctx    context.Context
+	orders *order.OrderStore
+	units  *stock.StockStore
+	stores storetest.Stores
+	engine models.Engine
+	car    models.Car
+}
+
+func setup(t *testing.T) fixture {
+	t.Helper()
+	db := storetest.OpenSQLite(t)
+	s := storetest.SQLStores(db)
+	ctx := storetest.NewTenant(t, s)
+	engine := storetest.NewEngine(ctx, t, s)
+	return fixture{
+		ctx:    ctx,
+		orders: order.NewOrderStore(db),
+		units:  stock.NewStockStore(db),
+		stores: s,
+		engine: engine,
+		car:    storetest.NewCar(ctx, t, s, "Honda", engine),
+	}
+}
+
+func (f fixture) draft(t *testing.T) models.Order {
+	t.Helper()
+	draft, err := f.orders.CreateOrder(f.ctx, models.Order{
+		CarID:        f.car.ID,
+		CustomerName: "Alex",
+		AgreedPrice:  models.Money{Amount: 2400000, Currency: "USD"},
+		Deposit:      models.Money{Amount: 100000, Currency: "USD"},
+	})
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	return draft
+}
+
+func (f fixture) receive(t *testing.T, vin string) models.StockUnit {
+	t.Helper()
+	unit, err := f.units.ReceiveUnit(f.ctx, models.StockUnit{CarID: f.car.ID, VIN: vin, Colour: "Red", Location: "Main", Lot: "A1"})
+	if err != nil {
+		t.Fatalf("ReceiveUnit: %v", err)
+	}
+	return unit
+}
+
+func (f fixture) move(t *testing.T, orderID uuid.UUID, status string) models.Order {
+	t.Helper()
+	changed, err := f.orders.TransitionOrder(f.ctx, orderID.String(), status, models.TransitionRequest{Note: "to " + status})
+	if err != nil {
+		t.Fatalf("TransitionOrder(%s): %v", status, err)
+	}
+	if changed.Status != status {
+		t.Fatalf("status = %q, want %q", changed.Status, status)
+	}
+	return changed
+}
+
+func (f fixture) unitStatus(t *testing.T, unitID uuid.UUID) string {
+	t.Helper()
+	unit, err := f.units.GetUnitById(f.ctx, unitID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return unit.Status
+}
+
+func TestOrderLifecycle(t *testing.T) {
+	f := setup(t)
+	unit := f.receive(t, "1HGCM82633A004352")
+
+	draft := f.draft(t)
+	if draft.Status != models.OrderDraft || draft.StockUnitID.Valid || draft.Deposit.Currency != "USD" {
+		t.Errorf("draft = %+v, want a draft without a stock unit", draft)
+	}
+
+	confirmed := f.move(t, draft.ID, models.OrderConfirmed)
+	if confirmed.StockUnitID.UUID != unit.ID {
+		t.Errorf("reserved unit = %v, want %s", confirmed.StockUnitID, unit.ID)
+	}
+	if got := f.unitStatus(t, unit.ID); got != models.StockReserved {
+		t.Errorf("unit after confirming = %q, want reserved", got)
+	}
+	if _, err := f.orders.UpdateOrder(f.ctx, confirmed); !errors.Is(err, store.ErrOrderNotDraft) {
+		t.Errorf("changing a confirmed order = %v, want ErrOrderNotDraft", err)
+	}
+
+	f.move(t, draft.ID, models.OrderPaid)
+	f.move(t, draft.ID, models.OrderDelivered)
+	if got := f.unitStatus(t, unit.ID); got != models.StockSold {
+		t.Errorf("unit after delivery = %q, want sold", got)
+	}
+	if _, err := f.orders.TransitionOrder(f.ctx, draft.ID.String(), models.OrderCancelled, models.TransitionRequest{}); !errors.Is(err, store.ErrInvalidOrderTransition) {
+		t.Errorf("cancelling a delivered order = %v, want ErrInvalidOrderTransition", err)
+	}
+
+	transitions, err := f.orders.GetOrderTransitions(f.ctx, draft.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{models.OrderDraft, models.OrderConfirmed, models.OrderPaid, models.OrderDelivered}
+	if len(transitions) != len(want) {
+		t.Fatalf("transitions = %+v, want %v", transitions, want)
+	}
+	for i, transition := range transitions {
+		if transition.ToStatus != want[i] || (i > 0 && transition.FromStatus != want[i-1]) {
+			t.Errorf("transition %d = %s -> %s, want -> %s", i, transition.FromStatus, transition.ToStatus, want[i])
+		}
+	}
+	if transitions[0].FromStatus != "" || transitions[1].Note != "to confirmed" {
+		t.Errorf("transitions = %+v, want creation first and notes kept", transitions)
+	}
+}
+
+func TestCancelReleasesUnit(t *testing.T) {
+	f := setup(t)
+	unit := f.receive(t, "1HGCM82633A004352")
+
+	first := f.draft(t)
+	f.move(t, first.ID, models.OrderConfirmed)
+
+	second := f.draft(t)
+	if _, err := f.orders.TransitionOrder(f.ctx, second.ID.String(), models.OrderConfirmed, models.TransitionRequest{}); !errors.Is(err, store.ErrNoStockAvailable) {
+		t.Errorf("confirming with every unit reserved = %v, want ErrNoStockAvailable", err)
+	}
+	if _, err := f.orders.TransitionOrder(f.ctx, second.ID.String(), models.OrderConfirmed, models.TransitionRequest{StockUnitID: uuid.NullUUID{UUID: unit.ID, Valid: true}}); !errors.Is(err, store.ErrStockUnitUnavailable) {
+		t.Errorf("confirming with a reserved unit = %v, want ErrStockUnitUnavailable", err)
+	}
+
+	f.move(t, first.ID, models.OrderCancelled)
+	if got := f.unitStatus(t, unit.ID); got != models.StockInStock {
+		t.Errorf("unit after cancelling = %q, want in stock", got)
+	}
+	if confirmed := f.move(t, second.ID, models.OrderConfirmed); confirmed.StockUnitID.UUID != unit.ID {
+		t.Errorf("second order reserved %v, want the released unit", confirmed.StockUnitID)
+	}
+
+	// A draft holds no unit, so cancelling it leaves stock alone.
+	f.move(t, f.draft(t).ID, models.OrderCancelled)
+	if got := f.unitStatus(t, unit.ID); got != models.StockReserved {
+		t.Errorf("unit after cancelling a draft = %q, want still reserved", got)
+	}
+}
+
+func TestReservedUnitCannotBeSoldDirectly(t *testing.T) {
+	f := setup(t)
+	unit := f.receive(t, "1HGCM82633A004352")
+	draft := f.draft(t)
+	f.move(t, draft.ID, models.OrderConfirmed)
+	f.move(t, draft.ID, models.OrderPaid)
+
+	if _, err := f.units.SellUnit(f.ctx, unit.ID.String()); !errors.Is(err, store.ErrInvalidStockTransition) {
+		t.Errorf("selling a unit reserved by an order = %v, want ErrInvalidStockTransition", err)
+	}
+	f.move(t, draft.ID, models.OrderDelivered)
+	if got := f.unitStatus(t, unit.ID); got != models.StockSold {
+		t.Errorf("unit after delivery = %q, want sold", got)
+	}
+}
+
+func TestOrderErrors(t *testing.T) {
+	f := setup(t)
+	draft := f.draft(t)
+
+	if _, err := f.orders.CreateOrder(f.ctx, models.Order{CarID: uuid.New(), CustomerName: "Alex"}); !errors.Is(err, store.ErrCarNotFound) {
+		t.Errorf("ordering an unknown car = %v, want ErrCarNotFound", err)
+	}
+	if _, err := f.orders.CreateOrder(f.ctx, models.Order{CarID: f.car.ID, CustomerID: uuid.NullUUID{UUID: uuid.New(), Valid: true}}); !errors.Is(err, store.ErrInvalidCustomer) {
+		t.Errorf("ordering for an unknown customer = %v, want ErrInvalidCustomer", err)
+	}
+	if _, err := f.orders.GetOrderById(f.ctx, uuid.NewString()); !errors.Is(err, store.ErrOrderNotFound) {
+		t.Errorf("GetOrderById(missing) = %v, want ErrOrderNotFound", err)
+	}
+	if _, err := f.orders.TransitionOrder(f.ctx, uuid.NewString(), models.OrderConfirmed, models.TransitionRequest{}); !errors.Is(err, store.ErrOrderNotFound) {
+		t.Errorf("TransitionOrder(missing) = %v, want ErrOrderNotFound", err)
+	}
+	if _, err := f.orders.TransitionOrder(f.ctx, draft.ID.String(), models.OrderDelivered, models.TransitionRequest{}); !errors.Is(err, store.ErrInvalidOrderTransition) {
+		t.Errorf("delivering a draft = %v, want ErrInvalidOrderTransition", err)
+	}
+	if _, err := f.orders.GetOrderById(storetest.NewTenant(t, f.stores), draft.ID.String()); !errors.Is(err, store.ErrOrderNotFound) {
+		t.Errorf("another tenant's order = %v, want ErrOrderNotFound", err)
+	}
+
+	drafts, err := f.orders.GetOrders(f.ctx, models.OrderDraft)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paid, err := f.orders.GetOrders(f.ctx, models.OrderPaid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drafts) != 1 || len(paid) != 0 {
+		t.Errorf("GetOrders found %d drafts and %d paid orders, want 1 and 0", len(drafts), len(paid))
+	}
+}
+
+func TestCarsWithOrdersCannotBeDeleted(t *testing.T) {
+	f := setup(t)
+	f.draft(t)
+
+	if err := f.stores.Cars.DeleteCar(f.ctx, f.car.ID.String()); !errors.Is(err, store.ErrCarHasOrders) {
+		t.Errorf("DeleteCar with an order = %v, want ErrCarHasOrders", err)
+	}
+	if err := f.stores.Engines.DeleteEngine(f.ctx, f.engine.EngineID.String(), true); !errors.Is(err, store.ErrCarHasOrders) {
+		t.Errorf("cascading an engine delete onto a car with an order = %v, want ErrCarHasOrders", err)
+	}
+	if _, err := f.stores.Cars.GetCarById(f.ctx, f.car.ID.String()); err != nil {
+		t.Errorf("car after refused deletes: %v", err)
+	}
+}
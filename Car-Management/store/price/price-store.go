@@ -0,0 +1,119 @@
+package price
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/driver"
+	"github.com/nitesh111sinha/car-management/models"
+	"go.opentelemetry.io/otel"
+)
+
+// PriceStore reads price history. The rows themselves are written by the car
+// store, in the same transaction as the price change.
+type PriceStore struct {
+	db *driver.DB
+}
+
+func NewPriceStore(db *driver.DB) *PriceStore {
+	return &PriceStore{db: db}
+}
+
+func (s PriceStore) GetPriceHistory(ctx context.Context, carID string) ([]models.PriceChange, error) {
+	tracer := otel.Tracer("price-store")
+	ctx, span := tracer.Start(ctx, "GetPriceHistory-Store")
+	defer span.End()
+	var changes []models.PriceChange
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return changes, err
+	}
+
+	query := `SELECT id, car_id, old_amount, old_currency, new_amount, new_currency, changed_by, changed_at FROM price_change WHERE car_id=$1 AND tenant_id=$2 ORDER BY changed_at, id`
+
+	rows, err := s.db.QueryContext(ctx, query, carID, tenantID)
+	if err != nil {
+		return changes, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var change models.PriceChange
+		var oldAmount sql.NullInt64
+		var oldCurrency sql.NullString
+		err := rows.Scan(&change.ID,
+			&change.CarID,
+			&oldAmount,
+			&oldCurrency,
+			&change.NewPrice.Amount,
+			&change.NewPrice.Currency,
+			&change.ChangedBy,
+			&change.ChangedAt)
+		if err != nil {
+			return changes, err
+		}
+		if oldAmount.Valid {
+			change.OldPrice = &models.Money{Amount: oldAmount.Int64, Currency: oldCurrency.String}
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return changes, err
+	}
+
+	return changes, nil
+}
+
+// GetPriceMovements counts and sums price drops and increases per brand and
+// currency for changes made since the given time. Initial listing prices are
+// not changes and are left out, and so are changes of currency, which are
+// neither drops nor increases.
+func (s PriceStore) GetPriceMovements(ctx context.Context, since time.Time) ([]models.BrandPriceMovement, error) {
+	tracer := otel.Tracer("price-store")
+	ctx, span := tracer.Start(ctx, "GetPriceMovements-Store")
+	defer span.End()
+	var movements []models.BrandPriceMovement
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return movements, err
+	}
+
+	query := `SELECT c.brand, p.new_currency,
+		SUM(CASE WHEN p.new_amount < p.old_amount THEN 1 ELSE 0 END),
+		SUM(CASE WHEN p.new_amount > p.old_amount THEN 1 ELSE 0 END),
+		COALESCE(SUM(CASE WHEN p.new_amount < p.old_amount THEN p.old_amount - p.new_amount END), 0),
+		COALESCE(SUM(CASE WHEN p.new_amount > p.old_amount THEN p.new_amount - p.old_amount END), 0)
+	FROM price_change p JOIN car c ON c.id = p.car_id
+	WHERE p.tenant_id=$1 AND p.changed_at >= $2 AND p.old_currency = p.new_currency
+	GROUP BY c.brand, p.new_currency
+	ORDER BY c.brand, p.new_currency`
+
+	rows, err := s.db.QueryContext(ctx, query, tenantID, since.UTC())
+	if err != nil {
+		return movements, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var movement models.BrandPriceMovement
+		err := rows.Scan(&movement.Brand,
+			&movement.Currency,
+			&movement.Drops,
+			&movement.Increases,
+			&movement.TotalDrop,
+			&movement.TotalIncrease)
+		if err != nil {
+			return movements, err
+		}
+		movements = append(movements, movement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return movements, err
+	}
+
+	return movements, nil
+}
@@ -0,0 +1,94 @@
+package price_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store/price"
+	"github.com/nitesh111sinha/car-management/store/storetest"
+)
+
+// reprice updates the car's price as alice and returns the updated car.
+func reprice(ctx context.Context, t *testing.T, s storetest.Stores, car models.Car, amount int64, currency string) models.Car {
+	t.Helper()
+	car.Price = models.Money{Amount: amount, Currency: currency}
+	updated, err := s.Cars.UpdateCar(ctx, car)
+	if err != nil {
+		t.Fatalf("UpdateCar: %v", err)
+	}
+	return updated
+}
+
+func TestPriceHistory(t *testing.T) {
+	db := storetest.OpenSQLite(t)
+	s := storetest.SQLStores(db)
+	ctx := storetest.NewTenant(t, s)
+	tenantID, _ := auth.TenantFromContext(ctx)
+	ctx = auth.WithPrincipal(ctx, auth.Principal{Username: "alice", TenantID: tenantID, Role: auth.RoleUser})
+	prices := price.NewPriceStore(db)
+
+	car := storetest.NewCar(ctx, t, s, "Honda", storetest.NewEngine(ctx, t, s))
+	car = reprice(ctx, t, s, car, 2400000, "USD")
+	car.Name = "Civic Sport"
+	car = reprice(ctx, t, s, car, 2400000, "USD")
+
+	history, err := prices.GetPriceHistory(ctx, car.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("history has %d entries, want the listing and one change: %+v", len(history), history)
+	}
+	if history[0].OldPrice != nil || history[0].NewPrice != (models.Money{Amount: 2500000, Currency: "USD"}) {
+		t.Errorf("first entry = %+v, want the listing price with no old price", history[0])
+	}
+	change := history[1]
+	if change.OldPrice == nil || *change.OldPrice != (models.Money{Amount: 2500000, Currency: "USD"}) ||
+		change.NewPrice != (models.Money{Amount: 2400000, Currency: "USD"}) || change.ChangedBy != "alice" {
+		t.Errorf("change = %+v, want 25000 to 24000 USD by alice", change)
+	}
+
+	if others, err := prices.GetPriceHistory(storetest.NewTenant(t, s), car.ID.String()); err != nil || len(others) != 0 {
+		t.Errorf("another tenant sees history %+v, %v; want none", others, err)
+	}
+}
+
+func TestPriceMovements(t *testing.T) {
+	db := storetest.OpenSQLite(t)
+	s := storetest.SQLStores(db)
+	ctx := storetest.NewTenant(t, s)
+	prices := price.NewPriceStore(db)
+	engine := storetest.NewEngine(ctx, t, s)
+	start := time.Now().Add(-time.Minute)
+
+	honda := storetest.NewCar(ctx, t, s, "Honda", engine)
+	honda = reprice(ctx, t, s, honda, 2300000, "USD")
+	reprice(ctx, t, s, honda, 2400000, "USD")
+	toyota := storetest.NewCar(ctx, t, s, "Toyota", engine)
+	toyota = reprice(ctx, t, s, toyota, 2000000, "USD")
+	reprice(ctx, t, s, toyota, 1800000, "EUR")
+
+	movements, err := prices.GetPriceMovements(ctx, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.BrandPriceMovement{
+		{Brand: "Honda", Currency: "USD", Drops: 1, Increases: 1, TotalDrop: 200000, TotalIncrease: 100000},
+		{Brand: "Toyota", Currency: "USD", Drops: 1, TotalDrop: 500000},
+	}
+	if len(movements) != len(want) {
+		t.Fatalf("movements = %+v, want %+v", movements, want)
+	}
+	for i := range want {
+		if movements[i] != want[i] {
+			t.Errorf("movements[%d] = %+v, want %+v", i, movements[i], want[i])
+		}
+	}
+
+	if later, err := prices.GetPriceMovements(ctx, time.Now().Add(time.Minute)); err != nil || len(later) != 0 {
+		t.Errorf("movements after the window = %+v, %v; want none", later, err)
+	}
+}
@@ -0,0 +1,517 @@
+package reservation
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/driver"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+const reservationColumns = `id, car_id, location, customer_name, customer_id, notes, status, starts_at, ends_at, created_by, created_at, updated_at`
+
+const selectReservationQuery = `SELECT ` + reservationColumns + ` FROM reservation WHERE id=$1 AND tenant_id=$2`
+
+// overlapQuery counts the booked reservations of a car overlapping a period,
+// other than the one being rescheduled.
+const overlapQuery = `SELECT COUNT(*) FROM reservation
+	WHERE car_id=$1 AND tenant_id=$2 AND status='booked' AND starts_at < $4 AND ends_at > $3 AND id <> $5`
+
+type ReservationStore struct {
+	db *driver.DB
+}
+
+func NewReservationStore(db *driver.DB) *ReservationStore {
+	return &ReservationStore{db: db}
+}
+
+// GetLocations returns every location of the tenant with its opening hours.
+func (s ReservationStore) GetLocations(ctx context.Context) ([]models.LocationHours, error) {
+	tracer := otel.Tracer("reservation-store")
+	ctx, span := tracer.Start(ctx, "GetLocations-Store")
+	defer span.End()
+	locations := []models.LocationHours{}
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return locations, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT name, time_zone FROM location WHERE tenant_id=$1 ORDER BY name`, tenantID)
+	if err != nil {
+		return locations, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		location := models.LocationHours{Hours: []models.OpeningHours{}}
+		if err := rows.Scan(&location.Location, &location.TimeZone); err != nil {
+			return locations, err
+		}
+		locations = append(locations, location)
+	}
+
+	if err := rows.Err(); err != nil {
+		return locations, err
+	}
+	rows.Close()
+
+	for i := range locations {
+		locations[i].Hours, err = openingHours(ctx, s.db, locations[i].Location, tenantID)
+		if err != nil {
+			return locations, err
+		}
+	}
+
+	return locations, nil
+}
+
+func (s ReservationStore) GetLocationHours(ctx context.Context, location string) (models.LocationHours, error) {
+	tracer := otel.Tracer("reservation-store")
+	ctx, span := tracer.Start(ctx, "GetLocationHours-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return models.LocationHours{}, err
+	}
+
+	return locationHours(ctx, s.db, location, tenantID)
+}
+
+// SetLocationHours creates the location if need be and replaces its time
+// zone and opening hours. Existing reservations are left as they are, even
+// if they now fall outside the opening hours.
+func (s ReservationStore) SetLocationHours(ctx context.Context, hours models.LocationHours) (models.LocationHours, error) {
+	tracer := otel.Tracer("reservation-store")
+	ctx, span := tracer.Start(ctx, "SetLocationHours-Store")
+	defer span.End()
+	var savedHours models.LocationHours
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return savedHours, err
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return savedHours, err
+	}
+
+	query := `INSERT INTO location (tenant_id, name, time_zone) VALUES ($1, $2, $3)
+		ON CONFLICT (tenant_id, name) DO UPDATE SET time_zone=excluded.time_zone`
+	_, err = tx.ExecContext(ctx, query, tenantID, hours.Location, hours.TimeZone)
+	if err != nil {
+		tx.Rollback()
+		return savedHours, err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM opening_hours WHERE tenant_id=$1 AND location=$2`, tenantID, hours.Location)
+	if err != nil {
+		tx.Rollback()
+		return savedHours, err
+	}
+
+	for _, period := range hours.Hours {
+		_, err = tx.ExecContext(ctx, `INSERT INTO opening_hours (tenant_id, location, weekday, opens, closes) VALUES ($1, $2, $3, $4, $5)`,
+			tenantID, hours.Location, period.Weekday, period.Opens, period.Closes)
+		if err != nil {
+			tx.Rollback()
+			return savedHours, err
+		}
+	}
+
+	savedHours, err = locationHours(ctx, tx, hours.Location, tenantID)
+	if err != nil {
+		tx.Rollback()
+		return savedHours, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return savedHours, err
+	}
+
+	return savedHours, nil
+}
+
+// CreateReservation books a test drive, failing with
+// store.ErrReservationConflict if it overlaps another booked test drive of
+// the car.
+func (s ReservationStore) CreateReservation(ctx context.Context, reservation models.Reservation) (models.Reservation, error) {
+	tracer := otel.Tracer("reservation-store")
+	ctx, span := tracer.Start(ctx, "CreateReservation-Store")
+	defer span.End()
+	var createdReservation models.Reservation
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return createdReservation, err
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return createdReservation, err
+	}
+
+	if err := lockCar(ctx, tx, reservation.CarID.String(), tenantID); err != nil {
+		tx.Rollback()
+		return createdReservation, err
+	}
+
+	name, err := store.CustomerName(ctx, tx, reservation.CustomerID, reservation.CustomerName, tenantID)
+	if err != nil {
+		tx.Rollback()
+		return createdReservation, err
+	}
+
+	id := uuid.New()
+	startsAt, endsAt := reservation.StartsAt.UTC(), reservation.EndsAt.UTC()
+	if err := checkOverlap(ctx, tx, reservation.CarID.String(), id, startsAt, endsAt, tenantID); err != nil {
+		tx.Rollback()
+		return createdReservation, err
+	}
+
+	now := time.Now().UTC()
+	query := `INSERT INTO reservation (id, tenant_id, car_id, location, customer_name, customer_id, notes, status, starts_at, ends_at, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+
+	_, err = tx.ExecContext(ctx, query,
+		id,
+		tenantID,
+		reservation.CarID,
+		reservation.Location,
+		name,
+		reservation.CustomerID,
+		reservation.Notes,
+		models.ReservationBooked,
+		startsAt,
+		endsAt,
+		auth.Actor(ctx),
+		now,
+		now)
+	if err != nil {
+		tx.Rollback()
+		if driver.IsExclusionViolation(err) {
+			return createdReservation, store.ErrReservationConflict
+		}
+		return createdReservation, err
+	}
+
+	createdReservation, err = scanReservation(tx.QueryRowContext(ctx, selectReservationQuery, id, tenantID))
+	if err != nil {
+		tx.Rollback()
+		return createdReservation, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return createdReservation, err
+	}
+
+	return createdReservation, nil
+}
+
+func (s ReservationStore) GetReservationById(ctx context.Context, reservationID string) (models.Reservation, error) {
+	tracer := otel.Tracer("reservation-store")
+	ctx, span := tracer.Start(ctx, "GetReservationById-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return models.Reservation{}, err
+	}
+
+	reservation, err := scanReservation(s.db.QueryRowContext(ctx, selectReservationQuery, reservationID, tenantID))
+	if err == sql.ErrNoRows {
+		return reservation, store.ErrReservationNotFound
+	}
+	return reservation, err
+}
+
+// GetReservationsByCar returns every reservation of the car, cancelled ones
+// included, by start time.
+func (s ReservationStore) GetReservationsByCar(ctx context.Context, carID string) ([]models.Reservation, error) {
+	tracer := otel.Tracer("reservation-store")
+	ctx, span := tracer.Start(ctx, "GetReservationsByCar-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkCar(ctx, s.db, carID, tenantID); err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + reservationColumns + ` FROM reservation WHERE car_id=$1 AND tenant_id=$2 ORDER BY starts_at, id`
+	return reservations(ctx, s.db, query, carID, tenantID)
+}
+
+// GetBookedReservations returns the booked reservations of the car that
+// overlap the period from `from` to `to`.
+func (s ReservationStore) GetBookedReservations(ctx context.Context, carID string, from, to time.Time) ([]models.Reservation, error) {
+	tracer := otel.Tracer("reservation-store")
+	ctx, span := tracer.Start(ctx, "GetBookedReservations-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkCar(ctx, s.db, carID, tenantID); err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + reservationColumns + ` FROM reservation
+		WHERE car_id=$1 AND tenant_id=$2 AND status='booked' AND starts_at < $4 AND ends_at > $3 ORDER BY starts_at, id`
+	return reservations(ctx, s.db, query, carID, tenantID, from.UTC(), to.UTC())
+}
+
+// RescheduleReservation moves a booked test drive to another time and
+// location, failing with store.ErrReservationConflict if it would overlap
+// another booked test drive of the car.
+func (s ReservationStore) RescheduleReservation(ctx context.Context, reservationID, location string, startsAt, endsAt time.Time) (models.Reservation, error) {
+	tracer := otel.Tracer("reservation-store")
+	ctx, span := tracer.Start(ctx, "RescheduleReservation-Store")
+	defer span.End()
+	var rescheduledReservation models.Reservation
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return rescheduledReservation, err
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return rescheduledReservation, err
+	}
+
+	reservation, err := lockReservation(ctx, tx, reservationID, tenantID)
+	if err != nil {
+		tx.Rollback()
+		return rescheduledReservation, err
+	}
+	if reservation.Status != models.ReservationBooked {
+		tx.Rollback()
+		return rescheduledReservation, store.ErrReservationCancelled
+	}
+
+	if err := lockCar(ctx, tx, reservation.CarID.String(), tenantID); err != nil {
+		tx.Rollback()
+		return rescheduledReservation, err
+	}
+
+	startsAt, endsAt = startsAt.UTC(), endsAt.UTC()
+	if err := checkOverlap(ctx, tx, reservation.CarID.String(), reservation.ID, startsAt, endsAt, tenantID); err != nil {
+		tx.Rollback()
+		return rescheduledReservation, err
+	}
+
+	query := `UPDATE reservation SET location=$3, starts_at=$4, ends_at=$5, updated_at=$6 WHERE id=$1 AND tenant_id=$2`
+	_, err = tx.ExecContext(ctx, query, reservationID, tenantID, location, startsAt, endsAt, time.Now().UTC())
+	if err != nil {
+		tx.Rollback()
+		if driver.IsExclusionViolation(err) {
+			return rescheduledReservation, store.ErrReservationConflict
+		}
+		return rescheduledReservation, err
+	}
+
+	rescheduledReservation, err = scanReservation(tx.QueryRowContext(ctx, selectReservationQuery, reservationID, tenantID))
+	if err != nil {
+		tx.Rollback()
+		return rescheduledReservation, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return rescheduledReservation, err
+	}
+
+	return rescheduledReservation, nil
+}
+
+// CancelReservation frees the time slot of a booked test drive. The
+// reservation is kept, with status cancelled.
+func (s ReservationStore) CancelReservation(ctx context.Context, reservationID string) (models.Reservation, error) {
+	tracer := otel.Tracer("reservation-store")
+	ctx, span := tracer.Start(ctx, "CancelReservation-Store")
+	defer span.End()
+	var cancelledReservation models.Reservation
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return cancelledReservation, err
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return cancelledReservation, err
+	}
+
+	reservation, err := lockReservation(ctx, tx, reservationID, tenantID)
+	if err != nil {
+		tx.Rollback()
+		return cancelledReservation, err
+	}
+	if reservation.Status != models.ReservationBooked {
+		tx.Rollback()
+		return cancelledReservation, store.ErrReservationCancelled
+	}
+
+	query := `UPDATE reservation SET status=$3, updated_at=$4 WHERE id=$1 AND tenant_id=$2`
+	_, err = tx.ExecContext(ctx, query, reservationID, tenantID, models.ReservationCancelled, time.Now().UTC())
+	if err != nil {
+		tx.Rollback()
+		return cancelledReservation, err
+	}
+
+	cancelledReservation, err = scanReservation(tx.QueryRowContext(ctx, selectReservationQuery, reservationID, tenantID))
+	if err != nil {
+		tx.Rollback()
+		return cancelledReservation, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return cancelledReservation, err
+	}
+
+	return cancelledReservation, nil
+}
+
+// lockCar checks that the car exists, locking its row on Postgres so that
+// bookings of the car are checked for overlaps one at a time. The exclusion
+// constraint on reservation would catch a missed overlap regardless.
+func lockCar(ctx context.Context, tx *driver.Tx, carID string, tenantID uuid.UUID) error {
+	query := `SELECT id FROM car WHERE id=$1 AND tenant_id=$2`
+	if tx.Dialect() == driver.Postgres {
+		query += ` FOR UPDATE`
+	}
+	var id uuid.UUID
+	err := tx.QueryRowContext(ctx, query, carID, tenantID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return store.ErrCarNotFound
+	}
+	return err
+}
+
+func checkCar(ctx context.Context, db store.Queryer, carID string, tenantID uuid.UUID) error {
+	var id uuid.UUID
+	err := db.QueryRowContext(ctx, `SELECT id FROM car WHERE id=$1 AND tenant_id=$2`, carID, tenantID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return store.ErrCarNotFound
+	}
+	return err
+}
+
+// lockReservation reads a reservation, locking its row on Postgres. SQLite
+// transactions already hold the database write lock.
+func lockReservation(ctx context.Context, tx *driver.Tx, reservationID string, tenantID uuid.UUID) (models.Reservation, error) {
+	query := selectReservationQuery
+	if tx.Dialect() == driver.Postgres {
+		query += ` FOR UPDATE`
+	}
+	reservation, err := scanReservation(tx.QueryRowContext(ctx, query, reservationID, tenantID))
+	if err == sql.ErrNoRows {
+		return reservation, store.ErrReservationNotFound
+	}
+	return reservation, err
+}
+
+func checkOverlap(ctx context.Context, tx *driver.Tx, carID string, reservationID uuid.UUID, startsAt, endsAt time.Time, tenantID uuid.UUID) error {
+	var overlapping int
+	err := tx.QueryRowContext(ctx, overlapQuery, carID, tenantID, startsAt, endsAt, reservationID).Scan(&overlapping)
+	if err != nil {
+		return err
+	}
+	if overlapping > 0 {
+		return store.ErrReservationConflict
+	}
+	return nil
+}
+
+func locationHours(ctx context.Context, db store.Queryer, location string, tenantID uuid.UUID) (models.LocationHours, error) {
+	hours := models.LocationHours{Location: location}
+	err := db.QueryRowContext(ctx, `SELECT time_zone FROM location WHERE tenant_id=$1 AND name=$2`, tenantID, location).Scan(&hours.TimeZone)
+	if err == sql.ErrNoRows {
+		return hours, store.ErrLocationNotFound
+	}
+	if err != nil {
+		return hours, err
+	}
+	hours.Hours, err = openingHours(ctx, db, location, tenantID)
+	return hours, err
+}
+
+func openingHours(ctx context.Context, db store.Queryer, location string, tenantID uuid.UUID) ([]models.OpeningHours, error) {
+	hours := []models.OpeningHours{}
+
+	query := `SELECT weekday, opens, closes FROM opening_hours WHERE tenant_id=$1 AND location=$2 ORDER BY weekday, opens`
+	rows, err := db.QueryContext(ctx, query, tenantID, location)
+	if err != nil {
+		return hours, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var period models.OpeningHours
+		if err := rows.Scan(&period.Weekday, &period.Opens, &period.Closes); err != nil {
+			return hours, err
+		}
+		hours = append(hours, period)
+	}
+
+	if err := rows.Err(); err != nil {
+		return hours, err
+	}
+
+	return hours, nil
+}
+
+func reservations(ctx context.Context, db store.Queryer, query string, args ...any) ([]models.Reservation, error) {
+	reservations := []models.Reservation{}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return reservations, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		reservation, err := scanReservation(rows)
+		if err != nil {
+			return reservations, err
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return reservations, err
+	}
+
+	return reservations, nil
+}
+
+func scanReservation(row store.Scanner) (models.Reservation, error) {
+	var reservation models.Reservation
+	err := row.Scan(&reservation.ID,
+		&reservation.CarID,
+		&reservation.Location,
+		&reservation.CustomerName,
+		&reservation.CustomerID,
+		&reservation.Notes,
+		&reservation.Status,
+		&reservation.StartsAt,
+		&reservation.EndsAt,
+		&reservation.CreatedBy,
+		&reservation.CreatedAt,
+		&reservation.UpdatedAt)
+	if err != nil {
+		return reservation, err
+	}
+	reservation.StartsAt = reservation.StartsAt.UTC()
+	reservation.EndsAt = reservation.EndsAt.UTC()
+	return reservation, nil
+}
@@ -0,0 +1,41 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+// Queryer is satisfied by both *driver.DB and *driver.Tx, so that the SQL
+// stores' helpers read the same way inside and outside a transaction.
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Scanner is satisfied by both *sql.Row and *sql.Rows.
+type Scanner interface {
+	Scan(dest ...any) error
+}
+
+// CustomerName checks that the customer, if any, exists and has not been
+// erased, and returns the name to record on a reservation or order: name, or
+// when that is empty the customer's own.
+func CustomerName(ctx context.Context, q Queryer, customerID uuid.NullUUID, name string, tenantID uuid.UUID) (string, error) {
+	if !customerID.Valid {
+		return name, nil
+	}
+	var customer string
+	err := q.QueryRowContext(ctx, `SELECT name FROM customer WHERE id=$1 AND tenant_id=$2 AND erased_at IS NULL`, customerID, tenantID).Scan(&customer)
+	if err == sql.ErrNoRows {
+		return name, ErrInvalidCustomer
+	}
+	if err != nil {
+		return name, err
+	}
+	if name == "" {
+		return customer, nil
+	}
+	return name, nil
+}
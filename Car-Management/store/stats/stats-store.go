@@ -0,0 +1,178 @@
+package stats
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/driver"
+	"github.com/nitesh111sinha/car-management/models"
+	"go.opentelemetry.io/otel"
+)
+
+// displacementBucket labels engine displacement (cc) ranges; ordering by the
+// smallest displacement in each bucket keeps them in ascending order.
+const displacementBucket = `CASE
+		WHEN e.displacement < 1000 THEN 'under 1000'
+		WHEN e.displacement < 1500 THEN '1000-1499'
+		WHEN e.displacement < 2000 THEN '1500-1999'
+		WHEN e.displacement < 3000 THEN '2000-2999'
+		WHEN e.displacement < 4000 THEN '3000-3999'
+		ELSE '4000 and over'
+	END`
+
+type StatsStore struct {
+	db *driver.DB
+}
+
+func NewStatsStore(db *driver.DB) *StatsStore {
+	return &StatsStore{db: db}
+}
+
+// GetCarStats computes every aggregate with one grouped query each.
+func (s StatsStore) GetCarStats(ctx context.Context, filter models.CarStatsFilter) (models.CarStats, error) {
+	tracer := otel.Tracer("stats-store")
+	ctx, span := tracer.Start(ctx, "GetCarStats-Store")
+	defer span.End()
+	var stats models.CarStats
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return stats, err
+	}
+	stats.Currency = models.BaseCurrency
+	where, args := whereClause(tenantID, filter)
+
+	overall, err := s.priceStats(ctx, `SELECT '', `+priceColumns+fromCars+where, args)
+	if err != nil {
+		return stats, err
+	}
+	if len(overall) == 1 {
+		stats.Overall = overall[0]
+	}
+
+	for _, group := range []struct {
+		column string
+		dst    *[]models.PriceStats
+	}{
+		{"c.brand", &stats.ByBrand},
+		{"c.fuel_type", &stats.ByFuelType},
+		{"c.year", &stats.ByYear},
+	} {
+		query := `SELECT ` + group.column + `, ` + priceColumns + fromCars + where +
+			` GROUP BY ` + group.column + ` ORDER BY ` + group.column
+		if *group.dst, err = s.priceStats(ctx, query, args); err != nil {
+			return stats, err
+		}
+	}
+
+	query := `SELECT e.no_of_cylinders, COUNT(*)` + fromCars + ` JOIN engine e ON e.id = c.engine_id` + where + combustionOnly +
+		` GROUP BY e.no_of_cylinders ORDER BY e.no_of_cylinders`
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return stats, err
+	}
+	defer rows.Close()
+	stats.ByCylinders = []models.CylinderCount{}
+	for rows.Next() {
+		var count models.CylinderCount
+		if err := rows.Scan(&count.Cylinders, &count.Count); err != nil {
+			return stats, err
+		}
+		stats.ByCylinders = append(stats.ByCylinders, count)
+	}
+	if err := rows.Err(); err != nil {
+		return stats, err
+	}
+
+	query = `SELECT ` + displacementBucket + ` AS bucket, COUNT(*)` + fromCars + ` JOIN engine e ON e.id = c.engine_id` + where + combustionOnly +
+		` GROUP BY bucket ORDER BY MIN(e.displacement)`
+	rows, err = s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return stats, err
+	}
+	defer rows.Close()
+	stats.ByDisplacement = []models.DisplacementBucket{}
+	for rows.Next() {
+		var bucket models.DisplacementBucket
+		if err := rows.Scan(&bucket.Bucket, &bucket.Count); err != nil {
+			return stats, err
+		}
+		stats.ByDisplacement = append(stats.ByDisplacement, bucket)
+	}
+	if err := rows.Err(); err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
+
+// combustionOnly keeps battery electric cars, which have neither cylinders
+// nor displacement, out of the engine breakdowns.
+const combustionOnly = ` AND e.type <> 'bev'`
+
+// fromCars joins each car to the exchange rate of its price's currency, so
+// that basePrice is the price in minor units of models.BaseCurrency. Cars
+// priced in a currency without an exchange rate are left out.
+const fromCars = ` FROM car c JOIN exchange_rate r ON r.currency = c.price_currency`
+
+const basePrice = `(c.price_amount * r.base_factor)`
+
+// priceColumns are the aggregates scanned by priceStats, after the group key.
+const priceColumns = `COUNT(*), COALESCE(CAST(ROUND(MIN(` + basePrice + `)) AS BIGINT), 0), COALESCE(CAST(ROUND(AVG(` + basePrice + `)) AS BIGINT), 0), COALESCE(CAST(ROUND(MAX(` + basePrice + `)) AS BIGINT), 0)`
+
+func (s StatsStore) priceStats(ctx context.Context, query string, args []any) ([]models.PriceStats, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []models.PriceStats{}
+	for rows.Next() {
+		var group models.PriceStats
+		if err := rows.Scan(&group.Key, &group.Count, &group.MinPrice, &group.AvgPrice, &group.MaxPrice); err != nil {
+			return nil, err
+		}
+		stats = append(stats, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// whereClause builds the tenant and filter conditions shared by every query.
+func whereClause(tenantID uuid.UUID, filter models.CarStatsFilter) (string, []any) {
+	conditions := []string{"c.tenant_id=$1"}
+	args := []any{tenantID}
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, condition+"$"+strconv.Itoa(len(args)))
+	}
+
+	// The brand may be the canonical name or any alias, in any case.
+	if filter.Brand != "" {
+		args = append(args, strings.TrimSpace(filter.Brand))
+		n := "$" + strconv.Itoa(len(args))
+		conditions = append(conditions, "c.brand_id IN (SELECT id FROM brand WHERE tenant_id=$1 AND lower(name)=lower("+n+") UNION SELECT brand_id FROM brand_alias WHERE tenant_id=$1 AND lower(alias)=lower("+n+"))")
+	}
+	if filter.FuelType != "" {
+		add("c.fuel_type=", filter.FuelType)
+	}
+	// Years are stored as four-digit strings, so they compare correctly as text.
+	if filter.YearFrom != "" {
+		add("c.year>=", filter.YearFrom)
+	}
+	if filter.YearTo != "" {
+		add("c.year<=", filter.YearTo)
+	}
+	if filter.MinPrice != nil {
+		add(basePrice+">=", models.MinorUnits(*filter.MinPrice, models.BaseCurrency))
+	}
+	if filter.MaxPrice != nil {
+		add(basePrice+"<=", models.MinorUnits(*filter.MaxPrice, models.BaseCurrency))
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
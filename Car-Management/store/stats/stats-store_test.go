@@ -0,0 +1,94 @@
+package stats_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store/brand"
+	"github.com/nitesh111sinha/car-management/store/exchangerate"
+	"github.com/nitesh111sinha/car-management/store/stats"
+	"github.com/nitesh111sinha/car-management/store/storetest"
+)
+
+// setup lists four cars: two Volkswagens (brand alias VW), a Honda priced in
+// EUR at two US dollars to the euro, and a Toyota priced in GBP, which has no
+// exchange rate.
+func setup(t *testing.T) (context.Context, *stats.StatsStore) {
+	t.Helper()
+	db := storetest.OpenSQLite(t)
+	s := storetest.SQLStores(db)
+	ctx := storetest.NewTenant(t, s)
+
+	if _, err := brand.NewBrandStore(db).CreateBrand(ctx, models.Brand{Name: "Volkswagen", Aliases: []string{"VW"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := exchangerate.NewExchangeRateStore(db).SetExchangeRate(ctx, models.ExchangeRate{Currency: "EUR", Rate: 0.5}); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := storetest.NewEngine(ctx, t, s)
+	for _, car := range []models.Car{
+		{Name: "Golf", Year: "2019", Brand: "vw", FuelType: "Petrol", Price: models.Money{Amount: 2000000, Currency: "USD"}},
+		{Name: "Passat", Year: "2022", Brand: "Volkswagen", FuelType: "Diesel", Price: models.Money{Amount: 3000000, Currency: "USD"}},
+		{Name: "Civic", Year: "2023", Brand: "Honda", FuelType: "Petrol", Price: models.Money{Amount: 1200000, Currency: "EUR"}},
+		{Name: "Corolla", Year: "2023", Brand: "Toyota", FuelType: "Petrol", Price: models.Money{Amount: 1000000, Currency: "GBP"}},
+	} {
+		car.Engine = models.Engine{EngineID: engine.EngineID}
+		if _, err := s.Cars.CreateCar(ctx, car); err != nil {
+			t.Fatalf("CreateCar %s: %v", car.Name, err)
+		}
+	}
+	return ctx, stats.NewStatsStore(db)
+}
+
+func price(p float64) *float64 { return &p }
+
+func TestGetCarStats(t *testing.T) {
+	ctx, statsStore := setup(t)
+
+	got, err := statsStore.GetCarStats(ctx, models.CarStatsFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := models.PriceStats{Count: 3, MinPrice: 2000000, AvgPrice: 2466667, MaxPrice: 3000000}
+	if got.Currency != "USD" || got.Overall != want {
+		t.Errorf("overall = %s %+v, want USD %+v", got.Currency, got.Overall, want)
+	}
+	if len(got.ByBrand) != 2 || got.ByBrand[1].Key != "Volkswagen" || got.ByBrand[1].Count != 2 {
+		t.Errorf("by brand = %+v, want Honda and two Volkswagens", got.ByBrand)
+	}
+	if len(got.ByCylinders) != 1 || got.ByCylinders[0] != (models.CylinderCount{Cylinders: 4, Count: 3}) {
+		t.Errorf("by cylinders = %+v, want three four-cylinder cars", got.ByCylinders)
+	}
+}
+
+func TestGetCarStatsFilters(t *testing.T) {
+	ctx, statsStore := setup(t)
+
+	tests := []struct {
+		name   string
+		filter models.CarStatsFilter
+		want   int
+	}{
+		{"brand", models.CarStatsFilter{Brand: "Volkswagen"}, 2},
+		{"brand ignoring case", models.CarStatsFilter{Brand: "VOLKSWAGEN"}, 2},
+		{"brand alias", models.CarStatsFilter{Brand: "vw"}, 2},
+		{"unknown brand", models.CarStatsFilter{Brand: "Skoda"}, 0},
+		{"fuel type", models.CarStatsFilter{FuelType: "Petrol"}, 2},
+		{"years", models.CarStatsFilter{YearFrom: "2020", YearTo: "2022"}, 1},
+		{"converted price", models.CarStatsFilter{MinPrice: price(24000), MaxPrice: price(24000)}, 1},
+		{"price range", models.CarStatsFilter{MaxPrice: price(25000)}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := statsStore.GetCarStats(ctx, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Overall.Count != tt.want {
+				t.Errorf("count = %d, want %d", got.Overall.Count, tt.want)
+			}
+		})
+	}
+}
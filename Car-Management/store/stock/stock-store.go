@@ -0,0 +1,289 @@
+package stock
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/driver"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+const selectUnitQuery = `SELECT id, car_id, vin, colour, location, lot, status, created_at, updated_at FROM stock_unit WHERE id=$1 AND tenant_id=$2`
+
+type StockStore struct {
+	db *driver.DB
+}
+
+func NewStockStore(db *driver.DB) *StockStore {
+	return &StockStore{db: db}
+}
+
+func (s StockStore) ReceiveUnit(ctx context.Context, unit models.StockUnit) (models.StockUnit, error) {
+	tracer := otel.Tracer("stock-store")
+	ctx, span := tracer.Start(ctx, "ReceiveUnit-Store")
+	defer span.End()
+	var receivedUnit models.StockUnit
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return receivedUnit, err
+	}
+
+	createdAt := time.Now()
+	newUnit := models.StockUnit{
+		ID:        uuid.New(),
+		CarID:     unit.CarID,
+		VIN:       unit.VIN,
+		Colour:    unit.Colour,
+		Location:  unit.Location,
+		Lot:       unit.Lot,
+		Status:    models.StockInStock,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return receivedUnit, err
+	}
+
+	var id uuid.UUID
+	err = tx.QueryRowContext(ctx, `SELECT id FROM car WHERE id=$1 AND tenant_id=$2`, unit.CarID, tenantID).Scan(&id)
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return receivedUnit, store.ErrCarNotFound
+		}
+		return receivedUnit, err
+	}
+
+	err = tx.QueryRowContext(ctx, `SELECT id FROM stock_unit WHERE vin=$1 AND tenant_id=$2`, unit.VIN, tenantID).Scan(&id)
+	if err == nil {
+		tx.Rollback()
+		return receivedUnit, store.ErrDuplicateVIN
+	}
+	if err != sql.ErrNoRows {
+		tx.Rollback()
+		return receivedUnit, err
+	}
+
+	query := `INSERT INTO stock_unit (id, tenant_id, car_id, vin, colour, location, lot, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err = tx.ExecContext(ctx, query,
+		newUnit.ID,
+		tenantID,
+		newUnit.CarID,
+		newUnit.VIN,
+		newUnit.Colour,
+		newUnit.Location,
+		newUnit.Lot,
+		newUnit.Status,
+		newUnit.CreatedAt,
+		newUnit.UpdatedAt)
+	if err != nil {
+		tx.Rollback()
+		return receivedUnit, err
+	}
+
+	receivedUnit, err = scanUnit(tx.QueryRowContext(ctx, selectUnitQuery, newUnit.ID, tenantID))
+	if err != nil {
+		tx.Rollback()
+		return receivedUnit, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return receivedUnit, err
+	}
+
+	return receivedUnit, nil
+}
+
+func (s StockStore) GetUnitById(ctx context.Context, unitID string) (models.StockUnit, error) {
+	tracer := otel.Tracer("stock-store")
+	ctx, span := tracer.Start(ctx, "GetUnitById-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return models.StockUnit{}, err
+	}
+
+	unit, err := scanUnit(s.db.QueryRowContext(ctx, selectUnitQuery, unitID, tenantID))
+	if err == sql.ErrNoRows {
+		return unit, store.ErrStockUnitNotFound
+	}
+	return unit, err
+}
+
+func (s StockStore) GetUnitsByCar(ctx context.Context, carID string) ([]models.StockUnit, error) {
+	tracer := otel.Tracer("stock-store")
+	ctx, span := tracer.Start(ctx, "GetUnitsByCar-Store")
+	defer span.End()
+	var units []models.StockUnit
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return units, err
+	}
+
+	query := `SELECT id, car_id, vin, colour, location, lot, status, created_at, updated_at FROM stock_unit WHERE car_id=$1 AND tenant_id=$2 ORDER BY created_at, id`
+
+	rows, err := s.db.QueryContext(ctx, query, carID, tenantID)
+	if err != nil {
+		return units, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var unit models.StockUnit
+		err := rows.Scan(&unit.ID,
+			&unit.CarID,
+			&unit.VIN,
+			&unit.Colour,
+			&unit.Location,
+			&unit.Lot,
+			&unit.Status,
+			&unit.CreatedAt,
+			&unit.UpdatedAt)
+		if err != nil {
+			return units, err
+		}
+		units = append(units, unit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return units, err
+	}
+
+	return units, nil
+}
+
+// MoveUnit changes where a unit is parked. Sold units have left the
+// dealership and cannot be moved.
+func (s StockStore) MoveUnit(ctx context.Context, unitID, location, lot string) (models.StockUnit, error) {
+	tracer := otel.Tracer("stock-store")
+	ctx, span := tracer.Start(ctx, "MoveUnit-Store")
+	defer span.End()
+
+	query := `UPDATE stock_unit SET location=$3, lot=$4, updated_at=$5 WHERE id=$1 AND tenant_id=$2 AND status <> 'sold'`
+	return s.update(ctx, unitID, query, location, lot, time.Now())
+}
+
+// SellUnit marks an in-stock unit as sold. A reserved unit belongs to its
+// order, which sells it on delivery.
+func (s StockStore) SellUnit(ctx context.Context, unitID string) (models.StockUnit, error) {
+	tracer := otel.Tracer("stock-store")
+	ctx, span := tracer.Start(ctx, "SellUnit-Store")
+	defer span.End()
+
+	query := `UPDATE stock_unit SET status='sold', updated_at=$3 WHERE id=$1 AND tenant_id=$2 AND status='in_stock'`
+	return s.update(ctx, unitID, query, time.Now())
+}
+
+func (s StockStore) GetAvailability(ctx context.Context, carID string) (models.Availability, error) {
+	tracer := otel.Tracer("stock-store")
+	ctx, span := tracer.Start(ctx, "GetAvailability-Store")
+	defer span.End()
+	var availability models.Availability
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return availability, err
+	}
+
+	query := `SELECT status, COUNT(*) FROM stock_unit WHERE car_id=$1 AND tenant_id=$2 GROUP BY status`
+
+	rows, err := s.db.QueryContext(ctx, query, carID, tenantID)
+	if err != nil {
+		return availability, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return availability, err
+		}
+		switch status {
+		case models.StockInStock:
+			availability.InStock = count
+		case models.StockReserved:
+			availability.Reserved = count
+		case models.StockSold:
+			availability.Sold = count
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return availability, err
+	}
+
+	return availability, nil
+}
+
+// update runs a conditional UPDATE whose first two parameters are the unit id
+// and tenant, followed by args. When no row matches it tells a missing unit
+// apart from one whose status rules out the change.
+func (s StockStore) update(ctx context.Context, unitID, query string, args ...any) (models.StockUnit, error) {
+	var updatedUnit models.StockUnit
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return updatedUnit, err
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return updatedUnit, err
+	}
+
+	result, err := tx.ExecContext(ctx, query, append([]any{unitID, tenantID}, args...)...)
+	if err != nil {
+		tx.Rollback()
+		return updatedUnit, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return updatedUnit, err
+	}
+
+	updatedUnit, err = scanUnit(tx.QueryRowContext(ctx, selectUnitQuery, unitID, tenantID))
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return updatedUnit, store.ErrStockUnitNotFound
+		}
+		return updatedUnit, err
+	}
+	if rowsAffected == 0 {
+		tx.Rollback()
+		return models.StockUnit{}, store.ErrInvalidStockTransition
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return updatedUnit, err
+	}
+
+	return updatedUnit, nil
+}
+
+// scanUnit reads a row produced by selectUnitQuery.
+func scanUnit(row *sql.Row) (models.StockUnit, error) {
+	var unit models.StockUnit
+	err := row.Scan(&unit.ID,
+		&unit.CarID,
+		&unit.VIN,
+		&unit.Colour,
+		&unit.Location,
+		&unit.Lot,
+		&unit.Status,
+		&unit.CreatedAt,
+		&unit.UpdatedAt)
+	return unit, err
+}
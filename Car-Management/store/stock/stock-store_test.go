@@ -0,0 +1,109 @@
+package stock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"github.com/nitesh111sinha/car-management/store/stock"
+	"github.com/nitesh111sinha/car-management/store/storetest"
+)
+
+func setup(t *testing.T) (context.Context, *stock.StockStore, models.Car, storetest.Stores) {
+	t.Helper()
+	db := storetest.OpenSQLite(t)
+	s := storetest.SQLStores(db)
+	ctx := storetest.NewTenant(t, s)
+	car := storetest.NewCar(ctx, t, s, "Honda", storetest.NewEngine(ctx, t, s))
+	return ctx, stock.NewStockStore(db), car, s
+}
+
+func receive(ctx context.Context, t *testing.T, units *stock.StockStore, carID uuid.UUID, vin string) models.StockUnit {
+	t.Helper()
+	unit, err := units.ReceiveUnit(ctx, models.StockUnit{CarID: carID, VIN: vin, Colour: "Red", Location: "Main", Lot: "A1"})
+	if err != nil {
+		t.Fatalf("ReceiveUnit: %v", err)
+	}
+	return unit
+}
+
+func TestReceiveUnit(t *testing.T) {
+	ctx, units, car, s := setup(t)
+
+	unit := receive(ctx, t, units, car.ID, "1HGCM82633A004352")
+	if unit.Status != models.StockInStock || unit.CarID != car.ID {
+		t.Errorf("unit = %+v, want an in-stock unit of the car", unit)
+	}
+
+	if _, err := units.ReceiveUnit(ctx, models.StockUnit{CarID: car.ID, VIN: "1HGCM82633A004352"}); !errors.Is(err, store.ErrDuplicateVIN) {
+		t.Errorf("receiving the VIN again = %v, want ErrDuplicateVIN", err)
+	}
+	if _, err := units.ReceiveUnit(ctx, models.StockUnit{CarID: uuid.New(), VIN: "1M8GDM9AXKP042788"}); !errors.Is(err, store.ErrCarNotFound) {
+		t.Errorf("receiving for an unknown car = %v, want ErrCarNotFound", err)
+	}
+	if _, err := units.ReceiveUnit(storetest.NewTenant(t, s), models.StockUnit{CarID: car.ID, VIN: "1M8GDM9AXKP042788"}); !errors.Is(err, store.ErrCarNotFound) {
+		t.Errorf("receiving for another tenant's car = %v, want ErrCarNotFound", err)
+	}
+}
+
+func TestMoveAndSellUnit(t *testing.T) {
+	ctx, units, car, _ := setup(t)
+	unit := receive(ctx, t, units, car.ID, "1HGCM82633A004352")
+
+	moved, err := units.MoveUnit(ctx, unit.ID.String(), "Annex", "B7")
+	if err != nil {
+		t.Fatalf("MoveUnit: %v", err)
+	}
+	if moved.Location != "Annex" || moved.Lot != "B7" {
+		t.Errorf("moved unit = %+v, want Annex B7", moved)
+	}
+
+	sold, err := units.SellUnit(ctx, unit.ID.String())
+	if err != nil {
+		t.Fatalf("SellUnit: %v", err)
+	}
+	if sold.Status != models.StockSold {
+		t.Errorf("status = %q, want sold", sold.Status)
+	}
+
+	if _, err := units.SellUnit(ctx, unit.ID.String()); !errors.Is(err, store.ErrInvalidStockTransition) {
+		t.Errorf("selling a sold unit = %v, want ErrInvalidStockTransition", err)
+	}
+	if _, err := units.MoveUnit(ctx, unit.ID.String(), "Main", "A1"); !errors.Is(err, store.ErrInvalidStockTransition) {
+		t.Errorf("moving a sold unit = %v, want ErrInvalidStockTransition", err)
+	}
+	if _, err := units.SellUnit(ctx, uuid.NewString()); !errors.Is(err, store.ErrStockUnitNotFound) {
+		t.Errorf("selling an unknown unit = %v, want ErrStockUnitNotFound", err)
+	}
+	if _, err := units.GetUnitById(ctx, uuid.NewString()); !errors.Is(err, store.ErrStockUnitNotFound) {
+		t.Errorf("getting an unknown unit = %v, want ErrStockUnitNotFound", err)
+	}
+}
+
+func TestGetAvailability(t *testing.T) {
+	ctx, units, car, _ := setup(t)
+	receive(ctx, t, units, car.ID, "1HGCM82633A004352")
+	sold := receive(ctx, t, units, car.ID, "1M8GDM9AXKP042788")
+	if _, err := units.SellUnit(ctx, sold.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+
+	availability, err := units.GetAvailability(ctx, car.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (models.Availability{InStock: 1, Sold: 1}); availability != want {
+		t.Errorf("availability = %+v, want %+v", availability, want)
+	}
+
+	listed, err := units.GetUnitsByCar(ctx, car.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 2 {
+		t.Errorf("listed %d units, want 2", len(listed))
+	}
+}
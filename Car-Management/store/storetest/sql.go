@@ -0,0 +1,51 @@
+package storetest
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/nitesh111sinha/car-management/config"
+	"github.com/nitesh111sinha/car-management/driver"
+	carStore "github.com/nitesh111sinha/car-management/store/car"
+	engineStore "github.com/nitesh111sinha/car-management/store/engine"
+	"github.com/nitesh111sinha/car-management/store/migrations"
+	tenantStore "github.com/nitesh111sinha/car-management/store/tenant"
+)
+
+// OpenDB connects to a freshly migrated database and closes it when the test
+// ends. Migrating twice checks that already applied migrations are skipped.
+func OpenDB(t *testing.T, cfg config.Database) *driver.DB {
+	t.Helper()
+	ctx := context.Background()
+	db, err := driver.InitDB(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	for range 2 {
+		if err := migrations.Migrate(ctx, db); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+// OpenSQLite opens a migrated SQLite database in a directory of its own, for
+// the tests of the SQL stores.
+func OpenSQLite(t *testing.T) *driver.DB {
+	t.Helper()
+	cfg := config.Default().Database
+	cfg.Driver = "sqlite"
+	cfg.SQLitePath = filepath.Join(t.TempDir(), "car-management.db")
+	return OpenDB(t, cfg)
+}
+
+// SQLStores returns the SQL car, engine and tenant stores of db.
+func SQLStores(db *driver.DB) Stores {
+	return Stores{
+		Cars:    carStore.NewCarStore(db),
+		Engines: engineStore.NewEngineStore(db),
+		Tenants: tenantStore.NewTenantStore(db),
+	}
+}
@@ -0,0 +1,759 @@
+// Package storetest is a conformance suite for implementations of
+// store.CarStoreInterface, store.EngineStoreInterface and
+// store.TenantStoreInterface. Every backend calls Run from its own tests so
+// that all of them agree on the same semantics.
+//
+// Each sub-test runs in a tenant of its own and only asserts on rows it
+// created itself, so the suite can run against a database that already
+// contains data.
+package storetest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+)
+
+// Stores is a car store, an engine store and a tenant store backed by the
+// same database.
+type Stores struct {
+	Cars    store.CarStoreInterface
+	Engines store.EngineStoreInterface
+	Tenants store.TenantStoreInterface
+}
+
+// Factory returns the stores under test. It is called once per sub-test.
+type Factory func(t *testing.T) Stores
+
+// Run executes the full conformance suite against the stores returned by
+// newStores.
+func Run(t *testing.T, newStores Factory) {
+	tests := []struct {
+		name string
+		fn   func(ctx context.Context, t *testing.T, s Stores)
+	}{
+		{"CreateEngine", testCreateEngine},
+		{"UpdateEngine", testUpdateEngine},
+		{"EngineNotFound", testEngineNotFound},
+		{"ElectricPowertrains", testElectricPowertrains},
+		{"CreateCar", testCreateCar},
+		{"CreateCarRequiresEngine", testCreateCarRequiresEngine},
+		{"FuelTypeMustFitPowertrain", testFuelTypeMustFitPowertrain},
+		{"GetCarByIdNotFound", testGetCarByIdNotFound},
+		{"GetCars", testGetCars},
+		{"GetCarsByIds", testGetCarsByIds},
+		{"GetCarsWithEngines", testGetCarsWithEngines},
+		{"GetCarByBrand", testGetCarByBrand},
+		{"GetCarByBrandIgnoresCase", testGetCarByBrandIgnoresCase},
+		{"UpdateCar", testUpdateCar},
+		{"UpdateCarNotFound", testUpdateCarNotFound},
+		{"CarVIN", testCarVIN},
+		{"DeleteCar", testDeleteCar},
+		{"DeleteEngineInUse", testDeleteEngineInUse},
+		{"DeleteEngineCascades", testDeleteEngineCascades},
+		{"TenantIsolation", testTenantIsolation},
+		{"Tenants", testTenants},
+		{"DeleteTenantInUse", testDeleteTenantInUse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newStores(t)
+			tt.fn(NewTenant(t, s), t, s)
+		})
+	}
+}
+
+// NewTenant creates a tenant and returns a context scoped to it.
+func NewTenant(t *testing.T, s Stores) context.Context {
+	t.Helper()
+	tenant, err := s.Tenants.CreateTenant(context.Background(), models.Tenant{Name: "tenant-" + uuid.NewString()})
+	if err != nil {
+		t.Fatalf("CreateTenant: %v", err)
+	}
+	return auth.WithTenant(context.Background(), tenant.ID)
+}
+
+// NewEngine creates a four-cylinder combustion engine.
+func NewEngine(ctx context.Context, t *testing.T, s Stores) models.Engine {
+	t.Helper()
+	engine, err := s.Engines.CreateEngine(ctx, models.Engine{
+		Displacement:  2000,
+		NoOfCylinders: 4,
+		CarRange:      600,
+	})
+	if err != nil {
+		t.Fatalf("CreateEngine: %v", err)
+	}
+	return engine
+}
+
+// NewCar creates a petrol car of brand priced at 25,000 USD.
+func NewCar(ctx context.Context, t *testing.T, s Stores, brand string, engine models.Engine) models.Car {
+	t.Helper()
+	car, err := s.Cars.CreateCar(ctx, models.Car{
+		Name:     "Civic",
+		Year:     "2023",
+		Brand:    brand,
+		FuelType: "Petrol",
+		Engine:   models.Engine{EngineID: engine.EngineID},
+		Price:    models.Money{Amount: 2500000, Currency: "USD"},
+	})
+	if err != nil {
+		t.Fatalf("CreateCar: %v", err)
+	}
+	return car
+}
+
+// uniqueBrand keeps brand queries isolated from data left by other tests.
+func uniqueBrand() string {
+	return "brand-" + uuid.NewString()
+}
+
+func testCreateEngine(ctx context.Context, t *testing.T, s Stores) {
+	created := NewEngine(ctx, t, s)
+	if created.EngineID == uuid.Nil {
+		t.Fatal("CreateEngine returned a nil id")
+	}
+
+	got, err := s.Engines.GetEngineById(ctx, created.EngineID.String())
+	if err != nil {
+		t.Fatalf("GetEngineById: %v", err)
+	}
+	if got != created {
+		t.Errorf("GetEngineById = %+v, want %+v", got, created)
+	}
+
+	engines, err := s.Engines.GetEngines(ctx)
+	if err != nil {
+		t.Fatalf("GetEngines: %v", err)
+	}
+	if !containsEngine(engines, created.EngineID) {
+		t.Errorf("GetEngines does not include engine %s", created.EngineID)
+	}
+}
+
+func testUpdateEngine(ctx context.Context, t *testing.T, s Stores) {
+	created := NewEngine(ctx, t, s)
+
+	updated, err := s.Engines.UpdateEngine(ctx, created.EngineID.String(), models.Engine{
+		Displacement:  3000,
+		NoOfCylinders: 6,
+		CarRange:      700,
+	})
+	if err != nil {
+		t.Fatalf("UpdateEngine: %v", err)
+	}
+	want := models.Engine{EngineID: created.EngineID, Type: models.PowertrainICE, Displacement: 3000, NoOfCylinders: 6, CarRange: 700}
+	if updated != want {
+		t.Errorf("UpdateEngine = %+v, want %+v", updated, want)
+	}
+
+	got, err := s.Engines.GetEngineById(ctx, created.EngineID.String())
+	if err != nil {
+		t.Fatalf("GetEngineById: %v", err)
+	}
+	if got != want {
+		t.Errorf("GetEngineById after update = %+v, want %+v", got, want)
+	}
+}
+
+func testEngineNotFound(ctx context.Context, t *testing.T, s Stores) {
+	missing := uuid.NewString()
+
+	if _, err := s.Engines.GetEngineById(ctx, missing); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetEngineById(missing) error = %v, want sql.ErrNoRows", err)
+	}
+	if _, err := s.Engines.UpdateEngine(ctx, missing, models.Engine{Displacement: 1, NoOfCylinders: 1, CarRange: 1}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("UpdateEngine(missing) error = %v, want sql.ErrNoRows", err)
+	}
+	if err := s.Engines.DeleteEngine(ctx, missing, false); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("DeleteEngine(missing) error = %v, want sql.ErrNoRows", err)
+	}
+	if _, err := s.Engines.GetEngineCars(ctx, missing); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetEngineCars(missing) error = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func testElectricPowertrains(ctx context.Context, t *testing.T, s Stores) {
+	bev, err := s.Engines.CreateEngine(ctx, models.Engine{
+		Type:     models.PowertrainBEV,
+		CarRange: 450,
+		Motor:    &models.ElectricMotor{BatteryKWh: 75, ChargingPowerKW: 170, EfficiencyWhPerKm: 160},
+	})
+	if err != nil {
+		t.Fatalf("CreateEngine(bev): %v", err)
+	}
+	got, err := s.Engines.GetEngineById(ctx, bev.EngineID.String())
+	if err != nil {
+		t.Fatalf("GetEngineById: %v", err)
+	}
+	if !reflect.DeepEqual(got, bev) || got.Type != models.PowertrainBEV || got.Motor == nil || got.Motor.BatteryKWh != 75 {
+		t.Errorf("GetEngineById(bev) = %+v, want %+v", got, bev)
+	}
+
+	hybrid, err := s.Engines.UpdateEngine(ctx, bev.EngineID.String(), models.Engine{
+		Type:          models.PowertrainHybrid,
+		Displacement:  1800,
+		NoOfCylinders: 4,
+		CarRange:      900,
+		Motor:         &models.ElectricMotor{BatteryKWh: 1.3, EfficiencyWhPerKm: 120},
+	})
+	if err != nil {
+		t.Fatalf("UpdateEngine(hybrid): %v", err)
+	}
+	want := models.Engine{
+		EngineID:      bev.EngineID,
+		Type:          models.PowertrainHybrid,
+		Displacement:  1800,
+		NoOfCylinders: 4,
+		CarRange:      900,
+		Motor:         &models.ElectricMotor{BatteryKWh: 1.3, EfficiencyWhPerKm: 120},
+	}
+	if !reflect.DeepEqual(hybrid, want) {
+		t.Errorf("UpdateEngine(hybrid) = %+v, want %+v", hybrid, want)
+	}
+
+	ice, err := s.Engines.UpdateEngine(ctx, bev.EngineID.String(), models.Engine{Displacement: 2000, NoOfCylinders: 4, CarRange: 600})
+	if err != nil {
+		t.Fatalf("UpdateEngine(ice): %v", err)
+	}
+	if ice.Type != models.PowertrainICE || ice.Motor != nil {
+		t.Errorf("UpdateEngine(ice) = %+v, want an ice engine without motor", ice)
+	}
+}
+
+func testFuelTypeMustFitPowertrain(ctx context.Context, t *testing.T, s Stores) {
+	ice := NewEngine(ctx, t, s)
+	bev, err := s.Engines.CreateEngine(ctx, models.Engine{
+		Type:     models.PowertrainBEV,
+		CarRange: 450,
+		Motor:    &models.ElectricMotor{BatteryKWh: 75, ChargingPowerKW: 170, EfficiencyWhPerKm: 160},
+	})
+	if err != nil {
+		t.Fatalf("CreateEngine(bev): %v", err)
+	}
+
+	electric := models.Car{
+		Name:     "Model 3",
+		Year:     "2023",
+		Brand:    uniqueBrand(),
+		FuelType: "Electric",
+		Engine:   models.Engine{EngineID: ice.EngineID},
+		Price:    models.Money{Amount: 4000000, Currency: "USD"},
+	}
+	if _, err := s.Cars.CreateCar(ctx, electric); !errors.Is(err, store.ErrPowertrainMismatch) {
+		t.Errorf("CreateCar(Electric with ice engine) error = %v, want store.ErrPowertrainMismatch", err)
+	}
+
+	electric.Engine.EngineID = bev.EngineID
+	created, err := s.Cars.CreateCar(ctx, electric)
+	if err != nil {
+		t.Fatalf("CreateCar(Electric with bev engine): %v", err)
+	}
+	got, err := s.Cars.GetCarById(ctx, created.ID.String())
+	if err != nil {
+		t.Fatalf("GetCarById: %v", err)
+	}
+	if !reflect.DeepEqual(got.Engine, bev) {
+		t.Errorf("GetCarById engine = %+v, want %+v", got.Engine, bev)
+	}
+
+	if _, err := s.Engines.UpdateEngine(ctx, bev.EngineID.String(), models.Engine{Displacement: 2000, NoOfCylinders: 4, CarRange: 600}); !errors.Is(err, store.ErrPowertrainMismatch) {
+		t.Errorf("UpdateEngine(bev to ice under an Electric car) error = %v, want store.ErrPowertrainMismatch", err)
+	}
+}
+
+func testCreateCar(ctx context.Context, t *testing.T, s Stores) {
+	engine := NewEngine(ctx, t, s)
+	brand := uniqueBrand()
+	created := NewCar(ctx, t, s, brand, engine)
+
+	if created.ID == uuid.Nil {
+		t.Fatal("CreateCar returned a nil id")
+	}
+	if created.Engine.EngineID != engine.EngineID {
+		t.Errorf("CreateCar engine id = %s, want %s", created.Engine.EngineID, engine.EngineID)
+	}
+	if created.CreatedAt.IsZero() || created.UpdatedAt.IsZero() {
+		t.Error("CreateCar did not set timestamps")
+	}
+
+	got, err := s.Cars.GetCarById(ctx, created.ID.String())
+	if err != nil {
+		t.Fatalf("GetCarById: %v", err)
+	}
+	if got.ID != created.ID || got.Name != "Civic" || got.Year != "2023" || got.Brand != brand ||
+		got.FuelType != "Petrol" || got.Price != (models.Money{Amount: 2500000, Currency: "USD"}) {
+		t.Errorf("GetCarById = %+v, want fields of %+v", got, created)
+	}
+	if got.Engine != engine {
+		t.Errorf("GetCarById engine = %+v, want full engine %+v", got.Engine, engine)
+	}
+}
+
+func testCreateCarRequiresEngine(ctx context.Context, t *testing.T, s Stores) {
+	_, err := s.Cars.CreateCar(ctx, models.Car{
+		Name:     "Civic",
+		Year:     "2023",
+		Brand:    uniqueBrand(),
+		FuelType: "Petrol",
+		Engine:   models.Engine{EngineID: uuid.New()},
+		Price:    models.Money{Amount: 2500000, Currency: "USD"},
+	})
+	if !errors.Is(err, store.ErrInvalidEngine) {
+		t.Errorf("CreateCar with unknown engine error = %v, want store.ErrInvalidEngine", err)
+	}
+}
+
+func testGetCarByIdNotFound(ctx context.Context, t *testing.T, s Stores) {
+	car, err := s.Cars.GetCarById(ctx, uuid.NewString())
+	if err != nil {
+		t.Fatalf("GetCarById(missing) error = %v, want nil", err)
+	}
+	if car.ID != uuid.Nil {
+		t.Errorf("GetCarById(missing) = %+v, want zero car", car)
+	}
+}
+
+func testGetCars(ctx context.Context, t *testing.T, s Stores) {
+	engine := NewEngine(ctx, t, s)
+	first := NewCar(ctx, t, s, uniqueBrand(), engine)
+	second := NewCar(ctx, t, s, uniqueBrand(), engine)
+
+	cars, err := s.Cars.GetCars(ctx)
+	if err != nil {
+		t.Fatalf("GetCars: %v", err)
+	}
+	for _, want := range []models.Car{first, second} {
+		got, ok := findCar(cars, want.ID)
+		if !ok {
+			t.Errorf("GetCars does not include car %s", want.ID)
+			continue
+		}
+		if got.Engine != (models.Engine{EngineID: engine.EngineID}) {
+			t.Errorf("GetCars engine = %+v, want only the engine id", got.Engine)
+		}
+	}
+}
+
+func testGetCarsByIds(ctx context.Context, t *testing.T, s Stores) {
+	engine := NewEngine(ctx, t, s)
+	first := NewCar(ctx, t, s, uniqueBrand(), engine)
+	second := NewCar(ctx, t, s, uniqueBrand(), engine)
+
+	cars, err := s.Cars.GetCarsByIds(ctx, []uuid.UUID{second.ID, uuid.New(), first.ID})
+	if err != nil {
+		t.Fatalf("GetCarsByIds: %v", err)
+	}
+	if len(cars) != 2 || cars[0].ID != second.ID || cars[1].ID != first.ID {
+		t.Fatalf("GetCarsByIds = %+v, want cars %s and %s in that order", cars, second.ID, first.ID)
+	}
+	for _, car := range cars {
+		if car.Engine != engine {
+			t.Errorf("GetCarsByIds engine = %+v, want %+v", car.Engine, engine)
+		}
+	}
+
+	none, err := s.Cars.GetCarsByIds(ctx, nil)
+	if err != nil {
+		t.Fatalf("GetCarsByIds(nil): %v", err)
+	}
+	if len(none) != 0 {
+		t.Errorf("GetCarsByIds(nil) = %+v, want no cars", none)
+	}
+}
+
+func testGetCarsWithEngines(ctx context.Context, t *testing.T, s Stores) {
+	engine := NewEngine(ctx, t, s)
+	first := NewCar(ctx, t, s, uniqueBrand(), engine)
+	second := NewCar(ctx, t, s, uniqueBrand(), engine)
+
+	cars, err := s.Cars.GetCarsWithEngines(ctx)
+	if err != nil {
+		t.Fatalf("GetCarsWithEngines: %v", err)
+	}
+	for _, want := range []models.Car{first, second} {
+		got, ok := findCar(cars, want.ID)
+		if !ok {
+			t.Errorf("GetCarsWithEngines does not include car %s", want.ID)
+			continue
+		}
+		if got.Engine != engine {
+			t.Errorf("GetCarsWithEngines engine = %+v, want %+v", got.Engine, engine)
+		}
+	}
+}
+
+func testGetCarByBrand(ctx context.Context, t *testing.T, s Stores) {
+	engine := NewEngine(ctx, t, s)
+	brand := uniqueBrand()
+	car := NewCar(ctx, t, s, brand, engine)
+	NewCar(ctx, t, s, uniqueBrand(), engine)
+
+	withEngine, err := s.Cars.GetCarByBrand(ctx, brand, true)
+	if err != nil {
+		t.Fatalf("GetCarByBrand(isEngine=true): %v", err)
+	}
+	if len(withEngine) != 1 || withEngine[0].ID != car.ID {
+		t.Fatalf("GetCarByBrand(isEngine=true) = %+v, want only car %s", withEngine, car.ID)
+	}
+	if withEngine[0].Engine != engine {
+		t.Errorf("GetCarByBrand(isEngine=true) engine = %+v, want %+v", withEngine[0].Engine, engine)
+	}
+
+	withoutEngine, err := s.Cars.GetCarByBrand(ctx, brand, false)
+	if err != nil {
+		t.Fatalf("GetCarByBrand(isEngine=false): %v", err)
+	}
+	if len(withoutEngine) != 1 || withoutEngine[0].Engine != (models.Engine{EngineID: engine.EngineID}) {
+		t.Errorf("GetCarByBrand(isEngine=false) = %+v, want one car with only the engine id", withoutEngine)
+	}
+
+	none, err := s.Cars.GetCarByBrand(ctx, uniqueBrand(), true)
+	if err != nil {
+		t.Fatalf("GetCarByBrand(unknown): %v", err)
+	}
+	if len(none) != 0 {
+		t.Errorf("GetCarByBrand(unknown) = %+v, want no cars", none)
+	}
+}
+
+func testGetCarByBrandIgnoresCase(ctx context.Context, t *testing.T, s Stores) {
+	engine := NewEngine(ctx, t, s)
+	brand := uniqueBrand()
+	car := NewCar(ctx, t, s, brand, engine)
+
+	cars, err := s.Cars.GetCarByBrand(ctx, strings.ToUpper(brand), false)
+	if err != nil {
+		t.Fatalf("GetCarByBrand: %v", err)
+	}
+	if len(cars) != 1 || cars[0].ID != car.ID {
+		t.Errorf("GetCarByBrand(%q) = %+v, want only car %s", strings.ToUpper(brand), cars, car.ID)
+	}
+}
+
+func testUpdateCar(ctx context.Context, t *testing.T, s Stores) {
+	engine := NewEngine(ctx, t, s)
+	other := NewEngine(ctx, t, s)
+	car := NewCar(ctx, t, s, uniqueBrand(), engine)
+
+	car.Name = "Accord"
+	car.Price = models.Money{Amount: 3000000, Currency: "EUR"}
+	car.Engine = models.Engine{EngineID: other.EngineID}
+	updated, err := s.Cars.UpdateCar(ctx, car)
+	if err != nil {
+		t.Fatalf("UpdateCar: %v", err)
+	}
+	if updated.ID != car.ID || updated.Name != "Accord" || updated.Price != (models.Money{Amount: 3000000, Currency: "EUR"}) || updated.Engine.EngineID != other.EngineID {
+		t.Errorf("UpdateCar = %+v, want updated fields", updated)
+	}
+	if updated.UpdatedAt.Before(updated.CreatedAt) {
+		t.Errorf("UpdateCar updated_at %v is before created_at %v", updated.UpdatedAt, updated.CreatedAt)
+	}
+
+	car.Engine = models.Engine{EngineID: uuid.New()}
+	if _, err := s.Cars.UpdateCar(ctx, car); !errors.Is(err, store.ErrInvalidEngine) {
+		t.Errorf("UpdateCar with unknown engine error = %v, want store.ErrInvalidEngine", err)
+	}
+}
+
+func testUpdateCarNotFound(ctx context.Context, t *testing.T, s Stores) {
+	engine := NewEngine(ctx, t, s)
+	_, err := s.Cars.UpdateCar(ctx, models.Car{
+		ID:       uuid.New(),
+		Name:     "Civic",
+		Year:     "2023",
+		Brand:    uniqueBrand(),
+		FuelType: "Petrol",
+		Engine:   models.Engine{EngineID: engine.EngineID},
+		Price:    models.Money{Amount: 2500000, Currency: "USD"},
+	})
+	if !errors.Is(err, store.ErrCarNotFound) {
+		t.Errorf("UpdateCar(missing) error = %v, want store.ErrCarNotFound", err)
+	}
+}
+
+func testCarVIN(ctx context.Context, t *testing.T, s Stores) {
+	engine := NewEngine(ctx, t, s)
+	// The VIN was issued to Honda, so only Honda cars may carry it.
+	const brand = "Honda"
+	const vin = "1HGCM82633A004352"
+
+	stranger := NewCar(ctx, t, s, uniqueBrand(), engine)
+	stranger.VIN = vin
+	var invalid *models.ValidationError
+	if _, err := s.Cars.UpdateCar(ctx, stranger); !errors.As(err, &invalid) {
+		t.Errorf("UpdateCar with another manufacturer's vin error = %v, want a *models.ValidationError", err)
+	}
+
+	car := NewCar(ctx, t, s, brand, engine)
+	car.VIN = vin
+	updated, err := s.Cars.UpdateCar(ctx, car)
+	if err != nil {
+		t.Fatalf("UpdateCar: %v", err)
+	}
+	if updated.VIN != vin {
+		t.Errorf("UpdateCar vin = %q, want %q", updated.VIN, vin)
+	}
+	got, err := s.Cars.GetCarByVIN(ctx, vin)
+	if err != nil {
+		t.Fatalf("GetCarByVIN: %v", err)
+	}
+	if got.ID != car.ID || got.Engine != engine {
+		t.Errorf("GetCarByVIN = %+v, want car %s with engine %+v", got, car.ID, engine)
+	}
+
+	// Cars without a VIN never conflict, but two cars cannot share one.
+	other := NewCar(ctx, t, s, brand, engine)
+	other.VIN = vin
+	if _, err := s.Cars.UpdateCar(ctx, other); !errors.Is(err, store.ErrDuplicateCarVIN) {
+		t.Errorf("UpdateCar with a taken vin error = %v, want store.ErrDuplicateCarVIN", err)
+	}
+	if _, err := s.Cars.CreateCar(ctx, other); !errors.Is(err, store.ErrDuplicateCarVIN) {
+		t.Errorf("CreateCar with a taken vin error = %v, want store.ErrDuplicateCarVIN", err)
+	}
+
+	// Clearing the VIN frees it; VINs are unique per tenant only.
+	car.VIN = ""
+	if _, err := s.Cars.UpdateCar(ctx, car); err != nil {
+		t.Fatalf("UpdateCar clearing the vin: %v", err)
+	}
+	if _, err := s.Cars.GetCarByVIN(ctx, vin); !errors.Is(err, store.ErrCarNotFound) {
+		t.Errorf("GetCarByVIN after clearing error = %v, want store.ErrCarNotFound", err)
+	}
+	if _, err := s.Cars.UpdateCar(ctx, other); err != nil {
+		t.Errorf("UpdateCar with a freed vin: %v", err)
+	}
+	otherTenant := NewTenant(t, s)
+	otherCar := NewCar(otherTenant, t, s, brand, NewEngine(otherTenant, t, s))
+	otherCar.VIN = vin
+	if _, err := s.Cars.UpdateCar(otherTenant, otherCar); err != nil {
+		t.Errorf("UpdateCar with another tenant's vin: %v", err)
+	}
+	if got, err := s.Cars.GetCarByVIN(ctx, vin); err != nil || got.ID != other.ID {
+		t.Errorf("GetCarByVIN = %+v, %v; want car %s", got, err, other.ID)
+	}
+}
+
+func testDeleteCar(ctx context.Context, t *testing.T, s Stores) {
+	engine := NewEngine(ctx, t, s)
+	car := NewCar(ctx, t, s, uniqueBrand(), engine)
+
+	if err := s.Cars.DeleteCar(ctx, car.ID.String()); err != nil {
+		t.Fatalf("DeleteCar: %v", err)
+	}
+	got, err := s.Cars.GetCarById(ctx, car.ID.String())
+	if err != nil || got.ID != uuid.Nil {
+		t.Errorf("GetCarById after delete = %+v, %v; want zero car", got, err)
+	}
+	if err := s.Cars.DeleteCar(ctx, car.ID.String()); !errors.Is(err, store.ErrCarNotFound) {
+		t.Errorf("DeleteCar twice error = %v, want store.ErrCarNotFound", err)
+	}
+	if _, err := s.Engines.GetEngineById(ctx, engine.EngineID.String()); err != nil {
+		t.Errorf("deleting a car removed its engine: %v", err)
+	}
+}
+
+func testDeleteEngineInUse(ctx context.Context, t *testing.T, s Stores) {
+	engine := NewEngine(ctx, t, s)
+	first := NewCar(ctx, t, s, uniqueBrand(), engine)
+	second := NewCar(ctx, t, s, uniqueBrand(), engine)
+	NewCar(ctx, t, s, uniqueBrand(), NewEngine(ctx, t, s))
+
+	cars, err := s.Engines.GetEngineCars(ctx, engine.EngineID.String())
+	if err != nil {
+		t.Fatalf("GetEngineCars: %v", err)
+	}
+	if len(cars) != 2 || cars[0].ID != first.ID || cars[1].ID != second.ID {
+		t.Errorf("GetEngineCars = %+v, want cars %s and %s", cars, first.ID, second.ID)
+	}
+
+	err = s.Engines.DeleteEngine(ctx, engine.EngineID.String(), false)
+	var inUse *store.EngineInUseError
+	if !errors.As(err, &inUse) || !errors.Is(err, store.ErrEngineInUse) {
+		t.Fatalf("DeleteEngine in use error = %v, want *store.EngineInUseError", err)
+	}
+	if len(inUse.Cars) != 2 {
+		t.Errorf("EngineInUseError cars = %+v, want 2 cars", inUse.Cars)
+	}
+	if _, err := s.Engines.GetEngineById(ctx, engine.EngineID.String()); err != nil {
+		t.Errorf("GetEngineById after refused delete: %v", err)
+	}
+	if got, err := s.Cars.GetCarById(ctx, first.ID.String()); err != nil || got.ID != first.ID {
+		t.Errorf("car %s after refused delete = %+v, %v", first.ID, got, err)
+	}
+
+	unused := NewEngine(ctx, t, s)
+	if err := s.Engines.DeleteEngine(ctx, unused.EngineID.String(), false); err != nil {
+		t.Errorf("DeleteEngine(unused): %v", err)
+	}
+}
+
+func testDeleteEngineCascades(ctx context.Context, t *testing.T, s Stores) {
+	engine := NewEngine(ctx, t, s)
+	brand := uniqueBrand()
+	car := NewCar(ctx, t, s, brand, engine)
+
+	if err := s.Engines.DeleteEngine(ctx, engine.EngineID.String(), true); err != nil {
+		t.Fatalf("DeleteEngine(cascade): %v", err)
+	}
+	if _, err := s.Engines.GetEngineById(ctx, engine.EngineID.String()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetEngineById after delete error = %v, want sql.ErrNoRows", err)
+	}
+	got, err := s.Cars.GetCarById(ctx, car.ID.String())
+	if err != nil || got.ID != uuid.Nil {
+		t.Errorf("car %s survived deleting its engine: %+v, %v", car.ID, got, err)
+	}
+	cars, err := s.Cars.GetCarByBrand(ctx, brand, false)
+	if err != nil {
+		t.Fatalf("GetCarByBrand: %v", err)
+	}
+	if len(cars) != 0 {
+		t.Errorf("GetCarByBrand after engine delete = %+v, want no cars", cars)
+	}
+}
+
+func testTenantIsolation(ctx context.Context, t *testing.T, s Stores) {
+	engine := NewEngine(ctx, t, s)
+	brand := uniqueBrand()
+	car := NewCar(ctx, t, s, brand, engine)
+	other := NewTenant(t, s)
+
+	if got, err := s.Cars.GetCarById(other, car.ID.String()); err != nil || got.ID != uuid.Nil {
+		t.Errorf("GetCarById from another tenant = %+v, %v; want zero car", got, err)
+	}
+	if cars, err := s.Cars.GetCars(other); err != nil || len(cars) != 0 {
+		t.Errorf("GetCars from another tenant = %+v, %v; want no cars", cars, err)
+	}
+	if cars, err := s.Cars.GetCarByBrand(other, brand, true); err != nil || len(cars) != 0 {
+		t.Errorf("GetCarByBrand from another tenant = %+v, %v; want no cars", cars, err)
+	}
+	if _, err := s.Engines.GetEngineById(other, engine.EngineID.String()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetEngineById from another tenant error = %v, want sql.ErrNoRows", err)
+	}
+	if engines, err := s.Engines.GetEngines(other); err != nil || len(engines) != 0 {
+		t.Errorf("GetEngines from another tenant = %+v, %v; want no engines", engines, err)
+	}
+
+	// Another tenant can neither use, change nor delete this tenant's rows.
+	if _, err := s.Cars.CreateCar(other, models.Car{
+		Name:     "Civic",
+		Year:     "2023",
+		Brand:    brand,
+		FuelType: "Petrol",
+		Engine:   models.Engine{EngineID: engine.EngineID},
+		Price:    models.Money{Amount: 2500000, Currency: "USD"},
+	}); !errors.Is(err, store.ErrInvalidEngine) {
+		t.Errorf("CreateCar with another tenant's engine error = %v, want store.ErrInvalidEngine", err)
+	}
+	otherEngine := NewEngine(other, t, s)
+	car.Engine = models.Engine{EngineID: otherEngine.EngineID}
+	if _, err := s.Cars.UpdateCar(other, car); !errors.Is(err, store.ErrCarNotFound) {
+		t.Errorf("UpdateCar from another tenant error = %v, want store.ErrCarNotFound", err)
+	}
+	if _, err := s.Engines.UpdateEngine(other, engine.EngineID.String(), models.Engine{Displacement: 1, NoOfCylinders: 1, CarRange: 1}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("UpdateEngine from another tenant error = %v, want sql.ErrNoRows", err)
+	}
+	if err := s.Cars.DeleteCar(other, car.ID.String()); !errors.Is(err, store.ErrCarNotFound) {
+		t.Errorf("DeleteCar from another tenant error = %v, want store.ErrCarNotFound", err)
+	}
+	if err := s.Engines.DeleteEngine(other, engine.EngineID.String(), true); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("DeleteEngine from another tenant error = %v, want sql.ErrNoRows", err)
+	}
+
+	if got, err := s.Cars.GetCarById(ctx, car.ID.String()); err != nil || got.Engine != engine {
+		t.Errorf("car changed by another tenant: %+v, %v", got, err)
+	}
+
+	if _, err := s.Cars.GetCars(context.Background()); !errors.Is(err, auth.ErrNoTenant) {
+		t.Errorf("GetCars without a tenant error = %v, want auth.ErrNoTenant", err)
+	}
+}
+
+func testTenants(ctx context.Context, t *testing.T, s Stores) {
+	created, err := s.Tenants.CreateTenant(ctx, models.Tenant{Name: "Northside Motors"})
+	if err != nil {
+		t.Fatalf("CreateTenant: %v", err)
+	}
+	if created.ID == uuid.Nil || created.Name != "Northside Motors" || created.CreatedAt.IsZero() {
+		t.Errorf("CreateTenant = %+v", created)
+	}
+
+	created.Name = "Southside Motors"
+	updated, err := s.Tenants.UpdateTenant(ctx, created)
+	if err != nil {
+		t.Fatalf("UpdateTenant: %v", err)
+	}
+	if updated.Name != "Southside Motors" {
+		t.Errorf("UpdateTenant name = %q, want Southside Motors", updated.Name)
+	}
+
+	tenants, err := s.Tenants.GetTenants(ctx)
+	if err != nil {
+		t.Fatalf("GetTenants: %v", err)
+	}
+	if !containsTenant(tenants, created.ID) || !containsTenant(tenants, models.DefaultTenantID) {
+		t.Errorf("GetTenants = %+v, want the new and the default tenant", tenants)
+	}
+
+	if err := s.Tenants.DeleteTenant(ctx, created.ID.String()); err != nil {
+		t.Fatalf("DeleteTenant: %v", err)
+	}
+	if _, err := s.Tenants.GetTenantById(ctx, created.ID.String()); !errors.Is(err, store.ErrTenantNotFound) {
+		t.Errorf("GetTenantById after delete error = %v, want store.ErrTenantNotFound", err)
+	}
+	if _, err := s.Tenants.UpdateTenant(ctx, created); !errors.Is(err, store.ErrTenantNotFound) {
+		t.Errorf("UpdateTenant(missing) error = %v, want store.ErrTenantNotFound", err)
+	}
+	if err := s.Tenants.DeleteTenant(ctx, created.ID.String()); !errors.Is(err, store.ErrTenantNotFound) {
+		t.Errorf("DeleteTenant twice error = %v, want store.ErrTenantNotFound", err)
+	}
+}
+
+func testDeleteTenantInUse(ctx context.Context, t *testing.T, s Stores) {
+	NewEngine(ctx, t, s)
+	tenantID, _ := auth.TenantFromContext(ctx)
+
+	if err := s.Tenants.DeleteTenant(ctx, tenantID.String()); !errors.Is(err, store.ErrTenantInUse) {
+		t.Errorf("DeleteTenant with engines error = %v, want store.ErrTenantInUse", err)
+	}
+	if _, err := s.Tenants.GetTenantById(ctx, tenantID.String()); err != nil {
+		t.Errorf("GetTenantById after refused delete: %v", err)
+	}
+}
+
+func containsTenant(tenants []models.Tenant, id uuid.UUID) bool {
+	for _, tenant := range tenants {
+		if tenant.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsEngine(engines []models.Engine, id uuid.UUID) bool {
+	for _, engine := range engines {
+		if engine.EngineID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func findCar(cars []models.Car, id uuid.UUID) (models.Car, bool) {
+	for _, car := range cars {
+		if car.ID == id {
+			return car, true
+		}
+	}
+	return models.Car{}, false
+}
@@ -0,0 +1,211 @@
+package tenant
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/driver"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+const selectTenantQuery = `SELECT id, name, created_at, updated_at FROM tenant WHERE id=$1`
+
+type TenantStore struct {
+	db *driver.DB
+}
+
+func NewTenantStore(db *driver.DB) *TenantStore {
+	return &TenantStore{db: db}
+}
+
+func (s TenantStore) CreateTenant(ctx context.Context, tenant models.Tenant) (models.Tenant, error) {
+	tracer := otel.Tracer("tenant-store")
+	ctx, span := tracer.Start(ctx, "CreateTenant-Store")
+	defer span.End()
+	var createdTenant models.Tenant
+
+	createdAt := time.Now()
+	newTenant := models.Tenant{
+		ID:        uuid.New(),
+		Name:      tenant.Name,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return createdTenant, err
+	}
+
+	query := `INSERT INTO tenant (id, name, created_at, updated_at) VALUES ($1, $2, $3, $4)`
+
+	_, err = tx.ExecContext(ctx, query, newTenant.ID, newTenant.Name, newTenant.CreatedAt, newTenant.UpdatedAt)
+	if err != nil {
+		tx.Rollback()
+		return createdTenant, err
+	}
+
+	createdTenant, err = scanTenant(tx.QueryRowContext(ctx, selectTenantQuery, newTenant.ID))
+	if err != nil {
+		tx.Rollback()
+		return createdTenant, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return createdTenant, err
+	}
+
+	return createdTenant, nil
+}
+
+func (s TenantStore) GetTenantById(ctx context.Context, tenantID string) (models.Tenant, error) {
+	tracer := otel.Tracer("tenant-store")
+	ctx, span := tracer.Start(ctx, "GetTenantById-Store")
+	defer span.End()
+
+	tenant, err := scanTenant(s.db.QueryRowContext(ctx, selectTenantQuery, tenantID))
+	if err == sql.ErrNoRows {
+		return tenant, store.ErrTenantNotFound
+	}
+	return tenant, err
+}
+
+func (s TenantStore) GetTenants(ctx context.Context) ([]models.Tenant, error) {
+	tracer := otel.Tracer("tenant-store")
+	ctx, span := tracer.Start(ctx, "GetTenants-Store")
+	defer span.End()
+	var tenants []models.Tenant
+
+	query := `SELECT id, name, created_at, updated_at FROM tenant ORDER BY name, id`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return tenants, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tenant models.Tenant
+		if err := rows.Scan(&tenant.ID, &tenant.Name, &tenant.CreatedAt, &tenant.UpdatedAt); err != nil {
+			return tenants, err
+		}
+		tenants = append(tenants, tenant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return tenants, err
+	}
+
+	return tenants, nil
+}
+
+func (s TenantStore) UpdateTenant(ctx context.Context, tenant models.Tenant) (models.Tenant, error) {
+	tracer := otel.Tracer("tenant-store")
+	ctx, span := tracer.Start(ctx, "UpdateTenant-Store")
+	defer span.End()
+	var updatedTenant models.Tenant
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return updatedTenant, err
+	}
+
+	query := `UPDATE tenant SET name=$2, updated_at=$3 WHERE id=$1`
+
+	result, err := tx.ExecContext(ctx, query, tenant.ID, tenant.Name, time.Now())
+	if err != nil {
+		tx.Rollback()
+		return updatedTenant, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return updatedTenant, err
+	}
+	if rowsAffected == 0 {
+		tx.Rollback()
+		return updatedTenant, store.ErrTenantNotFound
+	}
+
+	updatedTenant, err = scanTenant(tx.QueryRowContext(ctx, selectTenantQuery, tenant.ID))
+	if err != nil {
+		tx.Rollback()
+		return updatedTenant, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return updatedTenant, err
+	}
+
+	return updatedTenant, nil
+}
+
+// DeleteTenant refuses to delete a tenant that still owns cars or engines;
+// removing a dealership's inventory has to be a deliberate step of its own.
+func (s TenantStore) DeleteTenant(ctx context.Context, tenantID string) error {
+	tracer := otel.Tracer("tenant-store")
+	ctx, span := tracer.Start(ctx, "DeleteTenant-Store")
+	defer span.End()
+	id, err := uuid.Parse(tenantID)
+	if err != nil {
+		return store.ErrTenantNotFound
+	}
+	// The ownership check has to see the rows of the tenant being deleted,
+	// not those of the admin's own tenant.
+	ctx = auth.WithTenant(ctx, id)
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var owned int
+	query := `SELECT (SELECT COUNT(*) FROM car WHERE tenant_id=$1) + (SELECT COUNT(*) FROM engine WHERE tenant_id=$1)`
+	if err := tx.QueryRowContext(ctx, query, tenantID).Scan(&owned); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if owned > 0 {
+		tx.Rollback()
+		return store.ErrTenantInUse
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM tenant WHERE id=$1`, tenantID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if rowsAffected == 0 {
+		tx.Rollback()
+		return store.ErrTenantNotFound
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// scanTenant reads a row produced by selectTenantQuery.
+func scanTenant(row *sql.Row) (models.Tenant, error) {
+	var tenant models.Tenant
+	err := row.Scan(&tenant.ID, &tenant.Name, &tenant.CreatedAt, &tenant.UpdatedAt)
+	return tenant, err
+}
@@ -0,0 +1,182 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nitesh111sinha/car-management/auth"
+	"github.com/nitesh111sinha/car-management/driver"
+	"github.com/nitesh111sinha/car-management/models"
+	"github.com/nitesh111sinha/car-management/store"
+	"go.opentelemetry.io/otel"
+)
+
+const userColumns = `id, username, tenant_id, role, password_hash, created_at, updated_at`
+
+type UserStore struct {
+	db *driver.DB
+}
+
+func NewUserStore(db *driver.DB) *UserStore {
+	return &UserStore{db: db}
+}
+
+// CreateUser adds a user of user.TenantID, which must exist.
+func (s UserStore) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	tracer := otel.Tracer("user-store")
+	ctx, span := tracer.Start(ctx, "CreateUser-Store")
+	defer span.End()
+	var createdUser models.User
+
+	createdAt := time.Now().UTC()
+	newUser := models.User{
+		ID:           uuid.New(),
+		Username:     strings.TrimSpace(user.Username),
+		TenantID:     user.TenantID,
+		Role:         user.Role,
+		PasswordHash: user.PasswordHash,
+		CreatedAt:    createdAt,
+		UpdatedAt:    createdAt,
+	}
+
+	// Begin Transaction
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return createdUser, err
+	}
+
+	var exists int
+	err = tx.QueryRowContext(ctx, `SELECT 1 FROM tenant WHERE id=$1`, newUser.TenantID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		return createdUser, store.ErrTenantNotFound
+	}
+	if err != nil {
+		tx.Rollback()
+		return createdUser, err
+	}
+
+	err = tx.QueryRowContext(ctx, `SELECT 1 FROM app_user WHERE lower(username)=lower($1)`, newUser.Username).Scan(&exists)
+	if err == nil {
+		tx.Rollback()
+		return createdUser, store.ErrDuplicateUsername
+	}
+	if err != sql.ErrNoRows {
+		tx.Rollback()
+		return createdUser, err
+	}
+
+	query := `INSERT INTO app_user (` + userColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err = tx.ExecContext(ctx, query,
+		newUser.ID,
+		newUser.Username,
+		newUser.TenantID,
+		newUser.Role,
+		newUser.PasswordHash,
+		newUser.CreatedAt,
+		newUser.UpdatedAt)
+	if err != nil {
+		tx.Rollback()
+		return createdUser, err
+	}
+
+	createdUser, err = scanUser(tx.QueryRowContext(ctx, `SELECT `+userColumns+` FROM app_user WHERE id=$1`, newUser.ID))
+	if err != nil {
+		tx.Rollback()
+		return createdUser, err
+	}
+
+	// Commit Transaction
+	if err = tx.Commit(); err != nil {
+		return createdUser, err
+	}
+
+	return createdUser, nil
+}
+
+// GetUserByUsername finds a user of any tenant, ignoring the case of the
+// username. It reads from the primary so that a user can sign in as soon as
+// they have been created.
+func (s UserStore) GetUserByUsername(ctx context.Context, username string) (models.User, error) {
+	tracer := otel.Tracer("user-store")
+	ctx, span := tracer.Start(ctx, "GetUserByUsername-Store")
+	defer span.End()
+
+	query := `SELECT ` + userColumns + ` FROM app_user WHERE lower(username)=lower($1)`
+	user, err := scanUser(s.db.QueryRowContext(driver.WithPrimary(ctx), query, strings.TrimSpace(username)))
+	if err == sql.ErrNoRows {
+		return user, store.ErrUserNotFound
+	}
+	return user, err
+}
+
+// GetUsers lists the users of the tenant in ctx.
+func (s UserStore) GetUsers(ctx context.Context) ([]models.User, error) {
+	tracer := otel.Tracer("user-store")
+	ctx, span := tracer.Start(ctx, "GetUsers-Store")
+	defer span.End()
+	users := []models.User{}
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return users, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT `+userColumns+` FROM app_user WHERE tenant_id=$1 ORDER BY lower(username), id`, tenantID)
+	if err != nil {
+		return users, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return users, err
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return users, err
+	}
+
+	return users, nil
+}
+
+// DeleteUser deletes a user of the tenant in ctx.
+func (s UserStore) DeleteUser(ctx context.Context, userID string) error {
+	tracer := otel.Tracer("user-store")
+	ctx, span := tracer.Start(ctx, "DeleteUser-Store")
+	defer span.End()
+	tenantID, err := auth.TenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return store.ErrUserNotFound
+	}
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM app_user WHERE id=$1 AND tenant_id=$2`, id, tenantID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return store.ErrUserNotFound
+	}
+	return nil
+}
+
+// scanUser reads a row of userColumns from either *sql.Row or *sql.Rows.
+func scanUser(row interface{ Scan(dest ...any) error }) (models.User, error) {
+	var user models.User
+	err := row.Scan(&user.ID, &user.Username, &user.TenantID, &user.Role, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+	return user, err
+}